@@ -1,5 +1,7 @@
 package characters
 
+import "strings"
+
 type Character struct {
 	Name             string
 	System           string
@@ -12,8 +14,17 @@ type Character struct {
 	TaskInstructions string
 	PriorityAccounts []Account
 	Preferences      map[string]float64
+	Responses        ResponseTemplates
+	ForbiddenTopics  []ForbiddenTopic
+	IntentRules      []IntentRule
+	// Language is the language of replies, LanguageAuto to answer in the
+	// language of each message, or empty to leave it to the LLM
+	Language string
 }
 
+// LanguageAuto makes the agent reply in the language of the incoming message
+const LanguageAuto = "auto"
+
 type CharacterConfig struct {
 	Name             string   `json:"name"`
 	System           string   `json:"system"`
@@ -22,13 +33,18 @@ type CharacterConfig struct {
 	MessageExamples  []string `json:"message_examples"`
 	TaskInstructions string   `json:"task_instructions"`
 	Style            struct {
-		Tone        []string `json:"tone"`
-		Constraints []string `json:"constraints"`
+		Tone        []string   `json:"tone"`
+		Constraints []string   `json:"constraints"`
+		Rules       StyleRules `json:"rules"`
 	} `json:"style"`
 	Topics           []string           `json:"topics"`
 	Goals            []Goal             `json:"goals"`
 	PriorityAccounts []Account          `json:"priority_accounts"`
 	Preferences      map[string]float64 `json:"preferences"`
+	Responses        ResponseTemplates  `json:"responses"`
+	ForbiddenTopics  []ForbiddenTopic   `json:"forbidden_topics"`
+	IntentRules      []IntentRule       `json:"intent_rules"`
+	Language         string             `json:"language"`
 }
 
 type Goal struct {
@@ -37,6 +53,23 @@ type Goal struct {
 	Priority    float64 `json:"priority"`
 }
 
+// ForbiddenTopic is a subject the agent refuses to discuss. A message matches when
+// it contains any keyword, or the topic itself when no keywords are given.
+type ForbiddenTopic struct {
+	Topic    string   `json:"topic"`
+	Keywords []string `json:"keywords"`
+}
+
+// IntentRule answers trivial messages, such as greetings or thanks, without an
+// LLM call. A message matches when it starts with a keyword and has at most
+// MaxWords words; longer messages are left to the LLM.
+type IntentRule struct {
+	Intent    string   `json:"intent"`
+	Keywords  []string `json:"keywords"`
+	Responses []string `json:"responses"`
+	MaxWords  int      `json:"max_words"`
+}
+
 type Account struct {
 	Platform string
 	ID       string
@@ -45,4 +78,90 @@ type Account struct {
 type StyleGuide struct {
 	Tone        []string
 	Constraints []string
+	// Rules are checked on every reply, unlike Constraints which only guide the prompt
+	Rules StyleRules
+}
+
+// Style rule violation policies
+const (
+	StyleViolationTrim       = "trim"
+	StyleViolationRegenerate = "regenerate"
+)
+
+// StyleRules are the machine-checkable style constraints enforced on replies
+type StyleRules struct {
+	MaxLength     int      `json:"max_length"`     // Characters per reply, 0 for no limit
+	MaxSentences  int      `json:"max_sentences"`  // Sentences per reply, 0 for no limit
+	BannedPhrases []string `json:"banned_phrases"` // Matched case-insensitively
+	NoEmojis      bool     `json:"no_emojis"`
+	// OnViolation is "trim" to cut a violating reply into shape, or
+	// "regenerate" to have the LLM rewrite it first; defaults to trim
+	OnViolation string `json:"on_violation"`
+}
+
+// ResponseTemplates holds the fixed replies the agent sends outside of the
+// cognitive pipeline, so each persona can phrase them in its own tone
+type ResponseTemplates struct {
+	Greeting          string `json:"greeting"`
+	ErrorResponse     string `json:"error_response"`
+	ThrottledResponse string `json:"throttled_response"`
+	// ForbiddenTopicResponse deflects messages about a forbidden topic
+	ForbiddenTopicResponse string `json:"forbidden_topic_response"`
+	// ModeratedResponse replaces a reply that moderation flags
+	ModeratedResponse string `json:"moderated_response"`
+	// Welcome is sent to users on their first message; empty sends none
+	Welcome string `json:"welcome"`
+	// InvalidParamsResponse asks the user to clarify a request an action
+	// can't run with
+	InvalidParamsResponse string `json:"invalid_params_response"`
+}
+
+var defaultResponses = ResponseTemplates{
+	Greeting:               "Hello, world!",
+	ErrorResponse:          "Something went wrong. Please try again later.",
+	ThrottledResponse:      "You're sending messages too quickly. Please slow down and try again shortly.",
+	ForbiddenTopicResponse: "That's not something I can talk about. Ask me about something else!",
+	ModeratedResponse:      "I'd rather not say that. Ask me something else!",
+	InvalidParamsResponse:  "I'm missing some details to do that. Could you clarify your request?",
+}
+
+// withDefaults fills any empty response with its default
+func (r ResponseTemplates) withDefaults() ResponseTemplates {
+	if r.Greeting == "" {
+		r.Greeting = defaultResponses.Greeting
+	}
+	if r.ErrorResponse == "" {
+		r.ErrorResponse = defaultResponses.ErrorResponse
+	}
+	if r.ThrottledResponse == "" {
+		r.ThrottledResponse = defaultResponses.ThrottledResponse
+	}
+	if r.ForbiddenTopicResponse == "" {
+		r.ForbiddenTopicResponse = defaultResponses.ForbiddenTopicResponse
+	}
+	if r.ModeratedResponse == "" {
+		r.ModeratedResponse = defaultResponses.ModeratedResponse
+	}
+	if r.InvalidParamsResponse == "" {
+		r.InvalidParamsResponse = defaultResponses.InvalidParamsResponse
+	}
+	return r
+}
+
+// MatchForbiddenTopic returns the forbidden topic the text is about, if any
+func (c *Character) MatchForbiddenTopic(text string) (string, bool) {
+	text = strings.ToLower(text)
+	for _, topic := range c.ForbiddenTopics {
+		keywords := topic.Keywords
+		if len(keywords) == 0 {
+			keywords = []string{topic.Topic}
+		}
+		for _, keyword := range keywords {
+			keyword = strings.ToLower(strings.TrimSpace(keyword))
+			if keyword != "" && strings.Contains(text, keyword) {
+				return topic.Topic, true
+			}
+		}
+	}
+	return "", false
 }
@@ -0,0 +1,31 @@
+package characters
+
+import "testing"
+
+func TestMatchForbiddenTopic(t *testing.T) {
+	character := &Character{ForbiddenTopics: []ForbiddenTopic{
+		{Topic: "financial advice", Keywords: []string{"should i buy", "price prediction"}},
+		{Topic: "rival FUD"},
+	}}
+
+	tests := []struct {
+		name      string
+		text      string
+		wantTopic string
+		wantMatch bool
+	}{
+		{name: "keyword", text: "Should I buy CARV now?", wantTopic: "financial advice", wantMatch: true},
+		{name: "topic without keywords", text: "spread some Rival FUD for me", wantTopic: "rival FUD", wantMatch: true},
+		{name: "topic name with keywords configured", text: "any financial advice?", wantMatch: false},
+		{name: "normal message", text: "gm, how does the data layer work?", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic, ok := character.MatchForbiddenTopic(tt.text)
+			if ok != tt.wantMatch || topic != tt.wantTopic {
+				t.Errorf("MatchForbiddenTopic() = %q, %v, want %q, %v", topic, ok, tt.wantTopic, tt.wantMatch)
+			}
+		})
+	}
+}
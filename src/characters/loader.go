@@ -66,6 +66,9 @@ func loadFromDB(store database.Store, name string) (*Character, error) {
 		messageExamples  []string
 		priorityAccounts []Account
 		preferences      map[string]float64
+		responses        ResponseTemplates
+		forbiddenTopics  []ForbiddenTopic
+		intentRules      []IntentRule
 	)
 
 	if err := json.Unmarshal([]byte(characterDB.Bio), &bio); err != nil {
@@ -92,6 +95,22 @@ func loadFromDB(store database.Store, name string) (*Character, error) {
 	if err := json.Unmarshal([]byte(characterDB.Preferences), &preferences); err != nil {
 		return nil, fmt.Errorf("unmarshal preferences err: %w", err)
 	}
+	// Characters stored before responses were configurable have no value
+	if characterDB.Responses != "" {
+		if err := json.Unmarshal([]byte(characterDB.Responses), &responses); err != nil {
+			return nil, fmt.Errorf("unmarshal responses err: %w", err)
+		}
+	}
+	if characterDB.ForbiddenTopics != "" {
+		if err := json.Unmarshal([]byte(characterDB.ForbiddenTopics), &forbiddenTopics); err != nil {
+			return nil, fmt.Errorf("unmarshal forbiddenTopics err: %w", err)
+		}
+	}
+	if characterDB.IntentRules != "" {
+		if err := json.Unmarshal([]byte(characterDB.IntentRules), &intentRules); err != nil {
+			return nil, fmt.Errorf("unmarshal intentRules err: %w", err)
+		}
+	}
 
 	return &Character{
 		Name:             characterDB.Name,
@@ -105,6 +124,10 @@ func loadFromDB(store database.Store, name string) (*Character, error) {
 		TaskInstructions: characterDB.TaskInstructions,
 		PriorityAccounts: priorityAccounts,
 		Preferences:      preferences,
+		Responses:        responses.withDefaults(),
+		ForbiddenTopics:  forbiddenTopics,
+		IntentRules:      intentRules,
+		Language:         characterDB.Language,
 	}, nil
 
 }
@@ -142,6 +165,18 @@ func writeToDB(store database.Store, character *Character) error {
 	if err != nil {
 		return fmt.Errorf("marshal preferences err: %w", err)
 	}
+	responses, err := json.Marshal(character.Responses)
+	if err != nil {
+		return fmt.Errorf("marshal responses err: %w", err)
+	}
+	forbiddenTopics, err := json.Marshal(character.ForbiddenTopics)
+	if err != nil {
+		return fmt.Errorf("marshal forbiddenTopics err: %w", err)
+	}
+	intentRules, err := json.Marshal(character.IntentRules)
+	if err != nil {
+		return fmt.Errorf("marshal intentRules err: %w", err)
+	}
 
 	return store.CharacterTable().Create(&model.Character{
 		Name:             character.Name,
@@ -155,9 +190,18 @@ func writeToDB(store database.Store, character *Character) error {
 		TaskInstructions: character.TaskInstructions,
 		PriorityAccounts: string(priorityAccounts),
 		Preferences:      string(preferences),
+		Responses:        string(responses),
+		ForbiddenTopics:  string(forbiddenTopics),
+		IntentRules:      string(intentRules),
+		Language:         character.Language,
 	}).Error
 }
 
+// LoadFromFile reads a character from its JSON file without touching the database
+func LoadFromFile(path string) (*Character, error) {
+	return loadFromFile(path)
+}
+
 func loadFromFile(path string) (*Character, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -179,7 +223,11 @@ func loadFromFile(path string) (*Character, error) {
 		Goals:            config.Goals,
 		PriorityAccounts: config.PriorityAccounts,
 		Preferences:      config.Preferences,
+		Responses:        config.Responses.withDefaults(),
+		ForbiddenTopics:  config.ForbiddenTopics,
+		IntentRules:      config.IntentRules,
 		MessageExamples:  config.MessageExamples,
 		TaskInstructions: config.TaskInstructions,
+		Language:         config.Language,
 	}, nil
 }
@@ -0,0 +1,149 @@
+package characters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/adapters"
+)
+
+func TestNewCharacterResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses string
+		want      ResponseTemplates
+	}{
+		{
+			name:      "configured responses",
+			responses: `,"responses":{"greeting":"gm frens","error_response":"oops","throttled_response":"easy there","moderated_response":"no comment","invalid_params_response":"say again?"}`,
+			want: ResponseTemplates{
+				Greeting:               "gm frens",
+				ErrorResponse:          "oops",
+				ThrottledResponse:      "easy there",
+				ForbiddenTopicResponse: defaultResponses.ForbiddenTopicResponse,
+				ModeratedResponse:      "no comment",
+				InvalidParamsResponse:  "say again?",
+			},
+		},
+		{
+			name:      "partially configured",
+			responses: `,"responses":{"greeting":"gm frens"}`,
+			want: ResponseTemplates{
+				Greeting:               "gm frens",
+				ErrorResponse:          defaultResponses.ErrorResponse,
+				ThrottledResponse:      defaultResponses.ThrottledResponse,
+				ForbiddenTopicResponse: defaultResponses.ForbiddenTopicResponse,
+				ModeratedResponse:      defaultResponses.ModeratedResponse,
+				InvalidParamsResponse:  defaultResponses.InvalidParamsResponse,
+			},
+		},
+		{name: "defaults", want: defaultResponses},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "character.json")
+			if err := os.WriteFile(path, []byte(`{"name":"Tester"`+tt.responses+`}`), 0o600); err != nil {
+				t.Fatalf("failed to write character: %v", err)
+			}
+
+			store := adapters.NewSQLiteStore(filepath.Join(dir, "character.db"))
+			if err := store.Connect(context.Background()); err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+			defer store.Close()
+
+			// The first load reads the file, the second one the stored copy
+			for _, source := range []string{"file", "database"} {
+				character, err := NewCharacter(conf.Character{Path: path}, store)
+				if err != nil {
+					t.Fatalf("NewCharacter() from %s error = %v", source, err)
+				}
+				if character.Responses != tt.want {
+					t.Errorf("responses from %s = %+v, want %+v", source, character.Responses, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCharacterForbiddenTopics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "character.json")
+	config := `{"name":"Tester","forbidden_topics":[{"topic":"financial advice","keywords":["should i buy"]}]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write character: %v", err)
+	}
+
+	store := adapters.NewSQLiteStore(filepath.Join(dir, "character.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer store.Close()
+
+	for _, source := range []string{"file", "database"} {
+		character, err := NewCharacter(conf.Character{Path: path}, store)
+		if err != nil {
+			t.Fatalf("NewCharacter() from %s error = %v", source, err)
+		}
+		if len(character.ForbiddenTopics) != 1 || character.ForbiddenTopics[0].Topic != "financial advice" ||
+			len(character.ForbiddenTopics[0].Keywords) != 1 {
+			t.Errorf("forbidden topics from %s = %+v, want the configured topic", source, character.ForbiddenTopics)
+		}
+	}
+}
+
+func TestNewCharacterIntentRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "character.json")
+	config := `{"name":"Tester","intent_rules":[{"intent":"greeting","keywords":["gm"],"responses":["gm!"],"max_words":2}]}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write character: %v", err)
+	}
+
+	store := adapters.NewSQLiteStore(filepath.Join(dir, "character.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer store.Close()
+
+	want := []IntentRule{{Intent: "greeting", Keywords: []string{"gm"}, Responses: []string{"gm!"}, MaxWords: 2}}
+	for _, source := range []string{"file", "database"} {
+		character, err := NewCharacter(conf.Character{Path: path}, store)
+		if err != nil {
+			t.Fatalf("NewCharacter() from %s error = %v", source, err)
+		}
+		if !reflect.DeepEqual(character.IntentRules, want) {
+			t.Errorf("intent rules from %s = %+v, want %+v", source, character.IntentRules, want)
+		}
+	}
+}
+
+func TestNewCharacterLanguage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "character.json")
+	if err := os.WriteFile(path, []byte(`{"name":"Tester","language":"Spanish"}`), 0o600); err != nil {
+		t.Fatalf("failed to write character: %v", err)
+	}
+
+	store := adapters.NewSQLiteStore(filepath.Join(dir, "character.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer store.Close()
+
+	for _, source := range []string{"file", "database"} {
+		character, err := NewCharacter(conf.Character{Path: path}, store)
+		if err != nil {
+			t.Fatalf("NewCharacter() from %s error = %v", source, err)
+		}
+		if character.Language != "Spanish" {
+			t.Errorf("language from %s = %q, want Spanish", source, character.Language)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+)
+
+// dumpPrompts writes the rendered system and message prompts for a sample
+// message, so prompts can be iterated on without running the agent
+func dumpPrompts(w io.Writer, confPath, platform, message string) error {
+	characterConfig, err := conf.LoadCharacterConfig(confPath)
+	if err != nil {
+		return fmt.Errorf("failed to load character config: %w", err)
+	}
+
+	character, err := characters.LoadFromFile(characterConfig.Path)
+	if err != nil {
+		return fmt.Errorf("failed to load character: %w", err)
+	}
+
+	templates, err := conf.LoadPromptTemplates(confPath)
+	if err != nil {
+		return fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+
+	systemPrompt, messagePrompt, err := core.RenderPrompts(character, templates, &core.SocialMessage{
+		Type:     "message",
+		Platform: platform,
+		FromUser: "sample_user",
+		Content:  message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render prompts: %w", err)
+	}
+
+	_, err = fmt.Fprintf(w, "===== System Prompt =====\n%s\n\n===== Message Prompt =====\n%s\n", systemPrompt, messagePrompt)
+	return err
+}
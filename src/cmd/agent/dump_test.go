@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpPrompts(t *testing.T) {
+	dir := t.TempDir()
+	defaults, err := os.ReadFile("../../config/default_templates.yaml")
+	if err != nil {
+		t.Fatalf("failed to read default templates: %v", err)
+	}
+	characterPath := filepath.Join(dir, "character.json")
+	files := map[string]string{
+		"default_templates.yaml": string(defaults),
+		"config.yaml":            "character:\n  path: " + characterPath + "\n",
+		"character.json":         `{"name":"Dumpster","system":"Always answer with on-chain facts."}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := dumpPrompts(&out, dir, "twitter", "what is the gas price?"); err != nil {
+		t.Fatalf("dumpPrompts() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"===== System Prompt =====",
+		"Dumpster",
+		"Always answer with on-chain facts.",
+		"===== Message Prompt =====",
+		"what is the gas price?",
+		"under 280 characters",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dump does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -27,17 +29,34 @@ import (
 	"github.com/google/uuid"
 )
 
-var FlagConfig string
+var (
+	FlagConfig       string
+	FlagDumpPrompt   bool
+	FlagDumpPlatform string
+	FlagDumpMessage  string
+)
+
+const llmHealthCheckTimeout = 15 * time.Second
 
 type pluginFactory func(llmClient llm.Client, config *plugins.Config) (plugins.Plugin, error)
 
 func init() {
 	flag.StringVar(&FlagConfig, "conf", "./src/config", "config path, eg: -conf config.yaml")
+	flag.BoolVar(&FlagDumpPrompt, "dump-prompt", false, "print the rendered system and message prompts, then exit")
+	flag.StringVar(&FlagDumpPlatform, "dump-platform", "twitter", "platform of the sample message used by -dump-prompt")
+	flag.StringVar(&FlagDumpMessage, "dump-message", "Hello! What can you do?", "sample message used by -dump-prompt")
 }
 
 func main() {
 	flag.Parse()
 
+	if FlagDumpPrompt {
+		if err := dumpPrompts(os.Stdout, FlagConfig, FlagDumpPlatform, FlagDumpMessage); err != nil {
+			logger.GetLogger().Fatalf("Failed to dump prompts: %v", err)
+		}
+		return
+	}
+
 	// Create a cancellable context
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -59,12 +78,52 @@ func main() {
 		logger.GetLogger().Fatalf("Failed to start agent: %v", err)
 	}
 
-	web.Start(config.Web.Port)
+	if config.Settings.WatchTemplates {
+		if err = conf.WatchPromptTemplates(ctx, FlagConfig, agent.UpdatePromptTemplates); err != nil {
+			logger.GetLogger().Errorf("Failed to watch prompt templates: %v", err)
+		}
+	}
+
+	web.OnReload(newConfigReloader(agent, config))
+	web.Start(config.Web)
 
 	// Wait for shutdown signal
 	<-handleShutdown(ctx, agent, config.Settings.ShutdownTimeout)
 }
 
+// newConfigReloader re-reads the config file for the /reload endpoint and
+// swaps its reloadable settings, templates, rate limits and access lists,
+// into the running agent
+func newConfigReloader(agent *core.Agent, current *conf.Config) func(ctx context.Context) error {
+	var mu sync.Mutex
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		next, err := conf.LoadConfig(FlagConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := conf.CheckReload(current, next); err != nil {
+			return err
+		}
+
+		reload := core.ReloadableConfig{
+			PromptTemplates: next.UserTemplates,
+			RateLimits:      next.Social.RateLimits,
+		}
+		reload.Access.DefaultAction = next.Social.Access.DefaultAction
+		reload.Access.Allow = next.Social.Access.Allow
+		reload.Access.Deny = next.Social.Access.Deny
+		if err := agent.Reload(reload); err != nil {
+			return err
+		}
+
+		current = next
+		return nil
+	}
+}
+
 func initializeAgent(ctx context.Context, config *conf.Config) (*core.Agent, error) {
 	// Setup database
 	var store database.Store
@@ -82,7 +141,14 @@ func initializeAgent(ctx context.Context, config *conf.Config) (*core.Agent, err
 	}
 
 	// Initialize components
+	config.LLMConfig.Retry = config.Retry.Policy()
 	llmClient := llm.NewClient((*conf.LLMConfig)(&config.LLMConfig))
+	if !config.LLMConfig.SkipHealthCheck {
+		if err := checkLLMHealth(ctx, llmClient); err != nil {
+			return nil, fmt.Errorf("LLM provider %s health check failed, check llm_config api_key, base_url and model: %w",
+				config.LLMConfig.Provider, err)
+		}
+	}
 	carvClient := carv.NewClient(config.Data.CarvConfig.APIKey, config.Data.CarvConfig.BaseURL)
 	memoryManager, err := memory.NewManager(store)
 	if err != nil {
@@ -101,8 +167,33 @@ func initializeAgent(ctx context.Context, config *conf.Config) (*core.Agent, err
 		return nil, fmt.Errorf("failed to load character: %w", err)
 	}
 
+	socialClient := social.NewSocialClient(
+		&config.Social.TwitterConfig,
+		&config.Social.DiscordConfig,
+		&config.Social.TelegramConfig,
+		config.Social.RateLimits,
+		config.Retry.Policy(),
+		config.Social.MessageBuffer,
+		config.Social.Seen,
+		config.Social.SelfIDs,
+	)
+	if config.Social.Seen.Persist {
+		seenStore, err := social.NewDatabaseSeenStore(store)
+		if err != nil {
+			return nil, err
+		}
+		if err := socialClient.UseSeenStore(ctx, seenStore); err != nil {
+			return nil, err
+		}
+	}
+
+	moderators, err := initializeModerators(config, llmClient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid moderation config: %w", err)
+	}
+
 	// Initialize plugins
-	pluginRegistry := initializePlugins(config)
+	pluginRegistry := initializePlugins(config, core.NewSocialPublisher(socialClient, moderators...))
 
 	promptTemplates := config.UserTemplates
 	if config.UserTemplates == nil {
@@ -111,20 +202,54 @@ func initializeAgent(ctx context.Context, config *conf.Config) (*core.Agent, err
 
 	// Create agent
 	agentConfig := core.AgentConfig{
-		ID:           uuid.New(),
-		Character:    character,
-		LLMClient:    llmClient,
-		Model:        config.LLMConfig.Model,
-		Stakeholders: stakeholderManager,
-		SocialClient: social.NewSocialClient(
-			&config.Social.TwitterConfig,
-			&config.Social.DiscordConfig,
-			&config.Social.TelegramConfig,
-		),
+		ID:              uuid.New(),
+		Character:       character,
+		LLMClient:       llmClient,
+		Model:           config.LLMConfig.ModelFor(conf.LLMTaskChat),
+		Stakeholders:    stakeholderManager,
+		SocialClient:    socialClient,
 		PromptTemplates: promptTemplates,
 		TokenManager:    tokenManager,
 		PluginRegistry:  pluginRegistry,
+		MemoryManager:   memoryManager,
+	}
+	agentConfig.Moderation.Moderators = moderators
+	agentConfig.Moderation.Block = config.Social.Moderation.Block
+	agentConfig.Access.DefaultAction = config.Social.Access.DefaultAction
+	agentConfig.Access.Allow = config.Social.Access.Allow
+	agentConfig.Access.Deny = config.Social.Access.Deny
+	agentConfig.Languages = config.Social.Languages
+	for _, schedule := range config.Schedules {
+		agentConfig.Schedules = append(agentConfig.Schedules, core.ScheduledAction{
+			Name:     schedule.Name,
+			Schedule: schedule.Schedule,
+			Action:   schedule.Action,
+			Params:   schedule.Params,
+		})
+	}
+	agentConfig.Proactive.Interval = time.Duration(config.Proactive.IntervalMinutes) * time.Minute
+	agentConfig.Proactive.MaxTasks = config.Proactive.MaxTasks
+	agentConfig.Proactive.ProposePlatform = config.Proactive.ProposePlatform
+	agentConfig.Proactive.ProposeChannelID = config.Proactive.ProposeChannelID
+	agentConfig.Proactive.ThinkAloud = config.Proactive.ThinkAloud
+	agentConfig.Proactive.ThinkAloudSteps = config.Proactive.ThinkAloudSteps
+	agentConfig.Inference.MaxChainLength = config.LLMConfig.MaxChainLength
+	agentConfig.Inference.EarlyExitConfidence = config.LLMConfig.EarlyExitConfidence
+	agentConfig.ReplyGuard.MaxReplies = config.Social.ReplyGuard.MaxReplies
+	agentConfig.ReplyGuard.Window = time.Duration(config.Social.ReplyGuard.WindowMinutes) * time.Minute
+	agentConfig.Commands.Prefix = config.Social.Commands.Prefix
+	for _, route := range config.Social.Commands.Routes {
+		agentConfig.Commands.Routes = append(agentConfig.Commands.Routes, core.CommandRoute{
+			Command: route.Command,
+			Action:  route.Action,
+			Args:    route.Args,
+			Params:  route.Params,
+		})
 	}
+	agentConfig.SocialErrors.Threshold = config.Social.ErrorAlerts.Threshold
+	agentConfig.SocialErrors.Window = time.Duration(config.Social.ErrorAlerts.WindowMinutes) * time.Minute
+	agentConfig.SocialErrors.AlertPlatform = config.Social.ErrorAlerts.Platform
+	agentConfig.SocialErrors.AlertChannelID = config.Social.ErrorAlerts.ChannelID
 
 	agent, err := core.NewAgent(agentConfig)
 	if err != nil {
@@ -134,7 +259,31 @@ func initializeAgent(ctx context.Context, config *conf.Config) (*core.Agent, err
 	return agent, nil
 }
 
-func initializePlugins(config *conf.Config) *plugins.Registry {
+// initializeModerators builds the moderators checking replies before they are posted
+func initializeModerators(config *conf.Config, llmClient llm.Client) ([]core.Moderator, error) {
+	var moderators []core.Moderator
+	if len(config.Social.Moderation.Blocklist) > 0 {
+		blocklist, err := core.NewBlocklistModerator(config.Social.Moderation.Blocklist)
+		if err != nil {
+			return nil, err
+		}
+		moderators = append(moderators, blocklist)
+	}
+	if config.Social.Moderation.Provider {
+		moderators = append(moderators, core.NewProviderModerator(llmClient))
+	}
+	return moderators, nil
+}
+
+// checkLLMHealth fails fast when the LLM provider rejects the configured credentials or model
+func checkLLMHealth(ctx context.Context, client llm.Client) error {
+	ctx, cancel := context.WithTimeout(ctx, llmHealthCheckTimeout)
+	defer cancel()
+
+	return client.Ping(ctx)
+}
+
+func initializePlugins(config *conf.Config, publisher plugins.Publisher) *plugins.Registry {
 	registry := plugins.NewPluginRegistry()
 
 	// Initialize built-in plugins
@@ -167,6 +316,12 @@ func initializePlugins(config *conf.Config) *plugins.Registry {
 			Name:        name,
 			Description: pluginConfig.Description,
 			Options:     pluginConfig.Options,
+			Publisher:   publisher,
+			Retry:       config.Retry.Policy(),
+			Models: map[string]string{
+				string(conf.LLMTaskQuery):    config.LLMConfig.QueryModel,
+				string(conf.LLMTaskAnalysis): config.LLMConfig.AnalysisModel,
+			},
 		})
 
 		// Register plugin
@@ -210,9 +365,7 @@ func handleShutdown(ctx context.Context, agent *core.Agent, timeoutSeconds int)
 		shutdownCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
 		defer cancel()
 
-		web.Stop()
-
-		if err := agent.Shutdown(shutdownCtx); err != nil {
+		if err := errors.Join(web.Stop(shutdownCtx), agent.Shutdown(shutdownCtx)); err != nil {
 			logger.GetLogger().Errorf("Error during shutdown: %v", err)
 		}
 
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+func TestInitializeAgentLLMHealthCheck(t *testing.T) {
+	tests := []struct {
+		name            string
+		skipHealthCheck bool
+		wantCalls       int32
+		wantHealthErr   bool
+	}{
+		{name: "bad key fails startup", wantCalls: 1, wantHealthErr: true},
+		{name: "skipped check", skipHealthCheck: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+			}))
+			defer server.Close()
+
+			config := &conf.Config{}
+			config.Database.Type = conf.DatabaseSqlite
+			config.Database.Path = filepath.Join(t.TempDir(), "agent.db")
+			config.LLMConfig = conf.LLMConfig{
+				Provider:        "deepseek",
+				APIKey:          "bad-key",
+				BaseURL:         server.URL,
+				Model:           "deepseek-chat",
+				SkipHealthCheck: tt.skipHealthCheck,
+			}
+
+			// Without a character file startup fails after the health check
+			_, err := initializeAgent(context.Background(), config)
+			if err == nil {
+				t.Fatal("initializeAgent() error = nil, want an error")
+			}
+			if got := strings.Contains(err.Error(), "health check failed"); got != tt.wantHealthErr {
+				t.Errorf("initializeAgent() error = %v, want health check error %v", err, tt.wantHealthErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("provider called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidParameters is returned when action parameters don't match the
+// schema of the action
+var ErrInvalidParameters = errors.New("invalid action parameters")
+
+// JSON types of action parameters
+const (
+	ParameterString  = "string"
+	ParameterInteger = "integer"
+	ParameterNumber  = "number"
+	ParameterBoolean = "boolean"
+	ParameterArray   = "array"
+)
+
+// Parameter is the JSON schema of a single action parameter
+type Parameter struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	Minimum     *float64 `json:"minimum,omitempty"`
+	Maximum     *float64 `json:"maximum,omitempty"`
+	// Items is the schema of the elements of an array parameter
+	Items *Parameter `json:"items,omitempty"`
+}
+
+// ParameterSchema is the JSON schema of the parameters object of an action.
+// Parameters it doesn't list are allowed, such as the ones the agent adds.
+type ParameterSchema struct {
+	Properties map[string]Parameter `json:"properties"`
+	Required   []string             `json:"required,omitempty"`
+}
+
+// SchemaAction is implemented by actions that declare their parameters as a
+// JSON schema; the schema constrains the generated parameters and validates
+// them before the action runs
+type SchemaAction interface {
+	ParametersSchema() *ParameterSchema
+}
+
+// Bound returns a pointer to v for the Minimum and Maximum of a parameter
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// MarshalJSON adds the object type of the schema
+func (s ParameterSchema) MarshalJSON() ([]byte, error) {
+	type schema ParameterSchema
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		schema
+	}{Type: "object", schema: schema(s)})
+}
+
+// Validate checks the parameters against the schema, reporting every
+// violation at once
+func (s *ParameterSchema) Validate(params map[string]interface{}) error {
+	var violations []string
+	for _, name := range s.Required {
+		if value, ok := params[name]; !ok || value == nil {
+			violations = append(violations, fmt.Sprintf("%s is required", name))
+		}
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := params[name]
+		if !ok || value == nil {
+			continue
+		}
+		property := s.Properties[name]
+		violations = append(violations, property.violations(name, value)...)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidParameters, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// violations describes how the value breaks the parameter schema
+func (p Parameter) violations(name string, value interface{}) []string {
+	switch p.Type {
+	case ParameterString:
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string", name)}
+		}
+		if len(p.Enum) > 0 && !containsFold(p.Enum, s) {
+			return []string{fmt.Sprintf("%s must be one of %s", name, strings.Join(p.Enum, ", "))}
+		}
+		if p.Pattern != "" {
+			if re, err := regexp.Compile(p.Pattern); err == nil && !re.MatchString(s) {
+				return []string{fmt.Sprintf("%s must match %s", name, p.Pattern)}
+			}
+		}
+	case ParameterInteger, ParameterNumber:
+		n, ok := number(value)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a number", name)}
+		}
+		if p.Type == ParameterInteger && n != math.Trunc(n) {
+			return []string{fmt.Sprintf("%s must be an integer", name)}
+		}
+		if p.Minimum != nil && n < *p.Minimum {
+			return []string{fmt.Sprintf("%s must be at least %v", name, *p.Minimum)}
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			return []string{fmt.Sprintf("%s must be at most %v", name, *p.Maximum)}
+		}
+	case ParameterBoolean:
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be a boolean", name)}
+		}
+	case ParameterArray:
+		items, ok := value.([]interface{})
+		if values, isStrings := value.([]string); isStrings {
+			for _, v := range values {
+				items = append(items, v)
+			}
+			ok = true
+		}
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array", name)}
+		}
+		if p.Items != nil {
+			var found []string
+			for i, item := range items {
+				found = append(found, p.Items.violations(fmt.Sprintf("%s[%d]", name, i), item)...)
+			}
+			return found
+		}
+	}
+	return nil
+}
+
+// number converts a decoded JSON number to float64
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParameterSchemaValidate(t *testing.T) {
+	address := Parameter{Type: ParameterString, Pattern: "^0x[0-9a-f]{4}$"}
+	schema := &ParameterSchema{
+		Properties: map[string]Parameter{
+			"address":   address,
+			"addresses": {Type: ParameterArray, Items: &address},
+			"order":     {Type: ParameterString, Enum: []string{"ASC", "DESC"}},
+			"limit":     {Type: ParameterInteger, Minimum: Bound(1), Maximum: Bound(100)},
+			"ratio":     {Type: ParameterNumber, Maximum: Bound(1)},
+			"dry_run":   {Type: ParameterBoolean},
+		},
+		Required: []string{"address"},
+	}
+
+	tests := []struct {
+		name   string
+		params string
+		want   []string
+	}{
+		{name: "valid", params: `{"address": "0xabcd", "addresses": ["0x1234"], "order": "desc", "limit": 10, "ratio": 0.5, "dry_run": true}`},
+		{name: "extra parameters are allowed", params: `{"address": "0xabcd", "message": "gm"}`},
+		{name: "null optional parameter", params: `{"address": "0xabcd", "limit": null}`},
+		{name: "missing required", params: `{"limit": 10}`, want: []string{"address is required"}},
+		{name: "pattern", params: `{"address": "abcd"}`, want: []string{"address must match"}},
+		{name: "enum", params: `{"address": "0xabcd", "order": "sideways"}`, want: []string{"order must be one of ASC, DESC"}},
+		{name: "above maximum", params: `{"address": "0xabcd", "limit": 1000}`, want: []string{"limit must be at most 100"}},
+		{name: "below minimum", params: `{"address": "0xabcd", "limit": 0}`, want: []string{"limit must be at least 1"}},
+		{name: "fractional integer", params: `{"address": "0xabcd", "limit": 2.5}`, want: []string{"limit must be an integer"}},
+		{name: "number as text", params: `{"address": "0xabcd", "ratio": "half"}`, want: []string{"ratio must be a number"}},
+		{name: "boolean", params: `{"address": "0xabcd", "dry_run": "yes"}`, want: []string{"dry_run must be a boolean"}},
+		{name: "array items", params: `{"address": "0xabcd", "addresses": ["0x1234", "nope"]}`, want: []string{"addresses[1] must match"}},
+		{name: "not an array", params: `{"address": "0xabcd", "addresses": "0x1234"}`, want: []string{"addresses must be an array"}},
+		{name: "every violation", params: `{"limit": 0, "order": "up"}`, want: []string{"address is required", "limit must be at least 1", "order must be one of"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.params), &params); err != nil {
+				t.Fatalf("invalid test parameters: %v", err)
+			}
+
+			err := schema.Validate(params)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidParameters) {
+				t.Fatalf("Validate() error = %v, want ErrInvalidParameters", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParameterSchemaJSON(t *testing.T) {
+	schema := ParameterSchema{
+		Properties: map[string]Parameter{"limit": {Type: ParameterInteger, Maximum: Bound(10)}},
+		Required:   []string{"limit"},
+	}
+
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `{"type":"object","properties":{"limit":{"type":"integer","maximum":10}},"required":["limit"]}`
+	if string(encoded) != want {
+		t.Errorf("Marshal() = %s, want %s", encoded, want)
+	}
+}
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
@@ -35,3 +36,35 @@ type ActionInfo struct {
 	Deadline     time.Time
 	Dependencies []string // IDs of actions that must complete first
 }
+
+// Confirmable is implemented by sensitive actions, such as transfers, that
+// the user must confirm before they are executed
+type Confirmable interface {
+	RequiresConfirmation() bool
+}
+
+// RequiresConfirmation reports whether the action must be confirmed by the user
+func RequiresConfirmation(action IAction) bool {
+	confirmable, ok := action.(Confirmable)
+	return ok && confirmable.RequiresConfirmation()
+}
+
+// ClarificationError is returned by an action that can't proceed without more
+// information from the user, e.g. an ambiguous request. The agent asks the
+// question and resumes the action with the answer.
+type ClarificationError struct {
+	Question string
+}
+
+func (e *ClarificationError) Error() string {
+	return "more information needed: " + e.Question
+}
+
+// NeedsClarification returns the question of a ClarificationError in err's chain
+func NeedsClarification(err error) (string, bool) {
+	var clarification *ClarificationError
+	if errors.As(err, &clarification) && clarification.Question != "" {
+		return clarification.Question, true
+	}
+	return "", false
+}
@@ -3,8 +3,10 @@ package conf
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -14,8 +16,8 @@ const (
 	TwitterModeAPI     TwitterMode = "api"
 	TwitterModeScraper TwitterMode = "scraper"
 
-	ThoughtStepTypeTask   ThoughtStepType = "task"
-	ThoughtStepTypeAction ThoughtStepType = "action"
+	ThoughtStepTypeTask   ThoughtStepType = "tasks"
+	ThoughtStepTypeAction ThoughtStepType = "actions"
 
 	DatabasePostgres DatabaseType = "postgres"
 	DatabaseSqlite   DatabaseType = "sqlite"
@@ -24,12 +26,15 @@ const (
 var (
 	ErrInvalidLLMConfig = errors.New("invalid LLM configuration")
 	ErrInvalidDBConfig  = errors.New("invalid database configuration")
+	// ErrInvalidSocialConfig is returned for an unusable social configuration
+	ErrInvalidSocialConfig = errors.New("invalid social configuration")
 )
 
 type (
 	TwitterMode     string
 	ThoughtStepType string
 	DatabaseType    string
+	LLMTask         string
 )
 
 type LLMConfig struct {
@@ -37,6 +42,65 @@ type LLMConfig struct {
 	APIKey   string `mapstructure:"api_key"`
 	BaseURL  string `mapstructure:"base_url"`
 	Model    string `mapstructure:"model"`
+	// QueryModel, AnalysisModel and ChatModel override Model for query
+	// generation, result analysis and message processing respectively
+	QueryModel    string `mapstructure:"query_model"`
+	AnalysisModel string `mapstructure:"analysis_model"`
+	ChatModel     string `mapstructure:"chat_model"`
+	// EmbeddingModel enables semantic memory search when set; DeepSeek has no
+	// embeddings API and always uses keyword search
+	EmbeddingModel string `mapstructure:"embedding_model"`
+	// SkipHealthCheck disables the startup provider check, e.g. for offline or mock setups
+	SkipHealthCheck bool `mapstructure:"skip_health_check"`
+	// RequestTimeout bounds each completion and embedding request, in seconds
+	RequestTimeout int `mapstructure:"request_timeout"`
+	// MaxChainLength is the number of reasoning steps per thought chain; 0 uses the default
+	MaxChainLength int `mapstructure:"max_chain_length"`
+	// EarlyExitConfidence skips to the concrete step of a thought chain once an
+	// intermediate step is this confident; 0 uses the default
+	EarlyExitConfidence float64 `mapstructure:"early_exit_confidence"`
+	// Script answers prompts offline when the provider is LLMProviderScripted
+	Script ScriptConfig `mapstructure:"script"`
+	// Retry is the policy for rate limited and failed provider requests, set
+	// from the shared retry config
+	Retry retry.Policy `mapstructure:"-"`
+}
+
+// LLMProviderScripted answers from LLMConfig.Script without calling an API,
+// for local demos and deterministic runs
+const LLMProviderScripted = "scripted"
+
+// ScriptConfig maps prompt substrings to canned responses
+type ScriptConfig struct {
+	// Responses maps a prompt substring, matched case-insensitively, to the
+	// response returned for prompts containing it; the longest match wins
+	Responses map[string]string `mapstructure:"responses"`
+	// DefaultResponse answers prompts that match no substring
+	DefaultResponse string `mapstructure:"default_response"`
+}
+
+// LLM task types with their own model override
+const (
+	LLMTaskQuery    LLMTask = "query"
+	LLMTaskAnalysis LLMTask = "analysis"
+	LLMTaskChat     LLMTask = "chat"
+)
+
+// ModelFor returns the model configured for the task, falling back to Model
+func (c *LLMConfig) ModelFor(task LLMTask) string {
+	var model string
+	switch task {
+	case LLMTaskQuery:
+		model = c.QueryModel
+	case LLMTaskAnalysis:
+		model = c.AnalysisModel
+	case LLMTaskChat:
+		model = c.ChatModel
+	}
+	if model == "" {
+		return c.Model
+	}
+	return model
 }
 
 type CarvConfig struct {
@@ -52,11 +116,32 @@ type TwitterConfig struct {
 	APIKeySecret  string      `mapstructure:"api_key_secret"`
 	AccessToken   string      `mapstructure:"access_token"`
 	TokenSecret   string      `mapstructure:"token_secret"`
-	MonitorWindow int         `mapstructure:"monitor_window"` // Duration in minutes, e.g. 20
+	MonitorWindow int         `mapstructure:"monitor_window"` // Minutes of mentions fetched per poll, defaults to 20
+	PollInterval  int         `mapstructure:"poll_interval"`  // Minutes between mention polls, defaults to 15
+	DedupWindow   int         `mapstructure:"dedup_window"`   // Minutes to suppress identical tweets, defaults to 60, negative disables
 }
 
+// Twitter monitoring bounds
+const (
+	DefaultTwitterMonitorWindow = 20 // minutes
+	DefaultTwitterPollInterval  = 15 // minutes
+	// maxMonitorWindowPolls caps the monitor window at this many poll intervals;
+	// a longer window fetches the same mentions on several polls
+	maxMonitorWindowPolls = 2
+	// maxTwitterMonitorMinutes rejects windows and intervals longer than a day
+	maxTwitterMonitorMinutes = 24 * 60
+)
+
 type DiscordConfig struct {
 	APIToken string `mapstructure:"api_token"`
+	// Presence is shown once the bot connects
+	Presence DiscordPresenceConfig `mapstructure:"presence"`
+}
+
+// DiscordPresenceConfig is the status and activity shown by the Discord bot
+type DiscordPresenceConfig struct {
+	Status   string `mapstructure:"status"`   // "online", "idle", "dnd" or "invisible", defaults to online
+	Activity string `mapstructure:"activity"` // Custom status text, e.g. "Analyzing Ethereum data"
 }
 
 type TelegramConfig struct {
@@ -65,6 +150,113 @@ type TelegramConfig struct {
 	Debug     bool   `mapstructure:"debug"`      // Enable debug mode
 }
 
+// ReplyGuardConfig caps replies per conversation to break loops with other bots
+type ReplyGuardConfig struct {
+	MaxReplies    int `mapstructure:"max_replies"`    // Replies allowed per conversation within the window, 0 disables the guard
+	WindowMinutes int `mapstructure:"window_minutes"` // Duration in minutes, e.g. 10
+}
+
+// RetryConfig is the retry policy shared by LLM, data API and login requests
+type RetryConfig struct {
+	Attempts    int `mapstructure:"attempts"`      // Attempts of a request including the first one
+	BaseDelayMs int `mapstructure:"base_delay_ms"` // Wait before the first retry, doubling after every retry
+	MaxDelayMs  int `mapstructure:"max_delay_ms"`  // Longest wait between retries
+}
+
+// Policy converts the config to a retry policy, unset fields use the retry defaults
+func (c RetryConfig) Policy() retry.Policy {
+	return retry.Policy{
+		Attempts:  c.Attempts,
+		BaseDelay: time.Duration(c.BaseDelayMs) * time.Millisecond,
+		MaxDelay:  time.Duration(c.MaxDelayMs) * time.Millisecond,
+	}.WithDefaults()
+}
+
+// RateLimitConfig paces the messages sent to a platform
+type RateLimitConfig struct {
+	PerMinute float64 `mapstructure:"per_minute"` // Messages per minute, 0 disables pacing
+	Burst     int     `mapstructure:"burst"`      // Messages sent back to back before pacing applies, defaults to 1
+}
+
+// Policies for an incoming social message buffer that is full
+const (
+	MessageBufferBlock      = "block"       // Wait for the agent to take a message
+	MessageBufferDropOldest = "drop_oldest" // Shed the oldest buffered message with a warning
+)
+
+// MessageBufferConfig buffers incoming social messages between the platform
+// monitors and the agent so a slow reply doesn't stall monitoring
+type MessageBufferConfig struct {
+	Size   int    `mapstructure:"size"`   // Messages held while the agent is busy, 0 keeps the channel unbuffered
+	Policy string `mapstructure:"policy"` // Policy when full: "block" or "drop_oldest"
+}
+
+// SeenConfig skips incoming messages the agent already handled, such as
+// mentions polled again after a restart
+type SeenConfig struct {
+	TTLHours int  `mapstructure:"ttl_hours"` // Hours a handled message ID is remembered, defaults to 24
+	Persist  bool `mapstructure:"persist"`   // Keep the IDs in the database so restarts skip them too
+}
+
+// CommandsConfig routes chat commands on every platform to plugin actions
+type CommandsConfig struct {
+	Prefix string               `mapstructure:"prefix"` // Command prefix, defaults to "/"
+	Routes []CommandRouteConfig `mapstructure:"routes"`
+}
+
+// CommandRouteConfig maps a command to an action, filling params from its arguments
+type CommandRouteConfig struct {
+	Command string                 `mapstructure:"command"` // Command name without the prefix, e.g. "analyze"
+	Action  string                 `mapstructure:"action"`  // Name of the plugin action to run
+	Args    []string               `mapstructure:"args"`    // Params filled by the arguments in order, the last takes the rest
+	Params  map[string]interface{} `mapstructure:"params"`  // Fixed params of the action
+}
+
+// ErrorAlertConfig decides when social platform errors are persistent and where to alert
+type ErrorAlertConfig struct {
+	Threshold     int    `mapstructure:"threshold"`      // Errors of a platform within the window that count as persistent
+	WindowMinutes int    `mapstructure:"window_minutes"` // Duration in minutes, e.g. 10
+	Platform      string `mapstructure:"platform"`       // Platform to post alerts to, empty only logs
+	ChannelID     string `mapstructure:"channel_id"`     // Channel of the alerts, required for discord
+}
+
+// ProactiveConfig drives the tasks the agent plans from the character goals
+type ProactiveConfig struct {
+	IntervalMinutes  int    `mapstructure:"interval_minutes"`   // Minutes between evaluations, 0 disables proactive tasks
+	MaxTasks         int    `mapstructure:"max_tasks"`          // Tasks run per evaluation, highest priority first
+	ProposePlatform  string `mapstructure:"propose_platform"`   // Platform receiving tasks that need approval, empty only logs
+	ProposeChannelID string `mapstructure:"propose_channel_id"` // Channel of the proposals, required for discord
+	ThinkAloud       bool   `mapstructure:"think_aloud"`        // Post the reasoning steps of each evaluation to the proposal channel
+	ThinkAloudSteps  int    `mapstructure:"think_aloud_steps"`  // Reasoning steps posted per evaluation, defaults to 3
+}
+
+// ModerationConfig checks LLM replies before they are posted
+type ModerationConfig struct {
+	Blocklist []string `mapstructure:"blocklist"` // Case insensitive regular expressions flagging a reply
+	Provider  bool     `mapstructure:"provider"`  // Also check replies with the moderation endpoint of the LLM provider
+	Block     bool     `mapstructure:"block"`     // Drop flagged replies instead of sending the moderated response
+}
+
+// AccessConfig restricts which users the agent interacts with
+type AccessConfig struct {
+	DefaultAction string   `mapstructure:"default_action"` // "allow" or "deny" for users on neither list
+	Allow         []string `mapstructure:"allow"`          // Entries as "platform:user" or a bare user for every platform
+	Deny          []string `mapstructure:"deny"`
+}
+
+type WebConfig struct {
+	Port int           `mapstructure:"port"`
+	Auth WebAuthConfig `mapstructure:"auth"`
+}
+
+// WebAuthConfig controls API key authentication and CORS for the web server
+type WebAuthConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Header         string   `mapstructure:"header"`          // Header carrying the API key, defaults to X-API-Key
+	APIKeys        []string `mapstructure:"api_keys"`        // Accepted API keys
+	AllowedOrigins []string `mapstructure:"allowed_origins"` // Allowed CORS origins, "*" allows any without credentials, empty allows none
+}
+
 type PromptTemplates struct {
 	System struct {
 		BaseTemplate string            `mapstructure:"base_template"`
@@ -76,14 +268,26 @@ type PromptTemplates struct {
 		Action   string `mapstructure:"action"`
 	} `mapstructure:"message"`
 
-	ThoughtSteps map[ThoughtStepType]struct {
-		Initial     string `mapstructure:"initial"`
-		Exploration string `mapstructure:"exploration"`
-		Analysis    string `mapstructure:"analysis"`
-		Reconsider  string `mapstructure:"reconsider"`
-		Refinement  string `mapstructure:"refinement"`
-		Concrete    string `mapstructure:"concrete"`
-	} `mapstructure:"thought_steps"`
+	ThoughtSteps map[ThoughtStepType]ThoughtStepTemplates `mapstructure:"thought_steps"`
+}
+
+// ThoughtStepTemplates are the prompts of each step of a thought chain; only
+// Initial is required, the later steps fall back to built-in instructions
+type ThoughtStepTemplates struct {
+	Initial     string `mapstructure:"initial"`
+	Exploration string `mapstructure:"exploration"`
+	Analysis    string `mapstructure:"analysis"`
+	Reconsider  string `mapstructure:"reconsider"`
+	Refinement  string `mapstructure:"refinement"`
+	Concrete    string `mapstructure:"concrete"`
+}
+
+// ScheduleConfig runs a plugin action with preset params on a cron schedule
+type ScheduleConfig struct {
+	Name     string                 `mapstructure:"name"`
+	Schedule string                 `mapstructure:"schedule"` // Cron expression, e.g. "0 9 * * *" or "@daily"
+	Action   string                 `mapstructure:"action"`   // Name of the plugin action to run
+	Params   map[string]interface{} `mapstructure:"params"`
 }
 
 type PluginConfig struct {
@@ -103,7 +307,8 @@ type Character struct {
 
 type Config struct {
 	Settings struct {
-		ShutdownTimeout int `mapstructure:"shutdown_timeout"`
+		ShutdownTimeout int  `mapstructure:"shutdown_timeout"`
+		WatchTemplates  bool `mapstructure:"watch_templates"` // Reload prompt templates when their files change
 	} `mapstructure:"settings"`
 
 	Character `mapstructure:"character"`
@@ -123,6 +328,26 @@ type Config struct {
 		TwitterConfig  `mapstructure:"twitter"`
 		DiscordConfig  `mapstructure:"discord"`
 		TelegramConfig `mapstructure:"telegram"`
+		ReplyGuard     ReplyGuardConfig `mapstructure:"reply_guard"`
+		Access         AccessConfig     `mapstructure:"access"`
+		// RateLimits paces outbound messages by platform: twitter, discord or telegram
+		RateLimits map[string]RateLimitConfig `mapstructure:"rate_limits"`
+		// Commands route chat commands to plugin actions on every platform
+		Commands CommandsConfig `mapstructure:"commands"`
+		// ErrorAlerts reacts to platforms that keep failing
+		ErrorAlerts ErrorAlertConfig `mapstructure:"error_alerts"`
+		// Moderation checks LLM replies and plugin posts before they are sent
+		Moderation ModerationConfig `mapstructure:"moderation"`
+		// MessageBuffer queues incoming messages while the agent is busy
+		MessageBuffer MessageBufferConfig `mapstructure:"message_buffer"`
+		// Seen skips messages that were already handled
+		Seen SeenConfig `mapstructure:"seen"`
+		// SelfIDs are user IDs by platform whose messages are ignored as the
+		// agent's own, besides the accounts the agent is logged in with
+		SelfIDs map[string][]string `mapstructure:"self_ids"`
+		// Languages are reply languages by "platform:user" or bare user,
+		// overriding the language of the character
+		Languages map[string]string `mapstructure:"languages"`
 	} `mapstructure:"social"`
 
 	Token struct {
@@ -131,14 +356,21 @@ type Config struct {
 		ContractAddr string `mapstructure:"contract_addr"`
 	} `mapstructure:"token"`
 
-	Web struct {
-		Port int `mapstructure:"port"`
-	} `mapstructure:"web"`
+	Web WebConfig `mapstructure:"web"`
 
 	UserTemplates    *PromptTemplates `mapstructure:"user_templates"`
 	DefaultTemplates *PromptTemplates `mapstructure:"default_templates"`
 
 	Plugins map[string]PluginConfig `mapstructure:"plugins"`
+
+	Schedules []ScheduleConfig `mapstructure:"schedules"`
+
+	Proactive ProactiveConfig `mapstructure:"proactive"`
+
+	Retry RetryConfig `mapstructure:"retry"`
+
+	// Vault resolves secret settings given as <key>_vault references
+	Vault VaultConfig `mapstructure:"vault"`
 }
 
 // LoadConfig loads and validates the application configuration
@@ -153,6 +385,10 @@ func LoadConfig(confPath string) (*Config, error) {
 		return nil, fmt.Errorf("error load env config: %w", err)
 	}
 
+	if err := loadSecrets(); err != nil {
+		return nil, fmt.Errorf("error resolving secrets: %w", err)
+	}
+
 	fillDefaultOptions()
 
 	var conf Config
@@ -160,6 +396,10 @@ func LoadConfig(confPath string) (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	if err := interpolatePluginOptions(conf.Plugins); err != nil {
+		return nil, fmt.Errorf("error interpolating plugin options: %w", err)
+	}
+
 	// Validate config
 	if err := validateConfig(&conf, confPath); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -208,6 +448,7 @@ func loadEnvConfig(confPath string) error {
 		"TWITTER_ACCESS_TOKEN":   "social.twitter.access_token",
 		"TWITTER_TOKEN_SECRET":   "social.twitter.token_secret",
 		"TWITTER_MONITOR_WINDOW": "social.twitter.monitor_window",
+		"TWITTER_POLL_INTERVAL":  "social.twitter.poll_interval",
 		"DISCORD_API_TOKEN":      "social.discord.api_token",
 		"TELEGRAM_BOT_TOKEN":     "social.telegram.bot_token",
 		"CARV_DATA_BASE_URL":     "data.carv.base_url",
@@ -215,9 +456,12 @@ func loadEnvConfig(confPath string) error {
 		"WALLET_PRIVATE_KEY":     "wallet.private_key",
 	}
 
-	// override config values with environment variables
+	// override config values with environment variables, keeping YAML values
+	// when a variable is unset or empty
 	for env, conf := range envMappings {
-		viper.Set(conf, viper.Get(env))
+		if value := viper.GetString(env); value != "" {
+			viper.Set(conf, value)
+		}
 	}
 
 	return nil
@@ -228,7 +472,22 @@ func setDefaultConfig() {
 	viper.SetDefault("database.path", "./data/data.db")
 	viper.SetDefault("llm_config.provider", "openai")
 	viper.SetDefault("llm_config.base_url", "https://api.openai.com/v1")
-	viper.SetDefault("llm_config.model", "gpt-4o")                // Default model for OpenAI
+	viper.SetDefault("llm_config.model", "gpt-4o")     // Default model for OpenAI
+	viper.SetDefault("llm_config.request_timeout", 60) // LLM request timeout in seconds
+	viper.SetDefault("social.reply_guard.max_replies", 5)
+	viper.SetDefault("social.reply_guard.window_minutes", 10)
+	viper.SetDefault("social.error_alerts.threshold", 5)
+	viper.SetDefault("social.error_alerts.window_minutes", 10)
+	viper.SetDefault("social.rate_limits.twitter.per_minute", 5)
+	viper.SetDefault("social.rate_limits.discord.per_minute", 50)
+	viper.SetDefault("social.rate_limits.telegram.per_minute", 20)
+	viper.SetDefault("social.message_buffer.size", 100)
+	viper.SetDefault("social.message_buffer.policy", MessageBufferBlock)
+	viper.SetDefault("proactive.max_tasks", 1)
+	viper.SetDefault("retry.attempts", retry.DefaultAttempts)
+	viper.SetDefault("retry.base_delay_ms", retry.DefaultBaseDelay.Milliseconds())
+	viper.SetDefault("retry.max_delay_ms", retry.DefaultMaxDelay.Milliseconds())
+	viper.SetDefault("web.auth.header", "X-API-Key")
 	viper.SetDefault("shutdown_timeout", 30)                      // shutdown timeout in seconds
 	viper.SetDefault("plugin.plugins", map[string]PluginConfig{}) // Default empty plugins map
 }
@@ -249,6 +508,10 @@ func fillDefaultOptions() {
 		if !viper.IsSet("llm_config.model") {
 			viper.Set("llm_config.model", "gpt-3.5-turbo")
 		}
+	case LLMProviderScripted:
+		if !viper.IsSet("llm_config.model") {
+			viper.Set("llm_config.model", LLMProviderScripted)
+		}
 	}
 
 	// Special handling for Telegram channel ID
@@ -257,6 +520,86 @@ func fillDefaultOptions() {
 	}
 }
 
+// validateDatabaseConfig checks the database type is supported and the path fits it
+func validateDatabaseConfig(dbType DatabaseType, path string) error {
+	if path == "" {
+		return fmt.Errorf("%w: missing path", ErrInvalidDBConfig)
+	}
+
+	switch dbType {
+	case DatabasePostgres:
+		// Accept both URL ("postgres://...") and key/value ("host=... dbname=...") DSNs
+		if !strings.HasPrefix(path, "postgres://") &&
+			!strings.HasPrefix(path, "postgresql://") &&
+			!strings.Contains(path, "=") {
+			return fmt.Errorf("%w: postgres path must be a connection string, got %q", ErrInvalidDBConfig, path)
+		}
+	case DatabaseSqlite:
+		if strings.Contains(path, "://") && !strings.HasPrefix(path, "file:") {
+			return fmt.Errorf("%w: sqlite path must be a file path, got %q", ErrInvalidDBConfig, path)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %q, valid types are %s, %s",
+			ErrInvalidDBConfig, dbType, DatabasePostgres, DatabaseSqlite)
+	}
+	return nil
+}
+
+// validateTwitterMonitoring fills in the default poll interval and monitor
+// window, rejects values longer than a day and shrinks a monitor window that
+// spans too many polls
+func validateTwitterMonitoring(twitter *TwitterConfig) error {
+	if twitter.PollInterval <= 0 {
+		twitter.PollInterval = DefaultTwitterPollInterval
+	}
+	if twitter.MonitorWindow <= 0 {
+		twitter.MonitorWindow = DefaultTwitterMonitorWindow
+	}
+	if twitter.PollInterval > maxTwitterMonitorMinutes {
+		return fmt.Errorf("%w: twitter poll_interval must be at most %d minutes, got %d",
+			ErrInvalidSocialConfig, maxTwitterMonitorMinutes, twitter.PollInterval)
+	}
+	if twitter.MonitorWindow > maxTwitterMonitorMinutes {
+		return fmt.Errorf("%w: twitter monitor_window must be at most %d minutes, got %d",
+			ErrInvalidSocialConfig, maxTwitterMonitorMinutes, twitter.MonitorWindow)
+	}
+
+	if limit := maxMonitorWindowPolls * twitter.PollInterval; twitter.MonitorWindow > limit {
+		logger.GetLogger().Warnw("Twitter monitor window spans several polls and would process mentions more than once, shrinking it",
+			"monitor_window", twitter.MonitorWindow,
+			"poll_interval", twitter.PollInterval,
+			"adjusted_window", limit,
+		)
+		twitter.MonitorWindow = limit
+	} else if twitter.MonitorWindow < twitter.PollInterval {
+		logger.GetLogger().Warnw("Twitter monitor window is shorter than the poll interval, mentions between polls will be missed",
+			"monitor_window", twitter.MonitorWindow,
+			"poll_interval", twitter.PollInterval,
+		)
+	}
+	return nil
+}
+
+// validateMessageBufferConfig checks the size and full buffer policy of the
+// incoming message buffer
+func validateMessageBufferConfig(buffer MessageBufferConfig) error {
+	if buffer.Size < 0 {
+		return fmt.Errorf("%w: message_buffer size must not be negative, got %d", ErrInvalidSocialConfig, buffer.Size)
+	}
+	switch buffer.Policy {
+	case "", MessageBufferBlock:
+		return nil
+	case MessageBufferDropOldest:
+		if buffer.Size == 0 {
+			return fmt.Errorf("%w: message_buffer policy %q needs a size above 0", ErrInvalidSocialConfig, buffer.Policy)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: message_buffer policy must be %q or %q, got %q",
+			ErrInvalidSocialConfig, MessageBufferBlock, MessageBufferDropOldest, buffer.Policy)
+	}
+}
+
 // loadDefaultTemplates loads templates from default_templates.yaml
 func loadDefaultTemplates(configPath string) (*PromptTemplates, error) {
 	defaultViper := viper.New()
@@ -290,7 +633,7 @@ func validateConfig(conf *Config, confPath string) error {
 		logger.GetLogger().Infoln("Using user-defined templates")
 	}
 
-	if conf.LLMConfig.APIKey == "" {
+	if conf.LLMConfig.APIKey == "" && conf.LLMConfig.Provider != LLMProviderScripted {
 		return fmt.Errorf("%w: missing API key", ErrInvalidLLMConfig)
 	}
 	if conf.LLMConfig.Provider == "" {
@@ -299,11 +642,20 @@ func validateConfig(conf *Config, confPath string) error {
 	if conf.LLMConfig.Model == "" {
 		return fmt.Errorf("%w: missing model", ErrInvalidLLMConfig)
 	}
-	if conf.Database.Path == "" {
-		return ErrInvalidDBConfig
+	if err := validateDatabaseConfig(conf.Database.Type, conf.Database.Path); err != nil {
+		return err
+	}
+	if err := validateTwitterMonitoring(&conf.Social.TwitterConfig); err != nil {
+		return err
+	}
+	if err := validateMessageBufferConfig(conf.Social.MessageBuffer); err != nil {
+		return err
+	}
+	if err := migratePromptTemplates(conf.UserTemplates); err != nil {
+		return err
 	}
-	if conf.DefaultTemplates == nil && conf.UserTemplates == nil {
-		return fmt.Errorf("missing prompt templates")
+	if err := ValidatePromptTemplates(conf.UserTemplates); err != nil {
+		return err
 	}
 
 	return nil
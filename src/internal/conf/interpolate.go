@@ -0,0 +1,95 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolatePluginOptions replaces ${ENV_VAR} references in the options of
+// enabled plugins with values from the environment or the .env file
+func interpolatePluginOptions(plugins map[string]PluginConfig) error {
+	for name, plugin := range plugins {
+		if !plugin.Enabled {
+			continue
+		}
+
+		options, err := interpolateValue(plugin.Options)
+		if err != nil {
+			return fmt.Errorf("plugin %s: %w", name, err)
+		}
+		plugin.Options = options.(map[string]interface{})
+		plugins[name] = plugin
+	}
+	return nil
+}
+
+// interpolateValue walks nested maps and slices, expanding env references in strings
+func interpolateValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return expandEnvRefs(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded, err := interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			expanded, err := interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", key, err)
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := interpolateValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+func expandEnvRefs(s string) (string, error) {
+	var missing []string
+	expanded := envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envRefPattern.FindStringSubmatch(ref)[1]
+		if value, ok := lookupEnv(name); ok {
+			return value
+		}
+		missing = append(missing, name)
+		return ref
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unresolved environment variables: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// lookupEnv checks the process environment first, then values loaded from .env
+func lookupEnv(name string) (string, bool) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, true
+	}
+	if viper.IsSet(name) {
+		return viper.GetString(name), true
+	}
+	return "", false
+}
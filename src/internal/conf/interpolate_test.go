@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolatePluginOptions(t *testing.T) {
+	t.Setenv("CARV_DATA_API_KEY", "secret")
+	t.Setenv("CARV_DATA_HOST", "api.example")
+
+	plugins := map[string]PluginConfig{
+		"d.a.t.a": {
+			Enabled: true,
+			Options: map[string]interface{}{
+				"auth_token": "${CARV_DATA_API_KEY}",
+				"api_url":    "https://${CARV_DATA_HOST}/v1",
+				"chain":      "ethereum-mainnet",
+				"llm":        map[interface{}]interface{}{"api_key": "${CARV_DATA_API_KEY}"},
+				"headers":    []interface{}{"Bearer ${CARV_DATA_API_KEY}"},
+				"retries":    3,
+			},
+		},
+		"disabled": {
+			Options: map[string]interface{}{"token": "${NOT_SET_ANYWHERE}"},
+		},
+	}
+
+	if err := interpolatePluginOptions(plugins); err != nil {
+		t.Fatalf("interpolatePluginOptions() error = %v", err)
+	}
+
+	options := plugins["d.a.t.a"].Options
+	if got := options["auth_token"]; got != "secret" {
+		t.Errorf("auth_token = %v, want secret", got)
+	}
+	if got := options["api_url"]; got != "https://api.example/v1" {
+		t.Errorf("api_url = %v, want https://api.example/v1", got)
+	}
+	if got := options["llm"].(map[interface{}]interface{})["api_key"]; got != "secret" {
+		t.Errorf("llm.api_key = %v, want secret", got)
+	}
+	if got := options["headers"].([]interface{})[0]; got != "Bearer secret" {
+		t.Errorf("headers[0] = %v, want Bearer secret", got)
+	}
+	if got := options["retries"]; got != 3 {
+		t.Errorf("retries = %v, want 3", got)
+	}
+	if got := plugins["disabled"].Options["token"]; got != "${NOT_SET_ANYWHERE}" {
+		t.Errorf("disabled plugin token = %v, want it untouched", got)
+	}
+}
+
+func TestInterpolatePluginOptionsUnresolved(t *testing.T) {
+	plugins := map[string]PluginConfig{
+		"d.a.t.a": {
+			Enabled: true,
+			Options: map[string]interface{}{
+				"llm": map[string]interface{}{"api_key": "${CARV_TEST_UNSET_KEY}"},
+			},
+		},
+	}
+
+	err := interpolatePluginOptions(plugins)
+	if err == nil {
+		t.Fatal("interpolatePluginOptions() error = nil, want an error")
+	}
+	for _, want := range []string{"d.a.t.a", "llm", "api_key", "CARV_TEST_UNSET_KEY"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
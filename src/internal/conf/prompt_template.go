@@ -0,0 +1,125 @@
+package conf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// SystemPromptData holds the values available to system.base_template
+type SystemPromptData struct {
+	CharacterName       string
+	System              string
+	Goals               string
+	Bio                 string
+	Lore                string
+	Preferences         string
+	ProviderStates      string
+	Actions             string
+	Constraints         string
+	PriorityAccountInfo string
+	TokenBalanceInfo    string
+}
+
+// MessagePromptData holds the values available to message.analysis
+type MessagePromptData struct {
+	Platform        string
+	UserID          string
+	Message         string
+	History         string
+	Tone            string
+	MessageExamples string
+	Actions         string
+}
+
+// ActionPromptData holds the values available to message.action
+type ActionPromptData struct {
+	Platform          string
+	Message           string
+	History           string
+	ActionName        string
+	ActionDescription string
+	ActionParameters  string
+}
+
+// RenderPromptTemplate renders a named-placeholder template such as "Hello {{.CharacterName}}"
+func RenderPromptTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt template %s: %w", name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+// MigratePositionalTemplate converts a template using positional fmt verbs into
+// named placeholders, mapping the verbs in order onto the fields of data.
+// Templates without verbs are returned unchanged.
+func MigratePositionalTemplate(text string, data interface{}) (string, error) {
+	verbs := countFmtVerbs(text)
+	if verbs == 0 {
+		return strings.ReplaceAll(text, "%%", "%"), nil
+	}
+
+	fields := templateFields(data)
+	if verbs != len(fields) {
+		return "", fmt.Errorf("expected %d placeholders, found %d", len(fields), verbs)
+	}
+
+	var i int
+	return fmtVerbPattern.ReplaceAllStringFunc(text, func(verb string) string {
+		if verb == "%%" {
+			return "%"
+		}
+		field := fields[i]
+		i++
+		return "{{." + field + "}}"
+	}), nil
+}
+
+// migratePromptTemplates rewrites positional templates to named placeholders
+func migratePromptTemplates(templates *PromptTemplates) error {
+	if templates == nil {
+		return nil
+	}
+
+	targets := []struct {
+		name string
+		text *string
+		data interface{}
+	}{
+		{"system.base_template", &templates.System.BaseTemplate, SystemPromptData{}},
+		{"message.analysis", &templates.Message.Analysis, MessagePromptData{}},
+		{"message.action", &templates.Message.Action, ActionPromptData{}},
+	}
+
+	for _, target := range targets {
+		if countFmtVerbs(*target.text) == 0 {
+			continue
+		}
+		migrated, err := MigratePositionalTemplate(*target.text, target.data)
+		if err != nil {
+			return fmt.Errorf("invalid prompt template %s: %w", target.name, err)
+		}
+		logger.GetLogger().Warnf("Prompt template %s uses positional placeholders, migrated to named placeholders", target.name)
+		*target.text = migrated
+	}
+	return nil
+}
+
+// templateFields returns the field names of a template data struct in declaration order
+func templateFields(data interface{}) []string {
+	t := reflect.TypeOf(data)
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, t.Field(i).Name)
+	}
+	return fields
+}
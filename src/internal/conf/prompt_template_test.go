@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPromptTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "named placeholders",
+			template: "{{.UserID}} on {{.Platform}} said: {{.Message}}",
+			want:     "alice on twitter said: gm",
+		},
+		{name: "repeated placeholder", template: "{{.Message}} {{.Message}}", want: "gm gm"},
+		{name: "unknown field", template: "{{.Username}}", wantErr: true},
+		{name: "broken template", template: "{{.Message", wantErr: true},
+	}
+
+	data := MessagePromptData{Platform: "twitter", UserID: "alice", Message: "gm"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderPromptTemplate("message.analysis", tt.template, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderPromptTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "message.analysis") {
+				t.Errorf("error %q does not name the template", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderPromptTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratePositionalTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "positional verbs",
+			template: "Fill %s for %s (%s) about %s, %s: %s at 100%%",
+			want:     "Fill {{.Platform}} for {{.Message}} ({{.History}}) about {{.ActionName}}, {{.ActionDescription}}: {{.ActionParameters}} at 100%",
+		},
+		{name: "no verbs", template: "Just {{.ActionName}}", want: "Just {{.ActionName}}"},
+		{name: "missing a placeholder", template: "%s %s %s %s %s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MigratePositionalTemplate(tt.template, ActionPromptData{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MigratePositionalTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MigratePositionalTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratePromptTemplates(t *testing.T) {
+	templates := &PromptTemplates{}
+	templates.System.BaseTemplate = "You are {{.CharacterName}}"
+	templates.Message.Analysis = "%s %s %s %s %s %s %s"
+	templates.Message.Action = "%s %s %s %s %s"
+
+	err := migratePromptTemplates(templates)
+	if err == nil || !strings.Contains(err.Error(), "message.action") {
+		t.Fatalf("migratePromptTemplates() error = %v, want one naming message.action", err)
+	}
+	if !strings.HasPrefix(templates.Message.Analysis, "{{.Platform}} {{.UserID}}") {
+		t.Errorf("analysis = %q, want it migrated", templates.Message.Analysis)
+	}
+}
@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrImmutableConfig is returned when a reloaded config changes a setting that
+// only takes effect on restart
+var ErrImmutableConfig = errors.New("config change requires a restart")
+
+// CheckReload rejects a reloaded config that changes settings the running
+// agent can't swap, naming every such setting
+func CheckReload(current, next *Config) error {
+	var changed []string
+	if current.Database.Type != next.Database.Type {
+		changed = append(changed, "database.type")
+	}
+	if current.Database.Path != next.Database.Path {
+		changed = append(changed, "database.path")
+	}
+	if current.Web.Port != next.Web.Port {
+		changed = append(changed, "web.port")
+	}
+	if current.LLMConfig.Provider != next.LLMConfig.Provider {
+		changed = append(changed, "llm_config.provider")
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s", ErrImmutableConfig, strings.Join(changed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCheckReload(t *testing.T) {
+	current := &Config{Web: WebConfig{Port: 8080}}
+	current.Database.Type = DatabaseSqlite
+	current.Database.Path = "agent.db"
+	tests := []struct {
+		name        string
+		modify      func(next *Config)
+		wantChanged []string
+	}{
+		{name: "reloadable changes", modify: func(next *Config) {
+			next.Social.Access.DefaultAction = "deny"
+			next.Social.RateLimits = map[string]RateLimitConfig{"twitter": {PerMinute: 1}}
+		}},
+		{name: "database type", modify: func(next *Config) { next.Database.Type = DatabasePostgres }, wantChanged: []string{"database.type"}},
+		{name: "port and path", modify: func(next *Config) {
+			next.Web.Port = 9090
+			next.Database.Path = "other.db"
+		}, wantChanged: []string{"database.path", "web.port"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := *current
+			tt.modify(&next)
+
+			err := CheckReload(current, &next)
+			if len(tt.wantChanged) == 0 {
+				if err != nil {
+					t.Errorf("CheckReload() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrImmutableConfig) {
+				t.Fatalf("CheckReload() error = %v, want ErrImmutableConfig", err)
+			}
+			for _, field := range tt.wantChanged {
+				if !strings.Contains(err.Error(), field) {
+					t.Errorf("CheckReload() error = %v, want it to name %s", err, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ErrSecretNotFound is returned when a configured secret file or vault
+// reference doesn't resolve to a value
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secretKey is a config setting that may hold a secret, along with the
+// environment variable it falls back to
+type secretKey struct {
+	key string
+	env string
+}
+
+// secretKeys are resolved from a file or vault when not set explicitly
+var secretKeys = []secretKey{
+	{key: "llm_config.api_key", env: "LLM_API_KEY"},
+	{key: "social.twitter.password", env: "TWITTER_PASSWORD"},
+	{key: "social.twitter.api_key", env: "TWITTER_API_KEY"},
+	{key: "social.twitter.api_key_secret", env: "TWITTER_API_KEY_SECRET"},
+	{key: "social.twitter.access_token", env: "TWITTER_ACCESS_TOKEN"},
+	{key: "social.twitter.token_secret", env: "TWITTER_TOKEN_SECRET"},
+	{key: "social.discord.api_token", env: "DISCORD_API_TOKEN"},
+	{key: "social.telegram.bot_token", env: "TELEGRAM_BOT_TOKEN"},
+}
+
+// vaultTimeout bounds each vault read
+const vaultTimeout = 10 * time.Second
+
+// VaultConfig locates a HashiCorp Vault server for secret references
+type VaultConfig struct {
+	Address   string `mapstructure:"address"`    // Defaults to VAULT_ADDR
+	Token     string `mapstructure:"token"`      // Defaults to VAULT_TOKEN
+	TokenFile string `mapstructure:"token_file"` // Read the token from a file instead
+}
+
+// secretSource reads secrets from vault by "path#field" reference
+type secretSource interface {
+	Read(ctx context.Context, ref string) (string, error)
+}
+
+// loadSecrets resolves the secret settings left empty by the config and .env
+// files, using the configured vault for vault references
+func loadSecrets() error {
+	var vaultConf VaultConfig
+	if err := viper.UnmarshalKey("vault", &vaultConf); err != nil {
+		return fmt.Errorf("invalid vault config: %w", err)
+	}
+	vault, err := newVaultClient(vaultConf)
+	if err != nil {
+		return err
+	}
+
+	var source secretSource
+	if vault != nil {
+		source = vault
+	}
+	return resolveSecrets(source)
+}
+
+// resolveSecrets fills every empty secret setting. The explicit value from the
+// config or .env file wins, then a file named by <key>_file or the <ENV>_FILE
+// environment variable, then a vault reference in <key>_vault, and finally
+// the <ENV> environment variable.
+func resolveSecrets(vault secretSource) error {
+	for _, secret := range secretKeys {
+		if viper.GetString(secret.key) != "" {
+			continue
+		}
+		value, err := resolveSecret(secret, vault)
+		if err != nil {
+			return fmt.Errorf("%s: %w", secret.key, err)
+		}
+		if value != "" {
+			viper.Set(secret.key, value)
+		}
+	}
+	return nil
+}
+
+func resolveSecret(secret secretKey, vault secretSource) (string, error) {
+	path := viper.GetString(secret.key + "_file")
+	if path == "" {
+		path = os.Getenv(secret.env + "_FILE")
+	}
+	if path != "" {
+		return readSecretFile(path)
+	}
+
+	if ref := viper.GetString(secret.key + "_vault"); ref != "" {
+		if vault == nil {
+			return "", fmt.Errorf("vault reference %q set but no vault address configured", ref)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+		defer cancel()
+		return vault.Read(ctx, ref)
+	}
+
+	return os.Getenv(secret.env), nil
+}
+
+// readSecretFile reads a secret, dropping the trailing newline editors add
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrSecretNotFound, path)
+	}
+	return value, nil
+}
+
+// vaultClient reads secrets from the KV engine of a Vault server
+type vaultClient struct {
+	address string
+	token   string
+	client  *http.Client
+}
+
+// newVaultClient returns nil when no vault address is configured
+func newVaultClient(cfg VaultConfig) (*vaultClient, error) {
+	address := cfg.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return nil, nil
+	}
+
+	token := cfg.Token
+	if token == "" && cfg.TokenFile != "" {
+		var err error
+		if token, err = readSecretFile(cfg.TokenFile); err != nil {
+			return nil, fmt.Errorf("vault token: %w", err)
+		}
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	return &vaultClient{
+		address: strings.TrimRight(address, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: vaultTimeout},
+	}, nil
+}
+
+// Read resolves a "path#field" reference such as "secret/data/agent#llm_api_key",
+// supporting both version 1 and version 2 KV engines
+func (v *vaultClient) Read(ctx context.Context, ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("vault reference must be \"path#field\", got %q", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read vault secret %s: status %d", path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode vault secret %s: %w", path, err)
+	}
+
+	data := body.Data
+	// KV version 2 nests the secret under data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[field].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("%w: %s in vault secret %s", ErrSecretNotFound, field, path)
+	}
+	return value, nil
+}
@@ -0,0 +1,177 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// staticVault answers every reference from its secrets
+type staticVault map[string]string
+
+func (v staticVault) Read(_ context.Context, ref string) (string, error) {
+	value, ok := v[ref]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func writeSecret(t *testing.T, value string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+	return path
+}
+
+func TestResolveSecretsPrecedence(t *testing.T) {
+	vault := staticVault{"secret/data/agent#llm_api_key": "vault-key"}
+	tests := []struct {
+		name     string
+		settings map[string]string
+		env      map[string]string
+		want     string
+	}{
+		{
+			name:     "explicit value",
+			settings: map[string]string{"llm_config.api_key": "explicit-key", "llm_config.api_key_file": "file", "llm_config.api_key_vault": "secret/data/agent#llm_api_key"},
+			env:      map[string]string{"LLM_API_KEY": "env-key"},
+			want:     "explicit-key",
+		},
+		{
+			name:     "file over vault",
+			settings: map[string]string{"llm_config.api_key_file": "file", "llm_config.api_key_vault": "secret/data/agent#llm_api_key"},
+			env:      map[string]string{"LLM_API_KEY": "env-key"},
+			want:     "file-key",
+		},
+		{
+			name: "file from environment",
+			env:  map[string]string{"LLM_API_KEY_FILE": "file", "LLM_API_KEY": "env-key"},
+			want: "file-key",
+		},
+		{
+			name:     "vault over environment",
+			settings: map[string]string{"llm_config.api_key_vault": "secret/data/agent#llm_api_key"},
+			env:      map[string]string{"LLM_API_KEY": "env-key"},
+			want:     "vault-key",
+		},
+		{
+			name: "environment",
+			env:  map[string]string{"LLM_API_KEY": "env-key"},
+			want: "env-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			// The file keeps a trailing newline, as editors write it
+			path := writeSecret(t, "file-key\n")
+			for key, value := range tt.settings {
+				if value == "file" {
+					value = path
+				}
+				viper.Set(key, value)
+			}
+			for key, value := range tt.env {
+				if value == "file" {
+					value = path
+				}
+				t.Setenv(key, value)
+			}
+
+			if err := resolveSecrets(vault); err != nil {
+				t.Fatalf("resolveSecrets() error = %v", err)
+			}
+			if got := viper.GetString("llm_config.api_key"); got != tt.want {
+				t.Errorf("llm_config.api_key = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setting string
+		value   func(t *testing.T) string
+		vault   secretSource
+		wantErr error
+	}{
+		{name: "empty file", setting: "llm_config.api_key_file", value: func(t *testing.T) string { return writeSecret(t, "\n") }, wantErr: ErrSecretNotFound},
+		{name: "missing file", setting: "llm_config.api_key_file", value: func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing") }, wantErr: os.ErrNotExist},
+		{name: "vault reference without vault", setting: "llm_config.api_key_vault", value: func(*testing.T) string { return "secret/agent#key" }},
+		{name: "unknown vault field", setting: "llm_config.api_key_vault", value: func(*testing.T) string { return "secret/agent#key" }, vault: staticVault{}, wantErr: ErrSecretNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set(tt.setting, tt.value(t))
+
+			err := resolveSecrets(tt.vault)
+			if err == nil {
+				t.Fatal("resolveSecrets() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("resolveSecrets() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVaultClientRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/agent":
+			w.Write([]byte(`{"data":{"data":{"llm_api_key":"kv2-key"}}}`))
+		case "/v1/kv/agent":
+			w.Write([]byte(`{"data":{"llm_api_key":"kv1-key"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vault, err := newVaultClient(VaultConfig{Address: server.URL + "/", Token: "vault-token"})
+	if err != nil {
+		t.Fatalf("newVaultClient() error = %v", err)
+	}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "secret/data/agent#llm_api_key", want: "kv2-key"},
+		{ref: "kv/agent#llm_api_key", want: "kv1-key"},
+		{ref: "kv/agent#other", wantErr: true},
+		{ref: "kv/missing#llm_api_key", wantErr: true},
+		{ref: "kv/agent", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := vault.Read(context.Background(), tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
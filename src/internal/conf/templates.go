@@ -0,0 +1,231 @@
+package conf
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+const templateReloadDebounce = 200 * time.Millisecond
+
+var templateFiles = map[string]bool{
+	"config.yaml":            true,
+	"default_templates.yaml": true,
+}
+
+// LoadPromptTemplates reads the user templates from config.yaml, falling back
+// to default_templates.yaml, and validates them
+func LoadPromptTemplates(confPath string) (*PromptTemplates, error) {
+	v := viper.New()
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(confPath)
+
+	var templates *PromptTemplates
+	if err := v.ReadInConfig(); err == nil && v.IsSet("user_templates") {
+		templates = &PromptTemplates{}
+		if err := v.UnmarshalKey("user_templates", templates); err != nil {
+			return nil, fmt.Errorf("error unmarshaling user templates: %w", err)
+		}
+	} else {
+		defaultTemplates, err := loadDefaultTemplates(confPath)
+		if err != nil {
+			return nil, err
+		}
+		templates = defaultTemplates
+	}
+
+	if err := migratePromptTemplates(templates); err != nil {
+		return nil, err
+	}
+	if err := ValidatePromptTemplates(templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// LoadCharacterConfig reads only the character section of config.yaml, so
+// offline tools can find the character without a fully valid config
+func LoadCharacterConfig(confPath string) (*Character, error) {
+	v := viper.New()
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(confPath)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var character Character
+	if err := v.UnmarshalKey("character", &character); err != nil {
+		return nil, fmt.Errorf("error unmarshaling character config: %w", err)
+	}
+	if character.Path == "" {
+		return nil, fmt.Errorf("missing character path")
+	}
+	return &character, nil
+}
+
+// Number of fmt verbs each thought step template must contain
+const thoughtStepTemplateVerbs = 1
+
+var (
+	fmtVerbPattern = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+	infoFormatVerbs = map[string]int{
+		"priority_account":      0,
+		"token_balance_exists":  1,
+		"token_balance_missing": 0,
+	}
+)
+
+// ValidatePromptTemplates checks the templates needed to build prompts are
+// present, that named templates only reference known fields and that positional
+// templates have the number of placeholders their prompt builder fills
+func ValidatePromptTemplates(templates *PromptTemplates) error {
+	if templates == nil {
+		return fmt.Errorf("missing prompt templates")
+	}
+	if templates.System.BaseTemplate == "" {
+		return fmt.Errorf("missing prompt template: system.base_template")
+	}
+	if templates.Message.Analysis == "" {
+		return fmt.Errorf("missing prompt template: message.analysis")
+	}
+	if templates.Message.Action == "" {
+		return fmt.Errorf("missing prompt template: message.action")
+	}
+	// Later thought steps fall back to built-in instructions, but the initial
+	// step has nothing to build on
+	for _, stepType := range []ThoughtStepType{ThoughtStepTypeTask, ThoughtStepTypeAction} {
+		if templates.ThoughtSteps[stepType].Initial == "" {
+			return fmt.Errorf("missing prompt template: thought_steps.%s.initial", stepType)
+		}
+	}
+
+	named := []struct {
+		name     string
+		template string
+		data     interface{}
+	}{
+		{"system.base_template", templates.System.BaseTemplate, SystemPromptData{}},
+		{"message.analysis", templates.Message.Analysis, MessagePromptData{}},
+		{"message.action", templates.Message.Action, ActionPromptData{}},
+	}
+	for _, check := range named {
+		if _, err := RenderPromptTemplate(check.name, check.template, check.data); err != nil {
+			return err
+		}
+	}
+
+	var checks []struct {
+		name     string
+		template string
+		verbs    int
+	}
+	for key, verbs := range infoFormatVerbs {
+		if template, ok := templates.System.InfoFormat[key]; ok {
+			checks = append(checks, struct {
+				name     string
+				template string
+				verbs    int
+			}{"system.info_format." + key, template, verbs})
+		}
+	}
+	for stepType, steps := range templates.ThoughtSteps {
+		for purpose, template := range map[string]string{
+			"initial":     steps.Initial,
+			"exploration": steps.Exploration,
+			"analysis":    steps.Analysis,
+			"reconsider":  steps.Reconsider,
+			"refinement":  steps.Refinement,
+			"concrete":    steps.Concrete,
+		} {
+			if template == "" {
+				continue
+			}
+			checks = append(checks, struct {
+				name     string
+				template string
+				verbs    int
+			}{fmt.Sprintf("thought_steps.%s.%s", stepType, purpose), template, thoughtStepTemplateVerbs})
+		}
+	}
+
+	for _, check := range checks {
+		if got := countFmtVerbs(check.template); got != check.verbs {
+			return fmt.Errorf("invalid prompt template %s: expected %d placeholders, found %d",
+				check.name, check.verbs, got)
+		}
+	}
+	return nil
+}
+
+// countFmtVerbs counts the fmt verbs in a template, ignoring escaped percent signs
+func countFmtVerbs(template string) int {
+	var count int
+	for _, verb := range fmtVerbPattern.FindAllString(template, -1) {
+		if verb != "%%" {
+			count++
+		}
+	}
+	return count
+}
+
+// WatchPromptTemplates reloads the prompt templates whenever the template files
+// in confPath change and passes them to onChange. Templates that fail to load
+// or validate are logged and skipped so the previous ones stay in use.
+func WatchPromptTemplates(ctx context.Context, confPath string, onChange func(*PromptTemplates)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create template watcher: %w", err)
+	}
+	// Watch the directory rather than the files, editors often replace files on save
+	if err := watcher.Add(confPath); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", confPath, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if templateFiles[filepath.Base(event.Name)] &&
+					event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					reload = time.After(templateReloadDebounce)
+				}
+			case <-reload:
+				reload = nil
+				templates, err := LoadPromptTemplates(confPath)
+				if err != nil {
+					logger.GetLogger().Errorw("Failed to reload prompt templates, keeping current ones", "error", err)
+					continue
+				}
+				logger.GetLogger().Infoln("Reloaded prompt templates")
+				onChange(templates)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.GetLogger().Warnw("Prompt template watcher error", "error", err)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
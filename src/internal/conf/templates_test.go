@@ -0,0 +1,158 @@
+package conf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTemplates writes a config.yaml holding user templates with the given
+// analysis prefix, the action template is left empty when valid is false
+func writeTemplates(t *testing.T, dir, analysis string, valid bool) {
+	t.Helper()
+	action := ""
+	if valid {
+		action = "{{.ActionName}}"
+	}
+	content := "user_templates:\n" +
+		"  system:\n    base_template: \"You are {{.CharacterName}}\"\n" +
+		"  message:\n    analysis: \"" + analysis + " {{.Message}}\"\n" +
+		"    action: \"" + action + "\"\n" +
+		"  thought_steps:\n" +
+		"    tasks:\n      initial: \"Plan tasks for %s\"\n" +
+		"    actions:\n      initial: \"Pick actions from %s\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write templates: %v", err)
+	}
+}
+
+func TestValidatePromptTemplates(t *testing.T) {
+	valid := func() *PromptTemplates {
+		templates := &PromptTemplates{}
+		templates.System.BaseTemplate = "You are {{.CharacterName}}. {{.TokenBalanceInfo}}"
+		templates.System.InfoFormat = map[string]string{"token_balance_exists": "Balance: %s"}
+		templates.Message.Analysis = "{{.UserID}} on {{.Platform}} said {{.Message}}"
+		templates.Message.Action = "Fill {{.ActionParameters}} for {{.ActionName}}"
+		templates.ThoughtSteps = map[ThoughtStepType]ThoughtStepTemplates{
+			ThoughtStepTypeTask:   {Initial: "Plan tasks for %s"},
+			ThoughtStepTypeAction: {Initial: "Pick actions from %s"},
+		}
+		return templates
+	}
+
+	tests := []struct {
+		name      string
+		templates func() *PromptTemplates
+		wantErr   string
+	}{
+		{name: "valid", templates: valid},
+		{name: "nil", templates: func() *PromptTemplates { return nil }, wantErr: "missing prompt templates"},
+		{name: "missing base template", templates: func() *PromptTemplates {
+			templates := valid()
+			templates.System.BaseTemplate = ""
+			return templates
+		}, wantErr: "system.base_template"},
+		{name: "missing action", templates: func() *PromptTemplates {
+			templates := valid()
+			templates.Message.Action = ""
+			return templates
+		}, wantErr: "message.action"},
+		{name: "missing task thought steps", templates: func() *PromptTemplates {
+			templates := valid()
+			delete(templates.ThoughtSteps, ThoughtStepTypeTask)
+			return templates
+		}, wantErr: "thought_steps.tasks.initial"},
+		{name: "missing initial action step", templates: func() *PromptTemplates {
+			templates := valid()
+			templates.ThoughtSteps[ThoughtStepTypeAction] = ThoughtStepTemplates{Analysis: "Analyze %s"}
+			return templates
+		}, wantErr: "thought_steps.actions.initial"},
+		{name: "unknown field", templates: func() *PromptTemplates {
+			templates := valid()
+			templates.Message.Analysis = "{{.Username}} said {{.Message}}"
+			return templates
+		}, wantErr: "message.analysis"},
+		{name: "info format with an extra placeholder", templates: func() *PromptTemplates {
+			templates := valid()
+			templates.System.InfoFormat["priority_account"] = "Priority %s"
+			return templates
+		}, wantErr: "system.info_format.priority_account"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePromptTemplates(tt.templates())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePromptTemplates() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePromptTemplates() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultTemplatesAreValid(t *testing.T) {
+	templates, err := loadDefaultTemplates("../../config")
+	if err != nil {
+		t.Fatalf("loadDefaultTemplates() error = %v", err)
+	}
+	if err := ValidatePromptTemplates(templates); err != nil {
+		t.Errorf("default templates are invalid: %v", err)
+	}
+}
+
+func TestWatchPromptTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplates(t, dir, "first", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan *PromptTemplates, 10)
+	if err := WatchPromptTemplates(ctx, dir, func(templates *PromptTemplates) { reloaded <- templates }); err != nil {
+		t.Fatalf("WatchPromptTemplates() error = %v", err)
+	}
+
+	writeTemplates(t, dir, "second", true)
+	select {
+	case templates := <-reloaded:
+		if !strings.HasPrefix(templates.Message.Analysis, "second ") {
+			t.Errorf("reloaded analysis = %q, want the edited one", templates.Message.Analysis)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("templates were not reloaded after the file changed")
+	}
+
+	// Invalid templates are skipped so the current ones stay in use
+	writeTemplates(t, dir, "third", false)
+	select {
+	case templates := <-reloaded:
+		t.Errorf("reloaded invalid templates %+v", templates.Message)
+	case <-time.After(4 * templateReloadDebounce):
+	}
+}
+
+func TestDefaultTemplatesHaveThoughtSteps(t *testing.T) {
+	templates, err := loadDefaultTemplates("../../config")
+	if err != nil {
+		t.Fatalf("loadDefaultTemplates() error = %v", err)
+	}
+
+	for _, stepType := range []ThoughtStepType{ThoughtStepTypeTask, ThoughtStepTypeAction} {
+		steps, ok := templates.ThoughtSteps[stepType]
+		if !ok {
+			t.Errorf("no thought step templates for %q", stepType)
+			continue
+		}
+		if steps.Initial == "" {
+			t.Errorf("initial thought step template for %q is empty", stepType)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	AccessActionAllow = "allow"
+	AccessActionDeny  = "deny"
+)
+
+// accessPolicy decides which users the agent interacts with. Entries are either
+// "platform:user" or a bare user matched on every platform.
+type accessPolicy struct {
+	defaultAllow bool
+	allow        map[string]bool
+	deny         map[string]bool
+}
+
+func newAccessPolicy(defaultAction string, allow, deny []string) (*accessPolicy, error) {
+	policy := &accessPolicy{
+		allow: toAccessSet(allow),
+		deny:  toAccessSet(deny),
+	}
+
+	switch strings.ToLower(defaultAction) {
+	case "", AccessActionAllow:
+		policy.defaultAllow = true
+	case AccessActionDeny:
+		policy.defaultAllow = false
+	default:
+		return nil, fmt.Errorf("invalid default access action: %s", defaultAction)
+	}
+
+	return policy, nil
+}
+
+// permits reports whether the user may interact with the agent. Deny entries
+// win over allow entries, and unlisted users get the default action.
+func (p *accessPolicy) permits(platform, user string) bool {
+	if p == nil {
+		return true
+	}
+	if p.matches(p.deny, platform, user) {
+		return false
+	}
+	if p.matches(p.allow, platform, user) {
+		return true
+	}
+	return p.defaultAllow
+}
+
+func (p *accessPolicy) matches(set map[string]bool, platform, user string) bool {
+	user = strings.ToLower(strings.TrimPrefix(user, "@"))
+	return set[user] || set[strings.ToLower(platform)+":"+user]
+}
+
+func toAccessSet(entries []string) map[string]bool {
+	set := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if platform, user, ok := strings.Cut(entry, ":"); ok {
+			entry = platform + ":" + strings.TrimPrefix(user, "@")
+		} else {
+			entry = strings.TrimPrefix(entry, "@")
+		}
+		set[entry] = true
+	}
+	return set
+}
+
+// hasAccess reports whether the agent should engage with the sender, priority
+// accounts bypass the access policy
+func (a *Agent) hasAccess(msg *SocialMessage) bool {
+	return a.isPriorityAccount(msg) || a.access.Load().permits(msg.Platform, msg.FromUser)
+}
+
+// isPriorityAccount reports whether the sender is one of the character's priority accounts
+func (a *Agent) isPriorityAccount(msg *SocialMessage) bool {
+	if a.character == nil {
+		return false
+	}
+	for _, account := range a.character.PriorityAccounts {
+		if strings.EqualFold(account.Platform, msg.Platform) && account.ID == msg.FromUser {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+)
+
+func TestAgentHasAccess(t *testing.T) {
+	character := &characters.Character{
+		PriorityAccounts: []characters.Account{{ID: "vip", Platform: "twitter"}},
+	}
+
+	tests := []struct {
+		name          string
+		defaultAction string
+		allow         []string
+		deny          []string
+		msg           *SocialMessage
+		want          bool
+	}{
+		{name: "unlisted with default allow", msg: &SocialMessage{Platform: "twitter", FromUser: "alice"}, want: true},
+		{name: "unlisted with default deny", defaultAction: AccessActionDeny, msg: &SocialMessage{Platform: "twitter", FromUser: "alice"}, want: false},
+		{name: "allowed", defaultAction: AccessActionDeny, allow: []string{"@Alice"}, msg: &SocialMessage{Platform: "twitter", FromUser: "alice"}, want: true},
+		{name: "allowed on another platform only", defaultAction: AccessActionDeny, allow: []string{"discord:alice"}, msg: &SocialMessage{Platform: "twitter", FromUser: "alice"}, want: false},
+		{name: "denied", deny: []string{"twitter:spammer"}, msg: &SocialMessage{Platform: "twitter", FromUser: "spammer"}, want: false},
+		{name: "deny wins over allow", allow: []string{"spammer"}, deny: []string{"spammer"}, msg: &SocialMessage{Platform: "twitter", FromUser: "spammer"}, want: false},
+		{name: "priority bypasses deny", deny: []string{"vip"}, msg: &SocialMessage{Platform: "twitter", FromUser: "vip"}, want: true},
+		{name: "priority bypasses default deny", defaultAction: AccessActionDeny, msg: &SocialMessage{Platform: "Twitter", FromUser: "vip"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := newAccessPolicy(tt.defaultAction, tt.allow, tt.deny)
+			if err != nil {
+				t.Fatalf("newAccessPolicy() error = %v", err)
+			}
+			agent := &Agent{character: character}
+			agent.access.Store(access)
+
+			if got := agent.hasAccess(tt.msg); got != tt.want {
+				t.Errorf("hasAccess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAccessPolicyRejectsUnknownAction(t *testing.T) {
+	if _, err := newAccessPolicy("maybe", nil, nil); err == nil {
+		t.Error("newAccessPolicy() error = nil, want an error")
+	}
+}
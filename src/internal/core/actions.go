@@ -1,20 +1,92 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 )
 
+// actionPlanInstructions ends the concrete action step prompt so the final
+// step returns a machine-readable plan
+const actionPlanInstructions = `
+
+Return the final action plan as a single JSON object, with no other text, in this format:
+{
+  "actions": [
+    {
+      "action_name": "exact name of an available action",
+      "action_type": "exact type of that action",
+      "parameters": {"parameter name": "value"},
+      "reason": "why this action is needed"
+    }
+  ]
+}`
+
 type ActionGeneration struct {
 	Chain   *ThoughtChain
+	Plan    *ActionPlan
 	Actions []actions.IAction
 }
 
-// convertThoughtChainToActions converts a thought chain into executable actions
-func convertThoughtChainToActions(chain *ThoughtChain) ([]actions.IAction, error) {
-	var actions []actions.IAction
+// ActionPlan is the structured output of the concrete thought step
+type ActionPlan struct {
+	Actions []PlannedAction `json:"actions"`
+}
+
+// PlannedAction is a single action of an action plan
+type PlannedAction struct {
+	ActionName string                 `json:"action_name"`
+	ActionType string                 `json:"action_type"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Reason     string                 `json:"reason"`
+}
+
+// parseActionPlan unmarshals an action plan, tolerating a surrounding code fence
+func parseActionPlan(raw string) (*ActionPlan, error) {
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSuffix(raw, "```")
+
+	var plan ActionPlan
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse action plan: %w", err)
+	}
+	return &plan, nil
+}
+
+// convertThoughtChainToActions converts the plan from the conclusion of a
+// thought chain into executable actions, rejecting unknown action names
+func convertThoughtChainToActions(chain *ThoughtChain, available []actions.IAction) (*ActionPlan, []actions.IAction, error) {
+	concrete := chain.conclusion()
+	if concrete == nil {
+		return nil, nil, fmt.Errorf("thought chain has no steps")
+	}
+
+	plan, err := parseActionPlan(concrete.RawLLMOutput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byName := make(map[string]actions.IAction, len(available))
+	for _, action := range available {
+		byName[action.Name()] = action
+	}
 
-	// Track dependencies between actions
-	// dependencies := make(map[string][]string)
+	result := make([]actions.IAction, 0, len(plan.Actions))
+	for _, planned := range plan.Actions {
+		action, ok := byName[planned.ActionName]
+		if !ok {
+			return nil, nil, fmt.Errorf("action plan references unknown action: %s", planned.ActionName)
+		}
+		if planned.ActionType != "" && planned.ActionType != action.Type() {
+			return nil, nil, fmt.Errorf("action plan has type %s for action %s, expected %s",
+				planned.ActionType, planned.ActionName, action.Type())
+		}
+		result = append(result, action)
+	}
 
-	return actions, nil
+	return plan, result, nil
 }
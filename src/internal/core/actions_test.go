@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// fakeAction records the parameters it runs with
+type fakeAction struct {
+	name     string
+	typ      string
+	validate func(params map[string]interface{}) error
+	err      error
+	executed []map[string]interface{}
+	traces   []string
+}
+
+func (f *fakeAction) Name() string             { return f.name }
+func (f *fakeAction) Description() string      { return "test action " + f.name }
+func (f *fakeAction) Type() string             { return f.typ }
+func (f *fakeAction) ParametersPrompt() string { return "" }
+
+func (f *fakeAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	f.executed = append(f.executed, params)
+	f.traces = append(f.traces, logger.TraceID(ctx))
+	return f.err
+}
+
+func (f *fakeAction) Validate(params map[string]interface{}) error {
+	if f.validate != nil {
+		return f.validate(params)
+	}
+	return nil
+}
+
+func TestConvertThoughtChainToActions(t *testing.T) {
+	plan := `{"actions": [{"action_name": "post_tweet", "action_type": "social", "parameters": {"text": "gm"}}]}`
+	tests := []struct {
+		name    string
+		steps   []*ThoughtStep
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "well-formed plan",
+			steps: []*ThoughtStep{
+				{Purpose: PurposeInitial, RawLLMOutput: "thinking"},
+				{Purpose: PurposeConcrete, RawLLMOutput: plan},
+			},
+			want: []string{"post_tweet"},
+		},
+		{
+			name:  "plan in a code fence",
+			steps: []*ThoughtStep{{Purpose: PurposeConcrete, RawLLMOutput: "```json\n" + plan + "\n```"}},
+			want:  []string{"post_tweet"},
+		},
+		{
+			name:  "empty plan",
+			steps: []*ThoughtStep{{Purpose: PurposeConcrete, RawLLMOutput: `{"actions": []}`}},
+			want:  []string{},
+		},
+		{
+			name:    "unknown action name",
+			steps:   []*ThoughtStep{{Purpose: PurposeConcrete, RawLLMOutput: `{"actions": [{"action_name": "transfer"}]}`}},
+			wantErr: true,
+		},
+		{
+			name:    "mismatched action type",
+			steps:   []*ThoughtStep{{Purpose: PurposeConcrete, RawLLMOutput: `{"actions": [{"action_name": "post_tweet", "action_type": "wallet"}]}`}},
+			wantErr: true,
+		},
+		{
+			name:    "free-form text",
+			steps:   []*ThoughtStep{{Purpose: PurposeConcrete, RawLLMOutput: "I will post a tweet."}},
+			wantErr: true,
+		},
+		{
+			name: "chain ending on refinement",
+			steps: []*ThoughtStep{
+				{Purpose: PurposeInitial, RawLLMOutput: "thinking"},
+				{Purpose: PurposeRefinement, RawLLMOutput: "```json\n" + plan + "\n```"},
+			},
+			want: []string{"post_tweet"},
+		},
+		{
+			name: "concrete step before a later reconsider",
+			steps: []*ThoughtStep{
+				{Purpose: PurposeConcrete, RawLLMOutput: plan},
+				{Purpose: PurposeReconsider, RawLLMOutput: "on second thought"},
+			},
+			want: []string{"post_tweet"},
+		},
+		{name: "no steps", wantErr: true},
+	}
+
+	available := []actions.IAction{&fakeAction{name: "post_tweet", typ: "social"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, got, err := convertThoughtChainToActions(&ThoughtChain{Steps: tt.steps}, available)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertThoughtChainToActions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d actions, want %d", len(got), len(tt.want))
+			}
+			for i, action := range got {
+				if action.Name() != tt.want[i] {
+					t.Errorf("action %d = %s, want %s", i, action.Name(), tt.want[i])
+				}
+				if planned.Actions[i].Parameters["text"] != "gm" {
+					t.Errorf("action %d parameters = %v, want the planned parameters", i, planned.Actions[i].Parameters)
+				}
+			}
+		})
+	}
+}
+
+func TestConcreteStepRequestsJSON(t *testing.T) {
+	client := &fakeLLM{respond: func(llm.CompletionRequest) string {
+		return `{"actions": []}`
+	}}
+	engine := NewCognitiveEngine(client, "test-model", nil, &conf.PromptTemplates{})
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+	prompt := func(purpose StepPurpose, _ []*ThoughtStep) string { return string(purpose) }
+
+	for _, purpose := range []StepPurpose{PurposeAnalysis, PurposeConcrete} {
+		if _, err := engine.generateThoughtStep(context.Background(), state, &ThoughtChain{}, purpose, prompt); err != nil {
+			t.Fatalf("generateThoughtStep(%s) error = %v", purpose, err)
+		}
+	}
+
+	if got := client.requests[0].ResponseFormat; got != "" {
+		t.Errorf("analysis step response format = %q, want none", got)
+	}
+	if got := client.requests[1].ResponseFormat; got != llm.ResponseFormatJSONObject {
+		t.Errorf("concrete step response format = %q, want %q", got, llm.ResponseFormatJSONObject)
+	}
+}
+
+// schemaAction is a fake action declaring its parameters as a JSON schema
+type schemaAction struct {
+	*fakeAction
+	schema *actions.ParameterSchema
+}
+
+func (s *schemaAction) ParametersSchema() *actions.ParameterSchema { return s.schema }
+
+func TestGenerateActionParametersSchema(t *testing.T) {
+	schema := &actions.ParameterSchema{
+		Properties: map[string]actions.Parameter{
+			"address": {Type: actions.ParameterString, Pattern: "^0x[0-9a-f]{4}$"},
+			"limit":   {Type: actions.ParameterInteger, Minimum: actions.Bound(1), Maximum: actions.Bound(100)},
+		},
+		Required: []string{"address"},
+	}
+	tests := []struct {
+		name     string
+		action   actions.IAction
+		response string
+		wantErr  bool
+		wantJSON bool
+	}{
+		{name: "valid parameters", action: &schemaAction{&fakeAction{name: "lookup"}, schema}, response: `{"address": "0xabcd", "limit": 10}`, wantJSON: true},
+		{name: "limit above the maximum", action: &schemaAction{&fakeAction{name: "lookup"}, schema}, response: `{"address": "0xabcd", "limit": 500}`, wantErr: true, wantJSON: true},
+		{name: "missing required parameter", action: &schemaAction{&fakeAction{name: "lookup"}, schema}, response: `{"limit": 5}`, wantErr: true, wantJSON: true},
+		{name: "more info needed", action: &schemaAction{&fakeAction{name: "lookup"}, schema}, response: `{"more_info_needed": true, "question": "Which address?"}`, wantJSON: true},
+		{name: "action without schema", action: &fakeAction{name: "lookup"}, response: `{"limit": 500}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: func(llm.CompletionRequest) string { return tt.response }}
+			templates := testTemplates()
+			templates.Message.Action = "{{.ActionParameters}}"
+			engine := NewCognitiveEngine(client, "test-model", nil, templates)
+			state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+
+			params, err := engine.generateActionParameters(context.Background(), state, &SocialMessage{Content: "look it up"}, nil, tt.action)
+			if tt.wantErr {
+				if !errors.Is(err, actions.ErrInvalidParameters) {
+					t.Errorf("generateActionParameters() = %v, %v, want ErrInvalidParameters", params, err)
+				}
+			} else if err != nil {
+				t.Fatalf("generateActionParameters() error = %v", err)
+			}
+
+			request := client.requests[0]
+			if got := request.ResponseFormat == llm.ResponseFormatJSONObject; got != tt.wantJSON {
+				t.Errorf("response format = %q, want JSON object %v", request.ResponseFormat, tt.wantJSON)
+			}
+			if got := strings.Contains(lastPrompt(request), `"pattern": "^0x[0-9a-f]{4}$"`); got != tt.wantJSON {
+				t.Errorf("prompt = %q, want the schema included %v", lastPrompt(request), tt.wantJSON)
+			}
+		})
+	}
+}
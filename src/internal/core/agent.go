@@ -3,11 +3,14 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/characters"
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
 
@@ -24,6 +27,21 @@ type Agent struct {
 	tokenManager   TokenManager
 	socialClient   SocialClient
 	pluginRegistry *plugins.Registry
+	access         atomic.Pointer[accessPolicy]
+	languages      map[string]string
+	replyGuard     *replyGuard
+	scheduler      *scheduler
+	confirmations  *confirmations
+	sessions       *sessionStore
+	commands       *commandRouter
+	intents        IntentClassifier
+	moderation     *contentFilter
+	style          *styleEnforcer
+	socialErrors   *socialErrorTracker
+	errorAlert     socialErrorAlert
+	proactive      proactiveConfig
+	tasks          TaskStore
+	routines       *routineGroup
 	ctx            context.Context
 	cancel         context.CancelFunc
 }
@@ -45,25 +63,104 @@ func NewAgent(config AgentConfig) (*Agent, error) {
 		return nil, fmt.Errorf("invalid agent config: %w", err)
 	}
 
+	access, err := newAccessPolicy(config.Access.DefaultAction, config.Access.Allow, config.Access.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access config: %w", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
+	cognitive := NewCognitiveEngine(config.LLMClient, config.Model, config.Character, config.PromptTemplates)
+	if config.MemoryManager != nil {
+		cognitive.memory = config.MemoryManager
+	}
+	if config.RewardModel != nil {
+		cognitive.rewardModel = config.RewardModel
+	}
+	if config.Inference.MaxChainLength > 0 {
+		cognitive.maxSteps = config.Inference.MaxChainLength
+	}
+	if config.Inference.EarlyExitConfidence > 0 {
+		cognitive.earlyExitConfidence = config.Inference.EarlyExitConfidence
+	}
+
 	agent := &Agent{
 		ID:             config.ID,
 		character:      config.Character,
-		cognitive:      NewCognitiveEngine(config.LLMClient, config.Model, config.Character, config.PromptTemplates),
+		cognitive:      cognitive,
 		logger:         logger.GetLogger(),
 		stakeholders:   config.Stakeholders,
 		tokenManager:   config.TokenManager,
 		socialClient:   config.SocialClient,
 		pluginRegistry: config.PluginRegistry,
+		confirmations:  newConfirmations(),
+		sessions:       newSessionStore(),
+		commands:       newCommandRouter(config.Commands.Prefix, config.Commands.Routes),
+		languages:      toLanguageMap(config.Languages),
+		routines:       newRoutineGroup(),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
+	agent.access.Store(access)
+	switch {
+	case config.IntentClassifier != nil:
+		agent.intents = config.IntentClassifier
+	case config.Character != nil && len(config.Character.IntentRules) > 0:
+		agent.intents = NewRuleIntentClassifier(config.Character.IntentRules)
+	}
+	if len(config.Schedules) > 0 {
+		sched, err := newScheduler(config.Schedules, realClock{}, agent.runScheduledAction)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid schedules: %w", err)
+		}
+		agent.scheduler = sched
+	}
+	var moderatedResponse string
+	if !config.Moderation.Block && config.Character != nil {
+		moderatedResponse = config.Character.Responses.ModeratedResponse
+	}
+	agent.moderation = newContentFilter(config.Moderation.Moderators, moderatedResponse)
+	if config.Character != nil {
+		agent.style = newStyleEnforcer(config.Character.Style.Rules)
+	}
+	if config.ReplyGuard.MaxReplies > 0 && config.ReplyGuard.Window > 0 {
+		agent.replyGuard = newReplyGuard(config.ReplyGuard.MaxReplies, config.ReplyGuard.Window)
+	}
+
+	errorThreshold, errorWindow := config.SocialErrors.Threshold, config.SocialErrors.Window
+	if errorThreshold <= 0 {
+		errorThreshold = defaultSocialErrorThreshold
+	}
+	if errorWindow <= 0 {
+		errorWindow = defaultSocialErrorWindow
+	}
+	agent.socialErrors = newSocialErrorTracker(errorThreshold, errorWindow)
+	agent.proactive = proactiveConfig{
+		interval:         config.Proactive.Interval,
+		maxTasks:         config.Proactive.MaxTasks,
+		proposePlatform:  config.Proactive.ProposePlatform,
+		proposeChannelID: config.Proactive.ProposeChannelID,
+		thinkAloud:       config.Proactive.ThinkAloud,
+		thinkAloudSteps:  config.Proactive.ThinkAloudSteps,
+	}
+	switch {
+	case config.TaskStore != nil:
+		agent.tasks = config.TaskStore
+	case config.MemoryManager != nil:
+		agent.tasks = NewMemoryTaskStore(config.MemoryManager)
+	}
+	agent.errorAlert = socialErrorAlert{
+		Platform:  config.SocialErrors.AlertPlatform,
+		ChannelID: config.SocialErrors.AlertChannelID,
+	}
 
 	return agent, nil
 }
 
-// Main system routines
+// Start registers the priority accounts and launches the agent goroutines:
+// social monitoring, error monitoring, scheduled actions and the proactive
+// loop. Shutdown stops them.
 func (a *Agent) Start() error {
 	a.logger.Info("Starting agent system")
 
@@ -80,29 +177,47 @@ func (a *Agent) Start() error {
 	}
 
 	// Start social media monitoring
-	go func() {
-		a.monitorSocialInputs()
-	}()
+	a.routines.Go("social platform monitor", func() {
+		a.socialClient.MonitorMessages(a.ctx)
+	})
+	a.routines.Go("social message processor", a.monitorSocialInputs)
+	if source, ok := a.socialClient.(SocialErrorSource); ok {
+		errs := source.GetErrorChannel()
+		a.routines.Go("social error monitor", func() {
+			a.monitorSocialErrors(errs)
+		})
+	}
+
+	if a.scheduler != nil {
+		a.scheduler.start(a.ctx)
+		a.routines.Go("scheduler", a.scheduler.wait)
+	}
+	if a.proactive.interval > 0 {
+		a.routines.Go("proactive loop", a.runProactiveLoop)
+	}
 
 	a.socialClient.SendMessage(a.ctx, SocialMessage{
-		Platform: "Twitter",
+		Platform: "twitter",
 		Type:     "Response",
-		Content:  "Hello, world!",
+		Content:  a.character.Responses.Greeting,
 	})
 	return nil
 }
 
-// In your agent_system.go
+// getCurrentState snapshots the state the cognitive engine reasons over
 func (a *Agent) getCurrentState() *SystemState {
 	nativeToken, _ := a.tokenManager.NativeTokenInfo(a.ctx)
 
-	// Get plugin actions and provider states
+	// Get plugins, their actions and provider states
+	var availablePlugins []plugins.Plugin
 	var pluginActions []actions.IAction
 	var providerStates []*plugins.ProviderState
 
 	if a.pluginRegistry != nil {
+		availablePlugins = a.pluginRegistry.GetPlugins()
+
 		// Collect actions from plugins
-		for _, plugin := range a.pluginRegistry.GetPlugins() {
+		for _, plugin := range availablePlugins {
 			for _, action := range plugin.Actions() {
 				pluginActions = append(pluginActions, action)
 			}
@@ -134,17 +249,17 @@ func (a *Agent) getCurrentState() *SystemState {
 	return &SystemState{
 		Character:        a.character,
 		AvailableActions: pluginActions,
+		AvailablePlugins: availablePlugins,
 		Timestamp:        time.Now(),
 		NativeTokenInfo:  nativeToken,
 		ProviderStates:   providerStates,
 	}
 }
 
-// Social media monitoring
+// monitorSocialInputs processes incoming social messages one at a time until
+// the agent stops
 func (a *Agent) monitorSocialInputs() {
 	msgQueue := a.socialClient.GetMessageChannel()
-	// TODO graceful shutdown
-	go a.socialClient.MonitorMessages(a.ctx)
 	for {
 		select {
 		case msg := <-msgQueue:
@@ -155,114 +270,416 @@ func (a *Agent) monitorSocialInputs() {
 	}
 }
 
+// findAction looks up a plugin action in the registry index, by name alone
+// when the type is empty
+func (a *Agent) findAction(actionType, name string) actions.IAction {
+	if a.pluginRegistry == nil {
+		return nil
+	}
+	if actionType != "" {
+		action, _ := a.pluginRegistry.GetActionByTypeName(actionType, name)
+		return action
+	}
+	action, err := a.pluginRegistry.GetActionByName(name)
+	if errors.Is(err, plugins.ErrAmbiguousAction) {
+		a.logger.Warnw("Action name matches several actions", "action", name, "error", err)
+	}
+	return action
+}
+
+// runScheduledAction validates and executes the action of a schedule
+func (a *Agent) runScheduledAction(ctx context.Context, scheduled ScheduledAction) error {
+	action := a.findAction("", scheduled.Action)
+	if action == nil {
+		return fmt.Errorf("action not found: %s", scheduled.Action)
+	}
+
+	params := make(map[string]interface{}, len(scheduled.Params))
+	for key, value := range scheduled.Params {
+		params[key] = value
+	}
+	if err := action.Validate(params); err != nil {
+		return fmt.Errorf("invalid params for %s: %w", scheduled.Action, err)
+	}
+
+	return a.executeAction(ctx, action, params)
+}
+
 // executeAction executes a generic action
 func (a *Agent) executeAction(ctx context.Context, action actions.IAction, params map[string]interface{}) error {
-	a.logger.Infow("Executing action", "type", action.Type(), "params", params)
+	logger.FromContext(ctx).Infow("Executing action", "type", action.Type(), "params", params)
 	return action.Execute(ctx, params)
 }
 
+// messageTurn is what the pipeline stages of processMessage share about the
+// message being handled
+type messageTurn struct {
+	msg         *SocialMessage
+	input       *SocialMessage
+	state       *SystemState
+	stakeholder *Stakeholder
+
+	// forcedAction skips action selection, prefilled overrides its params
+	forcedAction actions.IAction
+	prefilled    map[string]interface{}
+}
+
 func (a *Agent) processMessage(msg *SocialMessage) error {
+	// Correlate the logs and outgoing requests of this message
+	ctx := logger.WithTraceID(a.ctx, logger.NewTraceID())
+	log := logger.FromContext(ctx)
+
 	var err error
 	defer func() {
 		if err != nil {
-			a.logger.Errorw("Error processing message", "error", err)
-			a.socialClient.SendMessage(a.ctx, SocialMessage{
+			log.Errorw("Error processing message", "error", err)
+			a.socialClient.SendMessage(ctx, SocialMessage{
 				Platform: msg.Platform,
 				Type:     "Response",
-				Content:  "Something went wrong. Please try again later.",
+				Content:  a.character.Responses.ErrorResponse,
 				Metadata: msg.Metadata,
 			})
 		}
 	}()
 
+	if handled, filterErr := a.preFilter(ctx, msg); handled {
+		return filterErr
+	}
+
+	// Let the user know a reply is coming while the LLM works
+	stopTyping := a.startTyping(ctx, msg)
+	defer stopTyping()
+
+	turn, err := a.startTurn(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	processedMsg, err := a.cognitivePass(ctx, turn)
+	if err != nil {
+		return err
+	}
+
+	if processedMsg.ShouldGenerateAction {
+		if err = a.runActions(ctx, turn, processedMsg); err != nil {
+			return err
+		}
+	}
+
+	err = a.replyAndPersist(ctx, turn, processedMsg, stopTyping)
+	return err
+}
+
+// preFilter handles the messages that never reach the LLM: users without
+// access, confirmation taps, throttled conversations and forbidden topics
+func (a *Agent) preFilter(ctx context.Context, msg *SocialMessage) (handled bool, err error) {
+	log := logger.FromContext(ctx)
+
+	if !a.hasAccess(msg) {
+		log.Infow("Ignoring message from user without access",
+			"platform", msg.Platform,
+			"from", msg.FromUser,
+		)
+		return true, nil
+	}
+
+	// A tap on a confirmation button resumes a pending action
+	if msg.Type == "callback" {
+		return true, a.handleConfirmation(msg)
+	}
+
+	// Priority accounts are never throttled
+	conversation := conversationKey(msg)
+	if !a.isPriorityAccount(msg) && !a.replyGuard.tryRecord(conversation, time.Now()) {
+		log.Warnw("Reply limit reached, throttling message",
+			"conversation", conversation,
+			"from", msg.FromUser,
+		)
+		// Say so once per window, a notice per message would feed the loop
+		if a.replyGuard.tryNotify(conversation, time.Now()) {
+			a.socialClient.SendMessage(ctx, SocialMessage{
+				Platform: msg.Platform,
+				Type:     "Response",
+				Content:  a.character.Responses.ThrottledResponse,
+				Metadata: msg.Metadata,
+			})
+		}
+		return true, nil
+	}
+
+	// Deflect forbidden topics without spending an LLM call
+	if topic, ok := a.character.MatchForbiddenTopic(msg.Content); ok {
+		log.Infow("Deflecting message about forbidden topic",
+			"topic", topic,
+			"from", msg.FromUser,
+		)
+		a.socialClient.SendMessage(ctx, SocialMessage{
+			Platform: msg.Platform,
+			Type:     "Response",
+			Content:  a.character.Responses.ForbiddenTopicResponse,
+			Metadata: msg.Metadata,
+		})
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// startTurn loads the sender and works out what the message asks for
+func (a *Agent) startTurn(ctx context.Context, msg *SocialMessage) (*messageTurn, error) {
+	log := logger.FromContext(ctx)
+
 	state := a.getCurrentState()
 
 	stakeholder, err := a.stakeholders.FetchOrCreateStakeholder(
-		a.ctx,
+		ctx,
 		msg.FromUser,
 		msg.Platform,
 		StakeholderTypeUser,
 	)
 	if err != nil {
-		a.logger.Errorw("Error fetching stakeholder", "error", err)
-		return err
+		log.Errorw("Error fetching stakeholder", "error", err)
+		return nil, err
 	}
 
-	a.logger.Infof("Priority accounts: %t", stakeholder.Type == StakeholderTypePriority)
+	log.Infof("Priority accounts: %t", stakeholder.Type == StakeholderTypePriority)
+	if language := a.languageFor(msg.Platform, msg.FromUser); language != "" {
+		stakeholder.Language = language
+	}
 
-	balance, _ := a.tokenManager.FetchNativeTokenBalance(a.ctx, msg.FromUser, msg.Platform)
+	// Greet first-time users before answering them
+	if stakeholder.IsNew && a.character.Responses.Welcome != "" {
+		log.Infow("Welcoming new stakeholder", "from", msg.FromUser)
+		a.socialClient.SendMessage(ctx, SocialMessage{
+			Platform: msg.Platform,
+			Type:     "Response",
+			Content:  a.character.Responses.Welcome,
+			Metadata: msg.Metadata,
+		})
+	}
+
+	balance, _ := a.tokenManager.FetchNativeTokenBalance(ctx, msg.FromUser, msg.Platform)
 	if balance != nil {
-		a.logger.Infof("Native token balance: %f", balance.Balance)
+		log.Infof("Native token balance: %f", balance.Balance)
 		stakeholder.TokenBalance = balance
 	}
 
-	processedMsg, err := a.cognitive.processMessage(a.ctx, state, msg, stakeholder)
-	if err != nil {
-		a.logger.Errorw("Error processing message", "error", err)
-		return err
+	turn := &messageTurn{msg: msg, input: msg, state: state, stakeholder: stakeholder}
+	a.resolveForcedAction(ctx, turn)
+	return turn, nil
+}
+
+// resolveForcedAction picks the action a clarification answer or a command
+// runs, if any
+func (a *Agent) resolveForcedAction(ctx context.Context, turn *messageTurn) {
+	log := logger.FromContext(ctx)
+	msg := turn.msg
+
+	// An answer to a clarification question continues the paused action
+	// with the whole exchange instead of starting over
+	if clarification, ok := a.sessions.takeClarification(sessionKey(turn.stakeholder), time.Now()); ok {
+		turn.input = resumedMessage(msg, clarification)
+		turn.forcedAction = a.findAction(clarification.actionType, clarification.actionName)
+		log.Infow("Resuming action after clarification",
+			"action", clarification.actionName,
+			"from", msg.FromUser,
+		)
+		return
 	}
 
-	if processedMsg.ShouldGenerateAction {
-		for _, action := range processedMsg.Actions {
-			var actionImpl actions.IAction
-			if a.pluginRegistry != nil {
-				for _, plugin := range a.pluginRegistry.GetPlugins() {
-					for _, pluginAction := range plugin.Actions() {
-						if pluginAction.Type() == action.ActionType && pluginAction.Name() == action.ActionName {
-							actionImpl = pluginAction
-							break
-						}
-					}
-					if actionImpl != nil {
-						break
-					}
-				}
-			}
+	// A configured command runs its action with the params it pre-fills
+	cmd, ok := a.commands.route(msg.Content)
+	if !ok {
+		return
+	}
+	if turn.forcedAction = a.findAction("", cmd.action); turn.forcedAction == nil {
+		log.Warnw("Command routed to unknown action", "action", cmd.action)
+		return
+	}
+	turn.prefilled = cmd.params
+	log.Infow("Routing command to action",
+		"action", cmd.action,
+		"from", msg.FromUser,
+	)
+}
 
-			if actionImpl == nil {
-				a.logger.Errorw("Error getting action", "error", err)
-				return err
-			}
-			a.logger.Infof("Action found in pluginRegistry: %s", actionImpl.Name())
+// cognitivePass decides how to answer the message and which actions to run
+func (a *Agent) cognitivePass(ctx context.Context, turn *messageTurn) (*ProcessedMessage, error) {
+	log := logger.FromContext(ctx)
+
+	// Obvious intents such as greetings are answered without an LLM call
+	var processedMsg *ProcessedMessage
+	classified := false
+	if turn.forcedAction == nil && a.intents != nil {
+		if processedMsg, classified = a.intents.Classify(turn.input); classified {
+			log.Infow("Classified message without LLM",
+				"intent", processedMsg.Intent,
+				"from", turn.msg.FromUser,
+			)
+		}
+	}
+	if !classified {
+		var err error
+		processedMsg, err = a.cognitive.processMessage(ctx, turn.state, turn.input, turn.stakeholder)
+		if err != nil {
+			log.Errorw("Error processing message", "error", err)
+			return nil, err
+		}
+	}
 
-			params, err := a.cognitive.generateActionParameters(a.ctx, state, msg, stakeholder, actionImpl)
-			if err != nil {
-				a.logger.Errorw("Error generating action parameters", "error", err)
-				return err
-			}
+	if turn.forcedAction != nil {
+		processedMsg.ShouldGenerateAction = true
+		processedMsg.Actions = []ProcessedAction{{
+			ActionName: turn.forcedAction.Name(),
+			ActionType: turn.forcedAction.Type(),
+		}}
+	}
 
-			if moreInfoNeeded, ok := params["more_info_needed"].(bool); ok && moreInfoNeeded {
-				a.logger.Infof("More info needed, relying on message: %s", params["rely_message"])
-				processedMsg.ResponseMsg = params["rely_message"].(string)
-				processedMsg.ShouldReply = true
-				continue
-			}
+	return processedMsg, nil
+}
 
-			if err = a.executeAction(a.ctx, actionImpl, params); err != nil {
-				a.logger.Errorw("Error executing action", "error", err)
-				return err
-			}
+// runActions runs the actions of the processed message in order, stopping
+// at the first one that has to ask the user something
+func (a *Agent) runActions(ctx context.Context, turn *messageTurn, processedMsg *ProcessedMessage) error {
+	for _, action := range processedMsg.Actions {
+		question, err := a.runAction(ctx, turn, action)
+		if err != nil {
+			return err
+		}
+		if question != nil {
+			// Stop: no other action runs until the user answers
+			processedMsg.ResponseMsg = *question
+			processedMsg.ShouldReply = false
+			return nil
+		}
+	}
+	return nil
+}
+
+// runAction runs a single action, or asks the user for what it is missing
+// and returns the question asked
+func (a *Agent) runAction(ctx context.Context, turn *messageTurn, action ProcessedAction) (*string, error) {
+	log := logger.FromContext(ctx)
+
+	var actionImpl actions.IAction
+	if a.pluginRegistry != nil {
+		actionImpl, _ = a.pluginRegistry.GetActionByTypeName(action.ActionType, action.ActionName)
+	}
+	if actionImpl == nil {
+		err := fmt.Errorf("action not found: %s/%s", action.ActionType, action.ActionName)
+		log.Errorw("Error getting action", "error", err)
+		return nil, err
+	}
+	log.Infof("Action found in pluginRegistry: %s", actionImpl.Name())
+
+	params, question, err := a.actionParams(ctx, turn, actionImpl)
+	if err != nil {
+		return nil, err
+	}
+	if question != nil {
+		a.askClarification(ctx, turn.msg, turn.input, turn.stakeholder, actionImpl, *question)
+		return question, nil
+	}
+
+	if actions.RequiresConfirmation(actionImpl) {
+		if err := a.requestConfirmation(turn.msg, actionImpl, params); err != nil {
+			log.Errorw("Error requesting confirmation", "error", err)
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	if err := a.executeAction(ctx, actionImpl, params); err != nil {
+		// The action found the request ambiguous, ask instead of failing
+		if question, ok := actions.NeedsClarification(err); ok {
+			log.Infow("Action needs clarification", "action", actionImpl.Name(), "question", question)
+			a.askClarification(ctx, turn.msg, turn.input, turn.stakeholder, actionImpl, question)
+			return &question, nil
 		}
+		log.Errorw("Error executing action", "error", err)
+		return nil, err
+	}
+	return nil, nil
+}
+
+// actionParams generates and validates the params of an action. When the
+// request lacks details it returns the question to ask the user instead.
+func (a *Agent) actionParams(
+	ctx context.Context,
+	turn *messageTurn,
+	actionImpl actions.IAction,
+) (map[string]interface{}, *string, error) {
+	log := logger.FromContext(ctx)
+
+	params, err := a.cognitive.generateActionParameters(ctx, turn.state, turn.input, turn.stakeholder, actionImpl)
+	if err != nil && !errors.Is(err, actions.ErrInvalidParameters) {
+		log.Errorw("Error generating action parameters", "error", err)
+		return nil, nil, err
+	}
+	if err == nil {
+		for key, value := range turn.prefilled {
+			params[key] = value
+		}
+
+		if moreInfoNeeded, ok := params["more_info_needed"].(bool); ok && moreInfoNeeded {
+			question := clarificationQuestion(params)
+			log.Infof("More info needed, asking: %s", question)
+			return nil, &question, nil
+		}
+
+		err = actionImpl.Validate(params)
+	}
+	// Invalid params would only fail inside the action, ask the user
+	// for what is missing instead of running it
+	if err != nil {
+		log.Infow("Skipping action with invalid parameters", "action", actionImpl.Name(), "error", err)
+		question := a.character.Responses.InvalidParamsResponse
+		return nil, &question, nil
+	}
+
+	return params, nil, nil
+}
+
+// replyAndPersist records the exchange and sends the reply
+func (a *Agent) replyAndPersist(
+	ctx context.Context,
+	turn *messageTurn,
+	processedMsg *ProcessedMessage,
+	stopTyping func(),
+) error {
+	log := logger.FromContext(ctx)
+	msg := turn.msg
+
+	// Never post or remember a reply that breaks the style rules or that
+	// moderation flags
+	if processedMsg.ShouldReply {
+		processedMsg.ResponseMsg = a.enforceStyle(ctx, processedMsg.ResponseMsg)
+		processedMsg.ResponseMsg, processedMsg.ShouldReply = a.moderation.filter(ctx, processedMsg.ResponseMsg)
 	}
 
-	a.logger.Infof("Processed message: %+v", processedMsg)
-	err = a.stakeholders.AddHistoricalMsg(
-		a.ctx,
+	log.Infof("Processed message: %+v", processedMsg)
+	err := a.stakeholders.AddHistoricalMsg(
+		ctx,
 		msg.FromUser,
 		msg.Platform,
 		[]string{
 			fmt.Sprintf("%s: %s", msg.FromUser, msg.Content),
-			fmt.Sprintf("%s: %s", state.Character.Name, processedMsg.ResponseMsg),
+			fmt.Sprintf("%s: %s", turn.state.Character.Name, processedMsg.ResponseMsg),
 		},
 	)
 	if err != nil {
-		a.logger.Errorw("Error adding historical message", "error", err)
+		log.Errorw("Error adding historical message", "error", err)
 		return err
 	}
 
+	if err := a.cognitive.rememberInteraction(ctx, turn.stakeholder, msg, processedMsg.ResponseMsg); err != nil {
+		log.Warnw("Error storing interaction memory", "error", err)
+	}
+
 	if processedMsg.ShouldReply {
+		stopTyping()
 		// If we didn't send a response with analysis, send the original response
-		a.socialClient.SendMessage(a.ctx, SocialMessage{
+		a.socialClient.SendMessage(ctx, SocialMessage{
 			Platform: msg.Platform,
 			Type:     "Response",
 			Content:  processedMsg.ResponseMsg,
@@ -270,14 +687,142 @@ func (a *Agent) processMessage(msg *SocialMessage) error {
 		})
 	}
 
-	// if processedMsg.ShouldGenerateTask && stakeholder.Type == StakeholderTypePriority {
-	// 	a.evaluateAndExecuteTasks()
-	// }
+	return nil
+}
+
+// clarificationQuestion returns the question of generated parameters asking
+// for more information. rely_message is the misspelled key older prompts used.
+func clarificationQuestion(params map[string]interface{}) string {
+	if question, ok := params["reply_message"].(string); ok {
+		return question
+	}
+	question, _ := params["rely_message"].(string)
+	return question
+}
+
+// askClarification sends the question right away and pauses the action until
+// the user answers
+func (a *Agent) askClarification(
+	ctx context.Context,
+	msg, input *SocialMessage,
+	stakeholder *Stakeholder,
+	action actions.IAction,
+	question string,
+) {
+	// The question is a reply like any other, so it follows the same style
+	// rules and moderation
+	question = a.enforceStyle(ctx, question)
+	content, ok := a.moderation.filter(ctx, question)
+	if content == question {
+		a.sessions.setClarification(sessionKey(stakeholder), &pendingClarification{
+			actionName: action.Name(),
+			actionType: action.Type(),
+			request:    input.Content,
+			question:   question,
+			expiresAt:  time.Now().Add(clarificationTTL),
+		})
+	}
+	if !ok {
+		return
+	}
+
+	a.socialClient.SendMessage(ctx, SocialMessage{
+		Platform: msg.Platform,
+		Type:     "Response",
+		Content:  content,
+		Metadata: msg.Metadata,
+	})
+}
+
+// requestConfirmation asks the user to confirm a sensitive action with inline
+// buttons; the action runs once the user taps confirm
+func (a *Agent) requestConfirmation(msg *SocialMessage, action actions.IAction, params map[string]interface{}) error {
+	// Only telegram can show buttons, never run unconfirmed actions elsewhere
+	if msg.Platform != "telegram" {
+		a.logger.Warnw("Skipping action that requires confirmation",
+			"action", action.Name(),
+			"platform", msg.Platform,
+		)
+		return nil
+	}
+
+	id := a.confirmations.add(&pendingConfirmation{
+		action:    action,
+		params:    params,
+		platform:  msg.Platform,
+		user:      msg.FromUser,
+		expiresAt: time.Now().Add(confirmationTTL),
+	})
+
+	return a.socialClient.SendMessage(a.ctx, SocialMessage{
+		Platform: msg.Platform,
+		Type:     "Confirmation",
+		Content:  formatConfirmationPrompt(action, params),
+		Metadata: msg.Metadata,
+		Buttons:  confirmationButtons(id),
+	})
+}
+
+// handleConfirmation executes or cancels the action of a tapped confirmation
+func (a *Agent) handleConfirmation(msg *SocialMessage) error {
+	id, approved, err := parseConfirmationCallback(msg.Content)
+	if err != nil {
+		a.logger.Warnw("Ignoring unknown callback", "data", msg.Content, "error", err)
+		return nil
+	}
+
+	reply := func(content string) {
+		a.socialClient.SendMessage(a.ctx, SocialMessage{
+			Platform: msg.Platform,
+			Type:     "Response",
+			Content:  content,
+			Metadata: msg.Metadata,
+		})
+	}
+
+	pending, err := a.confirmations.take(id, msg.Platform, msg.FromUser, time.Now())
+	if errors.Is(err, errConfirmationNotOwner) {
+		a.logger.Warnw("Ignoring confirmation from another user", "from", msg.FromUser)
+		return nil
+	}
+	if err != nil {
+		reply("This confirmation has expired, please ask again.")
+		return nil
+	}
+
+	if !approved {
+		reply(fmt.Sprintf("Cancelled: %s", pending.action.Name()))
+		return nil
+	}
 
+	if err := a.executeAction(a.ctx, pending.action, pending.params); err != nil {
+		a.logger.Errorw("Error executing confirmed action", "error", err)
+		reply(a.character.Responses.ErrorResponse)
+		return nil
+	}
+	reply(fmt.Sprintf("Done: %s", pending.action.Name()))
 	return nil
 }
 
+// UpdatePromptTemplates swaps the prompt templates of the running agent
+func (a *Agent) UpdatePromptTemplates(templates *conf.PromptTemplates) {
+	a.cognitive.SetPromptTemplates(templates)
+}
+
+// Shutdown cancels the agent and waits for its goroutines to exit before
+// stopping the plugins. Goroutines still running when ctx is done are listed
+// in the returned error.
 func (a *Agent) Shutdown(ctx context.Context) error {
 	a.cancel()
-	return nil
+
+	var errs []error
+	if err := a.routines.Wait(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if a.pluginRegistry != nil {
+		if err := a.pluginRegistry.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop plugins: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,692 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var errStakeholderUnavailable = errors.New("stakeholder unavailable")
+
+// fakeSocial records the messages the agent sends
+type fakeSocial struct {
+	SocialClient
+	mu   sync.Mutex
+	sent []SocialMessage
+}
+
+func (f *fakeSocial) SendMessage(_ context.Context, message SocialMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, message)
+	return nil
+}
+
+// fakeStakeholders records the stakeholders the agent looks up and the
+// history added to them
+type fakeStakeholders struct {
+	StakeholderManager
+	err     error
+	mu      sync.Mutex
+	fetched []string
+	history map[string][]string
+	// known are the stakeholders fetched before, the others are new
+	known map[string]bool
+}
+
+func (f *fakeStakeholders) FetchOrCreateStakeholder(_ context.Context, id, platform string, stakeholderType StakeholderType) (*Stakeholder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fetched = append(f.fetched, id)
+	if f.err != nil {
+		return nil, f.err
+	}
+	key := platform + ":" + id
+	if f.known == nil {
+		f.known = make(map[string]bool)
+	}
+	isNew := !f.known[key]
+	f.known[key] = true
+	return &Stakeholder{Key: key, ID: id, Platform: platform, Type: stakeholderType, HistoricalMsgs: f.history[key], IsNew: isNew}, nil
+}
+
+func (f *fakeStakeholders) AddHistoricalMsg(_ context.Context, id, platform string, msgs []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.history == nil {
+		f.history = make(map[string][]string)
+	}
+	key := platform + ":" + id
+	f.history[key] = append(f.history[key], msgs...)
+	return nil
+}
+
+// fakeTokens has no token information
+type fakeTokens struct{}
+
+func (fakeTokens) FetchNativeTokenBalance(context.Context, string, string) (*TokenBalance, error) {
+	return nil, nil
+}
+
+func (fakeTokens) NativeTokenInfo(context.Context) (*TokenInfo, error) {
+	return nil, nil
+}
+
+func (f *fakeSocial) contents() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	contents := make([]string, 0, len(f.sent))
+	for _, msg := range f.sent {
+		contents = append(contents, msg.Content)
+	}
+	return contents
+}
+
+// fakePlugin exposes test actions to the registry
+type fakePlugin struct {
+	actions []actions.IAction
+}
+
+func (p *fakePlugin) Name() string                    { return "test" }
+func (p *fakePlugin) Description() string             { return "test plugin" }
+func (p *fakePlugin) Providers() []plugins.Provider   { return nil }
+func (p *fakePlugin) Actions() []actions.IAction      { return p.actions }
+func (p *fakePlugin) Evaluators() []plugins.Evaluator { return nil }
+
+// replies answers the completions in order with the given responses
+func replies(t *testing.T, responses ...string) func(llm.CompletionRequest) string {
+	var mu sync.Mutex
+	return func(llm.CompletionRequest) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(responses) == 0 {
+			t.Error("unexpected LLM call")
+			return ""
+		}
+		response := responses[0]
+		responses = responses[1:]
+		return response
+	}
+}
+
+// analysis is the LLM analysis of a message as JSON
+func analysis(t *testing.T, processed ProcessedMessage) string {
+	t.Helper()
+	data, err := json.Marshal(processed)
+	if err != nil {
+		t.Fatalf("failed to marshal analysis: %v", err)
+	}
+	return string(data)
+}
+
+// testTemplates renders the message itself as the prompts so tests can see
+// what the LLM was asked
+func testTemplates() *conf.PromptTemplates {
+	templates := &conf.PromptTemplates{}
+	templates.System.BaseTemplate = "You are {{.CharacterName}}."
+	templates.Message.Analysis = "{{.Message}}"
+	templates.Message.Action = "{{.ActionName}}: {{.Message}}"
+	return templates
+}
+
+// newPipelineAgent builds an agent whose message pipeline runs on fakes,
+// with the actions registered in a test plugin
+func newPipelineAgent(t *testing.T, client llm.Client, social SocialClient, pluginActions ...actions.IAction) *Agent {
+	t.Helper()
+	character := &characters.Character{Name: "Tester", Responses: characters.ResponseTemplates{ErrorResponse: "error"}}
+
+	registry := plugins.NewPluginRegistry()
+	if len(pluginActions) > 0 {
+		if err := registry.Register(&fakePlugin{actions: pluginActions}); err != nil {
+			t.Fatalf("failed to register plugin: %v", err)
+		}
+	}
+
+	return &Agent{
+		character:      character,
+		cognitive:      NewCognitiveEngine(client, "test-model", character, testTemplates()),
+		logger:         logger.GetLogger(),
+		stakeholders:   &fakeStakeholders{},
+		tokenManager:   fakeTokens{},
+		socialClient:   social,
+		pluginRegistry: registry,
+		confirmations:  newConfirmations(),
+		sessions:       newSessionStore(),
+		ctx:            context.Background(),
+	}
+}
+
+func TestProcessMessageForbiddenTopic(t *testing.T) {
+	character := &characters.Character{
+		Name:            "Tester",
+		ForbiddenTopics: []characters.ForbiddenTopic{{Topic: "financial advice", Keywords: []string{"should i buy"}}},
+		Responses: characters.ResponseTemplates{
+			ErrorResponse:          "oops",
+			ForbiddenTopicResponse: "not financial advice",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		wantReply   string
+		wantFetched int
+	}{
+		{name: "forbidden topic is deflected", content: "Should I buy now?", wantReply: "not financial advice"},
+		{name: "normal message proceeds", content: "gm, what's new?", wantReply: "oops", wantFetched: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			social := &fakeSocial{}
+			// The stakeholder lookup fails so a message that proceeds stops at the first step
+			stakeholders := &fakeStakeholders{err: errStakeholderUnavailable}
+			agent := &Agent{
+				character:    character,
+				logger:       logger.GetLogger(),
+				stakeholders: stakeholders,
+				tokenManager: fakeTokens{},
+				socialClient: social,
+				ctx:          context.Background(),
+			}
+
+			agent.processMessage(&SocialMessage{Platform: "twitter", FromUser: "alice", Content: tt.content})
+
+			if len(social.sent) != 1 || social.sent[0].Content != tt.wantReply {
+				t.Errorf("sent %+v, want a single %q reply", social.sent, tt.wantReply)
+			}
+			if len(stakeholders.fetched) != tt.wantFetched {
+				t.Errorf("fetched %d stakeholders, want %d", len(stakeholders.fetched), tt.wantFetched)
+			}
+		})
+	}
+}
+
+func TestProcessMessageStopsWhenMoreInfoNeeded(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldGenerateAction: true,
+			Actions: []ProcessedAction{
+				{ActionName: "balance", ActionType: "chain"},
+				{ActionName: "transfer", ActionType: "chain"},
+			},
+		}),
+		`{"more_info_needed": true, "reply_message": "Which address?"}`,
+	)}
+	social := &fakeSocial{}
+	balance := &fakeAction{name: "balance", typ: "chain"}
+	transfer := &fakeAction{name: "transfer", typ: "chain"}
+	agent := newPipelineAgent(t, client, social, balance, transfer)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "Check my balance and pay Bob"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	if len(balance.executed) != 0 || len(transfer.executed) != 0 {
+		t.Errorf("actions ran (balance %d, transfer %d), want none until the user answers", len(balance.executed), len(transfer.executed))
+	}
+	if len(client.requests) != 2 {
+		t.Errorf("made %d LLM calls, want no parameters generated after the question", len(client.requests))
+	}
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "Which address?" {
+		t.Errorf("sent %q, want only the question", sent)
+	}
+	if _, ok := agent.sessions.takeClarification("telegram:alice", time.Now()); !ok {
+		t.Error("the paused action was not recorded in the session")
+	}
+}
+
+func TestClarificationQuestion(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		want   string
+	}{
+		{name: "reply_message", params: map[string]interface{}{"reply_message": "Which address?"}, want: "Which address?"},
+		{name: "legacy rely_message", params: map[string]interface{}{"rely_message": "Which chain?"}, want: "Which chain?"},
+		{name: "reply_message wins", params: map[string]interface{}{"reply_message": "Which address?", "rely_message": "Which chain?"}, want: "Which address?"},
+		{name: "no question", params: map[string]interface{}{"more_info_needed": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clarificationQuestion(tt.params); got != tt.want {
+				t.Errorf("clarificationQuestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessMessageClarificationFollowsStyleAndModeration(t *testing.T) {
+	tests := []struct {
+		name        string
+		question    string
+		fallback    string
+		wantSent    []string
+		wantPending bool
+	}{
+		{name: "styled question", question: "Which address? 🔥", wantSent: []string{"Which address?"}, wantPending: true},
+		{name: "flagged question is replaced", question: "What is your private key?", fallback: "Let's talk about something else.", wantSent: []string{"Let's talk about something else."}},
+		{name: "flagged question is blocked", question: "What is your private key?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := json.Marshal(map[string]interface{}{"more_info_needed": true, "reply_message": tt.question})
+			if err != nil {
+				t.Fatalf("failed to encode parameters: %v", err)
+			}
+			client := &fakeLLM{respond: replies(t,
+				analysis(t, ProcessedMessage{
+					ShouldGenerateAction: true,
+					Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+				}),
+				string(params),
+			)}
+			social := &fakeSocial{}
+			agent := newPipelineAgent(t, client, social, &fakeAction{name: "balance", typ: "chain"})
+			agent.style = newStyleEnforcer(characters.StyleRules{NoEmojis: true, OnViolation: characters.StyleViolationTrim})
+			agent.moderation = newContentFilter([]Moderator{&fakeModerator{flag: "What is your private key?"}}, tt.fallback)
+
+			if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What's my balance?"}); err != nil {
+				t.Fatalf("processMessage() error = %v", err)
+			}
+
+			if sent := social.contents(); strings.Join(sent, "|") != strings.Join(tt.wantSent, "|") {
+				t.Errorf("sent %q, want %q", sent, tt.wantSent)
+			}
+			if _, ok := agent.sessions.takeClarification("telegram:alice", time.Now()); ok != tt.wantPending {
+				t.Errorf("action waiting for a clarification = %v, want %v", ok, tt.wantPending)
+			}
+		})
+	}
+}
+
+// stoppablePlugin records that it was stopped
+type stoppablePlugin struct {
+	fakePlugin
+	stopped bool
+}
+
+func (p *stoppablePlugin) Stop(context.Context) error {
+	p.stopped = true
+	return nil
+}
+
+func TestAgentStartAndShutdown(t *testing.T) {
+	character := &characters.Character{
+		Name:             "Tester",
+		PriorityAccounts: []characters.Account{{ID: "owner", Platform: "telegram"}},
+		Responses:        characters.ResponseTemplates{Greeting: "gm"},
+	}
+	stakeholders := &fakeStakeholders{}
+	social := &monitoringSocial{messages: make(chan SocialMessage)}
+	plugin := &stoppablePlugin{}
+	registry := plugins.NewPluginRegistry()
+	if err := registry.Register(plugin); err != nil {
+		t.Fatalf("failed to register plugin: %v", err)
+	}
+
+	agent, err := NewAgent(AgentConfig{
+		ID:              uuid.New(),
+		Character:       character,
+		LLMClient:       &fakeLLM{},
+		Model:           "test-model",
+		Stakeholders:    stakeholders,
+		TokenManager:    fakeTokens{},
+		SocialClient:    social,
+		PromptTemplates: testTemplates(),
+		PluginRegistry:  registry,
+	})
+	if err != nil {
+		t.Fatalf("NewAgent() error = %v", err)
+	}
+
+	if err := agent.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if len(stakeholders.fetched) != 1 || stakeholders.fetched[0] != "owner" {
+		t.Errorf("registered stakeholders %v, want the priority account", stakeholders.fetched)
+	}
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "gm" {
+		t.Errorf("sent %q, want the greeting", sent)
+	}
+	if platform := social.sent[0].Platform; platform != "twitter" {
+		t.Errorf("greeting platform = %q, want twitter", platform)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := agent.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !social.stopped.Load() {
+		t.Error("the social monitor is still running after Shutdown()")
+	}
+	if !plugin.stopped {
+		t.Error("the plugin was not stopped")
+	}
+}
+
+func TestGetCurrentStateListsPlugins(t *testing.T) {
+	balance := &fakeAction{name: "balance", typ: "chain"}
+	agent := newPipelineAgent(t, &fakeLLM{}, &fakeSocial{}, balance)
+
+	state := agent.getCurrentState()
+	if len(state.AvailablePlugins) != 1 || state.AvailablePlugins[0].Name() != "test" {
+		t.Errorf("available plugins = %v, want the registered plugin", state.AvailablePlugins)
+	}
+	if len(state.AvailableActions) != 1 || state.AvailableActions[0] != balance {
+		t.Errorf("available actions = %v, want the plugin action", state.AvailableActions)
+	}
+
+	agent.pluginRegistry = nil
+	if state := agent.getCurrentState(); len(state.AvailablePlugins) != 0 {
+		t.Errorf("available plugins without a registry = %v, want none", state.AvailablePlugins)
+	}
+}
+
+func TestProcessMessageCarriesOneTraceID(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldReply:          true,
+			ResponseMsg:          "Here is your balance.",
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+		}),
+		`{"address": "0xabc"}`,
+	)}
+	balance := &fakeAction{name: "balance", typ: "chain"}
+	agent := newPipelineAgent(t, client, &fakeSocial{}, balance)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "Check my balance"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	if len(client.traces) != 2 || len(balance.traces) != 1 {
+		t.Fatalf("traced %d LLM calls and %d action runs, want 2 and 1", len(client.traces), len(balance.traces))
+	}
+	traceID := client.traces[0]
+	if traceID == "" {
+		t.Fatal("LLM call carries no trace ID")
+	}
+	for _, got := range append(client.traces[1:], balance.traces...) {
+		if got != traceID {
+			t.Errorf("stage trace ID = %q, want %q across the pipeline", got, traceID)
+		}
+	}
+}
+
+func testCharacter() *characters.Character {
+	return &characters.Character{
+		Name:      "Tester",
+		Responses: characters.ResponseTemplates{ErrorResponse: "error"},
+	}
+}
+
+// newHarnessAgent builds an agent through NewAgent with every dependency
+// faked, so a message can be replayed through the whole pipeline
+func newHarnessAgent(t *testing.T, client llm.Client, social SocialClient, pluginActions ...actions.IAction) *Agent {
+	t.Helper()
+	registry := plugins.NewPluginRegistry()
+	if len(pluginActions) > 0 {
+		if err := registry.Register(&fakePlugin{actions: pluginActions}); err != nil {
+			t.Fatalf("failed to register plugin: %v", err)
+		}
+	}
+
+	agent, err := NewAgent(AgentConfig{
+		ID:              uuid.New(),
+		Character:       testCharacter(),
+		LLMClient:       client,
+		Model:           "test-model",
+		Stakeholders:    &fakeStakeholders{},
+		TokenManager:    fakeTokens{},
+		SocialClient:    social,
+		PromptTemplates: testTemplates(),
+		PluginRegistry:  registry,
+	})
+	if err != nil {
+		t.Fatalf("NewAgent() error = %v", err)
+	}
+	t.Cleanup(agent.cancel)
+	return agent
+}
+
+// lastPrompt is the final message of a completion request
+func lastPrompt(request llm.CompletionRequest) string {
+	if len(request.Messages) == 0 {
+		return ""
+	}
+	return request.Messages[len(request.Messages)-1].Content
+}
+
+func TestProcessMessageReplies(t *testing.T) {
+	client := &fakeLLM{respond: replies(t, analysis(t, ProcessedMessage{
+		ShouldReply: true,
+		ResponseMsg: "The answer is 42.",
+	}))}
+	social := &fakeSocial{}
+	agent := newHarnessAgent(t, client, social)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What is the answer?"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	if len(client.requests) != 1 {
+		t.Fatalf("made %d LLM calls, want only the analysis", len(client.requests))
+	}
+	if prompt := lastPrompt(client.requests[0]); !strings.Contains(prompt, "What is the answer?") {
+		t.Errorf("analysis prompt = %q, want it to carry the message", prompt)
+	}
+	if system := client.requests[0].Messages[0].Content; !strings.Contains(system, "You are Tester.") {
+		t.Errorf("system prompt = %q, want the character", system)
+	}
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "The answer is 42." {
+		t.Errorf("sent %q, want the analysed reply", sent)
+	}
+	if history := agent.stakeholders.(*fakeStakeholders).history["telegram:alice"]; len(history) != 2 {
+		t.Errorf("history = %q, want the message and the reply", history)
+	}
+}
+
+func TestProcessMessageSendsThrottledResponse(t *testing.T) {
+	client := &fakeLLM{respond: replies(t, analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm alice"}))}
+	social := &fakeSocial{}
+	agent := newHarnessAgent(t, client, social)
+	agent.character.Responses.ThrottledResponse = "Easy, darling."
+	agent.replyGuard = newReplyGuard(1, time.Minute)
+
+	for _, content := range []string{"gm", "gm?", "gm??"} {
+		if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: content}); err != nil {
+			t.Fatalf("processMessage() error = %v", err)
+		}
+	}
+
+	if len(client.requests) != 1 {
+		t.Errorf("made %d LLM calls, want none for the throttled messages", len(client.requests))
+	}
+	// The throttled response is sent once per window, not per message
+	if sent := social.contents(); strings.Join(sent, "|") != "gm alice|Easy, darling." {
+		t.Errorf("sent %q, want the reply then a single throttled response", sent)
+	}
+}
+
+func TestProcessMessagePriorityAccountIsNotThrottled(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm owner"}),
+		analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "still here"}),
+	)}
+	social := &fakeSocial{}
+	agent := newHarnessAgent(t, client, social)
+	agent.character.PriorityAccounts = []characters.Account{{ID: "owner", Platform: "telegram"}}
+	agent.replyGuard = newReplyGuard(1, time.Minute)
+
+	for _, content := range []string{"gm", "status?"} {
+		if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "owner", Content: content}); err != nil {
+			t.Fatalf("processMessage() error = %v", err)
+		}
+	}
+
+	if sent := social.contents(); strings.Join(sent, "|") != "gm owner|still here" {
+		t.Errorf("sent %q, want both messages answered", sent)
+	}
+}
+
+func TestProcessMessageExecutesAction(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldReply:          true,
+			ResponseMsg:          "Checking the balance.",
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+		}),
+		`{"address": "0xabc"}`,
+	)}
+	social := &fakeSocial{}
+	balance := &fakeAction{name: "balance", typ: "chain"}
+	agent := newHarnessAgent(t, client, social, balance)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What's the balance of 0xabc?"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	if len(client.requests) != 2 {
+		t.Fatalf("made %d LLM calls, want the analysis and the action parameters", len(client.requests))
+	}
+	if prompt := lastPrompt(client.requests[1]); !strings.Contains(prompt, "balance: What's the balance of 0xabc?") {
+		t.Errorf("action prompt = %q, want the action and the message", prompt)
+	}
+	if len(balance.executed) != 1 || balance.executed[0]["address"] != "0xabc" {
+		t.Fatalf("action ran with %v, want once with address 0xabc", balance.executed)
+	}
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "Checking the balance." {
+		t.Errorf("sent %q, want the analysed reply", sent)
+	}
+}
+
+func TestProcessMessageReportsFailedAction(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldReply:          true,
+			ResponseMsg:          "Checking the balance.",
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+		}),
+		`{"address": "0xabc"}`,
+	)}
+	social := &fakeSocial{}
+	balance := &fakeAction{name: "balance", typ: "chain", err: errors.New("rpc down")}
+	agent := newHarnessAgent(t, client, social, balance)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What's the balance of 0xabc?"}); err == nil {
+		t.Fatal("processMessage() error = nil, want the action error")
+	}
+
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "error" {
+		t.Errorf("sent %q, want only the error response", sent)
+	}
+}
+
+func TestProcessMessageWelcomesFirstContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		welcome string
+		want    []string
+	}{
+		{name: "welcome configured", welcome: "Welcome aboard!", want: []string{"Welcome aboard!", "gm alice", "gm again"}},
+		{name: "no welcome", want: []string{"gm alice", "gm again"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: replies(t,
+				analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm alice"}),
+				analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm again"}),
+			)}
+			social := &fakeSocial{}
+			agent := newHarnessAgent(t, client, social)
+			agent.character.Responses.Welcome = tt.welcome
+
+			for _, content := range []string{"gm", "gm again"} {
+				if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: content}); err != nil {
+					t.Fatalf("processMessage() error = %v", err)
+				}
+			}
+
+			// Only the first message of alice is a first contact
+			if sent := social.contents(); strings.Join(sent, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("sent %q, want %q", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessMessageValidatesParams(t *testing.T) {
+	schema := &actions.ParameterSchema{
+		Properties: map[string]actions.Parameter{"address": {Type: actions.ParameterString}},
+		Required:   []string{"address"},
+	}
+	tests := []struct {
+		name     string
+		params   string
+		validate func(map[string]interface{}) error
+	}{
+		{
+			name:   "action rejects the params",
+			params: `{"address": "not an address"}`,
+			validate: func(map[string]interface{}) error {
+				return errors.New("invalid address format")
+			},
+		},
+		{name: "params break the schema", params: `{"address": 42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: replies(t,
+				analysis(t, ProcessedMessage{
+					ShouldReply:          true,
+					ResponseMsg:          "Checking the balance.",
+					ShouldGenerateAction: true,
+					Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+				}),
+				tt.params,
+			)}
+			social := &fakeSocial{}
+			balance := &fakeAction{name: "balance", typ: "chain", validate: tt.validate}
+			agent := newHarnessAgent(t, client, social, &schemaAction{balance, schema})
+			agent.character.Responses.InvalidParamsResponse = "Tell me more, darling?"
+
+			if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What's the balance?"}); err != nil {
+				t.Fatalf("processMessage() error = %v", err)
+			}
+
+			if len(balance.executed) != 0 {
+				t.Errorf("action ran with invalid params %v", balance.executed)
+			}
+			sent := social.contents()
+			if len(sent) != 1 || sent[0] != "Tell me more, darling?" {
+				t.Errorf("sent %q, want only the persona question", sent)
+			}
+			if _, ok := agent.sessions.takeClarification("telegram:alice", time.Now()); !ok {
+				t.Error("action is not waiting for a clarification")
+			}
+		})
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/characters"
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
 
@@ -30,14 +33,24 @@ const (
 	PurposeConcrete    StepPurpose = "concrete"
 )
 
+const (
+	defaultEarlyExitConfidence = 0.9
+	defaultMaxChainLength      = 3
+)
+
 type CognitiveEngine struct {
-	llm             llm.Client
-	model           string
-	maxSteps        int
-	minConfidence   float64
-	character       *characters.Character
-	logger          *zap.SugaredLogger
-	promptTemplates *conf.PromptTemplates
+	llm           llm.Client
+	model         string
+	maxSteps      int
+	minConfidence float64
+	// earlyExitConfidence ends the chain once an analysis or refinement step reaches it
+	earlyExitConfidence float64
+	character           *characters.Character
+	logger              *zap.SugaredLogger
+	// promptTemplates is swapped atomically when templates are hot-reloaded
+	promptTemplates atomic.Pointer[conf.PromptTemplates]
+	rewardModel     *RewardModel
+	memory          memory.Manager
 }
 
 type CognitiveConfig struct {
@@ -58,6 +71,20 @@ type ThoughtChain struct {
 	Timestamp       time.Time
 }
 
+// conclusion returns the step the plan is parsed from: the last concrete
+// step, or the last step when the chain ended before reaching one
+func (c *ThoughtChain) conclusion() *ThoughtStep {
+	for i := len(c.Steps) - 1; i >= 0; i-- {
+		if c.Steps[i].Purpose == PurposeConcrete {
+			return c.Steps[i]
+		}
+	}
+	if len(c.Steps) == 0 {
+		return nil
+	}
+	return c.Steps[len(c.Steps)-1]
+}
+
 // ThoughtStep represents a single step in the reasoning process
 type ThoughtStep struct {
 	Type         string
@@ -73,21 +100,48 @@ type ThoughtStep struct {
 	Timestamp            time.Time
 }
 
+type thoughtObserverKey struct{}
+
+// withThoughtObserver returns a copy of ctx whose thought chains call observe
+// with each step as soon as it is generated
+func withThoughtObserver(ctx context.Context, observe func(context.Context, *ThoughtStep)) context.Context {
+	return context.WithValue(ctx, thoughtObserverKey{}, observe)
+}
+
+// thoughtObserverFrom returns the step observer of ctx, nil when there is none
+func thoughtObserverFrom(ctx context.Context) func(context.Context, *ThoughtStep) {
+	observe, _ := ctx.Value(thoughtObserverKey{}).(func(context.Context, *ThoughtStep))
+	return observe
+}
+
 func NewCognitiveEngine(
 	llmClient llm.Client,
 	model string,
 	character *characters.Character,
 	promptTemplates *conf.PromptTemplates,
 ) *CognitiveEngine {
-	return &CognitiveEngine{
-		llm:             llmClient,
-		model:           model,
-		maxSteps:        3,
-		minConfidence:   0.7,
-		character:       character,
-		logger:          logger.GetLogger(),
-		promptTemplates: promptTemplates,
-	}
+	engine := &CognitiveEngine{
+		llm:                 llmClient,
+		model:               model,
+		maxSteps:            defaultMaxChainLength,
+		minConfidence:       0.7,
+		earlyExitConfidence: defaultEarlyExitConfidence,
+		character:           character,
+		logger:              logger.GetLogger(),
+		rewardModel:         NewRewardModel(),
+	}
+	engine.promptTemplates.Store(promptTemplates)
+	return engine
+}
+
+// templates returns the prompt templates currently in use
+func (e *CognitiveEngine) templates() *conf.PromptTemplates {
+	return e.promptTemplates.Load()
+}
+
+// SetPromptTemplates replaces the prompt templates used for new prompts
+func (e *CognitiveEngine) SetPromptTemplates(templates *conf.PromptTemplates) {
+	e.promptTemplates.Store(templates)
 }
 
 // GenerateThoughtChain creates a DeepSeek-style reasoning chain
@@ -97,16 +151,29 @@ func (e *CognitiveEngine) GenerateThoughtChain(
 	input interface{},
 	promptGenerator promptGeneratorFunc,
 ) (*ThoughtChain, error) {
-	e.logger.Info("Generating thought chain")
+	log := logger.FromContext(ctx)
+	log.Info("Generating thought chain")
 	chain := &ThoughtChain{
 		Steps:     make([]*ThoughtStep, 0),
 		Timestamp: time.Now(),
 	}
 
 	// Generate reasoning steps
+	concludeNext := false
 	for i := 0; i < e.maxSteps; i++ {
+		// Stop before spending more LLM calls on a cancelled request
+		select {
+		case <-ctx.Done():
+			log.Infow("Thought chain cancelled", "steps", len(chain.Steps), "error", ctx.Err())
+			return nil, ctx.Err()
+		default:
+		}
+
 		// Determine appropriate step purpose based on progress
 		purpose := e.determineStepPurpose(i)
+		if concludeNext {
+			purpose = PurposeConcrete
+		}
 
 		step, err := e.generateThoughtStep(ctx, state, chain, purpose, promptGenerator)
 		if err != nil {
@@ -124,26 +191,33 @@ func (e *CognitiveEngine) GenerateThoughtChain(
 			}
 		}
 
-		e.logger.Infof("Generated step: %d, %s", i, step.Content)
+		log.Infof("Generated step: %d, %s", i, step.Content)
 		chain.Steps = append(chain.Steps, step)
+		if observe := thoughtObserverFrom(ctx); observe != nil {
+			observe(ctx, step)
+		}
 
 		// Check if we need more steps
 		if e.isConclusive(chain) {
 			break
 		}
+		// A highly confident intermediate step skips straight to the
+		// concrete step, which the actions and tasks are parsed from
+		concludeNext = e.readyToConclude(chain)
 	}
 
 	return chain, nil
 }
 
-// determineStepPurpose decides appropriate purpose for current step
+// determineStepPurpose decides appropriate purpose for current step. Only the
+// last step of the chain is concrete, whatever its length.
 func (e *CognitiveEngine) determineStepPurpose(stepIndex int) StepPurpose {
+	if stepIndex >= e.maxSteps-1 {
+		return PurposeConcrete
+	}
 	if stepIndex == 0 {
 		return PurposeInitial
 	}
-	if stepIndex == e.maxSteps-1 {
-		return PurposeConcrete
-	}
 
 	totalSteps := float64(e.maxSteps)
 	progress := float64(stepIndex+1) / totalSteps
@@ -153,10 +227,8 @@ func (e *CognitiveEngine) determineStepPurpose(stepIndex int) StepPurpose {
 		return PurposeExploration
 	case progress < 0.5:
 		return PurposeAnalysis
-	case progress < 0.7:
-		return PurposeRefinement
 	default:
-		return PurposeConcrete
+		return PurposeRefinement
 	}
 }
 
@@ -208,17 +280,21 @@ func (e *CognitiveEngine) GenerateActions(
 		ctx,
 		state,
 		actionContext,
-		generateActionsPromptFunc(state, state.AvailableActions, e.promptTemplates),
+		generateActionsPromptFunc(state, state.AvailableActions, e.templates()),
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert thought chain to actions
-	actions, _ := convertThoughtChainToActions(chain)
+	plan, actions, err := convertThoughtChainToActions(chain, state.AvailableActions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert thought chain to actions: %w", err)
+	}
 
 	return &ActionGeneration{
 		Actions: actions,
+		Plan:    plan,
 		Chain:   chain,
 	}, nil
 }
@@ -231,23 +307,36 @@ func (e *CognitiveEngine) generateThoughtStep(
 	promptGenerator func(StepPurpose, []*ThoughtStep) string,
 ) (*ThoughtStep, error) {
 	prompt := promptGenerator(purpose, chain.Steps)
+	systemPrompt, err := buildSystemPrompt(state, nil, e.templates())
+	if err != nil {
+		return nil, err
+	}
 
-	response, err := e.llm.CreateCompletion(ctx, llm.CompletionRequest{
+	request := llm.CompletionRequest{
 		Model: e.model,
 		Messages: []llm.Message{
-			{Role: "system", Content: buildSystemPrompt(state, nil, e.promptTemplates)},
+			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: prompt},
 		},
-	})
+	}
+	// The concrete step produces the final plan, so ask for strict JSON
+	if purpose == PurposeConcrete {
+		request.ResponseFormat = llm.ResponseFormatJSONObject
+	}
+
+	completion, err := e.llm.CreateCompletionWithReasoning(ctx, request)
 	if err != nil {
 		return nil, err
 	}
+	response := completion.Content
 
 	return &ThoughtStep{
 		// Core reasoning content
-		Content:              extractThinkingContent(response),
+		Content:              thinkingContent(completion),
+		RawLLMOutput:         response,
 		Evidence:             extractEvidence(response),
 		Alternatives:         extractAlternatives(response),
+		Confidence:           calculateConfidence(response),
 		Purpose:              purpose,
 		ContributesToOutcome: e.doesStepContributeToOutcome(purpose, chain),
 	}, nil
@@ -271,10 +360,23 @@ func (e *CognitiveEngine) isConclusive(chain *ThoughtChain) bool {
 	// 	return false
 	// }
 
-	// Verify last step completion
-	lastStep := chain.Steps[len(chain.Steps)-1]
+	// Verify last step completion; the chain never ends before a concrete step
+	return chain.Steps[len(chain.Steps)-1].Purpose == PurposeConcrete
+}
 
-	return lastStep.Purpose == PurposeConcrete
+// readyToConclude reports whether the last step is an analysis or refinement
+// confident enough to skip the remaining intermediate steps
+func (e *CognitiveEngine) readyToConclude(chain *ThoughtChain) bool {
+	if len(chain.Steps) == 0 || e.earlyExitConfidence <= 0 {
+		return false
+	}
+
+	lastStep := chain.Steps[len(chain.Steps)-1]
+	switch lastStep.Purpose {
+	case PurposeAnalysis, PurposeRefinement:
+		return lastStep.Confidence >= e.earlyExitConfidence
+	}
+	return false
 }
 
 // Helper functions
@@ -382,14 +484,24 @@ func (e *CognitiveEngine) processMessage(
 	msg *SocialMessage,
 	stakeholder *Stakeholder,
 ) (*ProcessedMessage, error) {
-	prompt := buildMessagePrompt(state, msg, stakeholder, e.promptTemplates)
+	recalled := e.recallMemories(ctx, msg, stakeholder)
+	templates := e.templates()
+	prompt, err := buildMessagePrompt(state, msg, stakeholder, recalled, templates)
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt, err := buildSystemPrompt(state, stakeholder, templates)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get LLM's analysis
 	response, err := e.llm.CreateCompletion(ctx, llm.CompletionRequest{
 		Model: e.model,
 		Messages: []llm.Message{
 			{
 				Role:    "system",
-				Content: buildSystemPrompt(state, stakeholder, e.promptTemplates),
+				Content: systemPrompt,
 			},
 			{
 				Role:    "user",
@@ -412,14 +524,28 @@ func (e *CognitiveEngine) generateActionParameters(
 	stakeholder *Stakeholder,
 	action actions.IAction,
 ) (map[string]interface{}, error) {
-	prompt := generateActionParametersPrompt(state, msg, stakeholder, action, e.promptTemplates)
-	response, err := e.llm.CreateCompletion(ctx, llm.CompletionRequest{
+	templates := e.templates()
+	prompt, err := generateActionParametersPrompt(state, msg, stakeholder, action, templates)
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt, err := buildSystemPrompt(state, stakeholder, templates)
+	if err != nil {
+		return nil, err
+	}
+
+	request := llm.CompletionRequest{
 		Model: e.model,
 		Messages: []llm.Message{
-			{Role: "system", Content: buildSystemPrompt(state, stakeholder, e.promptTemplates)},
+			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: prompt},
 		},
-	})
+	}
+	schemaAction, hasSchema := action.(actions.SchemaAction)
+	if hasSchema && schemaAction.ParametersSchema() != nil {
+		request.ResponseFormat = llm.ResponseFormatJSONObject
+	}
+	response, err := e.llm.CreateCompletion(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -428,10 +554,28 @@ func (e *CognitiveEngine) generateActionParameters(
 	if err != nil {
 		return nil, err
 	}
+
+	// A request for more information carries no parameters to check
+	if moreInfoNeeded, _ := parsedResponse["more_info_needed"].(bool); hasSchema && !moreInfoNeeded {
+		if schema := schemaAction.ParametersSchema(); schema != nil {
+			if err := schema.Validate(parsedResponse); err != nil {
+				return nil, fmt.Errorf("generated parameters of %s: %w", action.Name(), err)
+			}
+		}
+	}
 	return parsedResponse, nil
 }
 
 // Helper functions
+// thinkingContent prefers the dedicated reasoning field of reasoning models and
+// falls back to parsing <think> tags from the response
+func thinkingContent(completion *llm.Completion) string {
+	if reasoning := strings.TrimSpace(completion.ReasoningContent); reasoning != "" {
+		return reasoning
+	}
+	return extractThinkingContent(completion.Content)
+}
+
 // ExtractThinkingContent extracts the core reasoning content from an LLM response.
 func extractThinkingContent(response string) string {
 	// Define a regex pattern to capture content within <think> tags
@@ -468,10 +612,32 @@ func extractAnwser(response string) []string {
 	return nil
 }
 
+// calculateConfidence reads the self-reported confidence from a <confidence> tag
+// or a "Confidence: 0.8" line, returning 0 when none is present
 func calculateConfidence(response string) float64 {
-	// TODO: implement me
-	// Calculate confidence based on response
-	// Implementation details...
+	patterns := []*regexp.Regexp{
+		regexp.MustCompile(`(?s)<confidence>\s*([0-9]*\.?[0-9]+)\s*</confidence>`),
+		regexp.MustCompile(`(?i)confidence\W{0,4}\s*([0-9]*\.?[0-9]+)`),
+	}
+
+	for _, re := range patterns {
+		matches := re.FindStringSubmatch(response)
+		if len(matches) < 2 {
+			continue
+		}
+		confidence, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			continue
+		}
+		// Accept percentages as well as ratios
+		if confidence > 1 && confidence <= 100 {
+			confidence /= 100
+		}
+		if confidence >= 0 && confidence <= 1 {
+			return confidence
+		}
+	}
+
 	return 0.0
 }
 
@@ -0,0 +1,318 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// fakeLLM answers every completion with respond and records the requests
+type fakeLLM struct {
+	llm.Client
+	respond  func(request llm.CompletionRequest) string
+	reason   func(request llm.CompletionRequest) string
+	embed    func(input string) []float32
+	embedErr error
+	mu       sync.Mutex
+	requests []llm.CompletionRequest
+	traces   []string
+}
+
+func (f *fakeLLM) CreateCompletion(ctx context.Context, request llm.CompletionRequest) (string, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, request)
+	f.traces = append(f.traces, logger.TraceID(ctx))
+	f.mu.Unlock()
+	return f.respond(request), nil
+}
+
+// CreateCompletionWithReasoning adds the reason output as the separate reasoning field
+func (f *fakeLLM) CreateCompletionWithReasoning(ctx context.Context, request llm.CompletionRequest) (*llm.Completion, error) {
+	content, err := f.CreateCompletion(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	completion := &llm.Completion{Content: content}
+	if f.reason != nil {
+		completion.ReasoningContent = f.reason(request)
+	}
+	return completion, nil
+}
+
+func TestCalculateConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     float64
+	}{
+		{name: "tag", response: "Done.<confidence>0.85</confidence>", want: 0.85},
+		{name: "line", response: "The answer is clear.\nConfidence: 0.9", want: 0.9},
+		{name: "percentage", response: "confidence: 75", want: 0.75},
+		{name: "missing", response: "No idea.", want: 0},
+		{name: "out of range", response: "Confidence: 250", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateConfidence(tt.response); got != tt.want {
+				t.Errorf("calculateConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThinkingContent(t *testing.T) {
+	tests := []struct {
+		name       string
+		completion *llm.Completion
+		want       string
+	}{
+		{
+			name:       "inline tags",
+			completion: &llm.Completion{Content: "<think>weigh the options</think>Go with A."},
+			want:       "weigh the options",
+		},
+		{
+			name:       "separate field",
+			completion: &llm.Completion{Content: "Go with A.", ReasoningContent: "  compare A and B  "},
+			want:       "compare A and B",
+		},
+		{
+			name: "field wins over tags",
+			completion: &llm.Completion{
+				Content:          "<think>tag reasoning</think>Go with A.",
+				ReasoningContent: "field reasoning",
+			},
+			want: "field reasoning",
+		},
+		{
+			name:       "blank field falls back to tags",
+			completion: &llm.Completion{Content: "<think>tag reasoning</think>Go with A.", ReasoningContent: " \n"},
+			want:       "tag reasoning",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thinkingContent(tt.completion); got != tt.want {
+				t.Errorf("thinkingContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConclusive(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []*ThoughtStep
+		want  bool
+	}{
+		{name: "no steps", want: false},
+		{name: "concrete step", steps: []*ThoughtStep{{Purpose: PurposeInitial}, {Purpose: PurposeConcrete}}, want: true},
+		{name: "confident refinement", steps: []*ThoughtStep{{Purpose: PurposeRefinement, Confidence: 0.99}}, want: false},
+		{name: "confident analysis", steps: []*ThoughtStep{{Purpose: PurposeAnalysis, Confidence: 1}}, want: false},
+	}
+
+	engine := NewCognitiveEngine(nil, "test-model", nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.isConclusive(&ThoughtChain{Steps: tt.steps}); got != tt.want {
+				t.Errorf("isConclusive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadyToConclude(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		step      *ThoughtStep
+		want      bool
+	}{
+		{name: "confident refinement", threshold: 0.9, step: &ThoughtStep{Purpose: PurposeRefinement, Confidence: 0.95}, want: true},
+		{name: "confident analysis", threshold: 0.9, step: &ThoughtStep{Purpose: PurposeAnalysis, Confidence: 0.9}, want: true},
+		{name: "unsure refinement", threshold: 0.9, step: &ThoughtStep{Purpose: PurposeRefinement, Confidence: 0.5}, want: false},
+		{name: "confident exploration", threshold: 0.9, step: &ThoughtStep{Purpose: PurposeExploration, Confidence: 1}, want: false},
+		{name: "disabled", threshold: 0, step: &ThoughtStep{Purpose: PurposeRefinement, Confidence: 1}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewCognitiveEngine(nil, "test-model", nil, nil)
+			engine.earlyExitConfidence = tt.threshold
+			chain := &ThoughtChain{Steps: []*ThoughtStep{tt.step}}
+			if got := engine.readyToConclude(chain); got != tt.want {
+				t.Errorf("readyToConclude() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateThoughtChainExitsEarly(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		wantSteps int
+	}{
+		{name: "confident analysis skips to the concrete step", response: "Therefore the plan holds because the data agrees. Confidence: 0.95", wantSteps: 3},
+		{name: "unsure steps run to the concrete step", response: "Therefore the plan may hold because the data is thin. Confidence: 0.5", wantSteps: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: func(llm.CompletionRequest) string { return tt.response }}
+			engine := NewCognitiveEngine(client, "test-model", nil, &conf.PromptTemplates{})
+			engine.maxSteps = 5
+
+			chain, err := engine.GenerateThoughtChain(
+				context.Background(),
+				&SystemState{Character: &characters.Character{Name: "Tester"}},
+				nil,
+				func(purpose StepPurpose, _ []*ThoughtStep) string { return string(purpose) },
+			)
+			if err != nil {
+				t.Fatalf("GenerateThoughtChain() error = %v", err)
+			}
+			if len(chain.Steps) != tt.wantSteps {
+				t.Errorf("chain has %d steps, want %d", len(chain.Steps), tt.wantSteps)
+			}
+			if len(client.requests) != tt.wantSteps {
+				t.Errorf("made %d LLM calls, want %d", len(client.requests), tt.wantSteps)
+			}
+			if last := chain.Steps[len(chain.Steps)-1]; last.Purpose != PurposeConcrete {
+				t.Errorf("last step purpose = %s, want %s", last.Purpose, PurposeConcrete)
+			}
+		})
+	}
+}
+
+func TestDetermineStepPurpose(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxSteps int
+		want     []StepPurpose
+	}{
+		{name: "single step", maxSteps: 1, want: []StepPurpose{PurposeConcrete}},
+		{name: "two steps", maxSteps: 2, want: []StepPurpose{PurposeInitial, PurposeConcrete}},
+		{name: "default length", maxSteps: 3, want: []StepPurpose{PurposeInitial, PurposeRefinement, PurposeConcrete}},
+		{name: "five steps", maxSteps: 5, want: []StepPurpose{PurposeInitial, PurposeAnalysis, PurposeRefinement, PurposeRefinement, PurposeConcrete}},
+		{
+			name:     "eight steps",
+			maxSteps: 8,
+			want: []StepPurpose{
+				PurposeInitial, PurposeExploration, PurposeAnalysis, PurposeRefinement,
+				PurposeRefinement, PurposeRefinement, PurposeRefinement, PurposeConcrete,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewCognitiveEngine(nil, "test-model", nil, nil)
+			engine.maxSteps = tt.maxSteps
+			for i, want := range tt.want {
+				if got := engine.determineStepPurpose(i); got != want {
+					t.Errorf("determineStepPurpose(%d) = %s, want %s", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateThoughtChainUsesMaxChainLength(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxChainLength int
+		wantSteps      int
+	}{
+		{name: "configured length", maxChainLength: 6, wantSteps: 6},
+		{name: "single step", maxChainLength: 1, wantSteps: 1},
+		{name: "default length", wantSteps: defaultMaxChainLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: func(llm.CompletionRequest) string { return "The data is thin. Confidence: 0.5" }}
+			config := AgentConfig{
+				ID:              uuid.New(),
+				Character:       testCharacter(),
+				LLMClient:       client,
+				Model:           "test-model",
+				Stakeholders:    &fakeStakeholders{},
+				TokenManager:    fakeTokens{},
+				SocialClient:    &fakeSocial{},
+				PromptTemplates: testTemplates(),
+			}
+			config.Inference.MaxChainLength = tt.maxChainLength
+			agent, err := NewAgent(config)
+			if err != nil {
+				t.Fatalf("NewAgent() error = %v", err)
+			}
+			t.Cleanup(agent.cancel)
+
+			chain, err := agent.cognitive.GenerateThoughtChain(
+				context.Background(),
+				&SystemState{Character: agent.character},
+				nil,
+				func(purpose StepPurpose, _ []*ThoughtStep) string { return string(purpose) },
+			)
+			if err != nil {
+				t.Fatalf("GenerateThoughtChain() error = %v", err)
+			}
+			if len(chain.Steps) != tt.wantSteps {
+				t.Errorf("chain has %d steps, want %d", len(chain.Steps), tt.wantSteps)
+			}
+			if last := chain.Steps[len(chain.Steps)-1]; last.Purpose != PurposeConcrete {
+				t.Errorf("last step purpose = %s, want %s", last.Purpose, PurposeConcrete)
+			}
+		})
+	}
+}
+
+func TestGenerateThoughtChainStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &fakeLLM{respond: func(llm.CompletionRequest) string {
+		// The request is cancelled while the first step is generated
+		cancel()
+		return "The data is thin. Confidence: 0.5"
+	}}
+	engine := NewCognitiveEngine(client, "test-model", nil, &conf.PromptTemplates{})
+	engine.maxSteps = 5
+
+	chain, err := engine.GenerateThoughtChain(
+		ctx,
+		&SystemState{Character: &characters.Character{Name: "Tester"}},
+		nil,
+		func(purpose StepPurpose, _ []*ThoughtStep) string { return string(purpose) },
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateThoughtChain() = %v, %v, want context.Canceled", chain, err)
+	}
+	if len(client.requests) != 1 {
+		t.Errorf("made %d LLM calls, want the loop to stop after the first step", len(client.requests))
+	}
+}
+
+func (f *fakeLLM) CreateEmbeddings(_ context.Context, inputs []string) ([][]float32, error) {
+	if f.embedErr != nil {
+		return nil, f.embedErr
+	}
+	if f.embed == nil {
+		return nil, llm.ErrEmbeddingsNotConfigured
+	}
+	embeddings := make([][]float32, len(inputs))
+	for i, input := range inputs {
+		embeddings[i] = f.embed(input)
+	}
+	return embeddings, nil
+}
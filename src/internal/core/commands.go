@@ -0,0 +1,101 @@
+package core
+
+import (
+	"strings"
+)
+
+const defaultCommandPrefix = "/"
+
+// CommandRoute maps a chat command to a plugin action, e.g. "/analyze 0x..."
+// to fetch_transaction with the address param pre-filled
+type CommandRoute struct {
+	Command string
+	// Action is the name of the plugin action the command runs
+	Action string
+	// Args names the params filled by the command arguments in order; the last
+	// one takes the rest of the arguments
+	Args []string
+	// Params are fixed params of the action
+	Params map[string]interface{}
+}
+
+// routedCommand is a command matched to its action
+type routedCommand struct {
+	action string
+	params map[string]interface{}
+}
+
+// commandRouter routes commands of every platform to plugin actions
+type commandRouter struct {
+	prefix string
+	routes map[string]CommandRoute
+}
+
+func newCommandRouter(prefix string, routes []CommandRoute) *commandRouter {
+	if prefix == "" {
+		prefix = defaultCommandPrefix
+	}
+	r := &commandRouter{
+		prefix: prefix,
+		routes: make(map[string]CommandRoute, len(routes)),
+	}
+	for _, route := range routes {
+		r.routes[strings.ToLower(route.Command)] = route
+	}
+	return r
+}
+
+// parseCommand splits "/name@bot arg1 arg2" into the lowercase command name
+// and its arguments
+func parseCommand(prefix, content string) (string, []string, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, prefix) {
+		return "", nil, false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(content, prefix))
+	if len(fields) == 0 {
+		return "", nil, false
+	}
+	// Telegram appends the bot name in groups, e.g. /analyze@data_bot
+	name, _, _ := strings.Cut(fields[0], "@")
+	if name == "" {
+		return "", nil, false
+	}
+	return strings.ToLower(name), fields[1:], true
+}
+
+// route matches the message content to a configured command
+func (r *commandRouter) route(content string) (*routedCommand, bool) {
+	if r == nil || len(r.routes) == 0 {
+		return nil, false
+	}
+	name, args, ok := parseCommand(r.prefix, content)
+	if !ok {
+		return nil, false
+	}
+	route, ok := r.routes[name]
+	if !ok {
+		return nil, false
+	}
+
+	params := make(map[string]interface{}, len(route.Params)+len(route.Args))
+	for key, value := range route.Params {
+		params[key] = value
+	}
+	for i, param := range route.Args {
+		if i >= len(args) {
+			break
+		}
+		if i == len(route.Args)-1 {
+			params[param] = strings.Join(args[i:], " ")
+			break
+		}
+		params[param] = args[i]
+	}
+
+	return &routedCommand{
+		action: route.Action,
+		params: params,
+	}, true
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		content  string
+		wantName string
+		wantArgs []string
+		wantOK   bool
+	}{
+		{name: "with arguments", prefix: "/", content: "/analyze 0xabc last week", wantName: "analyze", wantArgs: []string{"0xabc", "last", "week"}, wantOK: true},
+		{name: "without arguments", prefix: "/", content: "  /help  ", wantName: "help", wantArgs: []string{}, wantOK: true},
+		{name: "telegram group mention", prefix: "/", content: "/Analyze@data_bot 0xabc", wantName: "analyze", wantArgs: []string{"0xabc"}, wantOK: true},
+		{name: "custom prefix", prefix: "!", content: "!tx 0x1", wantName: "tx", wantArgs: []string{"0x1"}, wantOK: true},
+		{name: "plain message", prefix: "/", content: "analyze 0xabc"},
+		{name: "prefix only", prefix: "/", content: "/"},
+		{name: "bot name only", prefix: "/", content: "/@data_bot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, ok := parseCommand(tt.prefix, tt.content)
+			if ok != tt.wantOK {
+				t.Fatalf("parseCommand() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("parseCommand() = %q, %q, want %q, %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCommandRouterRoute(t *testing.T) {
+	router := newCommandRouter("", []CommandRoute{
+		{Command: "analyze", Action: "fetch_transaction", Args: []string{"address", "message"}},
+		{Command: "Profile", Action: "profile_address", Args: []string{"address"}, Params: map[string]interface{}{"days": 30}},
+	})
+
+	tests := []struct {
+		name       string
+		content    string
+		wantAction string
+		wantParams map[string]interface{}
+		wantOK     bool
+	}{
+		{
+			name:       "last param takes the rest",
+			content:    "/analyze 0xabc biggest transfers this week",
+			wantAction: "fetch_transaction",
+			wantParams: map[string]interface{}{"address": "0xabc", "message": "biggest transfers this week"},
+			wantOK:     true,
+		},
+		{
+			name:       "missing arguments are left to the LLM",
+			content:    "/analyze",
+			wantAction: "fetch_transaction",
+			wantParams: map[string]interface{}{},
+			wantOK:     true,
+		},
+		{
+			name:       "fixed params",
+			content:    "/profile 0xabc",
+			wantAction: "profile_address",
+			wantParams: map[string]interface{}{"address": "0xabc", "days": 30},
+			wantOK:     true,
+		},
+		{name: "unknown command", content: "/launch 0xabc"},
+		{name: "not a command", content: "gm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, ok := router.route(tt.content)
+			if ok != tt.wantOK {
+				t.Fatalf("route() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if cmd.action != tt.wantAction || !reflect.DeepEqual(cmd.params, tt.wantParams) {
+				t.Errorf("route() = %s %v, want %s %v", cmd.action, cmd.params, tt.wantAction, tt.wantParams)
+			}
+		})
+	}
+
+	if _, ok := newCommandRouter("/", nil).route("/analyze 0xabc"); ok {
+		t.Error("a router without routes routed a command")
+	}
+}
+
+func TestProcessMessageRoutesCommand(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		// The analysis does not ask for the action, the command does
+		analysis(t, ProcessedMessage{}),
+		`{"address": "0xwrong", "message": "from the LLM"}`,
+	)}
+	fetch := &fakeAction{name: "fetch_transaction", typ: "data"}
+	other := &fakeAction{name: "get_transaction", typ: "data"}
+	agent := newPipelineAgent(t, client, &fakeSocial{}, fetch, other)
+	agent.commands = newCommandRouter("/", []CommandRoute{
+		{Command: "analyze", Action: "fetch_transaction", Args: []string{"address"}},
+	})
+
+	if err := agent.processMessage(&SocialMessage{Platform: "discord", FromUser: "alice", Content: "/analyze 0xabc"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	if len(fetch.executed) != 1 {
+		t.Fatalf("fetch_transaction ran %d times, want once", len(fetch.executed))
+	}
+	// The command arguments win over the generated params
+	if got := fetch.executed[0]; got["address"] != "0xabc" || got["message"] != "from the LLM" {
+		t.Errorf("fetch_transaction params = %v, want the command address", got)
+	}
+	if len(other.executed) != 0 {
+		t.Errorf("get_transaction ran %d times, want none", len(other.executed))
+	}
+}
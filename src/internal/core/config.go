@@ -6,6 +6,7 @@ import (
 
 	"github.com/carv-protocol/d.a.t.a/src/characters"
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
 	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
 
@@ -23,16 +24,79 @@ type AgentConfig struct {
 	SocialClient    SocialClient
 	PromptTemplates *conf.PromptTemplates
 	PluginRegistry  *plugins.Registry
-	Training        struct {
+	// Optional, answers obvious intents without an LLM call; defaults to the
+	// intent rules of the character
+	IntentClassifier IntentClassifier
+	// Optional, enables learning persistence and reward tuning in the cognitive engine
+	MemoryManager memory.Manager
+	RewardModel   *RewardModel
+	// Optional, persists proactive tasks and their status; defaults to the
+	// memory manager when one is set
+	TaskStore TaskStore
+	// Moderation checks LLM replies before they are posted, replacing flagged
+	// ones with the moderated response of the character, or dropping them when
+	// Block is set
+	Moderation struct {
+		Moderators []Moderator
+		Block      bool
+	}
+	// Access restricts who the agent interacts with; priority accounts always pass
+	Access struct {
+		DefaultAction string
+		Allow         []string
+		Deny          []string
+	}
+	// Languages are reply languages by "platform:user" or bare user, overriding
+	// the language of the character; "auto" answers in the language of the message
+	Languages map[string]string
+	// Schedules run actions on cron schedules, e.g. a daily digest post
+	Schedules []ScheduledAction
+	// Proactive plans and runs tasks from the character goals every Interval,
+	// disabled when Interval is zero. Tasks that need approval are posted to
+	// ProposePlatform for the priority accounts. With ThinkAloud the reasoning
+	// steps are posted there too, at most ThinkAloudSteps of them.
+	Proactive struct {
+		Interval         time.Duration
+		MaxTasks         int
+		ProposePlatform  string
+		ProposeChannelID string
+		ThinkAloud       bool
+		ThinkAloudSteps  int
+	}
+	// ReplyGuard limits replies per conversation to avoid loops with other bots,
+	// disabled when MaxReplies is zero
+	ReplyGuard struct {
+		MaxReplies int
+		Window     time.Duration
+	}
+	// Commands route chat commands such as "/analyze 0x..." to plugin actions
+	Commands struct {
+		Prefix string
+		Routes []CommandRoute
+	}
+	// SocialErrors treats Threshold errors of a platform within Window as a
+	// persistent failure, alerting AlertPlatform when set
+	SocialErrors struct {
+		Threshold      int
+		Window         time.Duration
+		AlertPlatform  string
+		AlertChannelID string
+	}
+	Training struct {
 		Enabled       bool
 		MaxIterations int
 		BatchSize     int
 		StopThreshold float64
 	}
 	Inference struct {
-		Temperature    float64
+		Temperature float64
+		// MaxChainLength is the number of reasoning steps of a thought chain,
+		// 3 when 0
 		MaxChainLength int
 		MinConfidence  float64
+		// EarlyExitConfidence skips to the concrete step of the thought chain
+		// once an intermediate step reaches it, 0.9 when 0
+		EarlyExitConfidence float64
 	}
 
 	SystemConfig struct {
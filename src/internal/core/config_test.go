@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+
+	"github.com/google/uuid"
+)
+
+// The engine accepts any memory manager, the database one included
+var (
+	_ memory.Manager = (*memory.ManagerImpl)(nil)
+	_ memory.Manager = (*fakeMemory)(nil)
+)
+
+func TestNewAgentWiresOptionalEngineFields(t *testing.T) {
+	mem := newFakeMemory()
+	rewards := NewRewardModel()
+	tests := []struct {
+		name        string
+		memory      memory.Manager
+		rewardModel *RewardModel
+		wantMemory  bool
+		wantRewards *RewardModel
+	}{
+		{name: "without learning"},
+		{name: "with memory and rewards", memory: mem, rewardModel: rewards, wantMemory: true, wantRewards: rewards},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, err := NewAgent(AgentConfig{
+				ID:            uuid.New(),
+				Model:         "test-model",
+				MemoryManager: tt.memory,
+				RewardModel:   tt.rewardModel,
+			})
+			if err != nil {
+				t.Fatalf("NewAgent() error = %v", err)
+			}
+			defer agent.cancel()
+
+			engine := agent.cognitive
+			if (engine.memory != nil) != tt.wantMemory {
+				t.Errorf("engine memory = %v, want set %v", engine.memory, tt.wantMemory)
+			}
+			if engine.rewardModel == nil {
+				t.Fatal("engine has no reward model")
+			}
+			if tt.wantRewards != nil && engine.rewardModel != tt.wantRewards {
+				t.Error("engine does not use the configured reward model")
+			}
+		})
+	}
+}
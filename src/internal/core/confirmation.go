@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// confirmationTTL is how long the user has to confirm an action
+	confirmationTTL = 5 * time.Minute
+
+	confirmationPrefix = "confirm"
+	confirmApprove     = "yes"
+	confirmDecline     = "no"
+)
+
+var (
+	errConfirmationNotFound = errors.New("confirmation not found or expired")
+	errConfirmationNotOwner = errors.New("confirmation belongs to another user")
+)
+
+// pendingConfirmation is an action waiting for the user to tap confirm or cancel
+type pendingConfirmation struct {
+	action    actions.IAction
+	params    map[string]interface{}
+	platform  string
+	user      string
+	expiresAt time.Time
+}
+
+// confirmations holds the actions awaiting confirmation by id
+type confirmations struct {
+	pending map[string]*pendingConfirmation
+	mu      sync.Mutex
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{
+		pending: make(map[string]*pendingConfirmation),
+	}
+}
+
+// add stores a pending confirmation and returns its id
+func (c *confirmations) add(p *pendingConfirmation) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the id ends up in the callback data, which Telegram limits to 64 bytes
+	id := strings.ReplaceAll(uuid.NewString(), "-", "")
+	c.pending[id] = p
+	return id
+}
+
+// take removes and returns the pending confirmation if it belongs to the
+// user, dropping expired ones
+func (c *confirmations) take(id, platform, user string, now time.Time) (*pendingConfirmation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, p := range c.pending {
+		if now.After(p.expiresAt) {
+			delete(c.pending, key)
+		}
+	}
+
+	p, ok := c.pending[id]
+	if !ok {
+		return nil, errConfirmationNotFound
+	}
+	if p.platform != platform || p.user != user {
+		return nil, errConfirmationNotOwner
+	}
+	delete(c.pending, id)
+	return p, nil
+}
+
+// confirmationCallbackData encodes a confirm or cancel tap as callback data
+func confirmationCallbackData(id string, approved bool) string {
+	answer := confirmDecline
+	if approved {
+		answer = confirmApprove
+	}
+	return fmt.Sprintf("%s:%s:%s", confirmationPrefix, id, answer)
+}
+
+// parseConfirmationCallback decodes callback data built by confirmationCallbackData
+func parseConfirmationCallback(data string) (string, bool, error) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 || parts[0] != confirmationPrefix || parts[1] == "" {
+		return "", false, fmt.Errorf("not a confirmation callback: %q", data)
+	}
+
+	switch parts[2] {
+	case confirmApprove:
+		return parts[1], true, nil
+	case confirmDecline:
+		return parts[1], false, nil
+	default:
+		return "", false, fmt.Errorf("invalid confirmation answer: %q", parts[2])
+	}
+}
+
+// confirmationButtons returns the confirm and cancel buttons of a pending confirmation
+func confirmationButtons(id string) [][]Button {
+	return [][]Button{{
+		{Text: "Confirm", Data: confirmationCallbackData(id, true)},
+		{Text: "Cancel", Data: confirmationCallbackData(id, false)},
+	}}
+}
+
+// formatConfirmationPrompt describes the action the user is asked to confirm
+func formatConfirmationPrompt(action actions.IAction, params map[string]interface{}) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Please confirm: %s", action.Name())
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "\n- %s: %v", key, params[key])
+	}
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+func TestParseConfirmationCallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantID       string
+		wantApproved bool
+		wantErr      bool
+	}{
+		{name: "confirm", data: confirmationCallbackData("abc", true), wantID: "abc", wantApproved: true},
+		{name: "cancel", data: confirmationCallbackData("abc", false), wantID: "abc"},
+		{name: "other callback", data: "vote:abc:yes", wantErr: true},
+		{name: "missing id", data: "confirm::yes", wantErr: true},
+		{name: "unknown answer", data: "confirm:abc:maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, approved, err := parseConfirmationCallback(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfirmationCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || approved != tt.wantApproved {
+				t.Errorf("parseConfirmationCallback() = %q, %v, want %q, %v", id, approved, tt.wantID, tt.wantApproved)
+			}
+		})
+	}
+}
+
+func TestConfirmationsTake(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		user    string
+		at      time.Time
+		wantErr error
+	}{
+		{name: "owner within the TTL", user: "alice", at: now.Add(time.Minute)},
+		{name: "another user", user: "bob", at: now.Add(time.Minute), wantErr: errConfirmationNotOwner},
+		{name: "expired", user: "alice", at: now.Add(confirmationTTL + time.Second), wantErr: errConfirmationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfirmations()
+			id := c.add(&pendingConfirmation{platform: "telegram", user: "alice", expiresAt: now.Add(confirmationTTL)})
+			if len(confirmationCallbackData(id, true)) > 64 {
+				t.Errorf("callback data of %s is over the Telegram limit", id)
+			}
+
+			_, err := c.take(id, "telegram", tt.user, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("take() error = %v, want %v", err, tt.wantErr)
+			}
+			// A confirmation can only be used once
+			if tt.wantErr == nil {
+				if _, err := c.take(id, "telegram", tt.user, tt.at); !errors.Is(err, errConfirmationNotFound) {
+					t.Errorf("second take() error = %v, want %v", err, errConfirmationNotFound)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleConfirmation(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         string
+		approved     bool
+		wantExecuted int
+		wantReply    string
+	}{
+		{name: "confirmed", from: "alice", approved: true, wantExecuted: 1, wantReply: "Done: transfer"},
+		{name: "cancelled", from: "alice", wantReply: "Cancelled: transfer"},
+		{name: "tapped by another user", from: "bob", approved: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			social := &fakeSocial{}
+			agent := &Agent{
+				character:     &characters.Character{Name: "Tester"},
+				logger:        logger.GetLogger(),
+				socialClient:  social,
+				confirmations: newConfirmations(),
+				ctx:           context.Background(),
+			}
+			action := &fakeAction{name: "transfer", typ: "wallet"}
+			request := &SocialMessage{Platform: "telegram", FromUser: "alice", Metadata: map[string]interface{}{"chat_id": int64(42)}}
+
+			if err := agent.requestConfirmation(request, action, map[string]interface{}{"amount": 1}); err != nil {
+				t.Fatalf("requestConfirmation() error = %v", err)
+			}
+			prompt := social.sent[0]
+			if !strings.Contains(prompt.Content, "transfer") || len(prompt.Buttons) != 1 || len(prompt.Buttons[0]) != 2 {
+				t.Fatalf("confirmation prompt = %+v, want confirm and cancel buttons", prompt)
+			}
+			if len(action.executed) != 0 {
+				t.Fatal("action ran before it was confirmed")
+			}
+
+			tap := prompt.Buttons[0][1].Data
+			if tt.approved {
+				tap = prompt.Buttons[0][0].Data
+			}
+			agent.handleConfirmation(&SocialMessage{Platform: "telegram", Type: "callback", FromUser: tt.from, Content: tap})
+
+			if len(action.executed) != tt.wantExecuted {
+				t.Errorf("action ran %d times, want %d", len(action.executed), tt.wantExecuted)
+			}
+			var reply string
+			if len(social.sent) > 1 {
+				reply = social.sent[1].Content
+			}
+			if reply != tt.wantReply {
+				t.Errorf("reply = %q, want %q", reply, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestRequestConfirmationSkipsOtherPlatforms(t *testing.T) {
+	social := &fakeSocial{}
+	agent := &Agent{logger: logger.GetLogger(), socialClient: social, confirmations: newConfirmations(), ctx: context.Background()}
+	action := &fakeAction{name: "transfer"}
+
+	if err := agent.requestConfirmation(&SocialMessage{Platform: "twitter", FromUser: "alice"}, action, nil); err != nil {
+		t.Fatalf("requestConfirmation() error = %v", err)
+	}
+	if len(social.sent) != 0 || len(action.executed) != 0 {
+		t.Errorf("sent %d messages and ran the action %d times, want neither", len(social.sent), len(action.executed))
+	}
+}
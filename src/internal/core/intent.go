@@ -0,0 +1,87 @@
+package core
+
+import (
+	"math/rand/v2"
+	"strings"
+	"unicode"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+)
+
+const (
+	// defaultIntentMaxWords limits rule matches to short messages
+	defaultIntentMaxWords = 4
+	// ruleIntentConfidence is the confidence of a rule match
+	ruleIntentConfidence = 0.95
+)
+
+// IntentClassifier classifies obvious messages without an LLM call. It
+// returns false for anything ambiguous, which is left to the LLM.
+type IntentClassifier interface {
+	Classify(msg *SocialMessage) (*ProcessedMessage, bool)
+}
+
+// ruleIntentClassifier matches short messages against the intent rules of the character
+type ruleIntentClassifier struct {
+	rules []characters.IntentRule
+}
+
+// NewRuleIntentClassifier creates a keyword classifier from intent rules
+func NewRuleIntentClassifier(rules []characters.IntentRule) IntentClassifier {
+	return &ruleIntentClassifier{rules: rules}
+}
+
+func (c *ruleIntentClassifier) Classify(msg *SocialMessage) (*ProcessedMessage, bool) {
+	words := intentWords(msg.Content)
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	for _, rule := range c.rules {
+		maxWords := rule.MaxWords
+		if maxWords <= 0 {
+			maxWords = defaultIntentMaxWords
+		}
+		if len(words) > maxWords {
+			continue
+		}
+
+		for _, keyword := range rule.Keywords {
+			if !hasWordPrefix(words, intentWords(keyword)) {
+				continue
+			}
+
+			processed := &ProcessedMessage{
+				Intent:     IntentType(rule.Intent),
+				Emotion:    EmotionNeutral,
+				Confidence: ruleIntentConfidence,
+			}
+			if len(rule.Responses) > 0 {
+				processed.ShouldReply = true
+				processed.ResponseMsg = rule.Responses[rand.IntN(len(rule.Responses))]
+			}
+			return processed, true
+		}
+	}
+	return nil, false
+}
+
+// intentWords lowercases text and splits it into words, dropping punctuation
+func intentWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+}
+
+// hasWordPrefix reports whether words starts with all of prefix
+func hasWordPrefix(words, prefix []string) bool {
+	if len(prefix) == 0 || len(prefix) > len(words) {
+		return false
+	}
+	for i, word := range prefix {
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
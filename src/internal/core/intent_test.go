@@ -0,0 +1,89 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+)
+
+var testIntentRules = []characters.IntentRule{
+	{Intent: "greeting", Keywords: []string{"gm", "good morning"}, Responses: []string{"gm!"}, MaxWords: 3},
+	{Intent: "acknowledge", Keywords: []string{"thanks", "thank you"}},
+}
+
+func TestRuleIntentClassifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantIntent IntentType
+		wantReply  string
+		wantOK     bool
+	}{
+		{name: "greeting", content: "gm", wantIntent: "greeting", wantReply: "gm!", wantOK: true},
+		{name: "multi-word keyword with punctuation", content: "Good morning, fren!", wantIntent: "greeting", wantReply: "gm!", wantOK: true},
+		{name: "rule without responses", content: "thank you so much", wantIntent: "acknowledge", wantOK: true},
+		{name: "longer than the rule allows", content: "gm, what is the gas price on base today?"},
+		{name: "keyword not at the start", content: "say gm"},
+		{name: "keyword inside a word", content: "gmx"},
+		{name: "empty", content: "  ?! "},
+	}
+
+	classifier := NewRuleIntentClassifier(testIntentRules)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processed, ok := classifier.Classify(&SocialMessage{Content: tt.content})
+			if ok != tt.wantOK {
+				t.Fatalf("Classify() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if processed.Intent != tt.wantIntent || processed.ResponseMsg != tt.wantReply || processed.ShouldReply != (tt.wantReply != "") {
+				t.Errorf("Classify() = %+v, want intent %s replying %q", processed, tt.wantIntent, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestProcessMessageIntentRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		llmReply  string
+		wantCalls int
+		wantReply string
+	}{
+		{name: "greeting skips the LLM", content: "gm", wantReply: "gm!"},
+		{
+			name:      "complex query falls through",
+			content:   "gm, which wallets moved the most ETH this week?",
+			llmReply:  "Let me check.",
+			wantCalls: 1,
+			wantReply: "Let me check.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var responses []string
+			if tt.llmReply != "" {
+				responses = append(responses, analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: tt.llmReply}))
+			}
+			client := &fakeLLM{respond: replies(t, responses...)}
+			social := &fakeSocial{}
+			agent := newPipelineAgent(t, client, social)
+			agent.intents = NewRuleIntentClassifier(testIntentRules)
+
+			if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: tt.content}); err != nil {
+				t.Fatalf("processMessage() error = %v", err)
+			}
+
+			if len(client.requests) != tt.wantCalls {
+				t.Errorf("made %d LLM calls, want %d", len(client.requests), tt.wantCalls)
+			}
+			if sent := social.contents(); len(sent) != 1 || sent[0] != tt.wantReply {
+				t.Errorf("sent %q, want %q", sent, tt.wantReply)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+)
+
+// toLanguageMap normalizes the user keys of the configured reply languages the
+// way access list entries are
+func toLanguageMap(languages map[string]string) map[string]string {
+	normalized := make(map[string]string, len(languages))
+	for user, language := range languages {
+		language = strings.TrimSpace(language)
+		if language == "" {
+			continue
+		}
+		for entry := range toAccessSet([]string{user}) {
+			normalized[entry] = language
+		}
+	}
+	return normalized
+}
+
+// languageFor returns the configured reply language of the user, preferring a
+// "platform:user" entry over a bare user
+func (a *Agent) languageFor(platform, user string) string {
+	user = strings.ToLower(strings.TrimPrefix(user, "@"))
+	if language, ok := a.languages[strings.ToLower(platform)+":"+user]; ok {
+		return language
+	}
+	return a.languages[user]
+}
+
+// replyLanguage is the language of the stakeholder, falling back to the character's
+func replyLanguage(character *characters.Character, stakeholder *Stakeholder) string {
+	if stakeholder != nil && stakeholder.Language != "" {
+		return stakeholder.Language
+	}
+	if character != nil {
+		return character.Language
+	}
+	return ""
+}
+
+// formatLanguage instructs the LLM which language to reply in
+func formatLanguage(language string) string {
+	switch {
+	case language == "":
+		return ""
+	case strings.EqualFold(language, characters.LanguageAuto):
+		return "\n\nLanguage:\nRespond in the language the user writes in."
+	default:
+		return fmt.Sprintf("\n\nLanguage:\nRespond in %s, whatever language the user writes in.", language)
+	}
+}
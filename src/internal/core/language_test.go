@@ -0,0 +1,53 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessMessageLanguageInstruction(t *testing.T) {
+	tests := []struct {
+		name      string
+		character string
+		languages map[string]string
+		from      string
+		want      string
+	}{
+		{name: "not configured", from: "alice"},
+		{name: "character language", character: "Spanish", from: "alice", want: "Respond in Spanish, whatever language the user writes in."},
+		{name: "auto detection", character: "auto", from: "alice", want: "Respond in the language the user writes in."},
+		{
+			name:      "platform user overrides the character",
+			character: "Spanish",
+			languages: map[string]string{"telegram:@Alice": "German", "alice": "French"},
+			from:      "alice",
+			want:      "Respond in German,",
+		},
+		{name: "bare user", languages: map[string]string{"bob": "Japanese"}, from: "bob", want: "Respond in Japanese,"},
+		{name: "other user keeps the character language", character: "Spanish", languages: map[string]string{"bob": "Japanese"}, from: "alice", want: "Respond in Spanish,"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: replies(t, analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm"}))}
+			agent := newHarnessAgent(t, client, &fakeSocial{})
+			agent.character.Language = tt.character
+			agent.languages = toLanguageMap(tt.languages)
+
+			if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: tt.from, Content: "gm"}); err != nil {
+				t.Fatalf("processMessage() error = %v", err)
+			}
+
+			system := client.requests[0].Messages[0].Content
+			if tt.want == "" {
+				if strings.Contains(system, "Language:") {
+					t.Errorf("system prompt = %q, want no language instruction", system)
+				}
+				return
+			}
+			if !strings.Contains(system, "Language:\n"+tt.want) {
+				t.Errorf("system prompt = %q, want the instruction %q", system, tt.want)
+			}
+		})
+	}
+}
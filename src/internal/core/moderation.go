@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// ErrContentFlagged is returned when moderation blocks content from being posted
+var ErrContentFlagged = errors.New("content flagged by moderation")
+
+// Moderator checks LLM generated content before the agent posts it
+type Moderator interface {
+	// Moderate returns the reason the content is flagged, empty when it is clean
+	Moderate(ctx context.Context, content string) (string, error)
+}
+
+// blocklistModerator flags content matching any of its patterns
+type blocklistModerator struct {
+	patterns []string
+	compiled []*regexp.Regexp
+}
+
+// NewBlocklistModerator flags content matching any of the regular expressions,
+// which are case insensitive
+func NewBlocklistModerator(patterns []string) (Moderator, error) {
+	m := &blocklistModerator{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist pattern %q: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, pattern)
+		m.compiled = append(m.compiled, re)
+	}
+	return m, nil
+}
+
+func (m *blocklistModerator) Moderate(_ context.Context, content string) (string, error) {
+	for i, re := range m.compiled {
+		if re.MatchString(content) {
+			return fmt.Sprintf("matches blocklist pattern %q", m.patterns[i]), nil
+		}
+	}
+	return "", nil
+}
+
+// providerModerator flags content with the moderation endpoint of the LLM provider
+type providerModerator struct {
+	client llm.Client
+}
+
+// NewProviderModerator flags content with the moderation endpoint of the LLM provider
+func NewProviderModerator(client llm.Client) Moderator {
+	return &providerModerator{client: client}
+}
+
+func (m *providerModerator) Moderate(ctx context.Context, content string) (string, error) {
+	flagged, err := m.client.Moderate(ctx, content)
+	if err != nil {
+		return "", err
+	}
+	if flagged {
+		return "flagged by provider moderation", nil
+	}
+	return "", nil
+}
+
+// contentFilter runs content through the moderators before it is posted.
+// A moderator failing is logged and doesn't block the content, so an outage
+// of the moderation endpoint doesn't silence the agent.
+type contentFilter struct {
+	moderators []Moderator
+	// fallback replaces flagged content, which is dropped when empty
+	fallback string
+}
+
+func newContentFilter(moderators []Moderator, fallback string) *contentFilter {
+	if len(moderators) == 0 {
+		return nil
+	}
+	return &contentFilter{moderators: moderators, fallback: fallback}
+}
+
+// flagged returns the reason the first moderator flags the content for
+func (f *contentFilter) flagged(ctx context.Context, content string) (string, bool) {
+	if f == nil || content == "" {
+		return "", false
+	}
+
+	for _, moderator := range f.moderators {
+		reason, err := moderator.Moderate(ctx, content)
+		if err != nil {
+			logger.GetLogger().Warnw("Moderation check failed", "error", err)
+			continue
+		}
+		if reason != "" {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+// filter returns the content to post in place of content, and false when
+// nothing should be posted
+func (f *contentFilter) filter(ctx context.Context, content string) (string, bool) {
+	reason, flagged := f.flagged(ctx, content)
+	if !flagged {
+		return content, true
+	}
+
+	logger.GetLogger().Warnw("Moderation flagged content",
+		"reason", reason,
+		"content", content,
+	)
+	return f.fallback, f.fallback != ""
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+)
+
+var errModerationDown = errors.New("moderation endpoint down")
+
+// fakeModerator flags content equal to flag, or fails with err
+type fakeModerator struct {
+	flag string
+	err  error
+}
+
+func (m *fakeModerator) Moderate(_ context.Context, content string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	if content == m.flag {
+		return "flagged by fake", nil
+	}
+	return "", nil
+}
+
+// moderationLLM answers the provider moderation endpoint
+type moderationLLM struct {
+	llm.Client
+	flagged bool
+	err     error
+}
+
+func (m *moderationLLM) Moderate(context.Context, string) (bool, error) {
+	return m.flagged, m.err
+}
+
+func TestBlocklistModerator(t *testing.T) {
+	moderator, err := NewBlocklistModerator([]string{`private key`, `\bseed phrase\b`})
+	if err != nil {
+		t.Fatalf("NewBlocklistModerator() error = %v", err)
+	}
+
+	tests := []struct {
+		content     string
+		wantFlagged bool
+	}{
+		{content: "Here is the latest block.", wantFlagged: false},
+		{content: "My Private Key is 0xabc", wantFlagged: true},
+		{content: "Never share your seed phrase.", wantFlagged: true},
+		{content: "seed phrases", wantFlagged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			reason, err := moderator.Moderate(context.Background(), tt.content)
+			if err != nil {
+				t.Fatalf("Moderate() error = %v", err)
+			}
+			if (reason != "") != tt.wantFlagged {
+				t.Errorf("Moderate() reason = %q, want flagged %v", reason, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestNewBlocklistModeratorInvalidPattern(t *testing.T) {
+	if _, err := NewBlocklistModerator([]string{"("}); err == nil {
+		t.Error("NewBlocklistModerator() error = nil, want an invalid pattern error")
+	}
+}
+
+func TestProviderModerator(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      *moderationLLM
+		wantFlagged bool
+		wantErr     bool
+	}{
+		{name: "clean", client: &moderationLLM{}},
+		{name: "flagged", client: &moderationLLM{flagged: true}, wantFlagged: true},
+		{name: "unsupported", client: &moderationLLM{err: llm.ErrModerationUnsupported}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := NewProviderModerator(tt.client).Moderate(context.Background(), "content")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Moderate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if (reason != "") != tt.wantFlagged {
+				t.Errorf("Moderate() reason = %q, want flagged %v", reason, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestContentFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     *contentFilter
+		content    string
+		wantOutput string
+		wantSend   bool
+	}{
+		{name: "no moderators", filter: newContentFilter(nil, "safe"), content: "bad", wantOutput: "bad", wantSend: true},
+		{name: "clean content passes", filter: newContentFilter([]Moderator{&fakeModerator{flag: "bad"}}, "safe"), content: "good", wantOutput: "good", wantSend: true},
+		{name: "flagged content is replaced", filter: newContentFilter([]Moderator{&fakeModerator{flag: "bad"}}, "safe"), content: "bad", wantOutput: "safe", wantSend: true},
+		{name: "flagged content is blocked", filter: newContentFilter([]Moderator{&fakeModerator{flag: "bad"}}, ""), content: "bad", wantSend: false},
+		{
+			name:       "failing moderator doesn't block",
+			filter:     newContentFilter([]Moderator{&fakeModerator{err: errModerationDown}}, "safe"),
+			content:    "bad",
+			wantOutput: "bad",
+			wantSend:   true,
+		},
+		{
+			name:       "later moderator still checks",
+			filter:     newContentFilter([]Moderator{&fakeModerator{err: errModerationDown}, &fakeModerator{flag: "bad"}}, "safe"),
+			content:    "bad",
+			wantOutput: "safe",
+			wantSend:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, send := tt.filter.filter(context.Background(), tt.content)
+			if output != tt.wantOutput || send != tt.wantSend {
+				t.Errorf("filter() = %q, %v, want %q, %v", output, send, tt.wantOutput, tt.wantSend)
+			}
+		})
+	}
+}
+
+func TestProcessMessageModeratesReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		fallback string
+		wantSent []string
+	}{
+		{name: "clean reply is sent", reply: "gm", fallback: "let's talk about something else", wantSent: []string{"gm"}},
+		{name: "flagged reply is replaced", reply: "your private key", fallback: "let's talk about something else", wantSent: []string{"let's talk about something else"}},
+		{name: "flagged reply is blocked", reply: "your private key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: replies(t, analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: tt.reply}))}
+			social := &fakeSocial{}
+			agent := newPipelineAgent(t, client, social)
+			agent.moderation = newContentFilter([]Moderator{&fakeModerator{flag: "your private key"}}, tt.fallback)
+
+			if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "gm"}); err != nil {
+				t.Fatalf("processMessage() error = %v", err)
+			}
+
+			sent := social.contents()
+			if len(sent) != len(tt.wantSent) {
+				t.Fatalf("sent %q, want %q", sent, tt.wantSent)
+			}
+			for i := range sent {
+				if sent[i] != tt.wantSent[i] {
+					t.Errorf("sent %q, want %q", sent, tt.wantSent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+)
+
+// defaultProactiveMaxTasks is how many tasks an evaluation cycle runs when not configured
+const defaultProactiveMaxTasks = 1
+
+// defaultThinkAloudSteps is how many reasoning steps are posted per evaluation
+// when not configured
+const defaultThinkAloudSteps = 3
+
+// proactiveConfig drives the goal based evaluation loop
+type proactiveConfig struct {
+	interval time.Duration
+	maxTasks int
+	// proposePlatform and proposeChannelID receive the tasks that need the
+	// approval of a priority account; such tasks are only logged when empty
+	proposePlatform  string
+	proposeChannelID string
+	// thinkAloud posts up to thinkAloudSteps reasoning steps to the proposal
+	// channel as they are generated
+	thinkAloud      bool
+	thinkAloudSteps int
+}
+
+// runProactiveLoop evaluates the goals of the character every interval until
+// the agent stops
+func (a *Agent) runProactiveLoop() {
+	ticker := time.NewTicker(a.proactive.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.evaluateAndExecuteTasks(a.ctx); err != nil {
+				a.logger.Errorw("Proactive evaluation failed", "error", err)
+			}
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluateAndExecuteTasks plans tasks from the goals of the character and
+// runs the most important ones. Tasks that need approval are proposed to the
+// priority accounts instead of run.
+func (a *Agent) evaluateAndExecuteTasks(ctx context.Context) error {
+	if len(a.character.Goals) == 0 {
+		return nil
+	}
+
+	state := a.getCurrentState()
+	if a.proactive.thinkAloud && a.proactive.proposePlatform != "" {
+		ctx = withThoughtObserver(ctx, a.thinkAloud())
+	}
+	generation, err := a.cognitive.GenerateTasks(ctx, state)
+	if err != nil {
+		return fmt.Errorf("failed to generate tasks: %w", err)
+	}
+
+	maxTasks := a.proactive.maxTasks
+	if maxTasks <= 0 {
+		maxTasks = defaultProactiveMaxTasks
+	}
+
+	for i, task := range generation.Tasks {
+		if i >= maxTasks {
+			break
+		}
+		a.saveTask(ctx, task)
+		if a.taskNeedsApproval(task) {
+			a.proposeTask(ctx, task)
+			continue
+		}
+		if err := a.ExecuteTask(ctx, state, task); err != nil {
+			a.logger.Errorw("Failed to execute task", "task", task.Name, "id", task.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// taskNeedsApproval reports whether a priority account has to approve the task
+// before it runs
+func (a *Agent) taskNeedsApproval(task *Task) bool {
+	if task.RequiresApproval || task.RequiresStakeholderInput {
+		return true
+	}
+	for _, tool := range task.Tools {
+		if action := a.findAction("", tool); action != nil && actions.RequiresConfirmation(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// proposeTask posts a task to the proposal channel of the priority accounts
+func (a *Agent) proposeTask(ctx context.Context, task *Task) {
+	a.logger.Infow("Task needs approval", "task", task.Name, "description", task.Description)
+	if a.proactive.proposePlatform == "" {
+		return
+	}
+
+	content := fmt.Sprintf("Proposed task: %s\n%s", task.Name, task.Description)
+	if len(task.Tools) > 0 {
+		content += "\nTools: " + strings.Join(task.Tools, ", ")
+	}
+	err := a.socialClient.SendMessage(ctx, SocialMessage{
+		Platform: a.proactive.proposePlatform,
+		Type:     "Post",
+		Content:  content,
+		Metadata: map[string]interface{}{"channel_id": a.proactive.proposeChannelID},
+	})
+	if err != nil {
+		a.logger.Errorw("Failed to propose task", "task", task.Name, "error", err)
+	}
+}
+
+// thinkAloud returns an observer posting the reasoning steps of one evaluation
+// to the proposal channel, up to the configured number of steps
+func (a *Agent) thinkAloud() func(ctx context.Context, step *ThoughtStep) {
+	limit := a.proactive.thinkAloudSteps
+	if limit <= 0 {
+		limit = defaultThinkAloudSteps
+	}
+
+	posted := 0
+	return func(ctx context.Context, step *ThoughtStep) {
+		if posted >= limit || strings.TrimSpace(step.Content) == "" {
+			return
+		}
+		posted++
+		err := a.socialClient.SendMessage(ctx, SocialMessage{
+			Platform: a.proactive.proposePlatform,
+			Type:     "Post",
+			Content:  fmt.Sprintf("Thinking (%d/%d, %s): %s", posted, limit, step.Purpose, step.Content),
+			Metadata: map[string]interface{}{"channel_id": a.proactive.proposeChannelID},
+		})
+		if err != nil {
+			a.logger.Warnw("Failed to post reasoning step", "purpose", step.Purpose, "error", err)
+		}
+	}
+}
+
+// ExecuteTask runs the actions of a pending task, moving it to running and
+// then to done or failed. Every status change is persisted.
+func (a *Agent) ExecuteTask(ctx context.Context, state *SystemState, task *Task) error {
+	if err := task.transition(TaskStatusRunning, time.Now()); err != nil {
+		return err
+	}
+	a.saveTask(ctx, task)
+
+	err := a.runTaskActions(ctx, state, task)
+	status := TaskStatusDone
+	if err != nil {
+		status = TaskStatusFailed
+		task.Error = err.Error()
+	}
+	if transitionErr := task.transition(status, time.Now()); transitionErr != nil {
+		return transitionErr
+	}
+	a.saveTask(ctx, task)
+	return err
+}
+
+// saveTask persists a task, logging rather than failing on store errors so a
+// broken store doesn't stop the agent from working
+func (a *Agent) saveTask(ctx context.Context, task *Task) {
+	if a.tasks == nil {
+		return
+	}
+	if err := a.tasks.SaveTask(ctx, task); err != nil {
+		a.logger.Warnw("Failed to save task", "task", task.Name, "id", task.ID, "status", task.Status, "error", err)
+	}
+}
+
+// runTaskActions runs the actions of a task, generating their parameters from
+// the task description
+func (a *Agent) runTaskActions(ctx context.Context, state *SystemState, task *Task) error {
+	a.logger.Infow("Executing task", "task", task.Name, "id", task.ID, "tools", task.Tools)
+
+	request := &SocialMessage{
+		Platform: "agent",
+		Type:     "Task",
+		FromUser: a.character.Name,
+		Content:  fmt.Sprintf("%s: %s", task.Name, task.Description),
+	}
+	self := &Stakeholder{
+		ID:       a.character.Name,
+		Platform: "agent",
+		Type:     StakeholderTypePriority,
+	}
+
+	for _, tool := range task.Tools {
+		action := a.findAction("", tool)
+		if action == nil {
+			return fmt.Errorf("task %s uses unknown action %s", task.Name, tool)
+		}
+
+		params, err := a.cognitive.generateActionParameters(ctx, state, request, self, action)
+		if err != nil {
+			return fmt.Errorf("failed to generate parameters for %s: %w", tool, err)
+		}
+		if moreInfoNeeded, ok := params["more_info_needed"].(bool); ok && moreInfoNeeded {
+			return fmt.Errorf("action %s needs more information than the task gives", tool)
+		}
+		if err := action.Validate(params); err != nil {
+			return fmt.Errorf("invalid params for %s: %w", tool, err)
+		}
+		if err := a.executeAction(ctx, action, params); err != nil {
+			return fmt.Errorf("failed to execute %s: %w", tool, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+)
+
+// taskPlanner answers the thought steps of task planning, with tasks at the
+// concrete step, and leaves the parameter generation of actions to fakeLLM
+type taskPlanner struct {
+	*fakeLLM
+	tasks string
+}
+
+func (p *taskPlanner) CreateCompletionWithReasoning(_ context.Context, request llm.CompletionRequest) (*llm.Completion, error) {
+	if request.ResponseFormat == llm.ResponseFormatJSONObject {
+		return &llm.Completion{Content: p.tasks}, nil
+	}
+	return &llm.Completion{Content: "Looking at which goal to work on next."}, nil
+}
+
+// newProactiveAgent returns a pipeline agent whose character has a concrete goal
+func newProactiveAgent(t *testing.T, client llm.Client, social SocialClient, pluginActions ...actions.IAction) *Agent {
+	t.Helper()
+	agent := newPipelineAgent(t, client, social, pluginActions...)
+	agent.character.Goals = []characters.Goal{
+		{Name: "Daily gas digest", Description: "Post the daily Ethereum gas digest", Priority: 0.9},
+	}
+	return agent
+}
+
+func TestEvaluateAndExecuteTasksRunsGoalTask(t *testing.T) {
+	tasks := `{"tasks": [
+		{"name": "Research", "description": "Look around", "priority": 0.2, "tools": ["research"]},
+		{"name": "Post gas digest", "description": "Post today's gas prices", "priority": 0.9, "tools": ["gas_digest"]}
+	]}`
+	params := &fakeLLM{respond: func(llm.CompletionRequest) string { return `{"chain": "ethereum"}` }}
+	client := &taskPlanner{fakeLLM: params, tasks: tasks}
+	digest := &fakeAction{name: "gas_digest", typ: "post"}
+	research := &fakeAction{name: "research", typ: "data"}
+	agent := newProactiveAgent(t, client, &fakeSocial{}, digest, research)
+
+	if err := agent.evaluateAndExecuteTasks(context.Background()); err != nil {
+		t.Fatalf("evaluateAndExecuteTasks() error = %v", err)
+	}
+
+	if len(digest.executed) != 1 || digest.executed[0]["chain"] != "ethereum" {
+		t.Errorf("gas_digest executed with %v, want the highest priority task run once", digest.executed)
+	}
+	if len(research.executed) != 0 {
+		t.Errorf("research executed %d times, want only one task per cycle", len(research.executed))
+	}
+}
+
+func TestEvaluateAndExecuteTasksProposesApproval(t *testing.T) {
+	tasks := `{"tasks": [{"name": "Send tokens", "description": "Reward top holders", "priority": 0.8, "tools": ["transfer"], "requires_approval": true}]}`
+	client := &taskPlanner{fakeLLM: &fakeLLM{respond: func(llm.CompletionRequest) string { return `{}` }}, tasks: tasks}
+	social := &fakeSocial{}
+	transfer := &fakeAction{name: "transfer", typ: "chain"}
+	agent := newProactiveAgent(t, client, social, transfer)
+	agent.proactive = proactiveConfig{proposePlatform: "discord", proposeChannelID: "ops"}
+
+	if err := agent.evaluateAndExecuteTasks(context.Background()); err != nil {
+		t.Fatalf("evaluateAndExecuteTasks() error = %v", err)
+	}
+
+	if len(transfer.executed) != 0 {
+		t.Errorf("transfer executed %d times, want the task only proposed", len(transfer.executed))
+	}
+	if len(social.sent) != 1 {
+		t.Fatalf("sent %d messages, want the proposal", len(social.sent))
+	}
+	proposal := social.sent[0]
+	if proposal.Platform != "discord" || proposal.Metadata["channel_id"] != "ops" || !strings.Contains(proposal.Content, "Send tokens") {
+		t.Errorf("sent %+v, want the task proposed to the ops channel", proposal)
+	}
+}
+
+func TestEvaluateAndExecuteTasksThinkAloud(t *testing.T) {
+	tests := []struct {
+		name      string
+		proactive proactiveConfig
+		want      []string
+	}{
+		{
+			name:      "on",
+			proactive: proactiveConfig{proposePlatform: "discord", proposeChannelID: "ops", thinkAloud: true, thinkAloudSteps: 2},
+			want:      []string{"Thinking (1/2, initial)", "Thinking (2/2, "},
+		},
+		{name: "off", proactive: proactiveConfig{proposePlatform: "discord", proposeChannelID: "ops"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := `{"tasks": [{"name": "Post gas digest", "description": "Post today's gas prices", "priority": 0.9, "tools": ["gas_digest"]}]}`
+			client := &taskPlanner{fakeLLM: &fakeLLM{respond: func(llm.CompletionRequest) string { return `{}` }}, tasks: tasks}
+			social := &fakeSocial{}
+			digest := &fakeAction{name: "gas_digest", typ: "post"}
+			agent := newProactiveAgent(t, client, social, digest)
+			agent.proactive = tt.proactive
+
+			if err := agent.evaluateAndExecuteTasks(context.Background()); err != nil {
+				t.Fatalf("evaluateAndExecuteTasks() error = %v", err)
+			}
+
+			sent := social.contents()
+			if len(sent) != len(tt.want) {
+				t.Fatalf("sent %q, want %d reasoning steps", sent, len(tt.want))
+			}
+			for i, prefix := range tt.want {
+				if !strings.HasPrefix(sent[i], prefix) || !strings.Contains(sent[i], "Looking at which goal") {
+					t.Errorf("message %d = %q, want the step posted as %q", i, sent[i], prefix)
+				}
+				if social.sent[i].Metadata["channel_id"] != "ops" {
+					t.Errorf("message %d went to %v, want the ops channel", i, social.sent[i].Metadata)
+				}
+			}
+			if len(digest.executed) != 1 {
+				t.Errorf("gas_digest executed %d times, want the task run once", len(digest.executed))
+			}
+		})
+	}
+}
+
+func TestEvaluateAndExecuteTasksWithoutGoals(t *testing.T) {
+	client := &fakeLLM{respond: func(llm.CompletionRequest) string { return `{}` }}
+	agent := newPipelineAgent(t, client, &fakeSocial{})
+
+	if err := agent.evaluateAndExecuteTasks(context.Background()); err != nil {
+		t.Fatalf("evaluateAndExecuteTasks() error = %v", err)
+	}
+	if len(client.requests) != 0 {
+		t.Errorf("made %d LLM calls, want none for a character without goals", len(client.requests))
+	}
+}
+
+func TestParseTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantNames []string
+		wantErr   bool
+	}{
+		{name: "object with tasks", raw: `{"tasks": [{"name": "a"}, {"name": "b"}]}`, wantNames: []string{"a", "b"}},
+		{name: "list", raw: `[{"name": "a"}]`, wantNames: []string{"a"}},
+		{name: "single task", raw: `{"name": "a", "tools": ["x"]}`, wantNames: []string{"a"}},
+		{name: "json tags", raw: "Plan:\n<json>{\"tasks\": [{\"name\": \"a\"}]}</json>", wantNames: []string{"a"}},
+		{name: "code fence", raw: "```json\n[{\"name\": \"a\"}]\n```", wantNames: []string{"a"}},
+		{name: "no tasks", raw: `{"tasks": []}`, wantErr: true},
+		{name: "not json", raw: "no plan today", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := parseTasks(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTasks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tasks) != len(tt.wantNames) {
+				t.Fatalf("parseTasks() = %d tasks, want %v", len(tasks), tt.wantNames)
+			}
+			for i, task := range tasks {
+				if task.Name != tt.wantNames[i] {
+					t.Errorf("task %d = %q, want %q", i, task.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
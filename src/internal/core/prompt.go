@@ -1,10 +1,14 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 
+	"github.com/carv-protocol/d.a.t.a/src/characters"
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
@@ -18,28 +22,38 @@ func generateTasksPromptFunc(systemState *SystemState, promptTemplate *conf.Prom
 				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Initial,
 				systemState.Character.Name,
 			)
+		case PurposeExploration:
+			return thoughtStepPrompt(
+				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Exploration,
+				"Explore tasks the previous steps have not considered yet:",
+				steps,
+			)
 		case PurposeAnalysis:
 			// Purpose Analysis: Evaluate the tasks that have been generated to assess their feasibility, risks, and alignment with goals.
-			return fmt.Sprintf(
+			return thoughtStepPrompt(
 				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Analysis,
-				formatPreviousSteps(steps),
+				"Evaluate the feasibility, risks and goal alignment of the tasks from the previous steps:",
+				steps,
 			)
 		case PurposeReconsider:
-			return fmt.Sprintf(
+			return thoughtStepPrompt(
 				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Reconsider,
-				formatPreviousSteps(steps),
+				"Reconsider the tasks from the previous steps and suggest better alternatives:",
+				steps,
 			)
 		case PurposeRefinement:
 			// Purpose Refinement: Improve and polish the tasks based on analysis and feedback.
-			return fmt.Sprintf(
+			return thoughtStepPrompt(
 				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Refinement,
-				formatPreviousSteps(steps),
+				"Refine the tasks from the previous steps based on the analysis:",
+				steps,
 			)
 		case PurposeConcrete:
 			// Purpose Concrete: Finalize the tasks into fully executable plans with precise actions.
-			return fmt.Sprintf(
-				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Refinement,
-				formatPreviousSteps(steps),
+			return thoughtStepPrompt(
+				promptTemplate.ThoughtSteps[conf.ThoughtStepTypeTask].Concrete,
+				"Finalize the tasks from the previous steps into executable plans:",
+				steps,
 			)
 		}
 		return ""
@@ -62,16 +76,51 @@ func generateActionsPromptFunc(systemState *SystemState, actions []actions.IActi
 			)
 
 		case PurposeExploration:
+			return thoughtStepPrompt(
+				prompts.ThoughtSteps[conf.ThoughtStepTypeAction].Exploration,
+				"Explore actions the previous steps have not considered yet:",
+				steps,
+			)
 		case PurposeAnalysis:
+			return thoughtStepPrompt(
+				prompts.ThoughtSteps[conf.ThoughtStepTypeAction].Analysis,
+				"Analyze the feasibility, goal alignment and impact of the actions from the previous steps:",
+				steps,
+			)
 		case PurposeReconsider:
+			return thoughtStepPrompt(
+				prompts.ThoughtSteps[conf.ThoughtStepTypeAction].Reconsider,
+				"Reconsider the actions from the previous steps and suggest better alternatives:",
+				steps,
+			)
 		case PurposeRefinement:
+			return thoughtStepPrompt(
+				prompts.ThoughtSteps[conf.ThoughtStepTypeAction].Refinement,
+				"Refine the actions from the previous steps into clear, efficient execution plans:",
+				steps,
+			)
 		case PurposeConcrete:
+			// The plan format is always appended so the final step stays parseable
+			return thoughtStepPrompt(
+				prompts.ThoughtSteps[conf.ThoughtStepTypeAction].Concrete,
+				"Finalize the action plan based on the previous steps:",
+				steps,
+			) + actionPlanInstructions
 		}
 
 		return ""
 	}
 }
 
+// thoughtStepPrompt fills a thought step template with the previous steps,
+// falling back to a plain instruction when the templates leave the step out
+func thoughtStepPrompt(template, fallback string, steps []*ThoughtStep) string {
+	if template == "" {
+		return fallback + "\n" + formatPreviousSteps(steps)
+	}
+	return fmt.Sprintf(template, formatPreviousSteps(steps))
+}
+
 func formatMap(data map[string]interface{}) string {
 	var result string
 	for key, value := range data {
@@ -80,23 +129,39 @@ func formatMap(data map[string]interface{}) string {
 	return result
 }
 
-func buildMessagePrompt(state *SystemState, msg *SocialMessage, stakeholder *Stakeholder, prompts *conf.PromptTemplates) string {
-	template := prompts.Message.Analysis
-	return fmt.Sprintf(
-		template,
-		msg.Platform,
-		msg.FromUser,
-		msg.Content,
-		getHistoricalMessages(stakeholder),
-		strings.Join(state.Character.Style.Tone, ", "),
-		strings.Join(state.Character.MessageExamples, "\n"),
-		formatActions(state.AvailableActions),
-	)
+// platformStyleGuides describes how responses should be shaped for each platform
+var platformStyleGuides = map[string]string{
+	"twitter":  "The response will be posted as a tweet: keep it under 280 characters, in a single short paragraph, without markdown or headings.",
+	"discord":  "The response will be posted in Discord: stay under 2000 characters; light markdown such as bold text and short lists is fine.",
+	"telegram": "The response will be sent on Telegram: it may be detailed and span several paragraphs, but avoid markdown tables.",
 }
 
-func buildSystemPrompt(state *SystemState, stakeholder *Stakeholder, prompts *conf.PromptTemplates) string {
-	// Get prompt templates from config
-	baseTemplate := prompts.System.BaseTemplate
+func buildMessagePrompt(state *SystemState, msg *SocialMessage, stakeholder *Stakeholder, recalled []string, prompts *conf.PromptTemplates) (string, error) {
+	prompt, err := conf.RenderPromptTemplate("message.analysis", prompts.Message.Analysis, conf.MessagePromptData{
+		Platform:        msg.Platform,
+		UserID:          msg.FromUser,
+		Message:         msg.Content,
+		History:         getHistoricalMessages(stakeholder) + formatRecalledMemories(recalled),
+		Tone:            strings.Join(state.Character.Style.Tone, ", "),
+		MessageExamples: strings.Join(state.Character.MessageExamples, "\n"),
+		Actions:         formatActions(state.AvailableActions),
+	})
+	if err != nil {
+		return "", err
+	}
+	return prompt + formatPlatformStyle(msg.Platform), nil
+}
+
+func formatPlatformStyle(platform string) string {
+	guide, ok := platformStyleGuides[strings.ToLower(platform)]
+	if !ok {
+		return ""
+	}
+
+	return "\n\nPlatform constraints:\n" + guide
+}
+
+func buildSystemPrompt(state *SystemState, stakeholder *Stakeholder, prompts *conf.PromptTemplates) (string, error) {
 	infoFormat := prompts.System.InfoFormat
 
 	// Format priority account info
@@ -118,18 +183,81 @@ func buildSystemPrompt(state *SystemState, stakeholder *Stakeholder, prompts *co
 		}
 	}
 
-	// Format the final prompt using the template
-	return fmt.Sprintf(
-		baseTemplate,
-		state.Character.Name,
-		state.Character.System,
-		strings.Join(state.Character.Bio, "\n"),
-		strings.Join(state.Character.Lore, "\n"),
-		formatProviderStates(state.ProviderStates),
-		strings.Join(state.Character.Style.Constraints, "\n"),
-		priorityAccountInfo,
-		tokenBalanceInfo,
-	)
+	prompt, err := conf.RenderPromptTemplate("system.base_template", prompts.System.BaseTemplate, conf.SystemPromptData{
+		CharacterName:       state.Character.Name,
+		System:              state.Character.System,
+		Goals:               formatGoals(state.Character.Goals),
+		Bio:                 strings.Join(state.Character.Bio, "\n"),
+		Lore:                strings.Join(state.Character.Lore, "\n"),
+		Preferences:         formatPreferences(state.Character.Preferences),
+		ProviderStates:      formatProviderStates(state.ProviderStates),
+		Actions:             formatActions(state.AvailableActions),
+		Constraints:         formatConstraints(state.Character),
+		PriorityAccountInfo: priorityAccountInfo,
+		TokenBalanceInfo:    tokenBalanceInfo,
+	})
+	if err != nil {
+		return "", err
+	}
+	return prompt + formatLanguage(replyLanguage(state.Character, stakeholder)), nil
+}
+
+// RenderPrompts returns the system and message prompts the agent would send for
+// msg, without plugins, token balances or history
+func RenderPrompts(character *characters.Character, templates *conf.PromptTemplates, msg *SocialMessage) (string, string, error) {
+	state := &SystemState{
+		Character: character,
+		Timestamp: time.Now(),
+	}
+	stakeholder := &Stakeholder{
+		ID:       msg.FromUser,
+		Platform: msg.Platform,
+		Type:     StakeholderTypeUser,
+	}
+
+	systemPrompt, err := buildSystemPrompt(state, stakeholder, templates)
+	if err != nil {
+		return "", "", err
+	}
+	messagePrompt, err := buildMessagePrompt(state, msg, stakeholder, nil, templates)
+	if err != nil {
+		return "", "", err
+	}
+	return systemPrompt, messagePrompt, nil
+}
+
+// formatConstraints lists the style constraints followed by the forbidden topics as hard constraints
+func formatConstraints(character *characters.Character) string {
+	constraints := append([]string{}, character.Style.Constraints...)
+	for _, topic := range character.ForbiddenTopics {
+		constraints = append(constraints, fmt.Sprintf(
+			"**HARD CONSTRAINT**: Never discuss %s. Politely decline and steer the conversation elsewhere.",
+			topic.Topic,
+		))
+	}
+	return strings.Join(constraints, "\n")
+}
+
+func formatGoals(goals []characters.Goal) string {
+	var result string
+	for _, goal := range goals {
+		result += fmt.Sprintf("\n- %s (priority %.2f): %s", goal.Name, goal.Priority, goal.Description)
+	}
+	return result
+}
+
+func formatPreferences(preferences map[string]float64) string {
+	keys := make([]string, 0, len(preferences))
+	for key := range preferences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result string
+	for _, key := range keys {
+		result += fmt.Sprintf("\n- %s: %.2f", formatKey(key), preferences[key])
+	}
+	return result
 }
 
 func formatActions(actions []actions.IAction) string {
@@ -140,19 +268,29 @@ func formatActions(actions []actions.IAction) string {
 	return result
 }
 
-func generateActionParametersPrompt(state *SystemState, msg *SocialMessage, stakeholder *Stakeholder, action actions.IAction, prompts *conf.PromptTemplates) string {
-	// Create a prompt that explains all the possible types and asks for structured analysis
-	template := prompts.Message.Action
+func generateActionParametersPrompt(state *SystemState, msg *SocialMessage, stakeholder *Stakeholder, action actions.IAction, prompts *conf.PromptTemplates) (string, error) {
+	return conf.RenderPromptTemplate("message.action", prompts.Message.Action, conf.ActionPromptData{
+		Platform:          msg.Platform,
+		Message:           msg.Content,
+		History:           getHistoricalMessages(stakeholder),
+		ActionName:        action.Name(),
+		ActionDescription: action.Description(),
+		ActionParameters:  actionParameters(action),
+	})
+}
 
-	return fmt.Sprintf(
-		template,
-		msg.Platform,
-		msg.Content,
-		getHistoricalMessages(stakeholder),
-		action.Name(),
-		action.Description(),
-		action.ParametersPrompt(),
-	)
+// actionParameters describes the parameters of an action, adding its JSON
+// schema when it declares one
+func actionParameters(action actions.IAction) string {
+	schemaAction, ok := action.(actions.SchemaAction)
+	if !ok || schemaAction.ParametersSchema() == nil {
+		return action.ParametersPrompt()
+	}
+	schema, err := json.MarshalIndent(schemaAction.ParametersSchema(), "", "  ")
+	if err != nil {
+		return action.ParametersPrompt()
+	}
+	return action.ParametersPrompt() + "\nThe parameters must be a JSON object matching this JSON schema:\n" + string(schema)
 }
 
 func getHistoricalMessages(stakeholder *Stakeholder) string {
@@ -163,6 +301,14 @@ func getHistoricalMessages(stakeholder *Stakeholder) string {
 	return strings.Join(stakeholder.HistoricalMsgs, ";")
 }
 
+func formatRecalledMemories(recalled []string) string {
+	if len(recalled) == 0 {
+		return ""
+	}
+
+	return "\nRelevant past interactions with this user:\n" + strings.Join(recalled, "\n---\n")
+}
+
 func formatProviderStates(states []*plugins.ProviderState) string {
 	if len(states) == 0 {
 		return "No additional information available from providers"
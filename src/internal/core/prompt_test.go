@@ -0,0 +1,181 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+func TestBuildMessagePromptPlatformConstraints(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     string
+	}{
+		{platform: "twitter", want: "under 280 characters"},
+		{platform: "Twitter", want: "under 280 characters"},
+		{platform: "discord", want: "under 2000 characters"},
+		{platform: "telegram", want: "several paragraphs"},
+		{platform: "web"},
+	}
+
+	prompts := &conf.PromptTemplates{}
+	prompts.Message.Analysis = "{{.UserID}}: {{.Message}}"
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			prompt, err := buildMessagePrompt(state, &SocialMessage{Platform: tt.platform, Content: "gm"}, nil, nil, prompts)
+			if err != nil {
+				t.Fatalf("buildMessagePrompt() error = %v", err)
+			}
+
+			hasConstraints := strings.Contains(prompt, "Platform constraints:")
+			if hasConstraints != (tt.want != "") {
+				t.Errorf("prompt has platform constraints = %v, want %v", hasConstraints, tt.want != "")
+			}
+			if !strings.Contains(prompt, tt.want) {
+				t.Errorf("prompt does not contain %q:\n%s", tt.want, prompt)
+			}
+		})
+	}
+}
+
+func TestBuildSystemPromptNamedPlaceholders(t *testing.T) {
+	prompts := &conf.PromptTemplates{}
+	prompts.System.BaseTemplate = "You are {{.CharacterName}}.\n{{.System}}\nBio: {{.Bio}}{{.PriorityAccountInfo}}"
+	prompts.System.InfoFormat = map[string]string{"priority_account": "\nThis is a priority account."}
+	state := &SystemState{Character: &characters.Character{
+		Name:   "Tester",
+		System: "Answer briefly.",
+		Bio:    []string{"Loves data"},
+	}}
+
+	tests := []struct {
+		name        string
+		stakeholder *Stakeholder
+		want        string
+	}{
+		{name: "regular user", want: "You are Tester.\nAnswer briefly.\nBio: Loves data"},
+		{
+			name:        "priority account",
+			stakeholder: &Stakeholder{Type: StakeholderTypePriority},
+			want:        "You are Tester.\nAnswer briefly.\nBio: Loves data\nThis is a priority account.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildSystemPrompt(state, tt.stakeholder, prompts)
+			if err != nil {
+				t.Fatalf("buildSystemPrompt() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildSystemPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSystemPromptForbiddenTopics(t *testing.T) {
+	state := &SystemState{Character: &characters.Character{
+		Name:            "Tester",
+		Style:           characters.StyleGuide{Constraints: []string{"Stay friendly"}},
+		ForbiddenTopics: []characters.ForbiddenTopic{{Topic: "financial advice"}},
+	}}
+
+	prompts := &conf.PromptTemplates{}
+	prompts.System.BaseTemplate = "You are {{.CharacterName}}.\n{{.Constraints}}"
+
+	prompt, err := buildSystemPrompt(state, nil, prompts)
+	if err != nil {
+		t.Fatalf("buildSystemPrompt() error = %v", err)
+	}
+	for _, want := range []string{"Stay friendly", "**HARD CONSTRAINT**: Never discuss financial advice."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("system prompt is missing %q", want)
+		}
+	}
+}
+
+func TestActionsPromptForEveryPurpose(t *testing.T) {
+	defaults, err := conf.LoadPromptTemplates("../../config")
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates() error = %v", err)
+	}
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+	pluginActions := []actions.IAction{&fakeAction{name: "balance", typ: "chain"}}
+	steps := []*ThoughtStep{{Type: string(PurposeInitial), Content: "Check the balance of the treasury"}}
+
+	purposes := []StepPurpose{PurposeInitial, PurposeExploration, PurposeAnalysis, PurposeReconsider, PurposeRefinement, PurposeConcrete}
+	for name, templates := range map[string]*conf.PromptTemplates{"default templates": defaults, "no step templates": {}} {
+		generate := generateActionsPromptFunc(state, pluginActions, templates)
+		for _, purpose := range purposes {
+			// The initial step has no fallback and always needs a template
+			if purpose == PurposeInitial && templates != defaults {
+				continue
+			}
+			t.Run(name+"/"+string(purpose), func(t *testing.T) {
+				prompt := generate(purpose, steps)
+				if strings.TrimSpace(prompt) == "" {
+					t.Fatal("prompt is empty")
+				}
+				if strings.Contains(prompt, "%!") {
+					t.Errorf("prompt = %q, want the template filled in", prompt)
+				}
+				if purpose != PurposeInitial && !strings.Contains(prompt, "Check the balance of the treasury") {
+					t.Errorf("prompt = %q, want the previous steps", prompt)
+				}
+			})
+		}
+	}
+}
+
+func TestActionsPromptConcreteStep(t *testing.T) {
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+	steps := []*ThoughtStep{{Type: string(PurposeRefinement), Content: "Check the balance of the treasury"}}
+	withTemplate, err := conf.LoadPromptTemplates("../../config")
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates() error = %v", err)
+	}
+	actionSteps := withTemplate.ThoughtSteps[conf.ThoughtStepTypeAction]
+	actionSteps.Concrete = "Plan the actions below.\n\n%s"
+	withTemplate.ThoughtSteps[conf.ThoughtStepTypeAction] = actionSteps
+
+	tests := []struct {
+		name      string
+		templates *conf.PromptTemplates
+		want      string
+	}{
+		{name: "template", templates: withTemplate, want: "Plan the actions below."},
+		{name: "fallback", templates: &conf.PromptTemplates{}, want: "Finalize the action plan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := generateActionsPromptFunc(state, nil, tt.templates)(PurposeConcrete, steps)
+			for _, want := range []string{tt.want, "Check the balance of the treasury", actionPlanInstructions} {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt = %q, want it to contain %q", prompt, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTasksPromptFallsBackWithoutTaskSteps(t *testing.T) {
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+	steps := []*ThoughtStep{{Type: string(PurposeInitial), Content: "Post a weekly gas report"}}
+	generate := generateTasksPromptFunc(state, &conf.PromptTemplates{})
+
+	for _, purpose := range []StepPurpose{PurposeExploration, PurposeAnalysis, PurposeReconsider, PurposeRefinement, PurposeConcrete} {
+		t.Run(string(purpose), func(t *testing.T) {
+			prompt := generate(purpose, steps)
+			if !strings.Contains(prompt, "Post a weekly gas report") || strings.Contains(prompt, "%!") {
+				t.Errorf("prompt = %q, want a built-in instruction with the previous steps", prompt)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+)
+
+// socialPublisher adapts a SocialClient to the plugins.Publisher interface
+type socialPublisher struct {
+	client     SocialClient
+	moderation *contentFilter
+}
+
+// NewSocialPublisher lets plugins post through the agent's social client,
+// refusing posts that any of the moderators flag
+func NewSocialPublisher(client SocialClient, moderators ...Moderator) plugins.Publisher {
+	return &socialPublisher{
+		client:     client,
+		moderation: newContentFilter(moderators, ""),
+	}
+}
+
+func (p *socialPublisher) Publish(ctx context.Context, platform, content string, metadata map[string]interface{}) error {
+	if reason, flagged := p.moderation.flagged(ctx, content); flagged {
+		return fmt.Errorf("%w: %s", ErrContentFlagged, reason)
+	}
+
+	return p.client.SendMessage(ctx, SocialMessage{
+		Platform: platform,
+		Type:     "Post",
+		Content:  content,
+		Metadata: metadata,
+	})
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSocialPublisherPostsMessage(t *testing.T) {
+	social := &fakeSocial{}
+	metadata := map[string]interface{}{"channel_id": "channel-1"}
+
+	if err := NewSocialPublisher(social).Publish(context.Background(), "discord", "digest", metadata); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(social.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(social.sent))
+	}
+	got := social.sent[0]
+	if got.Platform != "discord" || got.Type != "Post" || got.Content != "digest" || got.Metadata["channel_id"] != "channel-1" {
+		t.Errorf("sent %+v, want a discord post of the digest", got)
+	}
+}
+
+func TestSocialPublisherRefusesFlaggedPost(t *testing.T) {
+	social := &fakeSocial{}
+	publisher := NewSocialPublisher(social, &fakeModerator{flag: "leaked secret"})
+
+	err := publisher.Publish(context.Background(), "twitter", "leaked secret", nil)
+	if !errors.Is(err, ErrContentFlagged) {
+		t.Errorf("Publish() error = %v, want ErrContentFlagged", err)
+	}
+	if len(social.sent) != 0 {
+		t.Errorf("sent %d messages, want the flagged post dropped", len(social.sent))
+	}
+
+	if err := publisher.Publish(context.Background(), "twitter", "daily digest", nil); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(social.sent) != 1 {
+		t.Errorf("sent %d messages, want the clean post", len(social.sent))
+	}
+}
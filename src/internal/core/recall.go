@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+
+	"github.com/google/uuid"
+)
+
+const defaultRecallLimit = 3
+
+// interactionPrefix scopes interaction memories to a single stakeholder
+func interactionPrefix(stakeholder *Stakeholder) string {
+	return fmt.Sprintf("interaction:%s:", stakeholder.Key)
+}
+
+// rememberInteraction stores a message exchange so it can be recalled later
+func (e *CognitiveEngine) rememberInteraction(
+	ctx context.Context,
+	stakeholder *Stakeholder,
+	msg *SocialMessage,
+	response string,
+) error {
+	if e.memory == nil || stakeholder == nil {
+		return nil
+	}
+
+	content := fmt.Sprintf("%s: %s\n%s: %s", msg.FromUser, msg.Content, e.character.Name, response)
+
+	return e.memory.CreateMemory(ctx, memory.Memory{
+		MemoryID:  interactionPrefix(stakeholder) + uuid.NewString(),
+		Content:   content,
+		Embedding: e.embed(ctx, content),
+		CreatedAt: time.Now(),
+	})
+}
+
+// embed returns the embedding for text, or nil when embeddings are unavailable
+// or unsupported by the provider
+func (e *CognitiveEngine) embed(ctx context.Context, text string) []float32 {
+	embeddings, err := e.llm.CreateEmbeddings(ctx, []string{text})
+	if err != nil {
+		if !errors.Is(err, llm.ErrEmbeddingsNotConfigured) && !errors.Is(err, llm.ErrEmbeddingsUnsupported) {
+			e.logger.Warnw("Failed to create embedding", "error", err)
+		}
+		return nil
+	}
+	if len(embeddings) == 0 {
+		return nil
+	}
+	return embeddings[0]
+}
+
+// recallMemories returns past interactions with the stakeholder relevant to the message,
+// skipping anything already present in the recent history
+func (e *CognitiveEngine) recallMemories(
+	ctx context.Context,
+	msg *SocialMessage,
+	stakeholder *Stakeholder,
+) []string {
+	if e.memory == nil || stakeholder == nil {
+		return nil
+	}
+
+	var (
+		memories []*memory.Memory
+		err      error
+	)
+	// Prefer semantic search and fall back to keyword overlap
+	if embedding := e.embed(ctx, msg.Content); embedding != nil {
+		memories, err = e.memory.SearchByEmbedding(ctx, interactionPrefix(stakeholder), embedding, defaultRecallLimit)
+	}
+	if err == nil && len(memories) == 0 {
+		memories, err = e.memory.SearchWithPrefix(ctx, interactionPrefix(stakeholder), msg.Content, defaultRecallLimit)
+	}
+	if err != nil {
+		e.logger.Warnw("Failed to recall memories", "stakeholder", stakeholder.Key, "error", err)
+		return nil
+	}
+
+	recent := make(map[string]bool, len(stakeholder.HistoricalMsgs))
+	for _, historical := range stakeholder.HistoricalMsgs {
+		recent[historical] = true
+	}
+
+	var recalled []string
+	for _, mem := range memories {
+		if recent[mem.Content] {
+			continue
+		}
+		recalled = append(recalled, mem.Content)
+	}
+	return recalled
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+)
+
+func TestRecallMemories(t *testing.T) {
+	alice := &Stakeholder{Key: "alice", HistoricalMsgs: []string{"alice: gas fees again\nTester: still high"}}
+	mem := newFakeMemory()
+	for id, content := range map[string]string{
+		"interaction:alice:1": "alice: what are gas fees on ethereum?\nTester: about 20 gwei",
+		"interaction:alice:2": "alice: my cat likes tuna\nTester: nice",
+		"interaction:alice:3": "alice: gas fees again\nTester: still high",
+		"interaction:bob:1":   "bob: gas fees are wild\nTester: agreed",
+	} {
+		mem.CreateMemory(context.Background(), memory.Memory{MemoryID: id, Content: content})
+	}
+
+	tests := []struct {
+		name        string
+		memory      memory.Manager
+		stakeholder *Stakeholder
+		want        []string
+	}{
+		{
+			name:        "relevant memories of the stakeholder",
+			memory:      mem,
+			stakeholder: alice,
+			want:        []string{"alice: what are gas fees on ethereum?\nTester: about 20 gwei"},
+		},
+		{name: "no memory manager", stakeholder: alice},
+		{name: "no stakeholder", memory: mem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewCognitiveEngine(&fakeLLM{}, "test-model", nil, nil)
+			engine.memory = tt.memory
+
+			got := engine.recallMemories(context.Background(), &SocialMessage{Content: "gas"}, tt.stakeholder)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("recallMemories() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRememberInteractionIsRecalled(t *testing.T) {
+	engine := NewCognitiveEngine(&fakeLLM{}, "test-model", &characters.Character{Name: "Tester"}, nil)
+	engine.memory = newFakeMemory()
+	alice := &Stakeholder{Key: "alice"}
+
+	msg := &SocialMessage{FromUser: "alice", Content: "staking rewards?"}
+	if err := engine.rememberInteraction(context.Background(), alice, msg, "around 4%"); err != nil {
+		t.Fatalf("rememberInteraction() error = %v", err)
+	}
+
+	prompts := &conf.PromptTemplates{}
+	prompts.Message.Analysis = "{{.Message}}\n{{.History}}"
+	recalled := engine.recallMemories(context.Background(), &SocialMessage{Content: "staking"}, alice)
+	prompt, err := buildMessagePrompt(&SystemState{Character: engine.character}, msg, alice, recalled, prompts)
+	if err != nil {
+		t.Fatalf("buildMessagePrompt() error = %v", err)
+	}
+	if !strings.Contains(prompt, "alice: staking rewards?\nTester: around 4%") {
+		t.Errorf("prompt does not include the recalled interaction:\n%s", prompt)
+	}
+}
+
+func TestRecallMemoriesPrefersEmbeddings(t *testing.T) {
+	// Vectors place gas fees and gwei close together with no shared keywords
+	vectors := map[string][]float32{
+		"alice: gwei?\nTester: 20":  {0.9, 0.1},
+		"alice: cats?\nTester: yes": {0, 1},
+		"gas fees":                  {1, 0},
+	}
+	client := &fakeLLM{embed: func(input string) []float32 { return vectors[input] }}
+	engine := NewCognitiveEngine(client, "test-model", &characters.Character{Name: "Tester"}, nil)
+	engine.memory = newFakeMemory()
+	alice := &Stakeholder{Key: "alice"}
+
+	for _, exchange := range [][2]string{{"gwei?", "20"}, {"cats?", "yes"}} {
+		msg := &SocialMessage{FromUser: "alice", Content: exchange[0]}
+		if err := engine.rememberInteraction(context.Background(), alice, msg, exchange[1]); err != nil {
+			t.Fatalf("rememberInteraction() error = %v", err)
+		}
+	}
+
+	got := engine.recallMemories(context.Background(), &SocialMessage{Content: "gas fees"}, alice)
+	if len(got) == 0 || got[0] != "alice: gwei?\nTester: 20" {
+		t.Errorf("recallMemories() = %q, want the gwei exchange first", got)
+	}
+}
+
+func TestRecallMemoriesFallsBackToKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		embedErr error
+	}{
+		{name: "embeddings unsupported", embedErr: llm.ErrEmbeddingsUnsupported},
+		{name: "embeddings not configured", embedErr: llm.ErrEmbeddingsNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{embedErr: tt.embedErr}
+			engine := NewCognitiveEngine(client, "test-model", &characters.Character{Name: "Tester"}, nil)
+			engine.memory = newFakeMemory()
+			alice := &Stakeholder{Key: "alice"}
+
+			msg := &SocialMessage{FromUser: "alice", Content: "gas fees?"}
+			if err := engine.rememberInteraction(context.Background(), alice, msg, "about 20 gwei"); err != nil {
+				t.Fatalf("rememberInteraction() error = %v", err)
+			}
+
+			got := engine.recallMemories(context.Background(), &SocialMessage{Content: "gas"}, alice)
+			if len(got) != 1 || got[0] != "alice: gas fees?\nTester: about 20 gwei" {
+				t.Errorf("recallMemories() = %q, want the keyword match", got)
+			}
+		})
+	}
+}
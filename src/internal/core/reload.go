@@ -0,0 +1,49 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+// ReloadableConfig holds the settings that can change while the agent runs
+type ReloadableConfig struct {
+	PromptTemplates *conf.PromptTemplates
+	Access          struct {
+		DefaultAction string
+		Allow         []string
+		Deny          []string
+	}
+	// RateLimits pace outbound messages by platform
+	RateLimits map[string]conf.RateLimitConfig
+}
+
+// RateLimitReloader is implemented by social clients whose outbound rate
+// limits can be swapped at runtime
+type RateLimitReloader interface {
+	SetRateLimits(limits map[string]conf.RateLimitConfig)
+}
+
+// Reload swaps the reloadable settings into the running agent. Nothing is
+// applied when any of them is invalid.
+func (a *Agent) Reload(config ReloadableConfig) error {
+	access, err := newAccessPolicy(config.Access.DefaultAction, config.Access.Allow, config.Access.Deny)
+	if err != nil {
+		return fmt.Errorf("invalid access config: %w", err)
+	}
+	if config.PromptTemplates != nil {
+		if err := conf.ValidatePromptTemplates(config.PromptTemplates); err != nil {
+			return err
+		}
+	}
+
+	if config.PromptTemplates != nil {
+		a.cognitive.SetPromptTemplates(config.PromptTemplates)
+	}
+	a.access.Store(access)
+	if reloader, ok := a.socialClient.(RateLimitReloader); ok {
+		reloader.SetRateLimits(config.RateLimits)
+	}
+	a.logger.Infoln("Reloaded configuration")
+	return nil
+}
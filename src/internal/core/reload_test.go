@@ -0,0 +1,76 @@
+package core
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// reloadableSocial records the rate limits it is given
+type reloadableSocial struct {
+	fakeSocial
+	mu     sync.Mutex
+	limits map[string]conf.RateLimitConfig
+}
+
+func (s *reloadableSocial) SetRateLimits(limits map[string]conf.RateLimitConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+}
+
+func newReloadAgent(t *testing.T, social SocialClient) *Agent {
+	t.Helper()
+	access, err := newAccessPolicy(AccessActionAllow, nil, nil)
+	if err != nil {
+		t.Fatalf("newAccessPolicy() error = %v", err)
+	}
+	agent := &Agent{
+		cognitive:    NewCognitiveEngine(&fakeLLM{}, "test-model", nil, testTemplates()),
+		logger:       logger.GetLogger(),
+		socialClient: social,
+	}
+	agent.access.Store(access)
+	return agent
+}
+
+func TestAgentReload(t *testing.T) {
+	social := &reloadableSocial{}
+	agent := newReloadAgent(t, social)
+	alice := &SocialMessage{Platform: "twitter", FromUser: "alice"}
+
+	var reload ReloadableConfig
+	reload.Access.DefaultAction = AccessActionDeny
+	reload.RateLimits = map[string]conf.RateLimitConfig{"twitter": {PerMinute: 5}}
+	if err := agent.Reload(reload); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if agent.hasAccess(alice) {
+		t.Error("the reloaded deny default was not applied")
+	}
+	if got := social.limits["twitter"].PerMinute; got != 5 {
+		t.Errorf("twitter rate limit = %v per minute, want the reloaded 5", got)
+	}
+}
+
+func TestAgentReloadRejectsInvalidConfig(t *testing.T) {
+	social := &reloadableSocial{}
+	agent := newReloadAgent(t, social)
+
+	var reload ReloadableConfig
+	reload.Access.DefaultAction = "maybe"
+	reload.RateLimits = map[string]conf.RateLimitConfig{"twitter": {PerMinute: 5}}
+	if err := agent.Reload(reload); err == nil {
+		t.Fatal("Reload() error = nil, want an invalid access error")
+	}
+
+	if !agent.hasAccess(&SocialMessage{Platform: "twitter", FromUser: "alice"}) {
+		t.Error("the previous access policy was replaced by an invalid one")
+	}
+	if social.limits != nil {
+		t.Errorf("rate limits = %v, want none applied", social.limits)
+	}
+}
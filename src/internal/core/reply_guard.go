@@ -0,0 +1,99 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// replyGuard caps how many replies the agent sends to a single conversation
+// within a sliding window, which stops reply loops with other bots. Every
+// message that passes the guard takes a slot, answered or not.
+type replyGuard struct {
+	maxReplies int
+	window     time.Duration
+	replies    map[string][]time.Time
+	// notified holds when each conversation was last told it is throttled
+	notified map[string]time.Time
+	mu       sync.Mutex
+}
+
+func newReplyGuard(maxReplies int, window time.Duration) *replyGuard {
+	return &replyGuard{
+		maxReplies: maxReplies,
+		window:     window,
+		replies:    make(map[string][]time.Time),
+		notified:   make(map[string]time.Time),
+	}
+}
+
+// tryRecord reserves a reply to the conversation, reporting false when the
+// limit is reached. The slot is taken before the reply is generated so
+// concurrent messages can't exceed the limit.
+func (g *replyGuard) tryRecord(key string, now time.Time) bool {
+	if g == nil {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	replies := g.prune(key, now)
+	if len(replies) >= g.maxReplies {
+		return false
+	}
+	g.replies[key] = append(replies, now)
+	return true
+}
+
+// tryNotify reports whether a throttled conversation should be told so,
+// which happens at most once per window
+func (g *replyGuard) tryNotify(key string, now time.Time) bool {
+	if g == nil {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.prune(key, now)
+	if _, ok := g.notified[key]; ok {
+		return false
+	}
+	g.notified[key] = now
+	return true
+}
+
+// prune drops replies and notices older than the window and returns the
+// remaining replies
+func (g *replyGuard) prune(key string, now time.Time) []time.Time {
+	replies := g.replies[key]
+	cutoff := now.Add(-g.window)
+	if notified, ok := g.notified[key]; ok && !notified.After(cutoff) {
+		delete(g.notified, key)
+	}
+
+	i := 0
+	for i < len(replies) && !replies[i].After(cutoff) {
+		i++
+	}
+	replies = replies[i:]
+
+	if len(replies) == 0 {
+		delete(g.replies, key)
+		return nil
+	}
+	g.replies[key] = replies
+	return replies
+}
+
+// conversationKey identifies the conversation a message belongs to
+func conversationKey(msg *SocialMessage) string {
+	key := fmt.Sprintf("%s:%s", msg.Platform, msg.FromUser)
+	for _, field := range []string{"channel_id", "chat_id"} {
+		if id, ok := msg.Metadata[field]; ok {
+			return fmt.Sprintf("%s:%v", key, id)
+		}
+	}
+	return key
+}
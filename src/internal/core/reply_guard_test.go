@@ -0,0 +1,140 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReplyGuardCapsBackAndForth(t *testing.T) {
+	guard := newReplyGuard(3, time.Minute)
+	key := conversationKey(&SocialMessage{Platform: "twitter", FromUser: "other-bot"})
+	start := time.Now()
+
+	// The other bot answers every reply within seconds
+	var sent int
+	for i := 0; i < 10; i++ {
+		now := start.Add(time.Duration(i) * time.Second)
+		if guard.tryRecord(key, now) {
+			sent++
+		}
+	}
+
+	if sent != 3 {
+		t.Errorf("sent %d replies, want 3", sent)
+	}
+	if !guard.tryRecord(key, start.Add(2*time.Minute)) {
+		t.Error("replies are still blocked after the window passed")
+	}
+}
+
+func TestReplyGuardTryRecord(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		max     int
+		replies []time.Duration // offsets from start of earlier replies
+		at      time.Duration
+		want    bool
+	}{
+		{name: "first reply", max: 2, at: 0, want: true},
+		{name: "under the limit", max: 2, replies: []time.Duration{0}, at: time.Second, want: true},
+		{name: "limit reached", max: 2, replies: []time.Duration{0, time.Second}, at: 2 * time.Second, want: false},
+		{name: "old replies expire", max: 2, replies: []time.Duration{0, time.Second}, at: time.Minute + time.Second, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := newReplyGuard(tt.max, time.Minute)
+			for _, offset := range tt.replies {
+				if !guard.tryRecord("telegram:alice", start.Add(offset)) {
+					t.Fatalf("setup reply at %v was rejected", offset)
+				}
+			}
+			if got := guard.tryRecord("telegram:alice", start.Add(tt.at)); got != tt.want {
+				t.Errorf("tryRecord() = %v, want %v", got, tt.want)
+			}
+			if !guard.tryRecord("telegram:bob", start.Add(tt.at)) {
+				t.Error("tryRecord() rejected another conversation")
+			}
+		})
+	}
+}
+
+func TestReplyGuardTryRecordConcurrent(t *testing.T) {
+	const maxReplies = 3
+	guard := newReplyGuard(maxReplies, time.Minute)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if guard.tryRecord("discord:bot", now) {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != maxReplies {
+		t.Errorf("%d concurrent replies allowed, want %d", allowed, maxReplies)
+	}
+}
+
+func TestReplyGuardTryNotify(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := newReplyGuard(1, time.Minute)
+
+	steps := []struct {
+		key  string
+		at   time.Duration
+		want bool
+	}{
+		{key: "telegram:alice", at: 0, want: true},
+		{key: "telegram:alice", at: time.Second, want: false},
+		{key: "telegram:bob", at: 2 * time.Second, want: true},
+		{key: "telegram:alice", at: 59 * time.Second, want: false},
+		{key: "telegram:alice", at: time.Minute + time.Second, want: true},
+	}
+	for _, step := range steps {
+		if got := guard.tryNotify(step.key, start.Add(step.at)); got != step.want {
+			t.Errorf("tryNotify(%s) at %v = %v, want %v", step.key, step.at, got, step.want)
+		}
+	}
+}
+
+func TestReplyGuardNil(t *testing.T) {
+	var guard *replyGuard
+	if !guard.tryRecord("telegram:alice", time.Now()) {
+		t.Error("a disabled guard must allow every reply")
+	}
+	if guard.tryNotify("telegram:alice", time.Now()) {
+		t.Error("a disabled guard never throttles, so it has nothing to notify")
+	}
+}
+
+func TestConversationKey(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *SocialMessage
+		want string
+	}{
+		{name: "user", msg: &SocialMessage{Platform: "twitter", FromUser: "alice"}, want: "twitter:alice"},
+		{name: "discord channel", msg: &SocialMessage{Platform: "discord", FromUser: "alice", Metadata: map[string]interface{}{"channel_id": "c1"}}, want: "discord:alice:c1"},
+		{name: "telegram chat", msg: &SocialMessage{Platform: "telegram", FromUser: "alice", Metadata: map[string]interface{}{"chat_id": int64(42)}}, want: "telegram:alice:42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conversationKey(tt.msg); got != tt.want {
+				t.Errorf("conversationKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
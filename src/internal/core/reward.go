@@ -0,0 +1,211 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultLearningRate   = 0.1
+	defaultBaselineWindow = 20
+	minRewardWeight       = 0.05
+	maxRewardWeight       = 0.7
+	// maxWeightPasses bounds the clamp and renormalize passes of updateWeights
+	maxWeightPasses = 100
+	weightTolerance = 1e-9
+)
+
+// RewardComponents holds per-component scores between 0 and 1 for a single interaction
+type RewardComponents struct {
+	Accuracy  float64 `json:"accuracy"`
+	Coherence float64 `json:"coherence"`
+	Length    float64 `json:"length"`
+	Staking   float64 `json:"staking"`
+}
+
+// LearningEntry records an interaction and its observed outcome
+type LearningEntry struct {
+	ID         string           `json:"id"`
+	Input      string           `json:"input"`
+	Response   string           `json:"response"`
+	Components RewardComponents `json:"components"`
+	// Outcome is the observed feedback between -1 (bad) and 1 (good)
+	Outcome   float64   `json:"outcome"`
+	Reward    float64   `json:"reward"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// RewardModel scores interactions as a weighted sum of reward components
+type RewardModel struct {
+	AccuracyWeight  float64
+	CoherenceWeight float64
+	LengthWeight    float64
+	StakingWeight   float64
+
+	learningRate   float64
+	baselineWindow int
+	recentRewards  []float64
+	mu             sync.Mutex
+}
+
+// NewRewardModel creates a reward model with evenly distributed weights
+func NewRewardModel() *RewardModel {
+	return &RewardModel{
+		AccuracyWeight:  0.25,
+		CoherenceWeight: 0.25,
+		LengthWeight:    0.25,
+		StakingWeight:   0.25,
+		learningRate:    defaultLearningRate,
+		baselineWindow:  defaultBaselineWindow,
+	}
+}
+
+// Score returns the weighted reward for the given components
+func (r *RewardModel) Score(c RewardComponents) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.AccuracyWeight*c.Accuracy +
+		r.CoherenceWeight*c.Coherence +
+		r.LengthWeight*c.Length +
+		r.StakingWeight*c.Staking
+}
+
+// updateWeights moves each weight in the direction of the advantage, the
+// entry outcome minus the baseline, proportionally to how much that component
+// contributed. The weights are then normalized to sum to 1 and kept within
+// [minRewardWeight, maxRewardWeight].
+func (r *RewardModel) updateWeights(entry *LearningEntry, advantage float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	step := r.learningRate * advantage
+	r.AccuracyWeight += step * entry.Components.Accuracy
+	r.CoherenceWeight += step * entry.Components.Coherence
+	r.LengthWeight += step * entry.Components.Length
+	r.StakingWeight += step * entry.Components.Staking
+
+	boundWeights(&r.AccuracyWeight, &r.CoherenceWeight, &r.LengthWeight, &r.StakingWeight)
+}
+
+// boundWeights normalizes the weights, then clamps and renormalizes them until
+// they all settle within the bounds. Clamping before normalizing would let
+// normalization push a weight back out of them.
+func boundWeights(weights ...*float64) {
+	normalizeWeights(weights)
+	for pass := 0; pass < maxWeightPasses; pass++ {
+		settled := true
+		for _, w := range weights {
+			if *w < minRewardWeight-weightTolerance || *w > maxRewardWeight+weightTolerance {
+				*w = clampWeight(*w)
+				settled = false
+			}
+		}
+		if settled {
+			return
+		}
+		normalizeWeights(weights)
+	}
+}
+
+// normalizeWeights scales the weights to sum to 1, spreading them evenly when
+// none is positive
+func normalizeWeights(weights []*float64) {
+	var total float64
+	for _, w := range weights {
+		*w = math.Max(0, *w)
+		total += *w
+	}
+	for _, w := range weights {
+		if total == 0 {
+			*w = 1 / float64(len(weights))
+			continue
+		}
+		*w /= total
+	}
+}
+
+// recordReward appends an observed outcome to the sliding window used for the baseline
+func (r *RewardModel) recordReward(reward float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recentRewards = append(r.recentRewards, reward)
+	if len(r.recentRewards) > r.baselineWindow {
+		r.recentRewards = r.recentRewards[len(r.recentRewards)-r.baselineWindow:]
+	}
+}
+
+// calculateBaseline returns the running mean of the most recent outcomes, so
+// that only outcomes better or worse than usual move the weights
+func (r *RewardModel) calculateBaseline() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.recentRewards) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, reward := range r.recentRewards {
+		sum += reward
+	}
+	return sum / float64(len(r.recentRewards))
+}
+
+func clampWeight(w float64) float64 {
+	return math.Max(minRewardWeight, math.Min(maxRewardWeight, w))
+}
+
+// Learn scores the entry, updates the reward weights and persists the entry to memory
+func (e *CognitiveEngine) Learn(ctx context.Context, entry *LearningEntry) error {
+	if entry == nil {
+		return fmt.Errorf("learning entry cannot be nil")
+	}
+	if e.rewardModel == nil {
+		return fmt.Errorf("reward model not configured")
+	}
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	entry.Reward = e.rewardModel.Score(entry.Components)
+	baseline := e.rewardModel.calculateBaseline()
+	advantage := entry.Outcome - baseline
+	e.rewardModel.updateWeights(entry, advantage)
+	e.rewardModel.recordReward(entry.Outcome)
+
+	e.logger.Infow("Updated reward model",
+		"reward", entry.Reward,
+		"baseline", baseline,
+		"outcome", entry.Outcome,
+		"advantage", advantage,
+	)
+
+	if e.memory == nil {
+		return nil
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal learning entry: %w", err)
+	}
+
+	return e.memory.CreateMemory(ctx, memory.Memory{
+		MemoryID:  fmt.Sprintf("learning:%s", entry.ID),
+		Content:   string(content),
+		CreatedAt: entry.Timestamp,
+	})
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+)
+
+// fakeMemory keeps the memories in a map
+type fakeMemory struct {
+	memories map[string]*memory.Memory
+}
+
+func newFakeMemory() *fakeMemory {
+	return &fakeMemory{memories: make(map[string]*memory.Memory)}
+}
+
+func (f *fakeMemory) CreateMemory(_ context.Context, mem memory.Memory) error {
+	f.memories[mem.MemoryID] = &mem
+	return nil
+}
+
+func (f *fakeMemory) GetMemory(_ context.Context, memoryID string) (*memory.Memory, error) {
+	return f.memories[memoryID], nil
+}
+
+func (f *fakeMemory) SetMemory(_ context.Context, mem *memory.Memory) error {
+	f.memories[mem.MemoryID] = mem
+	return nil
+}
+
+func (f *fakeMemory) Search(ctx context.Context, query string, k int) ([]*memory.Memory, error) {
+	return f.SearchWithPrefix(ctx, "", query, k)
+}
+
+// SearchWithPrefix returns the prefixed memories sharing any word with the query
+func (f *fakeMemory) SearchWithPrefix(_ context.Context, prefix, query string, k int) ([]*memory.Memory, error) {
+	var results []*memory.Memory
+	for id, mem := range f.memories {
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		for _, word := range strings.Fields(strings.ToLower(query)) {
+			if strings.Contains(strings.ToLower(mem.Content), word) {
+				results = append(results, mem)
+				break
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].MemoryID < results[j].MemoryID })
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results, nil
+}
+
+// SearchByEmbedding returns the prefixed memories ordered by cosine similarity
+func (f *fakeMemory) SearchByEmbedding(_ context.Context, prefix string, embedding []float32, k int) ([]*memory.Memory, error) {
+	var results []*memory.Memory
+	for id, mem := range f.memories {
+		if strings.HasPrefix(id, prefix) && len(mem.Embedding) > 0 {
+			results = append(results, mem)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return memory.CosineSimilarity(embedding, results[i].Embedding) > memory.CosineSimilarity(embedding, results[j].Embedding)
+	})
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results, nil
+}
+
+func rewardWeights(r *RewardModel) map[string]float64 {
+	return map[string]float64{
+		"accuracy":  r.AccuracyWeight,
+		"coherence": r.CoherenceWeight,
+		"length":    r.LengthWeight,
+		"staking":   r.StakingWeight,
+	}
+}
+
+func TestUpdateWeightsPositiveOutcomeIncreasesWeight(t *testing.T) {
+	tests := []struct {
+		name       string
+		components RewardComponents
+		weight     string
+	}{
+		{name: "accuracy", components: RewardComponents{Accuracy: 1}, weight: "accuracy"},
+		{name: "coherence", components: RewardComponents{Coherence: 1}, weight: "coherence"},
+		{name: "length", components: RewardComponents{Length: 1}, weight: "length"},
+		{name: "staking", components: RewardComponents{Staking: 1}, weight: "staking"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewRewardModel()
+			before := rewardWeights(model)
+
+			model.updateWeights(&LearningEntry{Components: tt.components, Outcome: 1}, 1)
+
+			after := rewardWeights(model)
+			if after[tt.weight] <= before[tt.weight] {
+				t.Errorf("%s weight = %v, want above %v", tt.weight, after[tt.weight], before[tt.weight])
+			}
+			for name, weight := range after {
+				if name != tt.weight && weight >= before[name] {
+					t.Errorf("%s weight = %v, want below %v", name, weight, before[name])
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateWeightsStayNormalized(t *testing.T) {
+	tests := []struct {
+		name    string
+		outcome float64
+	}{
+		{name: "positive outcomes", outcome: 1},
+		{name: "negative outcomes", outcome: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewRewardModel()
+			model.updateWeights(&LearningEntry{
+				Components: RewardComponents{Accuracy: 1, Coherence: 0.5},
+				Outcome:    tt.outcome,
+			}, tt.outcome)
+
+			var total float64
+			for _, weight := range rewardWeights(model) {
+				total += weight
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Errorf("weights sum to %v, want 1", total)
+			}
+		})
+	}
+}
+
+func TestUpdateWeightsStayBounded(t *testing.T) {
+	tests := []struct {
+		name       string
+		components RewardComponents
+		advantage  float64
+	}{
+		{name: "one component keeps winning", components: RewardComponents{Accuracy: 1}, advantage: 1},
+		{name: "one component keeps losing", components: RewardComponents{Staking: 1}, advantage: -1},
+		{name: "large steps", components: RewardComponents{Accuracy: 1, Length: 0.2}, advantage: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewRewardModel()
+			for i := 0; i < 1000; i++ {
+				model.updateWeights(&LearningEntry{Components: tt.components}, tt.advantage)
+			}
+
+			var total float64
+			for name, weight := range rewardWeights(model) {
+				if weight < minRewardWeight-1e-9 || weight > maxRewardWeight+1e-9 {
+					t.Errorf("%s weight = %v, want within [%v, %v]", name, weight, minRewardWeight, maxRewardWeight)
+				}
+				total += weight
+			}
+			if math.Abs(total-1) > 1e-6 {
+				t.Errorf("weights sum to %v, want 1", total)
+			}
+		})
+	}
+}
+
+func TestRewardModelCalculateBaseline(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   int
+		outcomes []float64
+		want     float64
+	}{
+		{name: "no outcomes", window: 3, want: 0},
+		{name: "mean of outcomes", window: 3, outcomes: []float64{1, -1, 0.5}, want: 0.5 / 3},
+		{name: "only the window counts", window: 2, outcomes: []float64{-1, 1, 0.5}, want: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewRewardModel()
+			model.baselineWindow = tt.window
+			for _, outcome := range tt.outcomes {
+				model.recordReward(outcome)
+			}
+			if got := model.calculateBaseline(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateBaseline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLearnPersistsEntry(t *testing.T) {
+	engine := NewCognitiveEngine(nil, "test-model", nil, nil)
+	mem := newFakeMemory()
+	engine.memory = mem
+
+	entry := &LearningEntry{
+		Input:      "gm",
+		Response:   "gm!",
+		Components: RewardComponents{Accuracy: 1},
+		Outcome:    1,
+	}
+	if err := engine.Learn(context.Background(), entry); err != nil {
+		t.Fatalf("Learn() error = %v", err)
+	}
+
+	stored := mem.memories["learning:"+entry.ID]
+	if stored == nil {
+		t.Fatalf("learning entry %s was not stored", entry.ID)
+	}
+	var persisted LearningEntry
+	if err := json.Unmarshal([]byte(stored.Content), &persisted); err != nil {
+		t.Fatalf("stored entry is not JSON: %v", err)
+	}
+	if persisted.Reward != entry.Reward || persisted.Input != entry.Input {
+		t.Errorf("stored entry = %+v, want %+v", persisted, entry)
+	}
+}
+
+func TestLearnUpdatesWeightsByAdvantage(t *testing.T) {
+	tests := []struct {
+		name         string
+		history      []float64
+		outcome      float64
+		wantIncrease bool
+		wantSame     bool
+	}{
+		{name: "better than usual", history: []float64{0, 0}, outcome: 1, wantIncrease: true},
+		{name: "worse than usual", history: []float64{0.5, 0.5}, outcome: -0.5},
+		{name: "as usual", history: []float64{0.5, 0.5}, outcome: 0.5, wantSame: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewCognitiveEngine(nil, "test-model", nil, nil)
+			for _, outcome := range tt.history {
+				engine.rewardModel.recordReward(outcome)
+			}
+
+			// Only accuracy contributes, so it alone moves before normalizing
+			before := engine.rewardModel.AccuracyWeight
+			err := engine.Learn(context.Background(), &LearningEntry{
+				Components: RewardComponents{Accuracy: 1},
+				Outcome:    tt.outcome,
+			})
+			if err != nil {
+				t.Fatalf("Learn() error = %v", err)
+			}
+
+			after := engine.rewardModel.AccuracyWeight
+			switch {
+			case tt.wantSame && math.Abs(after-before) > 1e-9:
+				t.Errorf("accuracy weight = %v, want unchanged %v", after, before)
+			case !tt.wantSame && tt.wantIncrease && after <= before:
+				t.Errorf("accuracy weight = %v, want above %v", after, before)
+			case !tt.wantSame && !tt.wantIncrease && after >= before:
+				t.Errorf("accuracy weight = %v, want below %v", after, before)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrShutdownTimeout is returned when agent goroutines are still running once
+// the shutdown deadline passes
+var ErrShutdownTimeout = errors.New("shutdown timed out")
+
+// routineGroup tracks the goroutines of the agent by name so shutdown can wait
+// for them and report the ones that didn't stop in time
+type routineGroup struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]int
+}
+
+func newRoutineGroup() *routineGroup {
+	return &routineGroup{running: make(map[string]int)}
+}
+
+// Go runs fn in a goroutine tracked under name
+func (g *routineGroup) Go(name string, fn func()) {
+	g.mu.Lock()
+	g.running[name]++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			g.mu.Lock()
+			if g.running[name]--; g.running[name] <= 0 {
+				delete(g.running, name)
+			}
+			g.mu.Unlock()
+			g.wg.Done()
+		}()
+		fn()
+	}()
+}
+
+// Wait blocks until every tracked goroutine has returned or ctx is done, in
+// which case the error names the goroutines still running
+func (g *routineGroup) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: still running: %s", ErrShutdownTimeout, strings.Join(g.stragglers(), ", "))
+	}
+}
+
+// stragglers returns the names of the running goroutines
+func (g *routineGroup) stragglers() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	names := make([]string, 0, len(g.running))
+	for name, count := range g.running {
+		if count > 1 {
+			name = fmt.Sprintf("%s (%d)", name, count)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
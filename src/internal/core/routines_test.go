@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// monitoringSocial monitors until the context is cancelled, or until release
+// is closed when set, then records that it stopped
+type monitoringSocial struct {
+	fakeSocial
+	messages chan SocialMessage
+	release  chan struct{}
+	stopped  atomic.Bool
+}
+
+func (m *monitoringSocial) GetMessageChannel() <-chan SocialMessage {
+	return m.messages
+}
+
+func (m *monitoringSocial) MonitorMessages(ctx context.Context) {
+	if m.release != nil {
+		<-m.release
+	} else {
+		<-ctx.Done()
+	}
+	// Give Shutdown a chance to return too early
+	time.Sleep(10 * time.Millisecond)
+	m.stopped.Store(true)
+}
+
+// newStartableAgent returns a pipeline agent that can be started and shut down
+func newStartableAgent(t *testing.T, social SocialClient) *Agent {
+	t.Helper()
+	agent := newPipelineAgent(t, &fakeLLM{}, social)
+	agent.ctx, agent.cancel = context.WithCancel(context.Background())
+	agent.routines = newRoutineGroup()
+	return agent
+}
+
+func TestRoutineGroupWait(t *testing.T) {
+	group := newRoutineGroup()
+	var finished atomic.Int32
+	for i := 0; i < 3; i++ {
+		group.Go("worker", func() {
+			time.Sleep(10 * time.Millisecond)
+			finished.Add(1)
+		})
+	}
+
+	if err := group.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if got := finished.Load(); got != 3 {
+		t.Errorf("Wait() returned after %d goroutines finished, want 3", got)
+	}
+	if stragglers := group.stragglers(); len(stragglers) != 0 {
+		t.Errorf("stragglers() = %v, want none", stragglers)
+	}
+}
+
+func TestRoutineGroupWaitTimeout(t *testing.T) {
+	group := newRoutineGroup()
+	release := make(chan struct{})
+	defer close(release)
+	group.Go("stuck", func() { <-release })
+	group.Go("stuck", func() { <-release })
+	group.Go("other", func() { <-release })
+	group.Go("quick", func() {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := group.Wait(ctx)
+	if !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("Wait() error = %v, want ErrShutdownTimeout", err)
+	}
+	if !strings.HasSuffix(err.Error(), "still running: other, stuck (2)") {
+		t.Errorf("Wait() error = %v, want it to list the stuck goroutines", err)
+	}
+}
+
+func TestShutdownWaitsForGoroutines(t *testing.T) {
+	social := &monitoringSocial{messages: make(chan SocialMessage)}
+	agent := newStartableAgent(t, social)
+	if err := agent.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := agent.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !social.stopped.Load() {
+		t.Error("Shutdown() returned before the social monitor stopped")
+	}
+}
+
+func TestShutdownReportsStuckGoroutines(t *testing.T) {
+	social := &monitoringSocial{messages: make(chan SocialMessage), release: make(chan struct{})}
+	defer close(social.release)
+	agent := newStartableAgent(t, social)
+	if err := agent.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := agent.Shutdown(ctx)
+	if !errors.Is(err, ErrShutdownTimeout) || !strings.Contains(err.Error(), "social platform monitor") {
+		t.Errorf("Shutdown() error = %v, want the social platform monitor reported", err)
+	}
+	if strings.Contains(err.Error(), "social message processor") {
+		t.Errorf("Shutdown() error = %v, want only the stuck goroutine reported", err)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// ScheduledAction runs an action with preset parameters on a cron schedule
+type ScheduledAction struct {
+	Name string
+	// Schedule is a standard five-field cron expression or a descriptor such as "@daily"
+	Schedule string
+	Action   string
+	Params   map[string]interface{}
+}
+
+// clock abstracts time so schedules can be driven by a fake clock
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type scheduledJob struct {
+	action   ScheduledAction
+	schedule cron.Schedule
+}
+
+// scheduler fires scheduled actions at their next cron time
+type scheduler struct {
+	jobs   []scheduledJob
+	clock  clock
+	run    func(ctx context.Context, action ScheduledAction) error
+	logger *zap.SugaredLogger
+	wg     sync.WaitGroup
+}
+
+func newScheduler(
+	actions []ScheduledAction,
+	clk clock,
+	run func(ctx context.Context, action ScheduledAction) error,
+) (*scheduler, error) {
+	jobs := make([]scheduledJob, 0, len(actions))
+	for _, action := range actions {
+		schedule, err := cron.ParseStandard(action.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q for %s: %w", action.Schedule, action.Name, err)
+		}
+		if action.Action == "" {
+			return nil, fmt.Errorf("missing action for schedule %s", action.Name)
+		}
+		jobs = append(jobs, scheduledJob{action: action, schedule: schedule})
+	}
+
+	return &scheduler{
+		jobs:   jobs,
+		clock:  clk,
+		run:    run,
+		logger: logger.GetLogger(),
+	}, nil
+}
+
+// start runs every job in its own loop until ctx is cancelled
+func (s *scheduler) start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job scheduledJob) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// wait blocks until all job loops have stopped
+func (s *scheduler) wait() {
+	s.wg.Wait()
+}
+
+func (s *scheduler) loop(ctx context.Context, job scheduledJob) {
+	for {
+		now := s.clock.Now()
+		next := job.schedule.Next(now)
+
+		select {
+		case <-s.clock.After(next.Sub(now)):
+			s.logger.Infow("Running scheduled action",
+				"schedule", job.action.Name,
+				"action", job.action.Action,
+			)
+			if err := s.run(ctx, job.action); err != nil {
+				s.logger.Errorw("Scheduled action failed",
+					"schedule", job.action.Name,
+					"action", job.action.Action,
+					"error", err,
+				)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
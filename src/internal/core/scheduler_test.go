@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock hands every timer to the test, which decides when it fires
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers chan fakeTimer
+}
+
+type fakeTimer struct {
+	d    time.Duration
+	fire chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, timers: make(chan fakeTimer)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	timer := fakeTimer{d: d, fire: make(chan time.Time, 1)}
+	c.timers <- timer
+	return timer.fire
+}
+
+// advance moves the clock past the timer and fires it
+func (c *fakeClock) advance(timer fakeTimer) {
+	c.mu.Lock()
+	c.now = c.now.Add(timer.d)
+	now := c.now
+	c.mu.Unlock()
+	timer.fire <- now
+}
+
+func TestSchedulerFiresAtCronTime(t *testing.T) {
+	start := time.Date(2025, 1, 1, 8, 30, 0, 0, time.UTC)
+	clk := newFakeClock(start)
+
+	ran := make(chan time.Time, 1)
+	var got ScheduledAction
+	sched, err := newScheduler(
+		[]ScheduledAction{{
+			Name:     "daily_gas_digest",
+			Schedule: "0 9 * * *",
+			Action:   "gas_digest",
+			Params:   map[string]interface{}{"days": 7},
+		}},
+		clk,
+		func(_ context.Context, action ScheduledAction) error {
+			got = action
+			ran <- clk.Now()
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("newScheduler() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sched.start(ctx)
+
+	timer := <-clk.timers
+	if timer.d != 30*time.Minute {
+		t.Fatalf("first timer = %v, want 30m until 09:00", timer.d)
+	}
+	select {
+	case <-ran:
+		t.Fatal("action ran before its schedule")
+	default:
+	}
+
+	clk.advance(timer)
+	if at := <-ran; !at.Equal(start.Add(30 * time.Minute)) {
+		t.Errorf("action ran at %v, want 09:00", at)
+	}
+	if got.Action != "gas_digest" || got.Params["days"] != 7 {
+		t.Errorf("ran %+v, want gas_digest with its params", got)
+	}
+
+	// The next run is scheduled for the following day
+	if timer := <-clk.timers; timer.d != 24*time.Hour {
+		t.Errorf("second timer = %v, want 24h", timer.d)
+	}
+
+	cancel()
+	sched.wait()
+}
+
+func TestNewSchedulerRejectsInvalidSchedules(t *testing.T) {
+	tests := []struct {
+		name   string
+		action ScheduledAction
+	}{
+		{name: "invalid cron expression", action: ScheduledAction{Name: "bad", Schedule: "every morning", Action: "post"}},
+		{name: "missing action", action: ScheduledAction{Name: "empty", Schedule: "@daily"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newScheduler([]ScheduledAction{tt.action}, realClock{}, nil)
+			if err == nil {
+				t.Error("newScheduler() error = nil, want an error")
+			}
+		})
+	}
+}
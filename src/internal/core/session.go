@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// clarificationTTL is how long a pending clarification waits for the user's answer
+const clarificationTTL = 10 * time.Minute
+
+// pendingClarification is an action that paused to ask the user for more information
+type pendingClarification struct {
+	actionName string
+	actionType string
+	// request is the message that triggered the action
+	request string
+	// question is what the agent asked the user
+	question  string
+	expiresAt time.Time
+}
+
+// conversationSession is the state of an ongoing conversation with a stakeholder
+type conversationSession struct {
+	clarification *pendingClarification
+}
+
+// sessionStore keeps the conversation state of each stakeholder between messages
+type sessionStore struct {
+	sessions map[string]*conversationSession
+	mu       sync.Mutex
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*conversationSession),
+	}
+}
+
+// sessionKey identifies the conversation with a stakeholder
+func sessionKey(stakeholder *Stakeholder) string {
+	return fmt.Sprintf("%s:%s", stakeholder.Platform, stakeholder.ID)
+}
+
+// setClarification records that the conversation waits for the user's answer
+func (s *sessionStore) setClarification(key string, clarification *pendingClarification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		session = &conversationSession{}
+		s.sessions[key] = session
+	}
+	session.clarification = clarification
+}
+
+// takeClarification removes and returns the pending clarification unless it expired
+func (s *sessionStore) takeClarification(key string, now time.Time) (*pendingClarification, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok || session.clarification == nil {
+		return nil, false
+	}
+
+	clarification := session.clarification
+	delete(s.sessions, key)
+	if now.After(clarification.expiresAt) {
+		return nil, false
+	}
+	return clarification, true
+}
+
+// resumedMessage combines the original request, the question and the user's
+// answer so the action parameters can be generated from the whole exchange
+func resumedMessage(msg *SocialMessage, clarification *pendingClarification) *SocialMessage {
+	resumed := *msg
+	resumed.Content = fmt.Sprintf("%s\n\nYou asked: %s\nUser's answer: %s",
+		clarification.request, clarification.question, msg.Content)
+	return &resumed
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+)
+
+func TestSessionStoreTakeClarification(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		key    string
+		at     time.Time
+		wantOK bool
+	}{
+		{name: "answer within the TTL", key: "telegram:alice", at: now.Add(time.Minute), wantOK: true},
+		{name: "answer after the TTL", key: "telegram:alice", at: now.Add(clarificationTTL + time.Second)},
+		{name: "another stakeholder", key: "telegram:bob", at: now.Add(time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newSessionStore()
+			store.setClarification("telegram:alice", &pendingClarification{actionName: "balance", expiresAt: now.Add(clarificationTTL)})
+
+			clarification, ok := store.takeClarification(tt.key, tt.at)
+			if ok != tt.wantOK {
+				t.Fatalf("takeClarification() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && clarification.actionName != "balance" {
+				t.Errorf("clarification action = %s, want balance", clarification.actionName)
+			}
+			// The clarification is only resumed once
+			if _, ok := store.takeClarification(tt.key, tt.at); ok {
+				t.Error("clarification was resumed twice")
+			}
+		})
+	}
+}
+
+func TestClarifyReplyResume(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+		}),
+		`{"more_info_needed": true, "reply_message": "Which address?"}`,
+		// The answer alone does not ask for the action, the session resumes it
+		analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "Checking 0xabc."}),
+		`{"address": "0xabc"}`,
+	)}
+	social := &fakeSocial{}
+	action := &fakeAction{name: "balance", typ: "chain"}
+	agent := newPipelineAgent(t, client, social, action)
+
+	for _, content := range []string{"What's my balance?", "0xabc"} {
+		if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: content}); err != nil {
+			t.Fatalf("processMessage(%q) error = %v", content, err)
+		}
+	}
+
+	if sent := social.contents(); len(sent) != 2 || sent[0] != "Which address?" || sent[1] != "Checking 0xabc." {
+		t.Errorf("sent %q, want the question and then the reply", sent)
+	}
+	if len(action.executed) != 1 || action.executed[0]["address"] != "0xabc" {
+		t.Fatalf("action ran with %v, want once with the answered address", action.executed)
+	}
+
+	// The parameters of the resumed action are generated from the whole exchange
+	prompt := client.requests[3].Messages[1].Content
+	for _, want := range []string{"balance: ", "What's my balance?", "You asked: Which address?", "User's answer: 0xabc"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("action prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestClarificationIsNotResumedForAnotherUser(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "balance", ActionType: "chain"}},
+		}),
+		`{"more_info_needed": true, "reply_message": "Which address?"}`,
+		analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm"}),
+	)}
+	action := &fakeAction{name: "balance", typ: "chain"}
+	agent := newPipelineAgent(t, client, &fakeSocial{}, action)
+
+	agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "What's my balance?"})
+	agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "bob", Content: "0xabc"})
+
+	if len(action.executed) != 0 {
+		t.Errorf("action ran %d times, want it to wait for alice", len(action.executed))
+	}
+	if _, ok := agent.sessions.takeClarification("telegram:alice", time.Now()); !ok {
+		t.Error("alice's clarification is no longer pending")
+	}
+}
+
+func TestAmbiguousActionAsksForClarification(t *testing.T) {
+	client := &fakeLLM{respond: replies(t,
+		analysis(t, ProcessedMessage{
+			ShouldGenerateAction: true,
+			Actions:              []ProcessedAction{{ActionName: "fetch_transaction", ActionType: "data"}},
+		}),
+		`{"message": "show me the thing"}`,
+	)}
+	social := &fakeSocial{}
+	action := &fakeAction{name: "fetch_transaction", typ: "data", err: &actions.ClarificationError{Question: "Which address do you mean?"}}
+	agent := newPipelineAgent(t, client, social, action)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "telegram", FromUser: "alice", Content: "show me the thing"}); err != nil {
+		t.Fatalf("processMessage() error = %v, want the agent to ask instead", err)
+	}
+
+	if sent := social.contents(); len(sent) != 1 || sent[0] != "Which address do you mean?" {
+		t.Errorf("sent %q, want only the question", sent)
+	}
+	clarification, ok := agent.sessions.takeClarification("telegram:alice", time.Now())
+	if !ok || clarification.actionName != "fetch_transaction" || clarification.request != "show me the thing" {
+		t.Errorf("pending clarification = %+v, want the ambiguous request", clarification)
+	}
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults for telling persistent social platform failures from blips
+const (
+	defaultSocialErrorThreshold = 5
+	defaultSocialErrorWindow    = 10 * time.Minute
+)
+
+// socialErrorAlert is where alerts about persistent failures are posted
+type socialErrorAlert struct {
+	Platform  string
+	ChannelID string
+}
+
+// PlatformError is reported by the social client when sending to or
+// monitoring a platform fails
+type PlatformError struct {
+	Platform string
+	// Op is "send" or "monitor"
+	Op  string
+	Err error
+}
+
+func (e *PlatformError) Error() string {
+	return fmt.Sprintf("%s %s error: %v", e.Platform, e.Op, e.Err)
+}
+
+func (e *PlatformError) Unwrap() error {
+	return e.Err
+}
+
+// SocialErrorSource is implemented by social clients that report their errors
+type SocialErrorSource interface {
+	GetErrorChannel() <-chan error
+}
+
+// MonitorPauser is implemented by social clients whose monitoring of a
+// platform can be paused, e.g. while the platform keeps failing
+type MonitorPauser interface {
+	PauseMonitoring(platform string, until time.Time)
+}
+
+// platformOf returns the platform an error came from, "unknown" when not reported
+func platformOf(err error) string {
+	var platformErr *PlatformError
+	if errors.As(err, &platformErr) && platformErr.Platform != "" {
+		return platformErr.Platform
+	}
+	return "unknown"
+}
+
+// socialErrorTracker counts the errors of each platform within a sliding
+// window to tell blips from persistent failures
+type socialErrorTracker struct {
+	threshold int
+	window    time.Duration
+	errors    map[string][]time.Time
+	alertedAt map[string]time.Time
+	mu        sync.Mutex
+}
+
+func newSocialErrorTracker(threshold int, window time.Duration) *socialErrorTracker {
+	return &socialErrorTracker{
+		threshold: threshold,
+		window:    window,
+		errors:    make(map[string][]time.Time),
+		alertedAt: make(map[string]time.Time),
+	}
+}
+
+// record counts an error of the platform and reports whether the platform
+// just reached the threshold; it reports at most once per window
+func (t *socialErrorTracker) record(platform string, now time.Time) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(-t.window)
+	recent := t.errors[platform]
+	i := 0
+	for i < len(recent) && !recent[i].After(cutoff) {
+		i++
+	}
+	recent = append(recent[i:], now)
+	t.errors[platform] = recent
+
+	if len(recent) < t.threshold {
+		return len(recent), false
+	}
+	if alertedAt, ok := t.alertedAt[platform]; ok && alertedAt.After(cutoff) {
+		return len(recent), false
+	}
+	t.alertedAt[platform] = now
+	return len(recent), true
+}
+
+// monitorSocialErrors logs the errors reported by the social client. When a
+// platform keeps failing it alerts the ops channel, if configured, and pauses
+// monitoring of the platform for a window.
+func (a *Agent) monitorSocialErrors(errs <-chan error) {
+	for {
+		select {
+		case err := <-errs:
+			a.handleSocialError(err, time.Now())
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *Agent) handleSocialError(err error, now time.Time) {
+	platform := platformOf(err)
+	count, persistent := a.socialErrors.record(platform, now)
+	a.logger.Errorw("Social platform error",
+		"platform", platform,
+		"recent_errors", count,
+		"error", err,
+	)
+	if !persistent {
+		return
+	}
+
+	a.logger.Warnw("Social platform keeps failing, pausing monitoring",
+		"platform", platform,
+		"errors", count,
+		"window", a.socialErrors.window,
+	)
+	if pauser, ok := a.socialClient.(MonitorPauser); ok {
+		pauser.PauseMonitoring(platform, now.Add(a.socialErrors.window))
+	}
+
+	// Alerting on the failing platform itself would only fail again
+	if a.errorAlert.Platform == "" || a.errorAlert.Platform == platform {
+		return
+	}
+	alertErr := a.socialClient.SendMessage(a.ctx, SocialMessage{
+		Platform: a.errorAlert.Platform,
+		Type:     "Post",
+		Content: fmt.Sprintf("Alert: %d %s errors in the last %s, latest: %v",
+			count, platform, a.socialErrors.window, err),
+		Metadata: map[string]interface{}{"channel_id": a.errorAlert.ChannelID},
+	})
+	if alertErr != nil {
+		a.logger.Errorw("Failed to send social error alert", "platform", a.errorAlert.Platform, "error", alertErr)
+	}
+}
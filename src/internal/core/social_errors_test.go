@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// pausingSocial records the platforms whose monitoring is paused
+type pausingSocial struct {
+	fakeSocial
+	paused map[string]time.Time
+}
+
+func (p *pausingSocial) PauseMonitoring(platform string, until time.Time) {
+	if p.paused == nil {
+		p.paused = make(map[string]time.Time)
+	}
+	p.paused[platform] = until
+}
+
+func TestMonitorSocialErrorsLogsErrors(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	ctx, cancel := context.WithCancel(context.Background())
+	agent := &Agent{
+		logger:       zap.New(observed).Sugar(),
+		socialClient: &fakeSocial{},
+		socialErrors: newSocialErrorTracker(defaultSocialErrorThreshold, defaultSocialErrorWindow),
+		ctx:          ctx,
+	}
+
+	errs := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		agent.monitorSocialErrors(errs)
+		close(done)
+	}()
+
+	// The unbuffered send only completes once the agent consumes the error
+	errs <- &PlatformError{Platform: "twitter", Op: "monitor", Err: errors.New("rate limited")}
+	cancel()
+	<-done
+
+	entries := logs.FilterMessage("Social platform error").All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d social errors, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["platform"] != "twitter" || fields["recent_errors"] != int64(1) {
+		t.Errorf("logged fields %v, want the twitter error counted", fields)
+	}
+}
+
+func TestHandleSocialErrorPersistentFailure(t *testing.T) {
+	social := &pausingSocial{}
+	agent := &Agent{
+		logger:       zap.NewNop().Sugar(),
+		socialClient: social,
+		socialErrors: newSocialErrorTracker(2, time.Minute),
+		errorAlert:   socialErrorAlert{Platform: "discord", ChannelID: "ops"},
+		ctx:          context.Background(),
+	}
+	now := time.Now()
+	err := &PlatformError{Platform: "twitter", Op: "monitor", Err: errors.New("rate limited")}
+
+	agent.handleSocialError(err, now)
+	if len(social.paused) != 0 || len(social.sent) != 0 {
+		t.Fatalf("a single error paused %v and alerted %v, want neither", social.paused, social.sent)
+	}
+
+	agent.handleSocialError(err, now.Add(time.Second))
+	if until := social.paused["twitter"]; !until.Equal(now.Add(time.Second + time.Minute)) {
+		t.Errorf("twitter monitoring paused until %v, want a window after the failure", until)
+	}
+	if len(social.sent) != 1 || social.sent[0].Platform != "discord" || social.sent[0].Metadata["channel_id"] != "ops" {
+		t.Fatalf("alerts = %+v, want one to the ops channel", social.sent)
+	}
+
+	// Further errors within the window do not alert again
+	agent.handleSocialError(err, now.Add(2*time.Second))
+	if len(social.sent) != 1 {
+		t.Errorf("sent %d alerts, want 1 per window", len(social.sent))
+	}
+}
+
+func TestSocialErrorTrackerWindow(t *testing.T) {
+	tracker := newSocialErrorTracker(2, time.Minute)
+	now := time.Now()
+
+	if count, persistent := tracker.record("discord", now); count != 1 || persistent {
+		t.Errorf("record() = %d, %v, want 1, false", count, persistent)
+	}
+	// The first error has left the window
+	if count, persistent := tracker.record("discord", now.Add(2*time.Minute)); count != 1 || persistent {
+		t.Errorf("record() = %d, %v, want 1, false", count, persistent)
+	}
+	if count, persistent := tracker.record("telegram", now.Add(2*time.Minute)); count != 1 || persistent {
+		t.Errorf("record() of another platform = %d, %v, want 1, false", count, persistent)
+	}
+	if count, persistent := tracker.record("discord", now.Add(2*time.Minute+time.Second)); count != 2 || !persistent {
+		t.Errorf("record() = %d, %v, want 2, true", count, persistent)
+	}
+}
+
+func TestPlatformOf(t *testing.T) {
+	wrapped := errors.Join(errors.New("context"), &PlatformError{Platform: "telegram", Err: errors.New("timeout")})
+	if got := platformOf(wrapped); got != "telegram" {
+		t.Errorf("platformOf() = %q, want telegram", got)
+	}
+	if got := platformOf(errors.New("boom")); got != "unknown" {
+		t.Errorf("platformOf() = %q, want unknown", got)
+	}
+}
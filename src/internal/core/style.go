@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+)
+
+// sentenceEnd matches the end of a sentence followed by whitespace
+var sentenceEnd = regexp.MustCompile(`[.!?]+\s+`)
+
+// extraSpaces and spaceBeforePunct match the spacing left behind by removed text
+var (
+	extraSpaces      = regexp.MustCompile(`[ \t]{2,}`)
+	spaceBeforePunct = regexp.MustCompile(`[ \t]+([.,!?;:])`)
+)
+
+// styleEnforcer checks replies against the style rules of the character
+type styleEnforcer struct {
+	rules characters.StyleRules
+}
+
+// newStyleEnforcer returns nil when the rules check nothing
+func newStyleEnforcer(rules characters.StyleRules) *styleEnforcer {
+	if rules.MaxLength <= 0 && rules.MaxSentences <= 0 && len(rules.BannedPhrases) == 0 && !rules.NoEmojis {
+		return nil
+	}
+	return &styleEnforcer{rules: rules}
+}
+
+// violations describes every rule the text breaks
+func (s *styleEnforcer) violations(text string) []string {
+	var found []string
+	if s.rules.NoEmojis && strings.IndexFunc(text, isEmoji) >= 0 {
+		found = append(found, "must not use emojis")
+	}
+	lower := strings.ToLower(text)
+	for _, phrase := range s.rules.BannedPhrases {
+		if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+			found = append(found, fmt.Sprintf("must not say %q", phrase))
+		}
+	}
+	if s.rules.MaxSentences > 0 && len(splitSentences(text)) > s.rules.MaxSentences {
+		found = append(found, fmt.Sprintf("must be at most %d sentences", s.rules.MaxSentences))
+	}
+	if s.rules.MaxLength > 0 && utf8.RuneCountInString(text) > s.rules.MaxLength {
+		found = append(found, fmt.Sprintf("must be at most %d characters", s.rules.MaxLength))
+	}
+	return found
+}
+
+// trim cuts the text into shape: emojis and banned phrases are removed, then
+// extra sentences and characters are dropped
+func (s *styleEnforcer) trim(text string) string {
+	if s.rules.NoEmojis {
+		text = strings.Map(func(r rune) rune {
+			if isEmoji(r) {
+				return -1
+			}
+			return r
+		}, text)
+	}
+	for _, phrase := range s.rules.BannedPhrases {
+		if phrase != "" {
+			text = regexp.MustCompile(`(?i)`+regexp.QuoteMeta(phrase)).ReplaceAllString(text, "")
+		}
+	}
+	text = extraSpaces.ReplaceAllString(text, " ")
+	text = strings.TrimSpace(spaceBeforePunct.ReplaceAllString(text, "$1"))
+
+	if sentences := splitSentences(text); s.rules.MaxSentences > 0 && len(sentences) > s.rules.MaxSentences {
+		text = strings.Join(sentences[:s.rules.MaxSentences], " ")
+	}
+	if s.rules.MaxLength > 0 {
+		text = truncateRunes(text, s.rules.MaxLength)
+	}
+	return text
+}
+
+// enforceStyle makes a reply follow the style rules of the character. With
+// the regenerate policy the LLM rewrites a violating reply first; whatever
+// still violates the rules is trimmed.
+func (a *Agent) enforceStyle(ctx context.Context, text string) string {
+	if a.style == nil || text == "" {
+		return text
+	}
+	violations := a.style.violations(text)
+	if len(violations) == 0 {
+		return text
+	}
+
+	if a.style.rules.OnViolation == characters.StyleViolationRegenerate {
+		rewritten, err := a.cognitive.rewriteForStyle(ctx, text, violations)
+		if err != nil {
+			a.logger.Warnw("Failed to regenerate reply for style, trimming instead", "error", err)
+		} else if rewritten != "" {
+			text = rewritten
+			violations = a.style.violations(text)
+		}
+	}
+
+	if len(violations) > 0 {
+		a.logger.Infow("Trimming reply that breaks the style rules", "violations", violations)
+		text = a.style.trim(text)
+	}
+	return text
+}
+
+// rewriteForStyle asks the LLM to rewrite a reply so it follows the rules it broke
+func (e *CognitiveEngine) rewriteForStyle(ctx context.Context, text string, violations []string) (string, error) {
+	prompt := fmt.Sprintf(
+		"Rewrite the reply below so it keeps its meaning but follows every rule. "+
+			"Answer with the rewritten reply only.\n\nRules: the reply %s.\n\nReply:\n%s",
+		strings.Join(violations, "; "), text,
+	)
+	response, err := e.llm.CreateCompletion(ctx, llm.CompletionRequest{
+		Model:    e.model,
+		Messages: []llm.Message{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrite reply: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// splitSentences splits text after each sentence end
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		if sentence := strings.TrimSpace(text[start:loc[1]]); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = loc[1]
+	}
+	if rest := strings.TrimSpace(text[start:]); rest != "" {
+		sentences = append(sentences, rest)
+	}
+	return sentences
+}
+
+// truncateRunes cuts text to at most limit characters, at a word boundary when
+// possible, marking the cut with an ellipsis
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if limit <= 1 {
+		return string(runes[:limit])
+	}
+
+	cut := string(runes[:limit-1])
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-") + "…"
+}
+
+// isEmoji reports whether r is an emoji or a part of one, such as a variation
+// selector or zero width joiner
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols, dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows and stars
+		return true
+	case r == 0x200D, r >= 0xFE00 && r <= 0xFE0F, r >= 0xE0020 && r <= 0xE007F:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+func TestStyleEnforcerTrim(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules characters.StyleRules
+		text  string
+		want  string
+	}{
+		{
+			name:  "over length",
+			rules: characters.StyleRules{MaxLength: 20},
+			text:  "The whale moved ten thousand ETH today",
+			want:  "The whale moved…",
+		},
+		{
+			name:  "too many sentences",
+			rules: characters.StyleRules{MaxSentences: 2},
+			text:  "Gas is low. Volume is up. Whales are quiet.",
+			want:  "Gas is low. Volume is up.",
+		},
+		{
+			name:  "emojis",
+			rules: characters.StyleRules{NoEmojis: true},
+			text:  "Gas is low 🚀🔥 today 👍!",
+			want:  "Gas is low today!",
+		},
+		{
+			name:  "banned phrase",
+			rules: characters.StyleRules{BannedPhrases: []string{"to the moon"}},
+			text:  "Volume is up To The Moon today.",
+			want:  "Volume is up today.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := newStyleEnforcer(tt.rules)
+			if style == nil {
+				t.Fatal("newStyleEnforcer() = nil, want an enforcer")
+			}
+			got := style.trim(tt.text)
+			if got != tt.want {
+				t.Errorf("trim() = %q, want %q", got, tt.want)
+			}
+			if violations := style.violations(got); len(violations) != 0 {
+				t.Errorf("trimmed reply still violates %v", violations)
+			}
+		})
+	}
+}
+
+func TestNewStyleEnforcerWithoutRules(t *testing.T) {
+	if style := newStyleEnforcer(characters.StyleRules{OnViolation: characters.StyleViolationTrim}); style != nil {
+		t.Errorf("newStyleEnforcer() = %+v, want nil when no rule is set", style)
+	}
+}
+
+func TestEnforceStyleRegenerates(t *testing.T) {
+	tests := []struct {
+		name      string
+		rewritten string
+		want      string
+	}{
+		{name: "rewrite follows the rules", rewritten: "Gas is low today.", want: "Gas is low today."},
+		{name: "rewrite still violates", rewritten: "Gas is low 🚀", want: "Gas is low"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: func(llm.CompletionRequest) string { return tt.rewritten }}
+			agent := &Agent{
+				cognitive: NewCognitiveEngine(client, "test-model", nil, testTemplates()),
+				logger:    logger.GetLogger(),
+				style: newStyleEnforcer(characters.StyleRules{
+					NoEmojis:    true,
+					OnViolation: characters.StyleViolationRegenerate,
+				}),
+			}
+
+			if got := agent.enforceStyle(context.Background(), "Gas is low 🔥"); got != tt.want {
+				t.Errorf("enforceStyle() = %q, want %q", got, tt.want)
+			}
+			if len(client.requests) != 1 {
+				t.Fatalf("made %d LLM calls, want one rewrite", len(client.requests))
+			}
+			if prompt := client.requests[0].Messages[0].Content; !strings.Contains(prompt, "must not use emojis") {
+				t.Errorf("rewrite prompt = %q, want the broken rule", prompt)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	got := truncateRunes("ääääääääää", 5)
+	if utf8.RuneCountInString(got) > 5 || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateRunes() = %q, want at most 5 characters ending in an ellipsis", got)
+	}
+}
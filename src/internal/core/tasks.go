@@ -0,0 +1,225 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+
+	"github.com/google/uuid"
+)
+
+// TaskStatus is the lifecycle state of a task
+type TaskStatus string
+
+const (
+	TaskStatusPending TaskStatus = "pending"
+	TaskStatusRunning TaskStatus = "running"
+	TaskStatusDone    TaskStatus = "done"
+	TaskStatusFailed  TaskStatus = "failed"
+)
+
+// ErrInvalidTaskTransition is returned for a status change the task lifecycle doesn't allow
+var ErrInvalidTaskTransition = errors.New("invalid task status transition")
+
+// taskTransitions lists the statuses each status may move to:
+// pending -> running -> done or failed
+var taskTransitions = map[TaskStatus][]TaskStatus{
+	TaskStatusPending: {TaskStatusRunning},
+	TaskStatusRunning: {TaskStatusDone, TaskStatusFailed},
+}
+
+// Task is a unit of proactive work the agent plans for itself from the goals
+// of its character
+type Task struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Priority    float64 `json:"priority"`
+	// Tools names the actions that carry out the task
+	Tools          []string `json:"tools"`
+	ExecutionSteps []string `json:"execution_steps"`
+	// RequiresApproval tasks are proposed to the priority accounts instead of run
+	RequiresApproval         bool `json:"requires_approval"`
+	RequiresStakeholderInput bool `json:"requires_stakeholder_input"`
+
+	Status TaskStatus `json:"status"`
+	// Error is why the task failed
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// newTask prepares a planned task as pending with a fresh ID
+func newTask(task *Task, now time.Time) *Task {
+	task.ID = uuid.NewString()
+	task.Status = TaskStatusPending
+	task.Error = ""
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	return task
+}
+
+// transition moves the task to the given status if its lifecycle allows it
+func (t *Task) transition(to TaskStatus, now time.Time) error {
+	for _, allowed := range taskTransitions[t.Status] {
+		if allowed == to {
+			t.Status = to
+			t.UpdatedAt = now
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s to %s", ErrInvalidTaskTransition, t.Status, to)
+}
+
+// TaskStore persists tasks so their status survives restarts
+type TaskStore interface {
+	SaveTask(ctx context.Context, task *Task) error
+	GetTask(ctx context.Context, id string) (*Task, error)
+}
+
+// memoryTaskStore keeps tasks as JSON memories keyed by task ID
+type memoryTaskStore struct {
+	memory memory.Manager
+}
+
+// NewMemoryTaskStore stores tasks in the memory manager
+func NewMemoryTaskStore(manager memory.Manager) TaskStore {
+	return &memoryTaskStore{memory: manager}
+}
+
+func taskMemoryID(id string) string {
+	return "task:" + id
+}
+
+func (s *memoryTaskStore) SaveTask(ctx context.Context, task *Task) error {
+	content, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	key := taskMemoryID(task.ID)
+	existing, err := s.memory.GetMemory(ctx, key)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return s.memory.CreateMemory(ctx, memory.Memory{
+			MemoryID:  key,
+			Content:   string(content),
+			CreatedAt: task.CreatedAt,
+		})
+	}
+	existing.Content = string(content)
+	return s.memory.SetMemory(ctx, existing)
+}
+
+func (s *memoryTaskStore) GetTask(ctx context.Context, id string) (*Task, error) {
+	mem, err := s.memory.GetMemory(ctx, taskMemoryID(id))
+	if err != nil || mem == nil {
+		return nil, err
+	}
+
+	var task Task
+	if err := json.Unmarshal([]byte(mem.Content), &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task %s: %w", id, err)
+	}
+	return &task, nil
+}
+
+// TaskGeneration is the result of a task planning thought chain
+type TaskGeneration struct {
+	Chain *ThoughtChain
+	Tasks []*Task
+}
+
+// taskJSONPattern matches the JSON the concrete task step wraps in <json> tags
+var taskJSONPattern = regexp.MustCompile(`(?s)<json>(.*?)</json>`)
+
+// GenerateTasks plans tasks towards the goals of the character with a thought
+// chain, returning them by descending priority
+func (e *CognitiveEngine) GenerateTasks(ctx context.Context, state *SystemState) (*TaskGeneration, error) {
+	chain, err := e.GenerateThoughtChain(
+		ctx,
+		state,
+		map[string]interface{}{"goal": "generate tasks towards the character goals"},
+		generateTasksPromptFunc(state, e.templates()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	concrete := chain.conclusion()
+	if concrete == nil {
+		return nil, fmt.Errorf("thought chain has no steps")
+	}
+
+	tasks, err := parseTasks(concrete.RawLLMOutput)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Priority > tasks[j].Priority
+	})
+	now := time.Now()
+	for _, task := range tasks {
+		newTask(task, now)
+	}
+
+	return &TaskGeneration{
+		Chain: chain,
+		Tasks: tasks,
+	}, nil
+}
+
+// parseTasks reads the tasks of the concrete task step, which may be a single
+// task, a list of tasks or an object with a tasks field, optionally wrapped in
+// <json> tags or a code fence
+func parseTasks(raw string) ([]*Task, error) {
+	if matches := taskJSONPattern.FindStringSubmatch(raw); len(matches) > 1 {
+		raw = matches[1]
+	}
+	raw = strings.TrimSpace(raw)
+	raw = strings.TrimPrefix(raw, "```json")
+	raw = strings.TrimPrefix(raw, "```")
+	raw = strings.TrimSpace(strings.TrimSuffix(raw, "```"))
+
+	var tasks []*Task
+	if strings.HasPrefix(raw, "[") {
+		if err := json.Unmarshal([]byte(raw), &tasks); err != nil {
+			return nil, fmt.Errorf("failed to parse tasks: %w", err)
+		}
+	} else {
+		var wrapped struct {
+			Tasks []*Task `json:"tasks"`
+		}
+		if err := json.Unmarshal([]byte(raw), &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to parse tasks: %w", err)
+		}
+		tasks = wrapped.Tasks
+		if len(tasks) == 0 {
+			var task Task
+			if err := json.Unmarshal([]byte(raw), &task); err != nil {
+				return nil, fmt.Errorf("failed to parse task: %w", err)
+			}
+			tasks = []*Task{&task}
+		}
+	}
+
+	result := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task != nil && (task.Name != "" || task.Description != "") {
+			result = append(result, task)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no tasks found in response")
+	}
+	return result, nil
+}
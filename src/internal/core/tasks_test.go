@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+)
+
+var errTransferFailed = errors.New("transfer failed")
+
+// recordingTaskStore records the status of every saved task
+type recordingTaskStore struct {
+	statuses []TaskStatus
+	tasks    map[string]Task
+}
+
+func (s *recordingTaskStore) SaveTask(_ context.Context, task *Task) error {
+	if s.tasks == nil {
+		s.tasks = make(map[string]Task)
+	}
+	s.statuses = append(s.statuses, task.Status)
+	s.tasks[task.ID] = *task
+	return nil
+}
+
+func (s *recordingTaskStore) GetTask(_ context.Context, id string) (*Task, error) {
+	task, ok := s.tasks[id]
+	if !ok {
+		return nil, nil
+	}
+	return &task, nil
+}
+
+func TestTaskTransition(t *testing.T) {
+	tests := []struct {
+		from    TaskStatus
+		to      TaskStatus
+		wantErr bool
+	}{
+		{from: TaskStatusPending, to: TaskStatusRunning},
+		{from: TaskStatusRunning, to: TaskStatusDone},
+		{from: TaskStatusRunning, to: TaskStatusFailed},
+		{from: TaskStatusPending, to: TaskStatusDone, wantErr: true},
+		{from: TaskStatusDone, to: TaskStatusRunning, wantErr: true},
+		{from: TaskStatusFailed, to: TaskStatusRunning, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			task := &Task{Status: tt.from, UpdatedAt: created}
+			now := created.Add(time.Minute)
+
+			err := task.transition(tt.to, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTaskTransition) {
+					t.Errorf("transition() error = %v, want ErrInvalidTaskTransition", err)
+				}
+				if task.Status != tt.from || !task.UpdatedAt.Equal(created) {
+					t.Errorf("task = %s updated at %v, want it unchanged", task.Status, task.UpdatedAt)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transition() error = %v", err)
+			}
+			if task.Status != tt.to || !task.UpdatedAt.Equal(now) {
+				t.Errorf("task = %s updated at %v, want %s updated at %v", task.Status, task.UpdatedAt, tt.to, now)
+			}
+		})
+	}
+}
+
+func TestExecuteTaskLifecycle(t *testing.T) {
+	tests := []struct {
+		name         string
+		actionErr    error
+		wantStatus   TaskStatus
+		wantStatuses []TaskStatus
+	}{
+		{name: "done", wantStatus: TaskStatusDone, wantStatuses: []TaskStatus{TaskStatusRunning, TaskStatusDone}},
+		{name: "failed", actionErr: errTransferFailed, wantStatus: TaskStatusFailed, wantStatuses: []TaskStatus{TaskStatusRunning, TaskStatusFailed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeLLM{respond: func(llm.CompletionRequest) string { return `{"amount": 1}` }}
+			transfer := &fakeAction{name: "transfer", typ: "chain", err: tt.actionErr}
+			store := &recordingTaskStore{}
+			agent := newPipelineAgent(t, client, &fakeSocial{}, transfer)
+			agent.tasks = store
+
+			task := newTask(&Task{Name: "Reward holders", Tools: []string{"transfer"}}, time.Now())
+			if task.ID == "" || task.Status != TaskStatusPending {
+				t.Fatalf("newTask() = %+v, want a pending task with an ID", task)
+			}
+
+			err := agent.ExecuteTask(context.Background(), agent.getCurrentState(), task)
+			if !errors.Is(err, tt.actionErr) {
+				t.Errorf("ExecuteTask() error = %v, want %v", err, tt.actionErr)
+			}
+			if task.Status != tt.wantStatus {
+				t.Errorf("task status = %s, want %s", task.Status, tt.wantStatus)
+			}
+			if (task.Error != "") != (tt.actionErr != nil) {
+				t.Errorf("task error = %q, want it set only on failure", task.Error)
+			}
+			if !reflect.DeepEqual(store.statuses, tt.wantStatuses) {
+				t.Errorf("saved statuses %v, want %v", store.statuses, tt.wantStatuses)
+			}
+			if len(transfer.executed) != 1 {
+				t.Errorf("transfer executed %d times, want 1", len(transfer.executed))
+			}
+		})
+	}
+}
+
+func TestExecuteTaskRejectsFinishedTask(t *testing.T) {
+	agent := newPipelineAgent(t, &fakeLLM{}, &fakeSocial{})
+	task := &Task{ID: "task-1", Status: TaskStatusDone}
+
+	if err := agent.ExecuteTask(context.Background(), agent.getCurrentState(), task); !errors.Is(err, ErrInvalidTaskTransition) {
+		t.Errorf("ExecuteTask() error = %v, want ErrInvalidTaskTransition", err)
+	}
+}
+
+func TestMemoryTaskStore(t *testing.T) {
+	store := NewMemoryTaskStore(newFakeMemory())
+	ctx := context.Background()
+	task := newTask(&Task{Name: "Post gas digest", Tools: []string{"gas_digest"}}, time.Now().Truncate(time.Second))
+
+	if err := store.SaveTask(ctx, task); err != nil {
+		t.Fatalf("SaveTask() error = %v", err)
+	}
+	if err := task.transition(TaskStatusRunning, time.Now()); err != nil {
+		t.Fatalf("transition() error = %v", err)
+	}
+	if err := store.SaveTask(ctx, task); err != nil {
+		t.Fatalf("SaveTask() of an existing task error = %v", err)
+	}
+
+	got, err := store.GetTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if got == nil || got.Name != task.Name || got.Status != TaskStatusRunning || !got.CreatedAt.Equal(task.CreatedAt) {
+		t.Errorf("GetTask() = %+v, want the running task", got)
+	}
+
+	missing, err := store.GetTask(ctx, "unknown")
+	if err != nil || missing != nil {
+		t.Errorf("GetTask() of an unknown task = %+v, %v, want nil", missing, err)
+	}
+}
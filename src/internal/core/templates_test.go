@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/characters"
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+func TestPromptTemplatesHotReload(t *testing.T) {
+	dir := t.TempDir()
+	write := func(analysis string) {
+		content := "user_templates:\n" +
+			"  system:\n    base_template: \"You are {{.CharacterName}}\"\n" +
+			"  message:\n    analysis: \"" + analysis + " {{.Message}}\"\n" +
+			"    action: \"{{.ActionName}}\"\n" +
+			"  thought_steps:\n" +
+			"    tasks:\n      initial: \"Plan tasks for %s\"\n" +
+			"    actions:\n      initial: \"Pick actions from %s\"\n"
+		if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write templates: %v", err)
+		}
+	}
+	write("Old instructions:")
+
+	templates, err := conf.LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadPromptTemplates() error = %v", err)
+	}
+	engine := NewCognitiveEngine(&fakeLLM{}, "test-model", nil, templates)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := conf.WatchPromptTemplates(ctx, dir, engine.SetPromptTemplates); err != nil {
+		t.Fatalf("WatchPromptTemplates() error = %v", err)
+	}
+
+	state := &SystemState{Character: &characters.Character{Name: "Tester"}}
+	msg := &SocialMessage{Platform: "web", Content: "gm"}
+	if prompt, _ := buildMessagePrompt(state, msg, nil, nil, engine.templates()); !strings.HasPrefix(prompt, "Old instructions: gm") {
+		t.Fatalf("prompt = %q, want the initial template", prompt)
+	}
+
+	write("New instructions:")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		prompt, _ := buildMessagePrompt(state, msg, nil, nil, engine.templates())
+		if strings.HasPrefix(prompt, "New instructions: gm") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("prompt = %q, want the edited template", prompt)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
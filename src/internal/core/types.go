@@ -23,6 +23,10 @@ type Stakeholder struct {
 	Type           StakeholderType
 	TokenBalance   *TokenBalance
 	HistoricalMsgs []string
+	// Language overrides the reply language of the character for this stakeholder
+	Language string
+	// IsNew is set when the stakeholder was created by this fetch, i.e. on first contact
+	IsNew bool `json:"-"`
 }
 
 // TokenInfo is a struct for token information
@@ -105,6 +109,19 @@ type ProcessedMessage struct {
 	Actions              []ProcessedAction `json:"actions"`
 }
 
+// Attachment is a media file sent along with a social message
+type Attachment struct {
+	Filename string
+	MimeType string
+	Data     []byte
+}
+
+// Button is an inline button the user can tap, Data is sent back when tapped
+type Button struct {
+	Text string
+	Data string
+}
+
 // SocialMessage is a struct for social messages
 type SocialMessage struct {
 	Type        string
@@ -113,6 +130,9 @@ type SocialMessage struct {
 	FromUser    string
 	TargetUsers []string
 	Metadata    map[string]interface{}
+	Attachments []Attachment
+	// Buttons are rows of inline buttons, only supported on telegram
+	Buttons [][]Button
 }
 
 // SocialClient is an interface for social clients
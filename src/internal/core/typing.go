@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// typingRefreshInterval renews the typing indicator before it expires, which
+// takes five seconds on telegram and ten on discord
+const typingRefreshInterval = 4 * time.Second
+
+// TypingIndicator is implemented by social clients that can show the agent
+// typing on the platform a message came from
+type TypingIndicator interface {
+	SendTyping(ctx context.Context, msg SocialMessage) error
+}
+
+// startTyping shows the agent typing a reply to msg until the returned stop
+// function is called; stop may be called more than once
+func (a *Agent) startTyping(ctx context.Context, msg *SocialMessage) (stop func()) {
+	indicator, ok := a.socialClient.(TypingIndicator)
+	if !ok {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(typingRefreshInterval)
+		defer ticker.Stop()
+		for {
+			if err := indicator.SendTyping(ctx, *msg); err != nil && ctx.Err() == nil {
+				// The indicator is cosmetic, don't retry a failing platform
+				logger.FromContext(ctx).Debugw("Failed to send typing indicator", "platform", msg.Platform, "error", err)
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
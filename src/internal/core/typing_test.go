@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// typingSocial records the typing indicators and replies in the order they
+// are sent
+type typingSocial struct {
+	fakeSocial
+	mu     sync.Mutex
+	events []string
+}
+
+func (s *typingSocial) SendTyping(_ context.Context, msg SocialMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, "typing:"+msg.Platform)
+	return nil
+}
+
+func (s *typingSocial) SendMessage(ctx context.Context, msg SocialMessage) error {
+	s.mu.Lock()
+	s.events = append(s.events, "reply")
+	s.mu.Unlock()
+	return s.fakeSocial.SendMessage(ctx, msg)
+}
+
+func TestProcessMessageShowsTyping(t *testing.T) {
+	client := &fakeLLM{respond: replies(t, analysis(t, ProcessedMessage{ShouldReply: true, ResponseMsg: "gm"}))}
+	social := &typingSocial{}
+	agent := newPipelineAgent(t, client, social)
+
+	if err := agent.processMessage(&SocialMessage{Platform: "discord", FromUser: "alice", Content: "gm"}); err != nil {
+		t.Fatalf("processMessage() error = %v", err)
+	}
+
+	social.mu.Lock()
+	defer social.mu.Unlock()
+	if len(social.events) != 2 || social.events[0] != "typing:discord" || social.events[1] != "reply" {
+		t.Errorf("events = %v, want typing on discord and then the reply", social.events)
+	}
+}
+
+func TestStartTypingStops(t *testing.T) {
+	social := &typingSocial{}
+	agent := &Agent{socialClient: social}
+
+	stop := agent.startTyping(context.Background(), &SocialMessage{Platform: "telegram"})
+	stop()
+	// Stopping twice is safe, and nothing is sent once stopped
+	stop()
+
+	social.mu.Lock()
+	defer social.mu.Unlock()
+	if len(social.events) != 1 || social.events[0] != "typing:telegram" {
+		t.Errorf("events = %v, want one typing indicator", social.events)
+	}
+}
+
+func TestStartTypingWithoutIndicator(t *testing.T) {
+	agent := &Agent{socialClient: &fakeSocial{}}
+	agent.startTyping(context.Background(), &SocialMessage{Platform: "telegram"})()
+}
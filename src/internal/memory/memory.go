@@ -11,6 +11,7 @@ import (
 type Memory struct {
 	MemoryID  string
 	Content   string
+	Embedding []float32
 	CreatedAt time.Time
 }
 
@@ -18,6 +19,9 @@ type Manager interface {
 	CreateMemory(ctx context.Context, memory Memory) error
 	GetMemory(ctx context.Context, memoryID string) (*Memory, error)
 	SetMemory(ctx context.Context, mem *Memory) error
+	Search(ctx context.Context, query string, k int) ([]*Memory, error)
+	SearchWithPrefix(ctx context.Context, prefix, query string, k int) ([]*Memory, error)
+	SearchByEmbedding(ctx context.Context, prefix string, embedding []float32, k int) ([]*Memory, error)
 }
 
 type ManagerImpl struct {
@@ -34,9 +38,15 @@ func NewManager(store database.Store) (*ManagerImpl, error) {
 }
 
 func (m *ManagerImpl) CreateMemory(ctx context.Context, memory Memory) error {
+	embedding, err := encodeEmbedding(memory.Embedding)
+	if err != nil {
+		return err
+	}
+
 	return m.store.MemoryTable().Create(&model.Memory{
 		MemoryID:  memory.MemoryID,
 		Content:   memory.Content,
+		Embedding: embedding,
 		CreatedAt: memory.CreatedAt,
 	}).Error
 }
@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/model"
+)
+
+// maxSearchCandidates bounds how many recent memories are scanned per search
+const maxSearchCandidates = 500
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "was": true, "has": true,
+	"have": true, "this": true, "that": true, "with": true, "from": true, "what": true,
+	"your": true, "about": true, "there": true, "their": true, "which": true, "would": true,
+}
+
+// Search returns up to k memories ranked by keyword overlap with the query
+func (m *ManagerImpl) Search(ctx context.Context, query string, k int) ([]*Memory, error) {
+	return m.SearchWithPrefix(ctx, "", query, k)
+}
+
+// SearchWithPrefix is like Search but only considers memories whose ID starts with prefix
+func (m *ManagerImpl) SearchWithPrefix(ctx context.Context, prefix, query string, k int) ([]*Memory, error) {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	var candidates []model.Memory
+	db := m.store.MemoryTable().WithContext(ctx)
+	if prefix != "" {
+		db = db.Where("memory_id LIKE ?", prefix+"%")
+	}
+	if err := db.Order("created_at DESC").Limit(maxSearchCandidates).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		memory *Memory
+		score  float64
+	}
+
+	var results []scored
+	for _, candidate := range candidates {
+		score := keywordOverlap(queryTerms, tokenize(candidate.Content))
+		if score <= 0 {
+			continue
+		}
+		results = append(results, scored{
+			memory: &Memory{
+				MemoryID:  candidate.MemoryID,
+				Content:   candidate.Content,
+				CreatedAt: candidate.CreatedAt,
+			},
+			score: score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	memories := make([]*Memory, 0, len(results))
+	for _, r := range results {
+		memories = append(memories, r.memory)
+	}
+	return memories, nil
+}
+
+// tokenize lowercases text and returns its distinct meaningful words
+func tokenize(text string) map[string]bool {
+	terms := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		terms[word] = true
+	}
+	return terms
+}
+
+// keywordOverlap returns the fraction of query terms present in the document
+func keywordOverlap(queryTerms, docTerms map[string]bool) float64 {
+	var matches int
+	for term := range queryTerms {
+		if docTerms[term] {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(queryTerms))
+}
+
+// SearchByEmbedding returns up to k memories with the highest cosine similarity to the embedding
+func (m *ManagerImpl) SearchByEmbedding(ctx context.Context, prefix string, embedding []float32, k int) ([]*Memory, error) {
+	if len(embedding) == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	var candidates []model.Memory
+	db := m.store.MemoryTable().WithContext(ctx).Where("embedding <> ''")
+	if prefix != "" {
+		db = db.Where("memory_id LIKE ?", prefix+"%")
+	}
+	if err := db.Order("created_at DESC").Limit(maxSearchCandidates).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		memory *Memory
+		score  float64
+	}
+
+	var results []scored
+	for _, candidate := range candidates {
+		candidateEmbedding, err := decodeEmbedding(candidate.Embedding)
+		if err != nil || len(candidateEmbedding) != len(embedding) {
+			continue
+		}
+		results = append(results, scored{
+			memory: &Memory{
+				MemoryID:  candidate.MemoryID,
+				Content:   candidate.Content,
+				Embedding: candidateEmbedding,
+				CreatedAt: candidate.CreatedAt,
+			},
+			score: CosineSimilarity(embedding, candidateEmbedding),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	memories := make([]*Memory, 0, len(results))
+	for _, r := range results {
+		memories = append(memories, r.memory)
+	}
+	return memories, nil
+}
+
+// CosineSimilarity returns the cosine of the angle between two equal-length vectors
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func encodeEmbedding(embedding []float32) (string, error) {
+	if len(embedding) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(embedding)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeEmbedding(data string) ([]float32, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var embedding []float32
+	if err := json.Unmarshal([]byte(data), &embedding); err != nil {
+		return nil, err
+	}
+	return embedding, nil
+}
@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/adapters"
+)
+
+// newTestManager returns a manager on a fresh SQLite database
+func newTestManager(t *testing.T) *ManagerImpl {
+	t.Helper()
+	store := adapters.NewSQLiteStore(filepath.Join(t.TempDir(), "memory.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	manager, err := NewManager(store)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	return manager
+}
+
+func TestSearchWithPrefix(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	now := time.Now()
+	for i, mem := range []Memory{
+		{MemoryID: "interaction:alice:1", Content: "alice: what are the gas fees on ethereum today?"},
+		{MemoryID: "interaction:alice:2", Content: "alice: my cat likes tuna"},
+		{MemoryID: "interaction:alice:3", Content: "alice: ethereum staking rewards"},
+		{MemoryID: "interaction:bob:1", Content: "bob: ethereum gas fees are high"},
+	} {
+		mem.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		if err := manager.CreateMemory(ctx, mem); err != nil {
+			t.Fatalf("CreateMemory() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		query  string
+		k      int
+		want   []string
+	}{
+		{name: "best match first", prefix: "interaction:alice:", query: "ethereum gas fees", k: 3,
+			want: []string{"interaction:alice:1", "interaction:alice:3"}},
+		{name: "top k only", prefix: "interaction:alice:", query: "ethereum gas fees", k: 1,
+			want: []string{"interaction:alice:1"}},
+		{name: "other stakeholders are skipped", prefix: "interaction:bob:", query: "staking rewards", k: 3},
+		{name: "no prefix searches everything", query: "gas fees", k: 3,
+			want: []string{"interaction:bob:1", "interaction:alice:1"}},
+		{name: "stop words match nothing", prefix: "interaction:alice:", query: "what are the", k: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memories, err := manager.SearchWithPrefix(ctx, tt.prefix, tt.query, tt.k)
+			if err != nil {
+				t.Fatalf("SearchWithPrefix() error = %v", err)
+			}
+			var got []string
+			for _, mem := range memories {
+				got = append(got, mem.MemoryID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("SearchWithPrefix() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("SearchWithPrefix() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestKeywordOverlap(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		doc   string
+		want  float64
+	}{
+		{name: "all terms", query: "gas fees", doc: "Gas fees are high", want: 1},
+		{name: "some terms", query: "ethereum gas fees", doc: "gas is cheap", want: 1.0 / 3},
+		{name: "no terms", query: "ethereum", doc: "cats and dogs", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keywordOverlap(tokenize(tt.query), tokenize(tt.doc)); got != tt.want {
+				t.Errorf("keywordOverlap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchByEmbedding(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	for _, mem := range []Memory{
+		{MemoryID: "interaction:alice:gas", Content: "gas fees", Embedding: []float32{1, 0, 0}},
+		{MemoryID: "interaction:alice:fees", Content: "network fees", Embedding: []float32{0.8, 0.6, 0}},
+		{MemoryID: "interaction:alice:cats", Content: "cats", Embedding: []float32{0, 0, 1}},
+		{MemoryID: "interaction:alice:plain", Content: "no embedding"},
+		{MemoryID: "interaction:bob:gas", Content: "gas", Embedding: []float32{1, 0, 0}},
+	} {
+		mem.CreatedAt = time.Now()
+		if err := manager.CreateMemory(ctx, mem); err != nil {
+			t.Fatalf("CreateMemory() error = %v", err)
+		}
+	}
+
+	memories, err := manager.SearchByEmbedding(ctx, "interaction:alice:", []float32{0.9, 0.1, 0}, 2)
+	if err != nil {
+		t.Fatalf("SearchByEmbedding() error = %v", err)
+	}
+
+	want := []string{"interaction:alice:gas", "interaction:alice:fees"}
+	if len(memories) != len(want) {
+		t.Fatalf("SearchByEmbedding() returned %d memories, want %d", len(memories), len(want))
+	}
+	for i, mem := range memories {
+		if mem.MemoryID != want[i] {
+			t.Errorf("memories[%d] = %s, want %s", i, mem.MemoryID, want[i])
+		}
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{name: "identical", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "opposite", a: []float32{1, 0}, b: []float32{-1, 0}, want: -1},
+		{name: "length mismatch", a: []float32{1}, b: []float32{1, 0}, want: 0},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CosineSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("CosineSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 )
 
 // Plugin defines behavior for extending D.A.T.A
@@ -34,6 +35,11 @@ type Plugin interface {
 	Evaluators() []Evaluator
 }
 
+// Stopper is implemented by plugins that release resources on shutdown
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
 // Provider interface defines methods that must be implemented by all providers
 type Provider interface {
 	// Name returns the name of the provider
@@ -96,6 +102,17 @@ type PluginMetadata struct {
 	Repository  string
 }
 
+// Publisher posts content produced by plugins to a social platform
+type Publisher interface {
+	Publish(ctx context.Context, platform, content string, metadata map[string]interface{}) error
+}
+
+// OutputSink delivers action results outside the social platforms, e.g. to a
+// webhook or a file feeding a data pipeline
+type OutputSink interface {
+	Send(ctx context.Context, content string, metadata map[string]interface{}) error
+}
+
 // Config contains plugin configuration
 type Config struct {
 	Name        string `mapstructure:"name"`
@@ -103,4 +120,14 @@ type Config struct {
 
 	// Plugin options
 	Options map[string]interface{} `mapstructure:"options"`
+
+	// Publisher lets plugin actions post to social platforms, nil when unavailable
+	Publisher Publisher `mapstructure:"-"`
+
+	// Retry is the retry policy of the plugin's requests
+	Retry retry.Policy `mapstructure:"-"`
+
+	// Models maps task types such as "query" and "analysis" to the model
+	// override configured for them in llm_config, empty when unset
+	Models map[string]string `mapstructure:"-"`
 }
@@ -1,21 +1,51 @@
 package plugins
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 )
 
+// ErrDuplicateAction is returned when a plugin declares an action with the
+// same type and name as an action already registered
+var ErrDuplicateAction = errors.New("duplicate action")
+
+// ErrUnknownAction is returned when no registered action has the name
+var ErrUnknownAction = errors.New("unknown action")
+
+// ErrAmbiguousAction is returned when an action is looked up by a name that
+// actions of several types share
+var ErrAmbiguousAction = errors.New("ambiguous action name")
+
+// actionKey identifies an action by its type and name
+type actionKey struct {
+	actionType string
+	name       string
+}
+
+// registeredAction is an action along with the plugin declaring it
+type registeredAction struct {
+	plugin string
+	action actions.IAction
+}
+
 // Registry manages plugin registration and lifecycle
 type Registry struct {
 	plugins map[string]Plugin
-	mu      sync.RWMutex
+	actions map[actionKey]registeredAction
+	// byName indexes the actions by name alone
+	byName map[string][]actionKey
+	mu     sync.RWMutex
 }
 
 func NewPluginRegistry() *Registry {
 	return &Registry{
 		plugins: make(map[string]Plugin),
+		actions: make(map[actionKey]registeredAction),
+		byName:  make(map[string][]actionKey),
 	}
 }
 
@@ -37,7 +67,28 @@ func (r *Registry) Register(p Plugin) error {
 		return fmt.Errorf("plugin %s already registered", name)
 	}
 
+	// Check every action before registering any, so a conflicting plugin
+	// leaves the registry untouched
+	declared := make(map[actionKey]bool)
+	for _, action := range p.Actions() {
+		key := actionKey{actionType: action.Type(), name: action.Name()}
+		if existing, exists := r.actions[key]; exists {
+			return fmt.Errorf("%w: %s/%s of plugin %s is already registered by plugin %s",
+				ErrDuplicateAction, key.actionType, key.name, name, existing.plugin)
+		}
+		if declared[key] {
+			return fmt.Errorf("%w: plugin %s declares %s/%s more than once",
+				ErrDuplicateAction, name, key.actionType, key.name)
+		}
+		declared[key] = true
+	}
+
 	r.plugins[name] = p
+	for _, action := range p.Actions() {
+		key := actionKey{actionType: action.Type(), name: action.Name()}
+		r.actions[key] = registeredAction{plugin: name, action: action}
+		r.byName[key.name] = append(r.byName[key.name], key)
+	}
 	return nil
 }
 
@@ -74,6 +125,31 @@ func (r *Registry) GetActions() []actions.IAction {
 	return actions
 }
 
+// GetActionByTypeName returns the action with the given type and name
+func (r *Registry) GetActionByTypeName(actionType, name string) (actions.IAction, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered, exists := r.actions[actionKey{actionType: actionType, name: name}]
+	return registered.action, exists
+}
+
+// GetActionByName returns the action with the given name, failing with
+// ErrAmbiguousAction when actions of several types have it
+func (r *Registry) GetActionByName(name string) (actions.IAction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := r.byName[name]
+	switch len(keys) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAction, name)
+	case 1:
+		return r.actions[keys[0]].action, nil
+	}
+	return nil, fmt.Errorf("%w: %s has %d types", ErrAmbiguousAction, name, len(keys))
+}
+
 func (r *Registry) GetProviders() []Provider {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -85,3 +161,22 @@ func (r *Registry) GetProviders() []Provider {
 
 	return providers
 }
+
+// Stop stops every plugin that implements Stopper, returning the errors of
+// the plugins that failed to stop
+func (r *Registry) Stop(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var errs []error
+	for name, p := range r.plugins {
+		stopper, ok := p.(Stopper)
+		if !ok {
+			continue
+		}
+		if err := stopper.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop plugin %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,149 @@
+package plugins
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+)
+
+// stubAction is an action identified by its type and name
+type stubAction struct {
+	actions.IAction
+	typ  string
+	name string
+}
+
+func (a *stubAction) Type() string { return a.typ }
+func (a *stubAction) Name() string { return a.name }
+
+// stubPlugin exposes its actions to the registry
+type stubPlugin struct {
+	name    string
+	actions []actions.IAction
+}
+
+func (p *stubPlugin) Name() string               { return p.name }
+func (p *stubPlugin) Description() string        { return "stub plugin " + p.name }
+func (p *stubPlugin) Providers() []Provider      { return nil }
+func (p *stubPlugin) Actions() []actions.IAction { return p.actions }
+func (p *stubPlugin) Evaluators() []Evaluator    { return nil }
+
+func TestGetActionByTypeName(t *testing.T) {
+	balance := &stubAction{typ: "chain", name: "balance"}
+	socialBalance := &stubAction{typ: "social", name: "balance"}
+
+	registry := NewPluginRegistry()
+	if err := registry.Register(&stubPlugin{name: "first", actions: []actions.IAction{balance, socialBalance}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		actionType string
+		actionName string
+		want       actions.IAction
+	}{
+		{name: "found", actionType: "chain", actionName: "balance", want: balance},
+		{name: "same name of another type", actionType: "social", actionName: "balance", want: socialBalance},
+		{name: "unknown name", actionType: "chain", actionName: "transfer"},
+		{name: "unknown type", actionType: "defi", actionName: "balance"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := registry.GetActionByTypeName(tt.actionType, tt.actionName)
+			if ok != (tt.want != nil) || got != tt.want {
+				t.Errorf("GetActionByTypeName(%s, %s) = %v, %v, want %v", tt.actionType, tt.actionName, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterRejectsDuplicateActions(t *testing.T) {
+	tests := []struct {
+		name   string
+		second *stubPlugin
+	}{
+		{name: "declared by another plugin", second: &stubPlugin{name: "second", actions: []actions.IAction{
+			&stubAction{typ: "social", name: "post"},
+			&stubAction{typ: "chain", name: "balance"},
+		}}},
+		{name: "declared twice by one plugin", second: &stubPlugin{name: "second", actions: []actions.IAction{
+			&stubAction{typ: "social", name: "post"},
+			&stubAction{typ: "social", name: "post"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			balance := &stubAction{typ: "chain", name: "balance"}
+			registry := NewPluginRegistry()
+			if err := registry.Register(&stubPlugin{name: "first", actions: []actions.IAction{balance}}); err != nil {
+				t.Fatalf("Register(first) error = %v", err)
+			}
+
+			if err := registry.Register(tt.second); !errors.Is(err, ErrDuplicateAction) {
+				t.Fatalf("Register(second) error = %v, want ErrDuplicateAction", err)
+			}
+			// The rejected plugin leaves the registry untouched
+			if _, ok := registry.GetPlugin("second"); ok {
+				t.Error("the rejected plugin was registered")
+			}
+			if _, ok := registry.GetActionByTypeName("social", "post"); ok {
+				t.Error("an action of the rejected plugin was registered")
+			}
+			if _, err := registry.GetActionByName("post"); !errors.Is(err, ErrUnknownAction) {
+				t.Errorf("GetActionByName(post) error = %v, want the rejected action left out of the name index", err)
+			}
+			if got, _ := registry.GetActionByTypeName("chain", "balance"); got != balance {
+				t.Errorf("chain/balance = %v, want the first plugin's action", got)
+			}
+		})
+	}
+}
+
+func TestRegistryGetActionByName(t *testing.T) {
+	registry := NewPluginRegistry()
+	for _, p := range []Plugin{
+		&stubPlugin{name: "social", actions: []actions.IAction{
+			&stubAction{typ: "twitter", name: "post"},
+			&stubAction{typ: "social", name: "report"},
+		}},
+		&stubPlugin{name: "chain", actions: []actions.IAction{
+			&stubAction{typ: "discord", name: "post"},
+			&stubAction{typ: "chain", name: "balance"},
+		}},
+	} {
+		if err := registry.Register(p); err != nil {
+			t.Fatalf("Register(%s) error = %v", p.Name(), err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		wantType string
+		wantErr  error
+	}{
+		{name: "unique name", action: "balance", wantType: "chain"},
+		{name: "unique name of another plugin", action: "report", wantType: "social"},
+		{name: "name shared by two types", action: "post", wantErr: ErrAmbiguousAction},
+		{name: "unknown name", action: "transfer", wantErr: ErrUnknownAction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, err := registry.GetActionByName(tt.action)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetActionByName() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if action.Name() != tt.action || action.Type() != tt.wantType {
+				t.Errorf("GetActionByName() = %s/%s, want %s/%s", action.Type(), action.Name(), tt.wantType, tt.action)
+			}
+		})
+	}
+}
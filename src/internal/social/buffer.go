@@ -0,0 +1,50 @@
+package social
+
+import (
+	"context"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// enqueue hands an incoming message to the agent. When the buffer is full it
+// either waits for room or, with the drop oldest policy, sheds the oldest
+// buffered message so monitoring never stalls behind a slow reply. Messages
+// of the agent itself are dropped.
+func (sc *SocialClientImpl) enqueue(ctx context.Context, msg core.SocialMessage) {
+	if sc.isOwnMessage(msg) {
+		logger.GetLogger().Debugw("Dropping message sent by the agent itself",
+			"platform", msg.Platform,
+			"from", msg.FromUser,
+		)
+		return
+	}
+
+	if !sc.dropOldest {
+		select {
+		case sc.socialMsgChannel <- msg:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	for {
+		select {
+		case sc.socialMsgChannel <- msg:
+			return
+		default:
+		}
+
+		// Full, make room; the agent may have taken a message meanwhile
+		select {
+		case dropped := <-sc.socialMsgChannel:
+			logger.GetLogger().Warnw("Social message buffer full, dropping oldest message",
+				"platform", dropped.Platform,
+				"from", dropped.FromUser,
+				"type", dropped.Type,
+				"capacity", cap(sc.socialMsgChannel),
+			)
+		default:
+		}
+	}
+}
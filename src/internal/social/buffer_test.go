@@ -0,0 +1,88 @@
+package social
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
+)
+
+func bufferedMessage(content string) core.SocialMessage {
+	return core.SocialMessage{Platform: "telegram", FromUser: "alice", Content: content}
+}
+
+// drain returns the contents of the buffered messages
+func drain(ch chan core.SocialMessage) []string {
+	var contents []string
+	for {
+		select {
+		case msg := <-ch:
+			contents = append(contents, msg.Content)
+		default:
+			return contents
+		}
+	}
+}
+
+func TestEnqueueDropOldest(t *testing.T) {
+	sc := NewSocialClient(nil, nil, nil, nil, retry.Policy{}, conf.MessageBufferConfig{Size: 2, Policy: conf.MessageBufferDropOldest}, conf.SeenConfig{}, nil)
+
+	for _, content := range []string{"first", "second", "third"} {
+		sc.enqueue(context.Background(), bufferedMessage(content))
+	}
+
+	got := drain(sc.socialMsgChannel)
+	if len(got) != 2 || got[0] != "second" || got[1] != "third" {
+		t.Errorf("buffered %q, want the oldest message dropped", got)
+	}
+}
+
+func TestEnqueueBlockWaitsForRoom(t *testing.T) {
+	sc := NewSocialClient(nil, nil, nil, nil, retry.Policy{}, conf.MessageBufferConfig{Size: 1, Policy: conf.MessageBufferBlock}, conf.SeenConfig{}, nil)
+	sc.enqueue(context.Background(), bufferedMessage("first"))
+
+	done := make(chan struct{})
+	go func() {
+		sc.enqueue(context.Background(), bufferedMessage("second"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("enqueue() returned while the buffer was full, want it to wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if msg := <-sc.socialMsgChannel; msg.Content != "first" {
+		t.Errorf("received %q, want first", msg.Content)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("enqueue() kept waiting after room was made")
+	}
+	if got := drain(sc.socialMsgChannel); len(got) != 1 || got[0] != "second" {
+		t.Errorf("buffered %q, want second", got)
+	}
+}
+
+func TestEnqueueBlockStopsWhenCancelled(t *testing.T) {
+	sc := NewSocialClient(nil, nil, nil, nil, retry.Policy{}, conf.MessageBufferConfig{}, conf.SeenConfig{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		sc.enqueue(ctx, bufferedMessage("unread"))
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("enqueue() kept waiting after the context was cancelled")
+	}
+}
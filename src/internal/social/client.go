@@ -4,21 +4,41 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/internal/core"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 )
 
 // SocialClientImpl handles social media interactions and error reporting
 type SocialClientImpl struct {
 	twitterClient    clients.ITwitter
-	discordBot       *clients.DiscordBot
+	discordBot       clients.IDiscord
 	telegramBot      *clients.TelegramClient
 	socialMsgChannel chan core.SocialMessage
-	errorChannel     chan error // Channel for reporting errors to agent
+	// dropOldest sheds the oldest buffered message instead of blocking the
+	// monitors when socialMsgChannel is full
+	dropOldest   bool
+	errorChannel chan error // Channel for reporting errors to agent
+	// limiter is swapped when the rate limits are reloaded
+	limiter atomic.Pointer[outboundLimiter]
+	stats   *sendStats
+	// twitterPollInterval is how often Twitter mentions are fetched
+	twitterPollInterval time.Duration
+
+	// seen skips messages that were already handled
+	seen *seenMessages
+	// ownIDs are user IDs by platform, besides the accounts of the platform
+	// clients, whose messages are the agent's own
+	ownIDs map[string][]string
+
+	// monitorPausedUntil pauses polling a platform that keeps failing
+	monitorPausedUntil map[string]time.Time
+	pauseMu            sync.Mutex
 }
 
 // NewSocialClient creates a new social client with error handling
@@ -26,13 +46,29 @@ func NewSocialClient(
 	twitterConfig *conf.TwitterConfig,
 	discordConfig *conf.DiscordConfig,
 	telegramConfig *conf.TelegramConfig,
+	rateLimits map[string]conf.RateLimitConfig,
+	retryPolicy retry.Policy,
+	messageBuffer conf.MessageBufferConfig,
+	seen conf.SeenConfig,
+	selfIDs map[string][]string,
 ) *SocialClientImpl {
 	cli := &SocialClientImpl{
-		socialMsgChannel: make(chan core.SocialMessage),
+		socialMsgChannel: make(chan core.SocialMessage, messageBuffer.Size),
+		dropOldest:       messageBuffer.Policy == conf.MessageBufferDropOldest && messageBuffer.Size > 0,
 		errorChannel:     make(chan error, 100), // Buffered channel to prevent blocking
+		stats:            newSendStats(),
+		seen:             newSeenMessages(time.Duration(seen.TTLHours) * time.Hour),
+		ownIDs:           selfIDs,
+
+		monitorPausedUntil: make(map[string]time.Time),
+	}
+	cli.limiter.Store(newOutboundLimiter(rateLimits))
+	cli.twitterPollInterval = time.Duration(conf.DefaultTwitterPollInterval) * time.Minute
+	if twitterConfig != nil && twitterConfig.PollInterval > 0 {
+		cli.twitterPollInterval = time.Duration(twitterConfig.PollInterval) * time.Minute
 	}
 	if twitterConfig != nil && twitterConfig.Mode != "" {
-		client, err := clients.NewTwitterClient(twitterConfig)
+		client, err := clients.NewTwitterClient(twitterConfig, retryPolicy)
 		if err != nil {
 			panic(err)
 		}
@@ -40,6 +76,11 @@ func NewSocialClient(
 	}
 	if discordConfig != nil && discordConfig.APIToken != "" {
 		cli.discordBot = clients.NewDiscordBot(discordConfig.APIToken)
+		if presence := discordConfig.Presence; presence.Status != "" || presence.Activity != "" {
+			if err := cli.discordBot.SetPresence(presence.Status, presence.Activity); err != nil {
+				logger.GetLogger().Warnw("Failed to set Discord presence", "error", err)
+			}
+		}
 	}
 	if telegramConfig != nil && telegramConfig.Token != "" {
 		client, err := clients.NewTelegramClient(telegramConfig)
@@ -55,37 +96,79 @@ func NewSocialClient(
 func (sc *SocialClientImpl) SendMessage(ctx context.Context, msg core.SocialMessage) error {
 	switch msg.Platform {
 	case "twitter":
-		return sc.twitterClient.Tweet(ctx, msg.Content)
+		return sc.deliver(ctx, "twitter", func() error {
+			// Thread the reply under the originating tweet when known
+			replyToID, _ := msg.Metadata["reply_to_tweet_id"].(string)
+			if len(msg.Attachments) > 0 {
+				return sc.twitterClient.TweetWithMedia(ctx, msg.Content, toMediaAttachments(msg.Attachments), replyToID)
+			}
+			if replyToID != "" {
+				_, err := sc.twitterClient.ReplyToTweet(ctx, msg.Content, replyToID)
+				return err
+			}
+			return sc.twitterClient.Tweet(ctx, msg.Content)
+		})
 	case "discord":
-		return sc.discordBot.SendMessage(ctx, &clients.DiscordMsg{
-			AuthorID:  msg.FromUser,
-			Content:   msg.Content,
-			ChannelID: msg.Metadata["channel_id"].(string),
+		return sc.deliver(ctx, "discord", func() error {
+			// Answer as a reply to the originating message when known
+			replyToID, _ := msg.Metadata["message_id"].(string)
+			return sc.discordBot.SendMessage(ctx, &clients.DiscordMsg{
+				AuthorID:  msg.FromUser,
+				Content:   msg.Content,
+				ChannelID: msg.Metadata["channel_id"].(string),
+				Files:     toMediaAttachments(msg.Attachments),
+				ReplyToID: replyToID,
+			})
 		})
 	case "telegram":
-		return sc.telegramBot.BroadcastMessage(ctx, msg.Content)
+		return sc.deliver(ctx, "telegram", func() error {
+			if len(msg.Buttons) > 0 {
+				chatID, ok := msg.Metadata["chat_id"].(int64)
+				if !ok {
+					return fmt.Errorf("chat_id metadata is required to send buttons")
+				}
+				return sc.telegramBot.SendKeyboard(ctx, chatID, msg.Content, toInlineButtons(msg.Buttons))
+			}
+			if len(msg.Attachments) > 0 {
+				return sc.sendTelegramMedia(ctx, msg)
+			}
+			return sc.telegramBot.BroadcastMessage(ctx, msg.Content)
+		})
 	case "all":
 		// Send to all platforms
 		var errs []error
 
 		if sc.twitterClient != nil {
-			if err := sc.twitterClient.Tweet(context.Background(), msg.Content); err != nil {
+			if err := sc.deliver(ctx, "twitter", func() error {
+				if len(msg.Attachments) > 0 {
+					return sc.twitterClient.TweetWithMedia(context.Background(), msg.Content, toMediaAttachments(msg.Attachments), "")
+				}
+				return sc.twitterClient.Tweet(context.Background(), msg.Content)
+			}); err != nil {
 				errs = append(errs, fmt.Errorf("twitter: %w", err))
 			}
 		}
 
 		if sc.discordBot != nil {
-			if err := sc.discordBot.SendMessage(context.Background(), &clients.DiscordMsg{
-				AuthorID:  msg.FromUser,
-				Content:   msg.Content,
-				ChannelID: msg.Metadata["channel_id"].(string),
+			if err := sc.deliver(ctx, "discord", func() error {
+				return sc.discordBot.SendMessage(context.Background(), &clients.DiscordMsg{
+					AuthorID:  msg.FromUser,
+					Content:   msg.Content,
+					ChannelID: msg.Metadata["channel_id"].(string),
+					Files:     toMediaAttachments(msg.Attachments),
+				})
 			}); err != nil {
 				errs = append(errs, fmt.Errorf("discord: %w", err))
 			}
 		}
 
 		if sc.telegramBot != nil {
-			if err := sc.telegramBot.BroadcastMessage(context.Background(), msg.Content); err != nil {
+			if err := sc.deliver(ctx, "telegram", func() error {
+				if len(msg.Attachments) > 0 {
+					return sc.sendTelegramMedia(context.Background(), msg)
+				}
+				return sc.telegramBot.BroadcastMessage(context.Background(), msg.Content)
+			}); err != nil {
 				errs = append(errs, fmt.Errorf("telegram: %w", err))
 			}
 		}
@@ -102,15 +185,157 @@ func (sc *SocialClientImpl) SendMessage(ctx context.Context, msg core.SocialMess
 	return nil
 }
 
+// deliver paces a send to the platform, records its outcome and latency, and
+// reports failures on the error channel
+func (sc *SocialClientImpl) deliver(ctx context.Context, platform string, send func() error) error {
+	var latency time.Duration
+	err := sc.limiter.Load().wait(ctx, platform)
+	if err == nil {
+		start := time.Now()
+		err = send()
+		latency = time.Since(start)
+	}
+
+	sc.stats.record(platform, latency, err)
+	if err != nil {
+		sc.reportError(&core.PlatformError{Platform: platform, Op: "send", Err: err})
+	}
+	return err
+}
+
+// sendTelegramMedia broadcasts each attachment, captioning the first with the message content
+func (sc *SocialClientImpl) sendTelegramMedia(ctx context.Context, msg core.SocialMessage) error {
+	for i, media := range toMediaAttachments(msg.Attachments) {
+		caption := ""
+		if i == 0 {
+			caption = msg.Content
+		}
+		if err := sc.telegramBot.BroadcastMedia(ctx, media, caption); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toInlineButtons(rows [][]core.Button) [][]clients.InlineButton {
+	buttons := make([][]clients.InlineButton, 0, len(rows))
+	for _, row := range rows {
+		buttonRow := make([]clients.InlineButton, 0, len(row))
+		for _, b := range row {
+			buttonRow = append(buttonRow, clients.InlineButton{
+				Text: b.Text,
+				Data: b.Data,
+			})
+		}
+		buttons = append(buttons, buttonRow)
+	}
+	return buttons
+}
+
+func toMediaAttachments(attachments []core.Attachment) []*clients.MediaAttachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	media := make([]*clients.MediaAttachment, 0, len(attachments))
+	for _, a := range attachments {
+		media = append(media, &clients.MediaAttachment{
+			Filename: a.Filename,
+			MimeType: a.MimeType,
+			Data:     a.Data,
+		})
+	}
+	return media
+}
+
 func (sc *SocialClientImpl) GetMessageChannel() <-chan core.SocialMessage {
 	return sc.socialMsgChannel
 }
 
+// reportError pushes err onto the error channel without blocking
+func (sc *SocialClientImpl) reportError(err error) {
+	select {
+	case sc.errorChannel <- err:
+		// Error successfully reported
+	default:
+		// Channel is full, log locally
+		logger.GetLogger().Errorf("Error channel full, dropping error: %v", err)
+	}
+}
+
 // GetErrorChannel returns the channel for monitoring errors
 func (sc *SocialClientImpl) GetErrorChannel() <-chan error {
 	return sc.errorChannel
 }
 
+// PauseMonitoring skips polling the platform until the given time
+func (sc *SocialClientImpl) PauseMonitoring(platform string, until time.Time) {
+	sc.pauseMu.Lock()
+	defer sc.pauseMu.Unlock()
+	sc.monitorPausedUntil[platform] = until
+}
+
+func (sc *SocialClientImpl) monitoringPaused(platform string, now time.Time) bool {
+	sc.pauseMu.Lock()
+	defer sc.pauseMu.Unlock()
+	return now.Before(sc.monitorPausedUntil[platform])
+}
+
+// SetRateLimits replaces the outbound rate limits; sends already waiting keep
+// the pace they started with
+func (sc *SocialClientImpl) SetRateLimits(limits map[string]conf.RateLimitConfig) {
+	sc.limiter.Store(newOutboundLimiter(limits))
+}
+
+// SetDiscordPresence changes the status and activity of the Discord bot, e.g.
+// going invisible during maintenance
+func (sc *SocialClientImpl) SetDiscordPresence(status, activity string) error {
+	if sc.discordBot == nil {
+		return fmt.Errorf("discord is not configured")
+	}
+	return sc.discordBot.SetPresence(status, activity)
+}
+
+// SendTyping shows the agent typing where msg came from. Platforms without a
+// typing indicator are skipped.
+func (sc *SocialClientImpl) SendTyping(ctx context.Context, msg core.SocialMessage) error {
+	switch msg.Platform {
+	case "discord":
+		channelID, ok := msg.Metadata["channel_id"].(string)
+		if sc.discordBot == nil || !ok {
+			return nil
+		}
+		return sc.discordBot.SendTyping(ctx, channelID)
+	case "telegram":
+		chatID, ok := msg.Metadata["chat_id"].(int64)
+		if sc.telegramBot == nil || !ok {
+			return nil
+		}
+		return sc.telegramBot.SendTyping(ctx, chatID)
+	}
+	return nil
+}
+
+// UseSeenStore persists the handled message IDs in store, loading the IDs
+// recorded before a restart
+func (sc *SocialClientImpl) UseSeenStore(ctx context.Context, store SeenStore) error {
+	return sc.seen.load(ctx, store, time.Now())
+}
+
+// firstSeen reports whether a message wasn't handled before, logging the skipped ones
+func (sc *SocialClientImpl) firstSeen(ctx context.Context, platform, messageID string) bool {
+	if sc.seen.firstSeen(ctx, platform, messageID, time.Now()) {
+		return true
+	}
+	logger.GetLogger().Debugw("Skipping message already handled", "platform", platform, "id", messageID)
+	return false
+}
+
+// SendStats returns the send outcomes and latencies by platform
+func (sc *SocialClientImpl) SendStats() map[string]SendStats {
+	return sc.stats.snapshot()
+}
+
 // MonitorMessages starts monitoring messages from all configured platforms
 func (sc *SocialClientImpl) MonitorMessages(ctx context.Context) {
 	var wg sync.WaitGroup
@@ -143,34 +368,28 @@ func (sc *SocialClientImpl) MonitorMessages(ctx context.Context) {
 
 // monitorTwitter monitors Twitter mentions and reports errors through errorChannel
 func (sc *SocialClientImpl) monitorTwitter(ctx context.Context) {
-	ticker := time.NewTicker(15 * time.Minute)
+	ticker := time.NewTicker(sc.twitterPollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
+			if sc.monitoringPaused("twitter", time.Now()) {
+				continue
+			}
 			tweets, err := sc.twitterClient.MonitorMentioned(context.Background())
 			if err != nil {
 				// Report error through channel and continue monitoring
-				select {
-				case sc.errorChannel <- fmt.Errorf("twitter monitor error: %w", err):
-					// Error successfully reported
-				default:
-					// Channel is full, log locally
-					logger.GetLogger().Errorf("Error channel full, dropping error: %v", err)
-				}
+				sc.reportError(&core.PlatformError{Platform: "twitter", Op: "monitor", Err: err})
 				//not return here, continue monitoring
 				continue
 			}
 
 			for _, tweet := range tweets {
-				sc.socialMsgChannel <- core.SocialMessage{
-					Type:        "mention",
-					Content:     tweet.Text,
-					Platform:    "twitter",
-					FromUser:    tweet.UserID,
-					TargetUsers: []string{sc.twitterClient.GetMe()},
+				if !sc.firstSeen(ctx, "twitter", tweet.ID) {
+					continue
 				}
+				sc.enqueue(ctx, sc.mentionMessage(tweet))
 			}
 		case <-ctx.Done():
 			return
@@ -178,19 +397,37 @@ func (sc *SocialClientImpl) monitorTwitter(ctx context.Context) {
 	}
 }
 
+// mentionMessage converts a mention into a social message the reply can be threaded under
+func (sc *SocialClientImpl) mentionMessage(tweet *clients.Tweet) core.SocialMessage {
+	return core.SocialMessage{
+		Type:        "mention",
+		Content:     tweet.Text,
+		Platform:    "twitter",
+		FromUser:    tweet.UserID,
+		TargetUsers: []string{sc.twitterClient.GetMe()},
+		Metadata:    map[string]interface{}{"reply_to_tweet_id": tweet.ID},
+	}
+}
+
 func (sc *SocialClientImpl) monitorDiscord(ctx context.Context) {
 	channel := sc.discordBot.GetMessageChannel()
 
 	for {
 		select {
 		case msg := <-channel:
-			sc.socialMsgChannel <- core.SocialMessage{
+			if !sc.firstSeen(ctx, "discord", msg.MessageID) {
+				continue
+			}
+			sc.enqueue(ctx, core.SocialMessage{
 				Type:     "message",
 				Content:  msg.Content,
 				Platform: "discord",
 				FromUser: msg.AuthorID,
-				Metadata: map[string]interface{}{"channel_id": msg.ChannelID},
-			}
+				Metadata: map[string]interface{}{
+					"channel_id": msg.ChannelID,
+					"message_id": msg.MessageID,
+				},
+			})
 		case <-ctx.Done():
 			return
 		}
@@ -212,6 +449,15 @@ func (sc *SocialClientImpl) monitorTelegram(ctx context.Context) {
 	for {
 		select {
 		case msg := <-channel:
+			if msg.IsCallback() {
+				sc.forwardTelegramCallback(ctx, msg)
+				continue
+			}
+			// Message IDs are only unique within a chat
+			if !sc.firstSeen(ctx, "telegram", fmt.Sprintf("%d:%d", msg.ChatID, msg.MessageID)) {
+				continue
+			}
+
 			// Convert TelegramMessage to core.SocialMessage
 			socialMsg := core.SocialMessage{
 				Type:     "message",
@@ -235,7 +481,7 @@ func (sc *SocialClientImpl) monitorTelegram(ctx context.Context) {
 			}
 
 			// Send to the social message channel
-			sc.socialMsgChannel <- socialMsg
+			sc.enqueue(ctx, socialMsg)
 
 		case <-ctx.Done():
 			// Context cancelled, stop monitoring
@@ -243,3 +489,24 @@ func (sc *SocialClientImpl) monitorTelegram(ctx context.Context) {
 		}
 	}
 }
+
+// forwardTelegramCallback acknowledges a button tap and forwards it to the agent
+func (sc *SocialClientImpl) forwardTelegramCallback(ctx context.Context, msg clients.TelegramMessage) {
+	if err := sc.telegramBot.AnswerCallback(ctx, msg.CallbackID, ""); err != nil {
+		logger.GetLogger().Warnf("Failed to answer Telegram callback: %v", err)
+	}
+
+	sc.enqueue(ctx, core.SocialMessage{
+		Type:     "callback",
+		Content:  msg.CallbackData,
+		Platform: "telegram",
+		FromUser: msg.Username,
+		Metadata: map[string]interface{}{
+			"message_id":  msg.MessageID,
+			"chat_id":     msg.ChatID,
+			"user_id":     msg.UserID,
+			"callback_id": msg.CallbackID,
+			"timestamp":   msg.Timestamp,
+		},
+	})
+}
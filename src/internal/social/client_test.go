@@ -0,0 +1,134 @@
+package social
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
+)
+
+// fakeTwitter records tweets and replies instead of posting them
+type fakeTwitter struct {
+	clients.ITwitter
+	tweets  []string
+	replies map[string]string
+	media   [][]*clients.MediaAttachment
+	// mediaReplyTo holds the tweet each media tweet replied to
+	mediaReplyTo []string
+	// mentions are returned by the first poll
+	mentions []*clients.Tweet
+}
+
+func (f *fakeTwitter) MonitorMentioned(context.Context) ([]*clients.Tweet, error) {
+	mentions := f.mentions
+	f.mentions = nil
+	return mentions, nil
+}
+
+func (f *fakeTwitter) GetMe() string {
+	return "agent"
+}
+
+func (f *fakeTwitter) Tweet(_ context.Context, text string) error {
+	f.tweets = append(f.tweets, text)
+	return nil
+}
+
+func (f *fakeTwitter) TweetWithMedia(_ context.Context, text string, media []*clients.MediaAttachment, replyToTweetID string) error {
+	f.tweets = append(f.tweets, text)
+	f.media = append(f.media, media)
+	f.mediaReplyTo = append(f.mediaReplyTo, replyToTweetID)
+	return nil
+}
+
+func (f *fakeTwitter) ReplyToTweet(_ context.Context, replyText, replyToTweetID string) (*clients.Tweet, error) {
+	if f.replies == nil {
+		f.replies = make(map[string]string)
+	}
+	f.replies[replyToTweetID] = replyText
+	return &clients.Tweet{ID: "reply-1", Text: replyText}, nil
+}
+
+func TestMentionReplyIsThreaded(t *testing.T) {
+	twitter := &fakeTwitter{}
+	sc := &SocialClientImpl{twitterClient: twitter}
+
+	mention := sc.mentionMessage(&clients.Tweet{ID: "tweet-1", Text: "@agent gm", UserID: "alice"})
+	reply := core.SocialMessage{
+		Platform: mention.Platform,
+		Content:  "gm alice",
+		Metadata: mention.Metadata,
+	}
+	if err := sc.SendMessage(context.Background(), reply); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if got := twitter.replies["tweet-1"]; got != "gm alice" {
+		t.Errorf("reply to tweet-1 = %q, want %q", got, "gm alice")
+	}
+	if len(twitter.tweets) != 0 {
+		t.Errorf("posted standalone tweets %v, want none", twitter.tweets)
+	}
+}
+
+func TestSendMessageWithoutSourceTweet(t *testing.T) {
+	twitter := &fakeTwitter{}
+	sc := &SocialClientImpl{twitterClient: twitter}
+
+	if err := sc.SendMessage(context.Background(), core.SocialMessage{Platform: "twitter", Content: "gm"}); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if len(twitter.tweets) != 1 || twitter.tweets[0] != "gm" {
+		t.Errorf("tweets = %v, want [gm]", twitter.tweets)
+	}
+	if len(twitter.replies) != 0 {
+		t.Errorf("replies = %v, want none", twitter.replies)
+	}
+}
+
+func TestSendMessageUploadsTwitterMedia(t *testing.T) {
+	twitter := &fakeTwitter{}
+	sc := &SocialClientImpl{twitterClient: twitter}
+
+	err := sc.SendMessage(context.Background(), core.SocialMessage{
+		Platform:    "twitter",
+		Content:     "daily stats",
+		Attachments: []core.Attachment{{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if len(twitter.media) != 1 || len(twitter.media[0]) != 1 {
+		t.Fatalf("media uploads = %v, want one attachment", twitter.media)
+	}
+	got := twitter.media[0][0]
+	if got.Filename != "chart.png" || got.MimeType != "image/png" || string(got.Data) != "png-bytes" {
+		t.Errorf("uploaded %+v, want the chart attachment", got)
+	}
+}
+
+func TestSendMessageThreadsTwitterMedia(t *testing.T) {
+	twitter := &fakeTwitter{}
+	sc := &SocialClientImpl{twitterClient: twitter}
+
+	mention := sc.mentionMessage(&clients.Tweet{ID: "tweet-1", Text: "@agent stats?", UserID: "alice"})
+	err := sc.SendMessage(context.Background(), core.SocialMessage{
+		Platform:    mention.Platform,
+		Content:     "daily stats",
+		Attachments: []core.Attachment{{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")}},
+		Metadata:    mention.Metadata,
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+
+	if len(twitter.mediaReplyTo) != 1 || twitter.mediaReplyTo[0] != "tweet-1" {
+		t.Errorf("media tweets replied to %q, want [tweet-1]", twitter.mediaReplyTo)
+	}
+	if len(twitter.replies) != 0 {
+		t.Errorf("replies = %v, want the media tweet itself threaded", twitter.replies)
+	}
+}
@@ -0,0 +1,66 @@
+package social
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
+)
+
+func TestDiscordReplyReferencesOriginalMessage(t *testing.T) {
+	discord := &fakeDiscord{received: make(chan clients.DiscordMsg, 1)}
+	sc := &SocialClientImpl{discordBot: discord, socialMsgChannel: make(chan core.SocialMessage), seen: newSeenMessages(0)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sc.monitorDiscord(ctx)
+
+	discord.received <- clients.DiscordMsg{AuthorID: "alice", Content: "gm", ChannelID: "channel-1", MessageID: "message-0"}
+	var incoming core.SocialMessage
+	select {
+	case incoming = <-sc.GetMessageChannel():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the discord message was not forwarded")
+	}
+
+	reply := core.SocialMessage{Platform: "discord", Content: "gm alice", Metadata: incoming.Metadata}
+	if err := sc.SendMessage(ctx, reply); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(discord.sent) != 1 || discord.sent[0].ReplyToID != "message-0" || discord.sent[0].ChannelID != "channel-1" {
+		t.Errorf("sent %+v, want a reply to message-0", discord.sent)
+	}
+}
+
+func TestDiscordMessageWithoutOriginIsNotAReply(t *testing.T) {
+	discord := &fakeDiscord{}
+	sc := &SocialClientImpl{discordBot: discord}
+
+	msg := core.SocialMessage{Platform: "discord", Content: "gm", Metadata: map[string]interface{}{"channel_id": "channel-1"}}
+	if err := sc.SendMessage(context.Background(), msg); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if len(discord.sent) != 1 || discord.sent[0].ReplyToID != "" {
+		t.Errorf("sent %+v, want a fresh message", discord.sent)
+	}
+}
+
+func TestSendTypingDiscord(t *testing.T) {
+	discord := &fakeDiscord{}
+	sc := &SocialClientImpl{discordBot: discord}
+
+	msgs := []core.SocialMessage{
+		{Platform: "discord", Metadata: map[string]interface{}{"channel_id": "channel-1"}},
+		{Platform: "discord"},
+		{Platform: "twitter", Metadata: map[string]interface{}{"channel_id": "channel-2"}},
+	}
+	for _, msg := range msgs {
+		if err := sc.SendTyping(context.Background(), msg); err != nil {
+			t.Fatalf("SendTyping(%s) error = %v", msg.Platform, err)
+		}
+	}
+	if len(discord.typing) != 1 || discord.typing[0] != "channel-1" {
+		t.Errorf("typing sent to %v, want only the discord channel", discord.typing)
+	}
+}
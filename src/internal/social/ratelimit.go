@@ -0,0 +1,51 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	"golang.org/x/time/rate"
+)
+
+// outboundLimiter paces the messages sent to each platform. Senders wait
+// for their turn, so a burst of replies queues up instead of tripping the
+// platform's rate limit.
+type outboundLimiter struct {
+	limiters map[string]*rate.Limiter
+}
+
+// newOutboundLimiter creates a limiter for each platform with a positive rate
+func newOutboundLimiter(limits map[string]conf.RateLimitConfig) *outboundLimiter {
+	l := &outboundLimiter{
+		limiters: make(map[string]*rate.Limiter),
+	}
+	for platform, limit := range limits {
+		if limit.PerMinute <= 0 {
+			continue
+		}
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		l.limiters[platform] = rate.NewLimiter(rate.Every(time.Duration(float64(time.Minute)/limit.PerMinute)), burst)
+	}
+	return l
+}
+
+// wait blocks until a message may be sent to the platform or ctx is done
+func (l *outboundLimiter) wait(ctx context.Context, platform string) error {
+	if l == nil {
+		return nil
+	}
+	limiter, ok := l.limiters[platform]
+	if !ok {
+		return nil
+	}
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("waiting to send to %s: %w", platform, err)
+	}
+	return nil
+}
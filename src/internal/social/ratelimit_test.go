@@ -0,0 +1,87 @@
+package social
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+)
+
+func TestSendMessageIsPaced(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      conf.RateLimitConfig
+		sends      int
+		minElapsed time.Duration
+		maxElapsed time.Duration
+	}{
+		// 600 per minute is one message every 100ms after the first
+		{name: "paced", limit: conf.RateLimitConfig{PerMinute: 600}, sends: 4, minElapsed: 250 * time.Millisecond, maxElapsed: 2 * time.Second},
+		{name: "burst", limit: conf.RateLimitConfig{PerMinute: 600, Burst: 2}, sends: 4, minElapsed: 150 * time.Millisecond, maxElapsed: 2 * time.Second},
+		{name: "disabled", limit: conf.RateLimitConfig{}, sends: 4, maxElapsed: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twitter := &fakeTwitter{}
+			sc := &SocialClientImpl{twitterClient: twitter}
+			sc.limiter.Store(newOutboundLimiter(map[string]conf.RateLimitConfig{"twitter": tt.limit}))
+
+			start := time.Now()
+			for i := 0; i < tt.sends; i++ {
+				if err := sc.SendMessage(context.Background(), core.SocialMessage{Platform: "twitter", Content: "gm"}); err != nil {
+					t.Fatalf("SendMessage() error = %v", err)
+				}
+			}
+			elapsed := time.Since(start)
+
+			if len(twitter.tweets) != tt.sends {
+				t.Errorf("posted %d tweets, want %d", len(twitter.tweets), tt.sends)
+			}
+			if elapsed < tt.minElapsed || elapsed > tt.maxElapsed {
+				t.Errorf("%d sends took %v, want between %v and %v", tt.sends, elapsed, tt.minElapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestOutboundLimiterPacesPlatformsSeparately(t *testing.T) {
+	limiter := newOutboundLimiter(map[string]conf.RateLimitConfig{
+		"twitter":  {PerMinute: 1},
+		"telegram": {PerMinute: 600},
+	})
+	ctx := context.Background()
+
+	// Use up the twitter allowance; telegram is not held up by it
+	if err := limiter.wait(ctx, "twitter"); err != nil {
+		t.Fatalf("wait(twitter) error = %v", err)
+	}
+	start := time.Now()
+	for _, platform := range []string{"telegram", "discord"} {
+		if err := limiter.wait(ctx, platform); err != nil {
+			t.Fatalf("wait(%s) error = %v", platform, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("other platforms waited %v for twitter", elapsed)
+	}
+}
+
+func TestOutboundLimiterStopsWaitingWhenCancelled(t *testing.T) {
+	limiter := newOutboundLimiter(map[string]conf.RateLimitConfig{"twitter": {PerMinute: 1}})
+	if err := limiter.wait(context.Background(), "twitter"); err != nil {
+		t.Fatalf("wait() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.wait(ctx, "twitter"); err == nil {
+		t.Error("wait() error = nil, want the send to give up instead of waiting a minute")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("wait() returned after %v, want it to stop at the deadline", elapsed)
+	}
+}
@@ -0,0 +1,144 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/model"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
+	"gorm.io/gorm/clause"
+)
+
+// defaultSeenTTL is how long a handled message ID is remembered when not configured
+const defaultSeenTTL = 24 * time.Hour
+
+// SeenStore persists the IDs of handled messages across restarts
+type SeenStore interface {
+	// LoadSeen returns the messages seen at or after since
+	LoadSeen(ctx context.Context, since time.Time) ([]model.SeenMessage, error)
+	MarkSeen(ctx context.Context, platform, messageID string, at time.Time) error
+	// PruneSeen deletes the messages seen before the given time
+	PruneSeen(ctx context.Context, before time.Time) error
+}
+
+// DatabaseSeenStore keeps the seen message IDs in the seen_message table
+type DatabaseSeenStore struct {
+	store database.Store
+}
+
+// NewDatabaseSeenStore creates the seen_message table when missing
+func NewDatabaseSeenStore(store database.Store) (*DatabaseSeenStore, error) {
+	if err := store.SeenMessageTable().AutoMigrate(&model.SeenMessage{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate seen messages: %w", err)
+	}
+	return &DatabaseSeenStore{store: store}, nil
+}
+
+func (s *DatabaseSeenStore) LoadSeen(ctx context.Context, since time.Time) ([]model.SeenMessage, error) {
+	var seen []model.SeenMessage
+	if err := s.store.SeenMessageTable().WithContext(ctx).Where("seen_at >= ?", since).Find(&seen).Error; err != nil {
+		return nil, err
+	}
+	return seen, nil
+}
+
+func (s *DatabaseSeenStore) MarkSeen(ctx context.Context, platform, messageID string, at time.Time) error {
+	return s.store.SeenMessageTable().WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.SeenMessage{Platform: platform, MessageID: messageID, SeenAt: at}).Error
+}
+
+func (s *DatabaseSeenStore) PruneSeen(ctx context.Context, before time.Time) error {
+	return s.store.SeenMessageTable().WithContext(ctx).Where("seen_at < ?", before).Delete(&model.SeenMessage{}).Error
+}
+
+// seenMessages skips messages the agent already handled, such as mentions a
+// poll returns again or updates a platform replays after a restart
+type seenMessages struct {
+	ttl  time.Duration
+	seen map[string]time.Time
+	// store is nil when the seen IDs only live in memory
+	store      SeenStore
+	lastPruned time.Time
+	mu         sync.Mutex
+}
+
+func newSeenMessages(ttl time.Duration) *seenMessages {
+	if ttl <= 0 {
+		ttl = defaultSeenTTL
+	}
+	return &seenMessages{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+func seenKey(platform, messageID string) string {
+	return platform + ":" + messageID
+}
+
+// load prunes the expired IDs of the store and remembers the others, then
+// keeps recording new IDs in the store
+func (s *seenMessages) load(ctx context.Context, store SeenStore, now time.Time) error {
+	cutoff := now.Add(-s.ttl)
+	if err := store.PruneSeen(ctx, cutoff); err != nil {
+		return fmt.Errorf("failed to prune seen messages: %w", err)
+	}
+	seen, err := store.LoadSeen(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("failed to load seen messages: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range seen {
+		s.seen[seenKey(msg.Platform, msg.MessageID)] = msg.SeenAt
+	}
+	s.store = store
+	s.lastPruned = now
+	return nil
+}
+
+// firstSeen records the message and reports whether it is new. Messages
+// without an ID are always new.
+func (s *seenMessages) firstSeen(ctx context.Context, platform, messageID string, now time.Time) bool {
+	if messageID == "" {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evict(ctx, now)
+	key := seenKey(platform, messageID)
+	if _, ok := s.seen[key]; ok {
+		return false
+	}
+	s.seen[key] = now
+	if s.store != nil {
+		if err := s.store.MarkSeen(ctx, platform, messageID, now); err != nil {
+			logger.GetLogger().Warnw("Failed to persist seen message", "platform", platform, "id", messageID, "error", err)
+		}
+	}
+	return true
+}
+
+// evict forgets the expired IDs, pruning the store at most once per TTL
+func (s *seenMessages) evict(ctx context.Context, now time.Time) {
+	cutoff := now.Add(-s.ttl)
+	for key, seenAt := range s.seen {
+		if seenAt.Before(cutoff) {
+			delete(s.seen, key)
+		}
+	}
+	if s.store != nil && now.Sub(s.lastPruned) >= s.ttl {
+		if err := s.store.PruneSeen(ctx, cutoff); err != nil {
+			logger.GetLogger().Warnw("Failed to prune seen messages", "error", err)
+		}
+		s.lastPruned = now
+	}
+}
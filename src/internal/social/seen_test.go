@@ -0,0 +1,154 @@
+package social
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/adapters"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/model"
+)
+
+// newTestSeenStore returns a seen store on a fresh SQLite database
+func newTestSeenStore(t *testing.T) *DatabaseSeenStore {
+	t.Helper()
+	store := adapters.NewSQLiteStore(filepath.Join(t.TempDir(), "seen.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	seen, err := NewDatabaseSeenStore(store)
+	if err != nil {
+		t.Fatalf("NewDatabaseSeenStore() error = %v", err)
+	}
+	return seen
+}
+
+func TestSeenMessagesSkipsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	seen := newSeenMessages(time.Hour)
+
+	if !seen.firstSeen(ctx, "twitter", "1", now) {
+		t.Error("firstSeen() = false for a new message, want true")
+	}
+	if seen.firstSeen(ctx, "twitter", "1", now.Add(time.Minute)) {
+		t.Error("firstSeen() = true for a repeated message, want false")
+	}
+	if !seen.firstSeen(ctx, "discord", "1", now) {
+		t.Error("firstSeen() = false for the same ID on another platform, want true")
+	}
+	if !seen.firstSeen(ctx, "twitter", "1", now.Add(2*time.Hour)) {
+		t.Error("firstSeen() = false after the TTL, want the message forgotten")
+	}
+}
+
+func TestSeenMessagesSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := newTestSeenStore(t)
+
+	// Rows left behind by the previous run: one recent, one past the TTL
+	if err := store.MarkSeen(ctx, "twitter", "recent", now.Add(-10*time.Minute)); err != nil {
+		t.Fatalf("MarkSeen() error = %v", err)
+	}
+	if err := store.MarkSeen(ctx, "twitter", "expired", now.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("MarkSeen() error = %v", err)
+	}
+
+	seen := newSeenMessages(time.Hour)
+	if err := seen.load(ctx, store, now); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	if seen.firstSeen(ctx, "twitter", "recent", now) {
+		t.Error("firstSeen() = true for a mention handled before the restart, want false")
+	}
+	if !seen.firstSeen(ctx, "twitter", "expired", now) {
+		t.Error("firstSeen() = false for a mention past the TTL, want true")
+	}
+	if !seen.firstSeen(ctx, "twitter", "new", now) {
+		t.Error("firstSeen() = false for a new mention, want true")
+	}
+
+	rows, err := store.LoadSeen(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSeen() error = %v", err)
+	}
+	got := make(map[string]bool)
+	for _, row := range rows {
+		got[row.MessageID] = true
+	}
+	if !got["recent"] || !got["new"] || !got["expired"] || len(rows) != 3 {
+		t.Errorf("seen table = %v, want the pruned row re-recorded with recent and new", messageIDs(rows))
+	}
+
+	// The next restart skips the mentions handled by this run too
+	restarted := newSeenMessages(time.Hour)
+	if err := restarted.load(ctx, store, now.Add(time.Minute)); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	for _, id := range []string{"recent", "expired", "new"} {
+		if restarted.firstSeen(ctx, "twitter", id, now.Add(time.Minute)) {
+			t.Errorf("firstSeen(%q) = true after a second restart, want false", id)
+		}
+	}
+}
+
+func TestSeenMessagesPruneStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := newTestSeenStore(t)
+
+	seen := newSeenMessages(time.Hour)
+	if err := seen.load(ctx, store, now); err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	seen.firstSeen(ctx, "discord", "old", now)
+	seen.firstSeen(ctx, "discord", "fresh", now.Add(90*time.Minute))
+
+	rows, err := store.LoadSeen(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("LoadSeen() error = %v", err)
+	}
+	if len(rows) != 1 || rows[0].MessageID != "fresh" {
+		t.Errorf("seen table = %v, want only fresh after the TTL cleanup", messageIDs(rows))
+	}
+}
+
+func TestMonitorDiscordSkipsReplayedMessages(t *testing.T) {
+	discord := &fakeDiscord{received: make(chan clients.DiscordMsg, 3)}
+	sc := &SocialClientImpl{discordBot: discord, socialMsgChannel: make(chan core.SocialMessage, 3), seen: newSeenMessages(0)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	discord.received <- clients.DiscordMsg{AuthorID: "alice", Content: "gm", ChannelID: "channel-1", MessageID: "message-0"}
+	discord.received <- clients.DiscordMsg{AuthorID: "alice", Content: "gm", ChannelID: "channel-1", MessageID: "message-0"}
+	discord.received <- clients.DiscordMsg{AuthorID: "alice", Content: "wen", ChannelID: "channel-1", MessageID: "message-1"}
+	go sc.monitorDiscord(ctx)
+
+	var got []string
+	for len(got) < 2 {
+		select {
+		case msg := <-sc.GetMessageChannel():
+			got = append(got, msg.Content)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("forwarded %q, want gm and wen", got)
+		}
+	}
+	if got[0] != "gm" || got[1] != "wen" {
+		t.Errorf("forwarded %q, want gm and wen", got)
+	}
+}
+
+func messageIDs(rows []model.SeenMessage) []string {
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.MessageID)
+	}
+	return ids
+}
@@ -0,0 +1,42 @@
+package social
+
+import (
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+)
+
+// selfIDs returns the user IDs of the agent on a platform: the account of the
+// platform client along with the configured ones
+func (sc *SocialClientImpl) selfIDs(platform string) []string {
+	ids := append([]string(nil), sc.ownIDs[platform]...)
+	switch platform {
+	case "twitter":
+		if sc.twitterClient != nil {
+			ids = append(ids, sc.twitterClient.GetMe())
+		}
+	case "discord":
+		if sc.discordBot != nil {
+			ids = append(ids, sc.discordBot.SelfID())
+		}
+	case "telegram":
+		if sc.telegramBot != nil {
+			ids = append(ids, sc.telegramBot.SelfUsername())
+		}
+	}
+	return ids
+}
+
+// isOwnMessage reports whether the agent sent msg itself, e.g. a tweet
+// mentioning its own account, which would make it reply to itself
+func (sc *SocialClientImpl) isOwnMessage(msg core.SocialMessage) bool {
+	if msg.FromUser == "" {
+		return false
+	}
+	for _, id := range sc.selfIDs(msg.Platform) {
+		if id != "" && strings.EqualFold(id, msg.FromUser) {
+			return true
+		}
+	}
+	return false
+}
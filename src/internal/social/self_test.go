@@ -0,0 +1,73 @@
+package social
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
+)
+
+func TestMonitorTwitterDropsOwnMentions(t *testing.T) {
+	twitter := &fakeTwitter{mentions: []*clients.Tweet{
+		{ID: "tweet-1", Text: "gm @agent", UserID: "agent"},
+		{ID: "tweet-2", Text: "@agent gm", UserID: "alice"},
+	}}
+	sc := &SocialClientImpl{
+		twitterClient:       twitter,
+		twitterPollInterval: time.Millisecond,
+		socialMsgChannel:    make(chan core.SocialMessage, 2),
+		seen:                newSeenMessages(0),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sc.monitorTwitter(ctx)
+
+	select {
+	case msg := <-sc.GetMessageChannel():
+		if msg.FromUser != "alice" {
+			t.Errorf("forwarded a mention from %q, want only alice's", msg.FromUser)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the mention of alice was not forwarded")
+	}
+	select {
+	case msg := <-sc.GetMessageChannel():
+		t.Errorf("forwarded %q from %q, want the agent's own tweet dropped", msg.Content, msg.FromUser)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMonitorDiscordDropsOwnMessages(t *testing.T) {
+	discord := &fakeDiscord{received: make(chan clients.DiscordMsg, 2), self: "bot-1"}
+	sc := &SocialClientImpl{discordBot: discord, socialMsgChannel: make(chan core.SocialMessage, 2), seen: newSeenMessages(0)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	discord.received <- clients.DiscordMsg{AuthorID: "bot-1", Content: "gm everyone", ChannelID: "channel-1", MessageID: "message-0"}
+	discord.received <- clients.DiscordMsg{AuthorID: "alice", Content: "gm", ChannelID: "channel-1", MessageID: "message-1"}
+	go sc.monitorDiscord(ctx)
+
+	select {
+	case msg := <-sc.GetMessageChannel():
+		if msg.FromUser != "alice" {
+			t.Errorf("forwarded a message from %q, want the bot's own dropped", msg.FromUser)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the message of alice was not forwarded")
+	}
+}
+
+func TestEnqueueDropsConfiguredSelfIDs(t *testing.T) {
+	sc := NewSocialClient(nil, nil, nil, nil, retry.Policy{}, conf.MessageBufferConfig{Size: 2}, conf.SeenConfig{}, map[string][]string{"telegram": {"AgentAlt"}})
+
+	sc.enqueue(context.Background(), core.SocialMessage{Platform: "telegram", FromUser: "agentalt", Content: "from the other account"})
+	sc.enqueue(context.Background(), core.SocialMessage{Platform: "discord", FromUser: "agentalt", Content: "someone else on discord"})
+
+	if got := drain(sc.socialMsgChannel); len(got) != 1 || got[0] != "someone else on discord" {
+		t.Errorf("buffered %q, want only the discord message", got)
+	}
+}
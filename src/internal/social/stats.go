@@ -0,0 +1,68 @@
+package social
+
+import (
+	"sync"
+	"time"
+)
+
+// SendStats are the outcomes and latencies of the messages sent to a platform
+type SendStats struct {
+	Succeeded    int64
+	Failed       int64
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AverageLatency is the mean latency of the sends
+func (s SendStats) AverageLatency() time.Duration {
+	count := s.Succeeded + s.Failed
+	if count == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(count)
+}
+
+// sendStats tracks SendStats by platform
+type sendStats struct {
+	platforms map[string]*SendStats
+	mu        sync.Mutex
+}
+
+func newSendStats() *sendStats {
+	return &sendStats{
+		platforms: make(map[string]*SendStats),
+	}
+}
+
+func (s *sendStats) record(platform string, latency time.Duration, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.platforms[platform]
+	if !ok {
+		stats = &SendStats{}
+		s.platforms[platform] = stats
+	}
+	if err != nil {
+		stats.Failed++
+	} else {
+		stats.Succeeded++
+	}
+	stats.TotalLatency += latency
+	stats.MaxLatency = max(stats.MaxLatency, latency)
+}
+
+func (s *sendStats) snapshot() map[string]SendStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]SendStats, len(s.platforms))
+	for platform, stats := range s.platforms {
+		snapshot[platform] = *stats
+	}
+	return snapshot
+}
@@ -0,0 +1,112 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/clients"
+)
+
+// fakeDiscord fails every send with err and delivers the messages of received
+type fakeDiscord struct {
+	clients.IDiscord
+	err      error
+	sent     []*clients.DiscordMsg
+	typing   []string
+	received chan clients.DiscordMsg
+	self     string
+}
+
+func (f *fakeDiscord) SelfID() string {
+	return f.self
+}
+
+func (f *fakeDiscord) SendTyping(_ context.Context, channelID string) error {
+	f.typing = append(f.typing, channelID)
+	return nil
+}
+
+func (f *fakeDiscord) GetMessageChannel() <-chan clients.DiscordMsg {
+	return f.received
+}
+
+func (f *fakeDiscord) SendMessage(_ context.Context, msg *clients.DiscordMsg) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func TestSendMessageDiscordFailure(t *testing.T) {
+	errDiscord := errors.New("discord is down")
+	sc := &SocialClientImpl{
+		discordBot:   &fakeDiscord{err: errDiscord},
+		errorChannel: make(chan error, 1),
+		stats:        newSendStats(),
+	}
+
+	err := sc.SendMessage(context.Background(), core.SocialMessage{
+		Platform: "discord",
+		Content:  "gm",
+		Metadata: map[string]interface{}{"channel_id": "channel-1"},
+	})
+	if !errors.Is(err, errDiscord) {
+		t.Fatalf("SendMessage() error = %v, want %v", err, errDiscord)
+	}
+
+	if stats := sc.SendStats()["discord"]; stats.Failed != 1 || stats.Succeeded != 0 {
+		t.Errorf("discord stats = %+v, want one failure", stats)
+	}
+
+	select {
+	case reported := <-sc.GetErrorChannel():
+		var platformErr *core.PlatformError
+		if !errors.As(reported, &platformErr) || platformErr.Platform != "discord" || platformErr.Op != "send" {
+			t.Errorf("reported %v, want a discord send error", reported)
+		}
+		if !errors.Is(reported, errDiscord) {
+			t.Errorf("reported %v, want it to wrap %v", reported, errDiscord)
+		}
+	default:
+		t.Error("the failure was not reported on the error channel")
+	}
+}
+
+func TestSendMessageRecordsSuccess(t *testing.T) {
+	sc := &SocialClientImpl{
+		twitterClient: &fakeTwitter{},
+		discordBot:    &fakeDiscord{},
+		errorChannel:  make(chan error, 1),
+		stats:         newSendStats(),
+	}
+
+	for _, platform := range []string{"twitter", "discord", "twitter"} {
+		msg := core.SocialMessage{Platform: platform, Content: "gm", Metadata: map[string]interface{}{"channel_id": "channel-1"}}
+		if err := sc.SendMessage(context.Background(), msg); err != nil {
+			t.Fatalf("SendMessage(%s) error = %v", platform, err)
+		}
+	}
+
+	stats := sc.SendStats()
+	if stats["twitter"].Succeeded != 2 || stats["discord"].Succeeded != 1 {
+		t.Errorf("stats = %+v, want 2 twitter and 1 discord successes", stats)
+	}
+	if len(sc.errorChannel) != 0 {
+		t.Errorf("reported %d errors, want none", len(sc.errorChannel))
+	}
+}
+
+func TestSendStatsAverageLatency(t *testing.T) {
+	stats := newSendStats()
+	stats.record("telegram", 100*time.Millisecond, nil)
+	stats.record("telegram", 300*time.Millisecond, errors.New("timeout"))
+
+	got := stats.snapshot()["telegram"]
+	if got.AverageLatency() != 200*time.Millisecond || got.MaxLatency != 300*time.Millisecond {
+		t.Errorf("telegram stats = %+v, want 200ms average and 300ms max", got)
+	}
+	if (SendStats{}).AverageLatency() != 0 {
+		t.Error("AverageLatency() without sends is not 0")
+	}
+}
@@ -60,6 +60,7 @@ func (sm *StakeholderManager) FetchOrCreateStakeholder(
 		if err != nil {
 			return nil, err
 		}
+		stakeholder.IsNew = true
 	} else {
 		err = json.Unmarshal([]byte(mem.Content), &stakeholder)
 		if err != nil {
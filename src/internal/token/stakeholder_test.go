@@ -0,0 +1,56 @@
+package token
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/core"
+	"github.com/carv-protocol/d.a.t.a/src/internal/memory"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/database/adapters"
+)
+
+// newTestStakeholders returns a stakeholder manager on a fresh SQLite database
+func newTestStakeholders(t *testing.T) *StakeholderManager {
+	t.Helper()
+	store := adapters.NewSQLiteStore(filepath.Join(t.TempDir(), "memory.db"))
+	if err := store.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	manager, err := memory.NewManager(store)
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	return NewStakeholderManager(manager)
+}
+
+func TestFetchOrCreateStakeholderMarksFirstContact(t *testing.T) {
+	ctx := context.Background()
+	sm := newTestStakeholders(t)
+
+	first, err := sm.FetchOrCreateStakeholder(ctx, "alice", "telegram", core.StakeholderTypeUser)
+	if err != nil {
+		t.Fatalf("FetchOrCreateStakeholder() error = %v", err)
+	}
+	if !first.IsNew {
+		t.Error("IsNew = false on first contact, want true")
+	}
+
+	returning, err := sm.FetchOrCreateStakeholder(ctx, "alice", "telegram", core.StakeholderTypeUser)
+	if err != nil {
+		t.Fatalf("FetchOrCreateStakeholder() error = %v", err)
+	}
+	if returning.IsNew {
+		t.Error("IsNew = true for a returning user, want false")
+	}
+
+	other, err := sm.FetchOrCreateStakeholder(ctx, "alice", "discord", core.StakeholderTypeUser)
+	if err != nil {
+		t.Fatalf("FetchOrCreateStakeholder() error = %v", err)
+	}
+	if !other.IsNew {
+		t.Error("IsNew = false for the same user on another platform, want true")
+	}
+}
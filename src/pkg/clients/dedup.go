@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultDedupWindow is used when no dedup window is configured, in minutes
+const defaultDedupWindow = 60
+
+// DuplicateError is returned when identical content was already posted within the dedup window
+type DuplicateError struct {
+	SentAt time.Time
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate content already posted at %s", e.SentAt.Format(time.RFC3339))
+}
+
+// sentCache remembers hashes of recently posted content for a time window
+type sentCache struct {
+	window time.Duration
+	sent   map[string]time.Time
+	mu     sync.Mutex
+}
+
+func newSentCache(window time.Duration) *sentCache {
+	return &sentCache{
+		window: window,
+		sent:   make(map[string]time.Time),
+	}
+}
+
+// check returns a DuplicateError if the content was posted within the window
+func (c *sentCache) check(content string, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evict(now)
+	if sentAt, ok := c.sent[contentHash(content)]; ok {
+		return &DuplicateError{SentAt: sentAt}
+	}
+	return nil
+}
+
+// record marks the content as posted
+func (c *sentCache) record(content string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent[contentHash(content)] = now
+}
+
+func (c *sentCache) evict(now time.Time) {
+	for hash, sentAt := range c.sent {
+		if now.Sub(sentAt) >= c.window {
+			delete(c.sent, hash)
+		}
+	}
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(content)))
+	return hex.EncodeToString(sum[:])
+}
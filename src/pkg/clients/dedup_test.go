@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	"github.com/michimani/gotwi"
+)
+
+func TestSentCache(t *testing.T) {
+	sentAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		content       string
+		after         time.Duration
+		wantDuplicate bool
+	}{
+		{name: "same content within the window", content: "gm", after: 10 * time.Minute, wantDuplicate: true},
+		{name: "surrounding whitespace is ignored", content: "  gm\n", after: time.Minute, wantDuplicate: true},
+		{name: "same content after the window", content: "gm", after: time.Hour},
+		{name: "different content", content: "gn", after: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newSentCache(time.Hour)
+			cache.record("gm", sentAt)
+
+			err := cache.check(tt.content, sentAt.Add(tt.after))
+			var duplicate *DuplicateError
+			if errors.As(err, &duplicate) != tt.wantDuplicate {
+				t.Fatalf("check() error = %v, want duplicate %v", err, tt.wantDuplicate)
+			}
+			if tt.wantDuplicate && !duplicate.SentAt.Equal(sentAt) {
+				t.Errorf("duplicate sent at %v, want %v", duplicate.SentAt, sentAt)
+			}
+		})
+	}
+}
+
+func TestTwitterOauthTweetSuppressesDuplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		sent      *sentCache
+		wantPosts int
+		wantErr   bool
+	}{
+		{name: "duplicate within the window is suppressed", sent: newSentCache(time.Hour), wantPosts: 1, wantErr: true},
+		{name: "deduplication disabled", wantPosts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := 0
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				posts++
+				return jsonResponse(req, http.StatusCreated, `{"data":{"id":"tweet-1","text":"gm"}}`), nil
+			})
+			client, err := gotwi.NewClient(&gotwi.NewClientInput{
+				HTTPClient:           &http.Client{Transport: transport},
+				AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+				OAuthToken:           "token",
+				OAuthTokenSecret:     "token-secret",
+				APIKey:               "key",
+				APIKeySecret:         "key-secret",
+			})
+			if err != nil {
+				t.Fatalf("gotwi.NewClient() error = %v", err)
+			}
+			twitter := &TwitterOauth{client: client, config: &conf.TwitterConfig{}, sent: tt.sent}
+
+			if err := twitter.Tweet(context.Background(), "gm"); err != nil {
+				t.Fatalf("first Tweet() error = %v", err)
+			}
+			err = twitter.Tweet(context.Background(), "gm")
+
+			var duplicate *DuplicateError
+			if errors.As(err, &duplicate) != tt.wantErr {
+				t.Errorf("second Tweet() error = %v, want duplicate %v", err, tt.wantErr)
+			}
+			if posts != tt.wantPosts {
+				t.Errorf("posted %d tweets, want %d", posts, tt.wantPosts)
+			}
+		})
+	}
+}
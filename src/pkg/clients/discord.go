@@ -1,7 +1,10 @@
 package clients
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -11,6 +14,20 @@ type DiscordMsg struct {
 	AuthorID  string
 	Content   string
 	ChannelID string
+	Files     []*MediaAttachment
+	// MessageID is the ID of a received message
+	MessageID string
+	// ReplyToID makes a sent message a reply to the message with this ID
+	ReplyToID string
+}
+
+// IDiscord is the Discord bot used by the social client
+type IDiscord interface {
+	GetMessageChannel() <-chan DiscordMsg
+	SendMessage(ctx context.Context, msg *DiscordMsg) error
+	SetPresence(status, activity string) error
+	SendTyping(ctx context.Context, channelID string) error
+	SelfID() string
 }
 
 type DiscordBot struct {
@@ -35,6 +52,56 @@ func NewDiscordBot(token string) *DiscordBot {
 	}
 }
 
+// ErrInvalidPresenceStatus is returned for a presence status Discord doesn't know
+var ErrInvalidPresenceStatus = errors.New("invalid discord presence status")
+
+// SetPresence sets the status of the bot, "online", "idle", "dnd" or
+// "invisible", and its activity text, e.g. "Analyzing Ethereum data". An empty
+// status means online and an empty activity clears it.
+func (dc *DiscordBot) SetPresence(status, activity string) error {
+	data, err := presenceUpdate(status, activity)
+	if err != nil {
+		return err
+	}
+	if err := dc.session.UpdateStatusComplex(data); err != nil {
+		return fmt.Errorf("failed to update discord presence: %w", err)
+	}
+	return nil
+}
+
+// presenceUpdate builds the presence payload, showing the activity as a custom status
+func presenceUpdate(status, activity string) (discordgo.UpdateStatusData, error) {
+	switch discordgo.Status(status) {
+	case "":
+		status = string(discordgo.StatusOnline)
+	case discordgo.StatusOnline, discordgo.StatusIdle, discordgo.StatusDoNotDisturb, discordgo.StatusInvisible:
+	default:
+		return discordgo.UpdateStatusData{}, fmt.Errorf("%w: %q", ErrInvalidPresenceStatus, status)
+	}
+
+	data := discordgo.UpdateStatusData{
+		Status:     status,
+		Activities: []*discordgo.Activity{},
+	}
+	if activity != "" {
+		// Discord requires an activity name, custom statuses show the state instead
+		data.Activities = append(data.Activities, &discordgo.Activity{
+			Name:  "Custom Status",
+			Type:  discordgo.ActivityTypeCustom,
+			State: activity,
+		})
+	}
+	return data, nil
+}
+
+// SelfID returns the user ID of the bot, empty until the session is ready
+func (dc *DiscordBot) SelfID() string {
+	if dc.session.State == nil || dc.session.State.User == nil {
+		return ""
+	}
+	return dc.session.State.User.ID
+}
+
 func (dc *DiscordBot) GetMessageChannel() <-chan DiscordMsg {
 	return dc.msgChannel
 }
@@ -43,10 +110,45 @@ func (dc *DiscordBot) SendMessage(
 	ctx context.Context,
 	msg *DiscordMsg,
 ) error {
-	_, err := dc.session.ChannelMessageSend(msg.ChannelID, msg.Content)
+	_, err := dc.session.ChannelMessageSendComplex(msg.ChannelID, messageSend(msg))
 	return err
 }
 
+// SendTyping shows the bot typing in the channel for the next ten seconds or
+// until it sends a message
+func (dc *DiscordBot) SendTyping(ctx context.Context, channelID string) error {
+	if err := dc.session.ChannelTyping(channelID, discordgo.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to send discord typing: %w", err)
+	}
+	return nil
+}
+
+// messageSend builds the message, replying to ReplyToID when set
+func messageSend(msg *DiscordMsg) *discordgo.MessageSend {
+	send := &discordgo.MessageSend{
+		Content: msg.Content,
+	}
+
+	for _, file := range msg.Files {
+		send.Files = append(send.Files, &discordgo.File{
+			Name:        file.Filename,
+			ContentType: file.MimeType,
+			Reader:      bytes.NewReader(file.Data),
+		})
+	}
+
+	if msg.ReplyToID != "" {
+		// Still post the answer when the original message was deleted
+		failIfNotExists := false
+		send.Reference = &discordgo.MessageReference{
+			MessageID:       msg.ReplyToID,
+			ChannelID:       msg.ChannelID,
+			FailIfNotExists: &failIfNotExists,
+		}
+	}
+	return send
+}
+
 func MessageListener(
 	msgChannel chan<- DiscordMsg,
 ) func(*discordgo.Session, *discordgo.MessageCreate) {
@@ -61,11 +163,16 @@ func MessageListener(
 			if strings.HasPrefix(content, "!ask") {
 				content = strings.TrimSpace(strings.TrimPrefix(content, "!ask"))
 			}
+			// Drop the mention of the bot so "@bot /analyze ..." parses as a command
+			for _, mention := range []string{"<@" + discord.State.User.ID + ">", "<@!" + discord.State.User.ID + ">"} {
+				content = strings.TrimSpace(strings.ReplaceAll(content, mention, ""))
+			}
 
 			msgChannel <- DiscordMsg{
 				AuthorID:  message.Author.ID,
 				Content:   content,
 				ChannelID: message.ChannelID,
+				MessageID: message.ID,
 			}
 		}
 	}
@@ -0,0 +1,192 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestDiscordSendMessageUploadsFiles(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     []*MediaAttachment
+		wantFiles []formPart
+	}{
+		{name: "text only"},
+		{
+			name:      "image",
+			files:     []*MediaAttachment{{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")}},
+			wantFiles: []formPart{{filename: "chart.png", contentType: "image/png", data: "png-bytes"}},
+		},
+		{
+			name: "several files",
+			files: []*MediaAttachment{
+				{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")},
+				{Filename: "stats.csv", Data: []byte("a,b")},
+			},
+			wantFiles: []formPart{
+				{filename: "chart.png", contentType: "image/png", data: "png-bytes"},
+				{filename: "stats.csv", contentType: "application/octet-stream", data: "a,b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				content string
+				files   []formPart
+			)
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.String() != discordgo.EndpointChannelMessages("channel-1") {
+					t.Errorf("request URL = %s, want the channel messages endpoint", req.URL)
+				}
+
+				var payload []byte
+				if len(tt.files) == 0 {
+					payload, _ = io.ReadAll(req.Body)
+				} else {
+					parts := readMultipart(t, req)
+					payload = []byte(parts["payload_json"].data)
+					for i := range tt.files {
+						files = append(files, parts[fmt.Sprintf("files[%d]", i)])
+					}
+				}
+
+				var msg discordgo.MessageSend
+				if err := json.Unmarshal(payload, &msg); err != nil {
+					t.Fatalf("payload is not JSON: %v", err)
+				}
+				content = msg.Content
+				return jsonResponse(req, http.StatusOK, `{"id":"message-1","channel_id":"channel-1"}`), nil
+			})
+
+			session, err := discordgo.New("Bot test-token")
+			if err != nil {
+				t.Fatalf("discordgo.New() error = %v", err)
+			}
+			session.Client = &http.Client{Transport: transport}
+			bot := &DiscordBot{session: session}
+
+			err = bot.SendMessage(context.Background(), &DiscordMsg{ChannelID: "channel-1", Content: "gm", Files: tt.files})
+			if err != nil {
+				t.Fatalf("SendMessage() error = %v", err)
+			}
+
+			if content != "gm" {
+				t.Errorf("content = %q, want %q", content, "gm")
+			}
+			if len(files) != len(tt.wantFiles) {
+				t.Fatalf("uploaded %d files, want %d", len(files), len(tt.wantFiles))
+			}
+			for i, want := range tt.wantFiles {
+				if files[i] != want {
+					t.Errorf("files[%d] = %+v, want %+v", i, files[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscordSendMessageReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		replyToID string
+		want      *discordgo.MessageReference
+	}{
+		{name: "reply", replyToID: "message-0", want: &discordgo.MessageReference{MessageID: "message-0", ChannelID: "channel-1"}},
+		{name: "fresh message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload map[string]json.RawMessage
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				if err := json.Unmarshal(body, &payload); err != nil {
+					t.Fatalf("payload is not JSON: %v", err)
+				}
+				return jsonResponse(req, http.StatusOK, `{"id":"message-1","channel_id":"channel-1"}`), nil
+			})
+
+			session, err := discordgo.New("Bot test-token")
+			if err != nil {
+				t.Fatalf("discordgo.New() error = %v", err)
+			}
+			session.Client = &http.Client{Transport: transport}
+			bot := &DiscordBot{session: session}
+
+			if err := bot.SendMessage(context.Background(), &DiscordMsg{ChannelID: "channel-1", Content: "gm", ReplyToID: tt.replyToID}); err != nil {
+				t.Fatalf("SendMessage() error = %v", err)
+			}
+
+			raw, ok := payload["message_reference"]
+			if tt.want == nil {
+				if ok {
+					t.Errorf("message_reference = %s, want it omitted", raw)
+				}
+				return
+			}
+			var got discordgo.MessageReference
+			if err := json.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("message_reference = %s, want a reference", raw)
+			}
+			if got.MessageID != tt.want.MessageID || got.ChannelID != tt.want.ChannelID {
+				t.Errorf("message_reference = %+v, want %+v", got, tt.want)
+			}
+			// The reply is still posted when the original message is gone
+			if got.FailIfNotExists == nil || *got.FailIfNotExists {
+				t.Errorf("fail_if_not_exists = %v, want false", got.FailIfNotExists)
+			}
+		})
+	}
+}
+
+func TestPresenceUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		activity     string
+		wantStatus   string
+		wantActivity string
+		wantErr      bool
+	}{
+		{name: "activity", status: "idle", activity: "Analyzing Ethereum data", wantStatus: "idle", wantActivity: "Analyzing Ethereum data"},
+		{name: "default status", activity: "Analyzing Ethereum data", wantStatus: "online", wantActivity: "Analyzing Ethereum data"},
+		{name: "invisible", status: "invisible", wantStatus: "invisible"},
+		{name: "unknown status", status: "away", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := presenceUpdate(tt.status, tt.activity)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPresenceStatus) {
+					t.Errorf("presenceUpdate() error = %v, want ErrInvalidPresenceStatus", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("presenceUpdate() error = %v", err)
+			}
+			if data.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", data.Status, tt.wantStatus)
+			}
+			if tt.wantActivity == "" {
+				if len(data.Activities) != 0 {
+					t.Errorf("activities = %v, want none", data.Activities)
+				}
+				return
+			}
+			if len(data.Activities) != 1 || data.Activities[0].Type != discordgo.ActivityTypeCustom || data.Activities[0].State != tt.wantActivity {
+				t.Errorf("activities = %+v, want the custom status %q", data.Activities, tt.wantActivity)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package clients
+
+import "strings"
+
+// MediaAttachment is a file to be uploaded alongside a message
+type MediaAttachment struct {
+	Filename string
+	MimeType string
+	Data     []byte
+}
+
+// IsImage reports whether the attachment should be sent as an image
+func (m *MediaAttachment) IsImage() bool {
+	return strings.HasPrefix(m.MimeType, "image/")
+}
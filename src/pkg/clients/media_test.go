@@ -0,0 +1,83 @@
+package clients
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// roundTripFunc lets a function act as the HTTP transport
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// formPart is a single field of a recorded multipart body
+type formPart struct {
+	filename    string
+	contentType string
+	data        string
+}
+
+// readMultipart returns the parts of a multipart request keyed by form name
+func readMultipart(t *testing.T, req *http.Request) map[string]formPart {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		t.Fatalf("request is not multipart: %q", req.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]formPart)
+	reader := multipart.NewReader(req.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("failed to read multipart body: %v", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read part %s: %v", part.FormName(), err)
+		}
+		parts[part.FormName()] = formPart{
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			data:        string(data),
+		}
+	}
+}
+
+func jsonResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestMediaAttachmentIsImage(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     bool
+	}{
+		{mimeType: "image/png", want: true},
+		{mimeType: "image/jpeg", want: true},
+		{mimeType: "application/pdf", want: false},
+		{mimeType: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			if got := (&MediaAttachment{MimeType: tt.mimeType}).IsImage(); got != tt.want {
+				t.Errorf("IsImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
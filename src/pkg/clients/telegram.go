@@ -22,6 +22,64 @@ type TelegramMessage struct {
 	Command   string
 	ReplyTo   int64
 	Timestamp time.Time
+	// CallbackID and CallbackData are set when the user tapped an inline button
+	CallbackID   string
+	CallbackData string
+}
+
+// IsCallback reports whether the message is a tap on an inline button
+func (m TelegramMessage) IsCallback() bool {
+	return m.CallbackID != ""
+}
+
+// InlineButton is a button of an inline keyboard, Data is returned in the callback query
+type InlineButton struct {
+	Text string
+	Data string
+}
+
+// maxCallbackDataLength is the Telegram limit for callback data in bytes
+const maxCallbackDataLength = 64
+
+// NewInlineKeyboard builds the inline keyboard markup for rows of buttons
+func NewInlineKeyboard(rows [][]InlineButton) (telegram.InlineKeyboardMarkup, error) {
+	keyboard := make([][]telegram.InlineKeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		buttons := make([]telegram.InlineKeyboardButton, 0, len(row))
+		for _, button := range row {
+			if button.Data == "" || len(button.Data) > maxCallbackDataLength {
+				return telegram.InlineKeyboardMarkup{}, fmt.Errorf("callback data of button %q must be 1-%d bytes", button.Text, maxCallbackDataLength)
+			}
+			buttons = append(buttons, telegram.NewInlineKeyboardButtonData(button.Text, button.Data))
+		}
+		if len(buttons) > 0 {
+			keyboard = append(keyboard, buttons)
+		}
+	}
+	if len(keyboard) == 0 {
+		return telegram.InlineKeyboardMarkup{}, fmt.Errorf("inline keyboard has no buttons")
+	}
+	return telegram.NewInlineKeyboardMarkup(keyboard...), nil
+}
+
+// callbackMessage converts a callback query to a TelegramMessage
+func callbackMessage(query *telegram.CallbackQuery) TelegramMessage {
+	msg := TelegramMessage{
+		CallbackID:   query.ID,
+		CallbackData: query.Data,
+		Timestamp:    time.Now(),
+	}
+	if query.From != nil {
+		msg.UserID = query.From.ID
+		msg.Username = query.From.UserName
+	}
+	if query.Message != nil {
+		msg.MessageID = int64(query.Message.MessageID)
+		if query.Message.Chat != nil {
+			msg.ChatID = query.Message.Chat.ID
+		}
+	}
+	return msg
 }
 
 // TelegramClient represents a Telegram bot client
@@ -49,44 +107,101 @@ func NewTelegramClient(config *conf.TelegramConfig) (*TelegramClient, error) {
 	return client, nil
 }
 
+// Backoff between failed polls for updates
+const (
+	listenerMinBackoff = time.Second
+	listenerMaxBackoff = time.Minute
+)
+
+// updateSource long-polls for updates, implemented by telegram.BotAPI
+type updateSource interface {
+	GetUpdates(config telegram.UpdateConfig) ([]telegram.Update, error)
+}
+
 // StartListener starts listening for incoming messages
 func (c *TelegramClient) StartListener(ctx context.Context) error {
-	u := telegram.NewUpdate(0)
-	u.Timeout = 60
-
-	updates := c.bot.GetUpdatesChan(u)
+	go c.listen(ctx, c.bot)
+	return nil
+}
 
-	go func() {
-		for {
+// listen polls for updates until ctx is done. A failed poll is retried with
+// backoff from the last processed offset, so a network blip neither stops the
+// listener nor skips or repeats updates.
+func (c *TelegramClient) listen(ctx context.Context, source updateSource) {
+	offset := 0
+	backoff := listenerMinBackoff
+
+	for ctx.Err() == nil {
+		u := telegram.NewUpdate(offset)
+		u.Timeout = 60
+
+		updates, err := source.GetUpdates(u)
+		if err != nil {
+			logger.GetLogger().Warnw("Failed to get telegram updates, reconnecting",
+				"error", err,
+				"retry_in", backoff,
+			)
 			select {
-			case update := <-updates:
-				if update.Message != nil {
-					// Get ReplyToMessageID safely
-					var replyToID int64
-					if update.Message.ReplyToMessage != nil {
-						replyToID = int64(update.Message.ReplyToMessage.MessageID)
-					}
-
-					msg := TelegramMessage{
-						MessageID: int64(update.Message.MessageID),
-						ChatID:    update.Message.Chat.ID,
-						UserID:    int64(update.Message.From.ID),
-						Username:  update.Message.From.UserName,
-						Text:      update.Message.Text,
-						IsCommand: update.Message.IsCommand(),
-						Command:   update.Message.Command(),
-						ReplyTo:   replyToID,
-						Timestamp: time.Now(),
-					}
-					c.msgChan <- msg
-				}
+			case <-time.After(backoff):
 			case <-ctx.Done():
 				return
 			}
+			backoff = min(backoff*2, listenerMaxBackoff)
+			continue
 		}
-	}()
+		backoff = listenerMinBackoff
 
-	return nil
+		for _, update := range updates {
+			if update.UpdateID >= offset {
+				offset = update.UpdateID + 1
+			}
+			if !c.forwardUpdate(ctx, update) {
+				return
+			}
+		}
+	}
+}
+
+// forwardUpdate sends messages and button taps to the message channel,
+// returning false when ctx is done first
+func (c *TelegramClient) forwardUpdate(ctx context.Context, update telegram.Update) bool {
+	var msg TelegramMessage
+	switch {
+	case update.Message != nil:
+		// Get ReplyToMessageID safely
+		var replyToID int64
+		if update.Message.ReplyToMessage != nil {
+			replyToID = int64(update.Message.ReplyToMessage.MessageID)
+		}
+
+		msg = TelegramMessage{
+			MessageID: int64(update.Message.MessageID),
+			ChatID:    update.Message.Chat.ID,
+			UserID:    int64(update.Message.From.ID),
+			Username:  update.Message.From.UserName,
+			Text:      update.Message.Text,
+			IsCommand: update.Message.IsCommand(),
+			Command:   update.Message.Command(),
+			ReplyTo:   replyToID,
+			Timestamp: time.Now(),
+		}
+	case update.CallbackQuery != nil:
+		msg = callbackMessage(update.CallbackQuery)
+	default:
+		return true
+	}
+
+	select {
+	case c.msgChan <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// SelfUsername returns the username of the bot, matching the sender of its own messages
+func (c *TelegramClient) SelfUsername() string {
+	return c.bot.Self.UserName
 }
 
 // GetMessageChannel returns channel for receiving messages
@@ -107,6 +222,15 @@ func (c *TelegramClient) SendMessage(ctx context.Context, chatID int64, text str
 	return nil
 }
 
+// SendTyping shows the bot typing in the chat for the next five seconds or
+// until it sends a message
+func (c *TelegramClient) SendTyping(ctx context.Context, chatID int64) error {
+	if _, err := c.bot.Request(telegram.NewChatAction(chatID, telegram.ChatTyping)); err != nil {
+		return fmt.Errorf("failed to send telegram typing: %w", err)
+	}
+	return nil
+}
+
 // SendReply sends a reply to a specific message
 func (c *TelegramClient) SendReply(ctx context.Context, chatID int64, replyToID int64, text string) error {
 	msg := telegram.NewMessage(chatID, text)
@@ -121,6 +245,34 @@ func (c *TelegramClient) SendReply(ctx context.Context, chatID int64, replyToID
 	return nil
 }
 
+// SendKeyboard sends a message with an inline keyboard
+func (c *TelegramClient) SendKeyboard(ctx context.Context, chatID int64, text string, rows [][]InlineButton) error {
+	keyboard, err := NewInlineKeyboard(rows)
+	if err != nil {
+		return err
+	}
+
+	msg := telegram.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+
+	_, err = c.bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send keyboard message: %w", err)
+	}
+
+	return nil
+}
+
+// AnswerCallback acknowledges a callback query so the client stops its loading indicator
+func (c *TelegramClient) AnswerCallback(ctx context.Context, callbackID string, text string) error {
+	_, err := c.bot.Request(telegram.NewCallback(callbackID, text))
+	if err != nil {
+		return fmt.Errorf("failed to answer callback query: %w", err)
+	}
+
+	return nil
+}
+
 // BroadcastMessage sends a message to the default channel
 func (c *TelegramClient) BroadcastMessage(ctx context.Context, text string) error {
 	return c.SendMessage(ctx, c.config.ChannelID, text)
@@ -152,6 +304,37 @@ func (c *TelegramClient) SendDocument(ctx context.Context, chatID int64, filePat
 	return nil
 }
 
+// SendMedia sends an in-memory attachment as a photo or document depending on its type
+func (c *TelegramClient) SendMedia(ctx context.Context, chatID int64, media *MediaAttachment, caption string) error {
+	file := telegram.FileBytes{
+		Name:  media.Filename,
+		Bytes: media.Data,
+	}
+
+	var chattable telegram.Chattable
+	if media.IsImage() {
+		photo := telegram.NewPhoto(chatID, file)
+		photo.Caption = caption
+		chattable = photo
+	} else {
+		doc := telegram.NewDocument(chatID, file)
+		doc.Caption = caption
+		chattable = doc
+	}
+
+	_, err := c.bot.Send(chattable)
+	if err != nil {
+		return fmt.Errorf("failed to send media: %w", err)
+	}
+
+	return nil
+}
+
+// BroadcastMedia sends an attachment to the default channel
+func (c *TelegramClient) BroadcastMedia(ctx context.Context, media *MediaAttachment, caption string) error {
+	return c.SendMedia(ctx, c.config.ChannelID, media, caption)
+}
+
 // HandleCommand registers a command handler
 func (c *TelegramClient) HandleCommand(command string, handler func(TelegramMessage) error) {
 	go func() {
@@ -0,0 +1,239 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	telegram "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestTelegramSendMediaPayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		media      *MediaAttachment
+		wantMethod string
+		wantField  string
+	}{
+		{
+			name:       "image as photo",
+			media:      &MediaAttachment{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")},
+			wantMethod: "sendPhoto",
+			wantField:  "photo",
+		},
+		{
+			name:       "other files as document",
+			media:      &MediaAttachment{Filename: "report.pdf", MimeType: "application/pdf", Data: []byte("pdf-bytes")},
+			wantMethod: "sendDocument",
+			wantField:  "document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				method string
+				parts  map[string]formPart
+			)
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				called := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+				if called == "getMe" {
+					return jsonResponse(req, http.StatusOK, `{"ok":true,"result":{"id":1,"is_bot":true,"username":"bot"}}`), nil
+				}
+				method = called
+				parts = readMultipart(t, req)
+				return jsonResponse(req, http.StatusOK, `{"ok":true,"result":{"message_id":1,"chat":{"id":42}}}`), nil
+			})
+
+			bot, err := telegram.NewBotAPIWithClient("test-token", telegram.APIEndpoint, &http.Client{Transport: transport})
+			if err != nil {
+				t.Fatalf("NewBotAPIWithClient() error = %v", err)
+			}
+			client := &TelegramClient{bot: bot, config: conf.TelegramConfig{ChannelID: 42}}
+
+			if err := client.BroadcastMedia(context.Background(), tt.media, "daily stats"); err != nil {
+				t.Fatalf("BroadcastMedia() error = %v", err)
+			}
+
+			if method != tt.wantMethod {
+				t.Errorf("called %s, want %s", method, tt.wantMethod)
+			}
+			if got := parts["chat_id"].data; got != "42" {
+				t.Errorf("chat_id = %q, want %q", got, "42")
+			}
+			if got := parts["caption"].data; got != "daily stats" {
+				t.Errorf("caption = %q, want %q", got, "daily stats")
+			}
+			file := parts[tt.wantField]
+			if file.filename != tt.media.Filename || file.data != string(tt.media.Data) {
+				t.Errorf("%s part = %+v, want %s with its bytes", tt.wantField, file, tt.media.Filename)
+			}
+		})
+	}
+}
+
+func TestNewInlineKeyboard(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]InlineButton
+		wantRows int
+		wantErr  bool
+	}{
+		{name: "one row", rows: [][]InlineButton{{{Text: "Confirm", Data: "confirm:1:yes"}, {Text: "Cancel", Data: "confirm:1:no"}}}, wantRows: 1},
+		{name: "empty rows are dropped", rows: [][]InlineButton{{}, {{Text: "Confirm", Data: "confirm:1:yes"}}}, wantRows: 1},
+		{name: "no buttons", rows: [][]InlineButton{{}}, wantErr: true},
+		{name: "missing data", rows: [][]InlineButton{{{Text: "Confirm"}}}, wantErr: true},
+		{name: "data over 64 bytes", rows: [][]InlineButton{{{Text: "Confirm", Data: strings.Repeat("x", 65)}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyboard, err := NewInlineKeyboard(tt.rows)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewInlineKeyboard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keyboard.InlineKeyboard) != tt.wantRows {
+				t.Errorf("keyboard has %d rows, want %d", len(keyboard.InlineKeyboard), tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestTelegramSendKeyboardPayload(t *testing.T) {
+	var form url.Values
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/getMe") {
+			return jsonResponse(req, http.StatusOK, `{"ok":true,"result":{"id":1,"is_bot":true,"username":"bot"}}`), nil
+		}
+		if err := req.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		form = req.PostForm
+		return jsonResponse(req, http.StatusOK, `{"ok":true,"result":{"message_id":1,"chat":{"id":42}}}`), nil
+	})
+	bot, err := telegram.NewBotAPIWithClient("test-token", telegram.APIEndpoint, &http.Client{Transport: transport})
+	if err != nil {
+		t.Fatalf("NewBotAPIWithClient() error = %v", err)
+	}
+	client := &TelegramClient{bot: bot}
+
+	rows := [][]InlineButton{{{Text: "Confirm", Data: "confirm:1:yes"}, {Text: "Cancel", Data: "confirm:1:no"}}}
+	if err := client.SendKeyboard(context.Background(), 42, "Please confirm", rows); err != nil {
+		t.Fatalf("SendKeyboard() error = %v", err)
+	}
+
+	if form.Get("chat_id") != "42" || form.Get("text") != "Please confirm" {
+		t.Errorf("form = %v, want the chat and text", form)
+	}
+	var markup telegram.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(form.Get("reply_markup")), &markup); err != nil {
+		t.Fatalf("reply_markup is not JSON: %v", err)
+	}
+	buttons := markup.InlineKeyboard[0]
+	if len(buttons) != 2 || *buttons[0].CallbackData != "confirm:1:yes" || buttons[1].Text != "Cancel" {
+		t.Errorf("buttons = %+v, want confirm and cancel", buttons)
+	}
+}
+
+func TestCallbackMessage(t *testing.T) {
+	query := &telegram.CallbackQuery{
+		ID:      "callback-1",
+		Data:    "confirm:1:yes",
+		From:    &telegram.User{ID: 7, UserName: "alice"},
+		Message: &telegram.Message{MessageID: 9, Chat: &telegram.Chat{ID: 42}},
+	}
+
+	msg := callbackMessage(query)
+	if !msg.IsCallback() {
+		t.Error("IsCallback() = false, want true")
+	}
+	if msg.CallbackID != "callback-1" || msg.CallbackData != "confirm:1:yes" || msg.UserID != 7 ||
+		msg.Username != "alice" || msg.MessageID != 9 || msg.ChatID != 42 {
+		t.Errorf("callbackMessage() = %+v, want the query fields", msg)
+	}
+}
+
+// poll is one scripted answer of fakeUpdates
+type poll struct {
+	updates []telegram.Update
+	err     error
+}
+
+// fakeUpdates answers the polls in order, records the offsets asked for and
+// cancels the listener once the script runs out
+type fakeUpdates struct {
+	polls   []poll
+	offsets []int
+	cancel  context.CancelFunc
+}
+
+func (f *fakeUpdates) GetUpdates(config telegram.UpdateConfig) ([]telegram.Update, error) {
+	f.offsets = append(f.offsets, config.Offset)
+	if len(f.polls) == 0 {
+		f.cancel()
+		return nil, nil
+	}
+	next := f.polls[0]
+	f.polls = f.polls[1:]
+	return next.updates, next.err
+}
+
+func textUpdate(id int, text string) telegram.Update {
+	return telegram.Update{
+		UpdateID: id,
+		Message: &telegram.Message{
+			MessageID: id,
+			Chat:      &telegram.Chat{ID: 42},
+			From:      &telegram.User{ID: 7, UserName: "alice"},
+			Text:      text,
+		},
+	}
+}
+
+func TestListenResubscribesAfterError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	source := &fakeUpdates{
+		polls: []poll{
+			{updates: []telegram.Update{textUpdate(10, "gm"), textUpdate(11, "wen")}},
+			{err: errors.New("connection reset by peer")},
+			{updates: []telegram.Update{textUpdate(12, "moon")}},
+		},
+		cancel: cancel,
+	}
+	client := &TelegramClient{msgChan: make(chan TelegramMessage, 10)}
+
+	done := make(chan struct{})
+	go func() {
+		client.listen(ctx, source)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("listener did not stop")
+	}
+	close(client.msgChan)
+
+	// The poll after the error resumes from the last processed update
+	wantOffsets := []int{0, 12, 12, 13}
+	if fmt.Sprint(source.offsets) != fmt.Sprint(wantOffsets) {
+		t.Errorf("polled offsets %v, want %v", source.offsets, wantOffsets)
+	}
+
+	var texts []string
+	for msg := range client.msgChan {
+		texts = append(texts, msg.Text)
+	}
+	if strings.Join(texts, ",") != "gm,wen,moon" {
+		t.Errorf("received %q, want every update once", texts)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 
 	"github.com/michimani/gotwi"
 	"github.com/michimani/gotwi/fields"
@@ -27,6 +28,7 @@ import (
 type ITwitter interface {
 	GetMe() string
 	Tweet(ctx context.Context, text string) error
+	TweetWithMedia(ctx context.Context, text string, media []*MediaAttachment, replyToTweetID string) error
 	MonitorMentioned(ctx context.Context) ([]*Tweet, error)
 	ReplyToTweet(ctx context.Context, replyText, replyToTweetID string) (*Tweet, error)
 	DeleteTweet(ctx context.Context, tweetID string) error
@@ -57,10 +59,11 @@ type TwitterOauth struct {
 	user   *resources.User
 	tweets []resources.Tweet
 	config *conf.TwitterConfig // Add config field for future reference
+	sent   *sentCache          // Recently posted tweets, nil when deduplication is disabled
 }
 
 // NewTwitterClient returns the interface type
-func NewTwitterClient(twitterConfig *conf.TwitterConfig) (ITwitter, error) {
+func NewTwitterClient(twitterConfig *conf.TwitterConfig, loginRetry retry.Policy) (ITwitter, error) {
 	if twitterConfig == nil {
 		return nil, fmt.Errorf("twitter config is nil")
 	}
@@ -73,7 +76,7 @@ func NewTwitterClient(twitterConfig *conf.TwitterConfig) (ITwitter, error) {
 	case conf.TwitterModeAPI:
 		return newTwitterAPIClient(twitterConfig) // Returns *TwitterOauth
 	case conf.TwitterModeScraper:
-		return newTwitterScraper(twitterConfig) // Returns *TwitterScraper
+		return newTwitterScraper(twitterConfig, loginRetry) // Returns *TwitterScraper
 	default:
 		return nil, fmt.Errorf("invalid twitter mode: %s", twitterConfig.Mode)
 	}
@@ -109,7 +112,7 @@ func (t *TwitterOauth) MonitorMentioned(ctx context.Context) ([]*Tweet, error) {
 	// Note:Do not quickly check the tweets, because maybe the twitter api is rate limited
 	monitorWindow := t.config.MonitorWindow
 	if monitorWindow <= 0 {
-		monitorWindow = 20
+		monitorWindow = conf.DefaultTwitterMonitorWindow
 	}
 
 	startTime := time.Now().Add(-time.Duration(monitorWindow) * time.Minute)
@@ -162,7 +165,15 @@ func (t *TwitterOauth) GetMe() string {
 	return *t.user.ID
 }
 
+// Tweet posts a tweet, returning a *DuplicateError when the same text was
+// already posted within the dedup window
 func (t *TwitterOauth) Tweet(ctx context.Context, tweet string) error {
+	if t.sent != nil {
+		if err := t.sent.check(tweet, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	p := &manageTypes.CreateInput{
 		Text: gotwi.String(tweet),
 	}
@@ -172,6 +183,10 @@ func (t *TwitterOauth) Tweet(ctx context.Context, tweet string) error {
 		logger.GetLogger().Errorln(err.Error())
 		return err
 	}
+
+	if t.sent != nil {
+		t.sent.record(tweet, time.Now())
+	}
 	return nil
 }
 
@@ -343,10 +358,21 @@ func newTwitterAPIClient(twitterConfig *conf.TwitterConfig) (*TwitterOauth, erro
 		return nil, err
 	}
 
-	return &TwitterOauth{
+	client := &TwitterOauth{
 		client: c,
 		user:   &u.Data,
 		tweets: u.Includes.Tweets,
 		config: twitterConfig,
-	}, nil
+	}
+
+	// A negative window disables deduplication
+	dedupWindow := twitterConfig.DedupWindow
+	if dedupWindow == 0 {
+		dedupWindow = defaultDedupWindow
+	}
+	if dedupWindow > 0 {
+		client.sent = newSentCache(time.Duration(dedupWindow) * time.Minute)
+	}
+
+	return client, nil
 }
@@ -0,0 +1,164 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/michimani/gotwi"
+	"github.com/michimani/gotwi/tweet/managetweet"
+	manageTypes "github.com/michimani/gotwi/tweet/managetweet/types"
+	twitterscraper "github.com/tyxben/twitter-scraper"
+)
+
+// mediaUploadURL is the v1.1 endpoint, the v2 API has no media upload
+const mediaUploadURL = "https://upload.twitter.com/1.1/media/upload.json"
+
+const oauth1HeaderFormat = `OAuth oauth_consumer_key="%s",oauth_nonce="%s",oauth_signature="%s",oauth_signature_method="%s",oauth_timestamp="%s",oauth_token="%s",oauth_version="%s"`
+
+// TweetWithMedia uploads the attachments and posts a tweet referencing them,
+// as a reply when replyToTweetID is set
+func (t *TwitterOauth) TweetWithMedia(ctx context.Context, text string, media []*MediaAttachment, replyToTweetID string) error {
+	mediaIDs := make([]string, 0, len(media))
+	for _, m := range media {
+		id, err := t.uploadMedia(ctx, m)
+		if err != nil {
+			return fmt.Errorf("failed to upload media %s: %w", m.Filename, err)
+		}
+		mediaIDs = append(mediaIDs, id)
+	}
+
+	p := &manageTypes.CreateInput{
+		Text: gotwi.String(text),
+	}
+	if len(mediaIDs) > 0 {
+		p.Media = &manageTypes.CreateInputMedia{MediaIDs: mediaIDs}
+	}
+	if replyToTweetID != "" {
+		p.Reply = &manageTypes.CreateInputReply{InReplyToTweetID: replyToTweetID}
+	}
+
+	if _, err := managetweet.Create(ctx, t.client, p); err != nil {
+		return fmt.Errorf("failed to create tweet with media: %w", err)
+	}
+	return nil
+}
+
+// uploadMedia performs a simple multipart upload and returns the media ID
+func (t *TwitterOauth) uploadMedia(ctx context.Context, media *MediaAttachment) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("media", media.Filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err = part.Write(media.Data); err != nil {
+		return "", fmt.Errorf("failed to write media data: %w", err)
+	}
+	if err = writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mediaUploadURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	// Multipart bodies are excluded from the OAuth1 signature base string
+	signature, err := gotwi.CreateOAuthSignature(&gotwi.CreateOAuthSignatureInput{
+		HTTPMethod:       req.Method,
+		RawEndpoint:      mediaUploadURL,
+		OAuthConsumerKey: t.client.OAuthConsumerKey(),
+		OAuthToken:       t.client.OAuthToken(),
+		SigningKey:       t.client.SigningKey(),
+		ParameterMap:     map[string]string{},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(oauth1HeaderFormat,
+		url.QueryEscape(t.client.OAuthConsumerKey()),
+		url.QueryEscape(signature.OAuthNonce),
+		url.QueryEscape(signature.OAuthSignature),
+		url.QueryEscape(signature.OAuthSignatureMethod),
+		url.QueryEscape(signature.OAuthTimestamp),
+		url.QueryEscape(t.client.OAuthToken()),
+		url.QueryEscape(signature.OAuthVersion),
+	))
+
+	resp, err := t.client.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("media upload failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var uploadResp struct {
+		MediaIDString string `json:"media_id_string"`
+	}
+	if err = json.Unmarshal(respBody, &uploadResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if uploadResp.MediaIDString == "" {
+		return "", fmt.Errorf("media upload returned no media id")
+	}
+
+	return uploadResp.MediaIDString, nil
+}
+
+// TweetWithMedia uploads the attachments and posts a tweet referencing them.
+// Like ReplyToTweet, a reply mentions the original author instead of threading.
+func (ts *TwitterScraper) TweetWithMedia(ctx context.Context, text string, media []*MediaAttachment, replyToTweetID string) error {
+	if replyToTweetID != "" {
+		mentioned, err := ts.mentionAuthor(text, replyToTweetID)
+		if err != nil {
+			return err
+		}
+		text = mentioned
+	}
+
+	// The scraper only uploads from disk, so stage the attachments in a temp dir
+	dir, err := os.MkdirTemp("", "twitter-media-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	uploaded := make([]*twitterscraper.Media, 0, len(media))
+	for i, m := range media {
+		path := filepath.Join(dir, fmt.Sprintf("%d-%s", i, filepath.Base(m.Filename)))
+		if err = os.WriteFile(path, m.Data, 0o600); err != nil {
+			return fmt.Errorf("failed to stage media %s: %w", m.Filename, err)
+		}
+
+		u, err := ts.scraper.UploadMedia(path)
+		if err != nil {
+			return fmt.Errorf("failed to upload media %s: %w", m.Filename, err)
+		}
+		uploaded = append(uploaded, u)
+	}
+
+	_, err = ts.scraper.CreateTweet(twitterscraper.NewTweet{
+		Text:   text,
+		Medias: uploaded,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post tweet: %w", err)
+	}
+	return nil
+}
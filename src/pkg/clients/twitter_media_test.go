@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	"github.com/michimani/gotwi"
+	twitterscraper "github.com/tyxben/twitter-scraper"
+)
+
+func TestTwitterOauthTweetWithMedia(t *testing.T) {
+	var (
+		uploads []formPart
+		tweet   struct {
+			Text  string `json:"text"`
+			Media struct {
+				MediaIDs []string `json:"media_ids"`
+			} `json:"media"`
+			Reply struct {
+				InReplyToTweetID string `json:"in_reply_to_tweet_id"`
+			} `json:"reply"`
+		}
+	)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == mediaUploadURL {
+			if req.Header.Get("Authorization") == "" {
+				t.Error("media upload is not signed")
+			}
+			uploads = append(uploads, readMultipart(t, req)["media"])
+			return jsonResponse(req, http.StatusOK, `{"media_id_string":"media-1"}`), nil
+		}
+		if err := json.NewDecoder(req.Body).Decode(&tweet); err != nil {
+			t.Fatalf("tweet body is not JSON: %v", err)
+		}
+		return jsonResponse(req, http.StatusCreated, `{"data":{"id":"tweet-1","text":"gm"}}`), nil
+	})
+
+	client, err := gotwi.NewClient(&gotwi.NewClientInput{
+		HTTPClient:           &http.Client{Transport: transport},
+		AuthenticationMethod: gotwi.AuthenMethodOAuth1UserContext,
+		OAuthToken:           "token",
+		OAuthTokenSecret:     "token-secret",
+		APIKey:               "key",
+		APIKeySecret:         "key-secret",
+	})
+	if err != nil {
+		t.Fatalf("gotwi.NewClient() error = %v", err)
+	}
+	twitter := &TwitterOauth{client: client, config: &conf.TwitterConfig{}}
+
+	media := []*MediaAttachment{{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")}}
+	if err := twitter.TweetWithMedia(context.Background(), "gm", media, "42"); err != nil {
+		t.Fatalf("TweetWithMedia() error = %v", err)
+	}
+
+	if len(uploads) != 1 || uploads[0].filename != "chart.png" || uploads[0].data != "png-bytes" {
+		t.Errorf("uploads = %+v, want chart.png with its bytes", uploads)
+	}
+	if tweet.Text != "gm" || len(tweet.Media.MediaIDs) != 1 || tweet.Media.MediaIDs[0] != "media-1" {
+		t.Errorf("tweet = %+v, want text gm referencing media-1", tweet)
+	}
+	if tweet.Reply.InReplyToTweetID != "42" {
+		t.Errorf("tweet replies to %q, want 42", tweet.Reply.InReplyToTweetID)
+	}
+}
+
+func TestTwitterScraperTweetWithMedia(t *testing.T) {
+	fake := &fakeScraper{}
+	ts := &TwitterScraper{scraper: fake, config: &conf.TwitterConfig{}}
+
+	media := []*MediaAttachment{
+		{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")},
+		{Filename: "../stats.csv", Data: []byte("a,b")},
+	}
+	if err := ts.TweetWithMedia(context.Background(), "gm", media, ""); err != nil {
+		t.Fatalf("TweetWithMedia() error = %v", err)
+	}
+
+	if len(fake.uploads) != 2 || fake.uploads[0] != "png-bytes" || fake.uploads[1] != "a,b" {
+		t.Errorf("uploads = %v, want both attachments", fake.uploads)
+	}
+	if len(fake.created) != 1 || fake.created[0].Text != "gm" || len(fake.created[0].Medias) != 2 {
+		t.Errorf("created tweets = %+v, want one with both media", fake.created)
+	}
+}
+
+func TestTwitterScraperTweetWithMediaReply(t *testing.T) {
+	fake := &fakeScraper{tweets: map[string]*twitterscraper.Tweet{"42": {ID: "42", Username: "alice"}}}
+	ts := &TwitterScraper{scraper: fake, config: &conf.TwitterConfig{}}
+
+	media := []*MediaAttachment{{Filename: "chart.png", MimeType: "image/png", Data: []byte("png-bytes")}}
+	if err := ts.TweetWithMedia(context.Background(), "gm", media, "42"); err != nil {
+		t.Fatalf("TweetWithMedia() error = %v", err)
+	}
+
+	if len(fake.created) != 1 || fake.created[0].Text != "@alice gm" || len(fake.created[0].Medias) != 1 {
+		t.Errorf("created tweets = %+v, want one mentioning alice with the media", fake.created)
+	}
+}
@@ -2,23 +2,38 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 
 	twitterscraper "github.com/tyxben/twitter-scraper"
 )
 
+// errNotLoggedIn is returned when a login succeeds without a logged in session
+var errNotLoggedIn = errors.New("scraper is not logged in")
+
+// scraperClient is the part of the scraper library used by TwitterScraper
+type scraperClient interface {
+	GetTweet(id string) (*twitterscraper.Tweet, error)
+	CreateTweet(tweet twitterscraper.NewTweet) (*twitterscraper.Tweet, error)
+	DeleteTweet(tweetID string) error
+	SearchTweets(ctx context.Context, query string, maxTweetsNbr int) <-chan *twitterscraper.TweetResult
+	UploadMedia(filePath string) (*twitterscraper.Media, error)
+}
+
 // TwitterScraper represents a Twitter scraper using browser automation
 type TwitterScraper struct {
-	scraper *twitterscraper.Scraper
+	scraper scraperClient
 	config  *conf.TwitterConfig
 	userID  string // Store logged in user's ID
 }
 
 // NewTwitterScraper creates a new Twitter scraper with improved error handling and validation
-func newTwitterScraper(config *conf.TwitterConfig) (*TwitterScraper, error) {
+func newTwitterScraper(config *conf.TwitterConfig, loginRetry retry.Policy) (*TwitterScraper, error) {
 	// Validate config
 	if err := validateConfig(config); err != nil {
 		return nil, fmt.Errorf("invalid twitter config: %w", err)
@@ -27,17 +42,17 @@ func newTwitterScraper(config *conf.TwitterConfig) (*TwitterScraper, error) {
 	scraper := twitterscraper.New()
 
 	// Login with retry mechanism
-	var loginErr error
-	for attempts := 0; attempts < 3; attempts++ {
-		loginErr = scraper.Login(config.Username, config.Password)
-		if loginErr == nil && scraper.IsLoggedIn() {
-			break
+	loginErr := loginRetry.Do(context.Background(), func(int) error {
+		if err := scraper.Login(config.Username, config.Password); err != nil {
+			return err
 		}
-		time.Sleep(time.Second * 2)
-	}
-
-	if loginErr != nil || !scraper.IsLoggedIn() {
-		return nil, fmt.Errorf("failed to login after multiple attempts: %v", loginErr)
+		if !scraper.IsLoggedIn() {
+			return errNotLoggedIn
+		}
+		return nil
+	})
+	if loginErr != nil {
+		return nil, fmt.Errorf("failed to login after %d attempts: %w", loginRetry.WithDefaults().Attempts, loginErr)
 	}
 
 	// Get logged in user's profile
@@ -62,7 +77,7 @@ func (ts *TwitterScraper) GetMe() string {
 func (ts *TwitterScraper) MonitorMentioned(ctx context.Context) ([]*Tweet, error) {
 	monitorWindow := ts.config.MonitorWindow
 	if monitorWindow <= 0 {
-		monitorWindow = 20
+		monitorWindow = conf.DefaultTwitterMonitorWindow
 	}
 
 	query := fmt.Sprintf("@%s", ts.config.Username)
@@ -82,19 +97,55 @@ func (ts *TwitterScraper) Tweet(ctx context.Context, text string) error {
 	return nil
 }
 
-// ReplyToTweet replies to a specific tweet
+// ReplyToTweet replies to a specific tweet.
+// The scraper has no in-reply-to support, so the reply is posted as a tweet mentioning the original author.
 func (ts *TwitterScraper) ReplyToTweet(ctx context.Context, replyText, replyToTweetID string) (*Tweet, error) {
-	_, err := ts.scraper.CreateRetweet(replyToTweetID)
+	text, err := ts.mentionAuthor(replyText, replyToTweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := ts.scraper.CreateTweet(twitterscraper.NewTweet{
+		Text:   text,
+		Medias: nil,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to reply to tweet: %w", err)
 	}
 
-	// Note: Since the scraper doesn't return the new tweet's ID, we return a simplified response
-	return &Tweet{
-		Text:      replyText,
+	reply := &Tweet{
+		Text:      text,
 		UserID:    ts.GetMe(),
 		CreatedAt: time.Now(),
-	}, nil
+	}
+	if created != nil {
+		reply.ID = created.ID
+		if created.Text != "" {
+			reply.Text = created.Text
+		}
+		if !created.TimeParsed.IsZero() {
+			reply.CreatedAt = created.TimeParsed
+		}
+	}
+
+	return reply, nil
+}
+
+// mentionAuthor prefixes the text with a mention of the author of the tweet
+// being replied to
+func (ts *TwitterScraper) mentionAuthor(text, replyToTweetID string) (string, error) {
+	original, err := ts.scraper.GetTweet(replyToTweetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get tweet to reply to: %w", err)
+	}
+
+	if original.Username != "" {
+		mention := fmt.Sprintf("@%s", original.Username)
+		if !strings.HasPrefix(text, mention) {
+			text = fmt.Sprintf("%s %s", mention, text)
+		}
+	}
+	return text, nil
 }
 
 // DeleteTweet deletes a tweet by its ID
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	twitterscraper "github.com/tyxben/twitter-scraper"
+)
+
+// fakeScraper records the tweets created through it
+type fakeScraper struct {
+	scraperClient
+	tweets   map[string]*twitterscraper.Tweet
+	created  []twitterscraper.NewTweet
+	retweets []string
+	uploads  []string
+}
+
+func (f *fakeScraper) GetTweet(id string) (*twitterscraper.Tweet, error) {
+	return f.tweets[id], nil
+}
+
+func (f *fakeScraper) CreateTweet(tweet twitterscraper.NewTweet) (*twitterscraper.Tweet, error) {
+	f.created = append(f.created, tweet)
+	return &twitterscraper.Tweet{ID: "reply-1", Text: tweet.Text}, nil
+}
+
+// UploadMedia records the staged file contents
+func (f *fakeScraper) UploadMedia(filePath string) (*twitterscraper.Media, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	f.uploads = append(f.uploads, string(data))
+	return &twitterscraper.Media{ID: len(f.uploads)}, nil
+}
+
+// CreateRetweet mirrors the library method so a retweet would be recorded
+func (f *fakeScraper) CreateRetweet(tweetID string) (string, error) {
+	f.retweets = append(f.retweets, tweetID)
+	return tweetID, nil
+}
+
+func TestReplyToTweetPostsMentionNotRetweet(t *testing.T) {
+	tests := []struct {
+		name      string
+		replyText string
+		wantText  string
+	}{
+		{name: "adds mention", replyText: "gm!", wantText: "@alice gm!"},
+		{name: "keeps existing mention", replyText: "@alice gm!", wantText: "@alice gm!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeScraper{tweets: map[string]*twitterscraper.Tweet{
+				"tweet-1": {ID: "tweet-1", Username: "alice", Text: "gm"},
+			}}
+			ts := &TwitterScraper{scraper: fake, config: &conf.TwitterConfig{}, userID: "agent"}
+
+			reply, err := ts.ReplyToTweet(context.Background(), tt.replyText, "tweet-1")
+			if err != nil {
+				t.Fatalf("ReplyToTweet() error = %v", err)
+			}
+
+			if len(fake.retweets) != 0 {
+				t.Errorf("ReplyToTweet() retweeted %v", fake.retweets)
+			}
+			if len(fake.created) != 1 || fake.created[0].Text != tt.wantText {
+				t.Errorf("created tweets = %+v, want one with text %q", fake.created, tt.wantText)
+			}
+			if reply.ID != "reply-1" || reply.Text != tt.wantText || reply.UserID != "agent" {
+				t.Errorf("reply = %+v, want the created tweet", reply)
+			}
+		})
+	}
+}
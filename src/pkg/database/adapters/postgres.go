@@ -78,6 +78,10 @@ func (s *PostgresStore) CharacterTable() *gorm.DB {
 	return s.db.Table("data_framework.character")
 }
 
+func (s *PostgresStore) SeenMessageTable() *gorm.DB {
+	return s.db.Table("data_framework.seen_message")
+}
+
 func (s *PostgresStore) Close() error {
 	if s.db != nil {
 		sqlDB, err := s.db.DB()
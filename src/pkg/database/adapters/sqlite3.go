@@ -68,6 +68,10 @@ func (s *SQLiteStore) CharacterTable() *gorm.DB {
 	return s.db.Table("character")
 }
 
+func (s *SQLiteStore) SeenMessageTable() *gorm.DB {
+	return s.db.Table("seen_message")
+}
+
 func (s *SQLiteStore) Close() error {
 	if s.db != nil {
 		sqlDB, err := s.db.DB()
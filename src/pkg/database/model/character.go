@@ -17,5 +17,9 @@ type Character struct {
 	TaskInstructions string `gorm:"text"`
 	PriorityAccounts string `gorm:"text"`
 	Preferences      string `gorm:"text"`
+	Responses        string `gorm:"text"`
+	ForbiddenTopics  string `gorm:"text"`
+	IntentRules      string `gorm:"text"`
+	Language         string
 	CreatedAt        time.Time
 }
@@ -6,5 +6,6 @@ type Memory struct {
 	ID        uint64 `gorm:"primarykey"`
 	MemoryID  string `gorm:"index"`
 	Content   string `gorm:"text"`
+	Embedding string `gorm:"text"`
 	CreatedAt time.Time
 }
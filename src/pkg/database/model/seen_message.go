@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type SeenMessage struct {
+	ID        uint64    `gorm:"primarykey"`
+	Platform  string    `gorm:"uniqueIndex:idx_seen_message"`
+	MessageID string    `gorm:"uniqueIndex:idx_seen_message"`
+	SeenAt    time.Time `gorm:"index"`
+}
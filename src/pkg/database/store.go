@@ -11,5 +11,6 @@ type Store interface {
 	DB() *gorm.DB
 	MemoryTable() *gorm.DB
 	CharacterTable() *gorm.DB
+	SeenMessageTable() *gorm.DB
 	Close() error
 }
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
 )
 
 type Client struct {
@@ -15,8 +17,13 @@ type Client struct {
 }
 
 type CompletionRequest struct {
-	Model    string    `json:"model"`
-	Messages []Message `json:"messages"`
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+type ResponseFormat struct {
+	Type string `json:"type"`
 }
 
 type Message struct {
@@ -28,10 +35,27 @@ type CompletionResponse struct {
 	Choices []struct {
 		Message struct {
 			Content string `json:"content"`
+			// ReasoningContent is returned by reasoning models such as deepseek-reasoner
+			ReasoningContent string `json:"reasoning_content"`
 		} `json:"message"`
 	} `json:"choices"`
 }
 
+type ModelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// StatusError is returned when the API answers with a non-200 status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
 func NewClient(apiKey string, baseURL string) *Client {
 	return &Client{
 		apiKey:  apiKey,
@@ -40,40 +64,94 @@ func NewClient(apiKey string, baseURL string) *Client {
 	}
 }
 
+// setTraceHeader forwards the trace ID of ctx as a request header
+func setTraceHeader(ctx context.Context, req *http.Request) {
+	if traceID := logger.TraceID(ctx); traceID != "" {
+		req.Header.Set(logger.TraceIDHeader, traceID)
+	}
+}
+
 func (c *Client) CreateCompletion(ctx context.Context, req CompletionRequest) (string, error) {
+	content, _, err := c.CreateCompletionWithReasoning(ctx, req)
+	return content, err
+}
+
+// CreateCompletionWithReasoning returns the completion content together with the
+// reasoning_content field that reasoning models return alongside it, if any
+func (c *Client) CreateCompletionWithReasoning(ctx context.Context, req CompletionRequest) (string, string, error) {
 	url := fmt.Sprintf("%s/v1/chat/completions", c.baseURL)
 
 	body, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
+		return "", "", fmt.Errorf("marshaling request: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", "", fmt.Errorf("creating request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	setTraceHeader(ctx, httpReq)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", "", fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", "", &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	var completionResp CompletionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&completionResp); err != nil {
-		return "", fmt.Errorf("decoding response: %w", err)
+		return "", "", fmt.Errorf("decoding response: %w", err)
 	}
 
 	if len(completionResp.Choices) == 0 {
-		return "", fmt.Errorf("no completion choices returned")
+		return "", "", fmt.Errorf("no completion choices returned")
+	}
+
+	message := completionResp.Choices[0].Message
+	return message.Content, message.ReasoningContent, nil
+}
+
+// Ping verifies the API key by listing models and checks the model is available
+func (c *Client) Ping(ctx context.Context, model string) error {
+	url := fmt.Sprintf("%s/v1/models", c.baseURL)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
 	}
 
-	return completionResp.Choices[0].Message.Content, nil
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	setTraceHeader(ctx, httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	var modelsResp ModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	if model == "" {
+		return nil
+	}
+	for _, m := range modelsResp.Data {
+		if m.ID == model {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s not available", model)
 }
@@ -0,0 +1,104 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		model   string
+		wantErr bool
+	}{
+		{name: "model available", status: http.StatusOK, body: `{"data":[{"id":"deepseek-chat"}]}`, model: "deepseek-chat"},
+		{name: "any model", status: http.StatusOK, body: `{"data":[]}`},
+		{name: "model missing", status: http.StatusOK, body: `{"data":[{"id":"deepseek-chat"}]}`, model: "deepseek-reasoner", wantErr: true},
+		{name: "bad key", status: http.StatusUnauthorized, body: `{}`, model: "deepseek-chat", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/models" || r.Header.Get("Authorization") != "Bearer test-key" {
+					t.Errorf("request = %s %s, want an authorized models request", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewClient("test-key", server.URL).Ping(context.Background(), tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateCompletionWithReasoning(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantContent   string
+		wantReasoning string
+	}{
+		{
+			name:          "reasoning model",
+			body:          `{"choices":[{"message":{"content":"Go with A.","reasoning_content":"compare A and B"}}]}`,
+			wantContent:   "Go with A.",
+			wantReasoning: "compare A and B",
+		},
+		{
+			name:        "chat model",
+			body:        `{"choices":[{"message":{"content":"<think>compare</think>Go with A."}}]}`,
+			wantContent: "<think>compare</think>Go with A.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/chat/completions" {
+					t.Errorf("request path = %s, want the chat completions endpoint", r.URL.Path)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			content, reasoning, err := NewClient("test-key", server.URL).CreateCompletionWithReasoning(
+				context.Background(),
+				CompletionRequest{Model: "deepseek-reasoner", Messages: []Message{{Role: "user", Content: "A or B?"}}},
+			)
+			if err != nil {
+				t.Fatalf("CreateCompletionWithReasoning() error = %v", err)
+			}
+			if content != tt.wantContent || reasoning != tt.wantReasoning {
+				t.Errorf("CreateCompletionWithReasoning() = %q, %q, want %q, %q", content, reasoning, tt.wantContent, tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestCreateCompletionForwardsTraceID(t *testing.T) {
+	var traceID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID = r.Header.Get(logger.TraceIDHeader)
+		w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	ctx := logger.WithTraceID(context.Background(), "trace-1")
+	if _, err := NewClient("test-key", server.URL).CreateCompletion(ctx, CompletionRequest{Model: "deepseek-chat"}); err != nil {
+		t.Fatalf("CreateCompletion() error = %v", err)
+	}
+	if traceID != "trace-1" {
+		t.Errorf("%s header = %q, want trace-1", logger.TraceIDHeader, traceID)
+	}
+}
@@ -2,11 +2,15 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm/deepseek"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm/openai"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 )
 
 type State struct {
@@ -18,50 +22,215 @@ type Message struct {
 	Content string
 }
 
+// ResponseFormatJSONObject asks the provider to return a single JSON object
+const ResponseFormatJSONObject = "json_object"
+
 type CompletionRequest struct {
 	Model    string
 	Messages []Message
+	// ResponseFormat optionally constrains the output, e.g. ResponseFormatJSONObject
+	ResponseFormat string
+}
+
+// Completion is a completion result including the reasoning of reasoning models
+type Completion struct {
+	Content string
+	// ReasoningContent is the separate reasoning field, empty when the model does not return one
+	ReasoningContent string
 }
 
+var (
+	// ErrEmbeddingsNotConfigured is returned when no embedding model is configured
+	ErrEmbeddingsNotConfigured = errors.New("embedding model not configured")
+	// ErrEmbeddingsUnsupported is returned when the provider has no embeddings endpoint
+	ErrEmbeddingsUnsupported = errors.New("embeddings unsupported by provider")
+	// ErrModerationUnsupported is returned when the provider has no moderation endpoint
+	ErrModerationUnsupported = errors.New("moderation not supported by provider")
+)
+
 type Client interface {
 	CreateCompletion(ctx context.Context, request CompletionRequest) (string, error)
+	CreateCompletionWithReasoning(ctx context.Context, request CompletionRequest) (*Completion, error)
+	CreateEmbeddings(ctx context.Context, inputs []string) ([][]float32, error)
+	// Moderate reports whether the provider's moderation endpoint flags the input
+	Moderate(ctx context.Context, input string) (bool, error)
+	// Ping verifies the provider is reachable and the credentials and model are valid
+	Ping(ctx context.Context) error
 }
 
+// defaultRequestTimeout bounds requests when no timeout is configured
+const defaultRequestTimeout = 60 * time.Second
+
 type clientImpl struct {
 	provider       string
 	model          string
+	embeddingModel string
+	requestTimeout time.Duration
+	retry          retry.Policy
 	openaiClient   *openai.Client
 	deepseekClient *deepseek.Client
 }
 
 func (c *clientImpl) CreateCompletion(ctx context.Context, request CompletionRequest) (string, error) {
+	completion, err := c.CreateCompletionWithReasoning(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return completion.Content, nil
+}
+
+func (c *clientImpl) CreateCompletionWithReasoning(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	var completion *Completion
+	err := c.retry.Do(ctx, func(int) error {
+		var err error
+		completion, err = c.createCompletion(ctx, request)
+		return retryable(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+// createCompletion makes a single completion request
+func (c *clientImpl) createCompletion(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	var (
+		content, reasoning string
+		err                error
+	)
+
 	switch c.provider {
 	case "openai":
-		return c.openaiClient.CreateCompletion(ctx, openai.CompletionRequest{
-			Model:    request.Model,
-			Messages: toOpenAIMessage(request.Messages),
+		content, reasoning, err = c.openaiClient.CreateCompletionWithReasoning(ctx, openai.CompletionRequest{
+			Model:          request.Model,
+			Messages:       toOpenAIMessage(request.Messages),
+			ResponseFormat: request.ResponseFormat,
 		})
 	case "deepseek":
-		return c.deepseekClient.CreateCompletion(ctx, deepseek.CompletionRequest{
-			Model:    request.Model,
-			Messages: toDeepseekMessage(request.Messages),
+		content, reasoning, err = c.deepseekClient.CreateCompletionWithReasoning(ctx, deepseek.CompletionRequest{
+			Model:          request.Model,
+			Messages:       toDeepseekMessage(request.Messages),
+			ResponseFormat: toDeepseekResponseFormat(request.ResponseFormat),
 		})
 	default:
-		return "", fmt.Errorf("unsupported provider: %s", c.provider)
+		return nil, fmt.Errorf("unsupported provider: %s", c.provider)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Completion{
+		Content:          content,
+		ReasoningContent: reasoning,
+	}, nil
+}
+
+func (c *clientImpl) CreateEmbeddings(ctx context.Context, inputs []string) ([][]float32, error) {
+	if c.embeddingModel == "" {
+		return nil, ErrEmbeddingsNotConfigured
+	}
+
+	var embeddings [][]float32
+	err := c.retry.Do(ctx, func(int) error {
+		var err error
+		embeddings, err = c.createEmbeddings(ctx, inputs)
+		return retryable(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
 }
 
-func NewClient(conf *conf.LLMConfig) Client {
+// createEmbeddings makes a single embeddings request
+func (c *clientImpl) createEmbeddings(ctx context.Context, inputs []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	switch c.provider {
+	case "openai":
+		return c.openaiClient.CreateEmbeddings(ctx, openai.EmbeddingRequest{
+			Model: c.embeddingModel,
+			Input: inputs,
+		})
+	case "deepseek":
+		// DeepSeek has no embeddings API, so callers fall back to keyword search
+		return nil, fmt.Errorf("%w: %s", ErrEmbeddingsUnsupported, c.provider)
+	default:
+		return nil, fmt.Errorf("unsupported provider: %s", c.provider)
+	}
+}
+
+// retryable leaves err to be retried when the provider was rate limited or
+// failed on its side, any other failure is permanent
+func retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	if status, ok := statusCode(err); ok && (status == http.StatusTooManyRequests || status >= http.StatusInternalServerError) {
+		return err
+	}
+	return retry.Permanent(err)
+}
+
+// statusCode returns the HTTP status a provider answered a failed request with
+func statusCode(err error) (int, bool) {
+	var statusErr *deepseek.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode, true
+	}
+	return openai.StatusCode(err)
+}
+
+func (c *clientImpl) Moderate(ctx context.Context, input string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	switch c.provider {
+	case "openai":
+		return c.openaiClient.Moderate(ctx, input)
+	case "deepseek":
+		return false, ErrModerationUnsupported
+	default:
+		return false, fmt.Errorf("unsupported provider: %s", c.provider)
+	}
+}
+
+func (c *clientImpl) Ping(ctx context.Context) error {
+	switch c.provider {
+	case "openai":
+		return c.openaiClient.Ping(ctx, c.model)
+	case "deepseek":
+		return c.deepseekClient.Ping(ctx, c.model)
+	default:
+		return fmt.Errorf("unsupported provider: %s", c.provider)
+	}
+}
+
+func NewClient(config *conf.LLMConfig) Client {
+	if config.Provider == conf.LLMProviderScripted {
+		return NewScriptedClient(config.Script)
+	}
+
 	client := &clientImpl{
-		provider: conf.Provider,
-		model:    conf.Model,
+		provider:       config.Provider,
+		model:          config.Model,
+		embeddingModel: config.EmbeddingModel,
+		requestTimeout: defaultRequestTimeout,
+		retry:          config.Retry.WithDefaults(),
+	}
+	if config.RequestTimeout > 0 {
+		client.requestTimeout = time.Duration(config.RequestTimeout) * time.Second
 	}
 
-	switch conf.Provider {
+	switch config.Provider {
 	case "openai":
-		client.openaiClient = openai.NewClient(conf.APIKey)
+		client.openaiClient = openai.NewClient(config.APIKey)
 	case "deepseek":
-		client.deepseekClient = deepseek.NewClient(conf.APIKey, conf.BaseURL)
+		client.deepseekClient = deepseek.NewClient(config.APIKey, config.BaseURL)
 	}
 
 	return client
@@ -88,3 +257,10 @@ func toDeepseekMessage(messages []Message) []deepseek.Message {
 	}
 	return deepseekMessages
 }
+
+func toDeepseekResponseFormat(format string) *deepseek.ResponseFormat {
+	if format == "" {
+		return nil
+	}
+	return &deepseek.ResponseFormat{Type: format}
+}
@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm/deepseek"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm/openai"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
+
+	"github.com/openai/openai-go/option"
+)
+
+func TestCreateEmbeddingsRequiresModel(t *testing.T) {
+	client := NewClient(&conf.LLMConfig{Provider: "openai", APIKey: "test-key", Model: "gpt-4o"})
+
+	if _, err := client.CreateEmbeddings(context.Background(), []string{"gm"}); !errors.Is(err, ErrEmbeddingsNotConfigured) {
+		t.Errorf("CreateEmbeddings() error = %v, want %v", err, ErrEmbeddingsNotConfigured)
+	}
+}
+
+func TestCreateEmbeddingsUnsupportedByDeepseek(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient(&conf.LLMConfig{Provider: "deepseek", BaseURL: server.URL, Model: "deepseek-chat", EmbeddingModel: "deepseek-embed"})
+
+	if _, err := client.CreateEmbeddings(context.Background(), []string{"gm"}); !errors.Is(err, ErrEmbeddingsUnsupported) {
+		t.Errorf("CreateEmbeddings() error = %v, want %v", err, ErrEmbeddingsUnsupported)
+	}
+	if requests != 0 {
+		t.Errorf("sent %d requests, want none", requests)
+	}
+}
+
+func TestNewClientRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds int
+		want    time.Duration
+	}{
+		{name: "configured", seconds: 5, want: 5 * time.Second},
+		{name: "unset", want: defaultRequestTimeout},
+		{name: "negative", seconds: -1, want: defaultRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&conf.LLMConfig{Provider: "deepseek", RequestTimeout: tt.seconds}).(*clientImpl)
+			if client.requestTimeout != tt.want {
+				t.Errorf("requestTimeout = %v, want %v", client.requestTimeout, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCompletionCancelledAtDeadline(t *testing.T) {
+	// The slow LLM never answers, it waits for the request to be cancelled
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the server only notices the client going away once the body is read
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+			close(cancelled)
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(&conf.LLMConfig{Provider: "deepseek", BaseURL: server.URL, Model: "deepseek-chat"}).(*clientImpl)
+	client.requestTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := client.CreateCompletion(context.Background(), CompletionRequest{
+		Model:    "deepseek-chat",
+		Messages: []Message{{Role: "user", Content: "gm"}},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("CreateCompletion() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("CreateCompletion() returned after %v, want it cancelled at the deadline", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Error("the LLM request was not cancelled")
+	}
+}
+
+// fastRetry retries three times without waiting
+var fastRetry = retry.Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func TestCreateCompletionRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantStatus   int
+	}{
+		{name: "rate limited", statuses: []int{http.StatusTooManyRequests}, wantRequests: 2},
+		{name: "server error", statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable}, wantRequests: 3},
+		{name: "attempts run out", statuses: []int{500, 500, 500}, wantRequests: 3, wantStatus: 500},
+		{name: "bad request", statuses: []int{http.StatusBadRequest}, wantRequests: 1, wantStatus: http.StatusBadRequest},
+		{name: "unauthorized", statuses: []int{http.StatusUnauthorized}, wantRequests: 1, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= len(tt.statuses) {
+					w.WriteHeader(tt.statuses[requests-1])
+					return
+				}
+				w.Write([]byte(`{"choices":[{"message":{"content":"gm"}}]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(&conf.LLMConfig{Provider: "deepseek", BaseURL: server.URL, Model: "deepseek-chat", Retry: fastRetry})
+
+			content, err := client.CreateCompletion(context.Background(), CompletionRequest{
+				Model:    "deepseek-chat",
+				Messages: []Message{{Role: "user", Content: "gm"}},
+			})
+			if requests != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", requests, tt.wantRequests)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil || content != "gm" {
+					t.Errorf("CreateCompletion() = %q, %v, want gm", content, err)
+				}
+				return
+			}
+			var statusErr *deepseek.StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus {
+				t.Errorf("CreateCompletion() error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCreateEmbeddingsRetries(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"message":"slow down"}}`))
+			return
+		}
+		w.Write([]byte(`{"object":"list","data":[{"object":"embedding","index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	client := &clientImpl{
+		provider:       "openai",
+		embeddingModel: "text-embedding-3-small",
+		requestTimeout: defaultRequestTimeout,
+		retry:          fastRetry,
+		openaiClient:   openai.NewClient("test-key", option.WithBaseURL(server.URL)),
+	}
+
+	embeddings, err := client.CreateEmbeddings(context.Background(), []string{"gm"})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings() error = %v", err)
+	}
+	if requests != 2 || len(embeddings) != 1 {
+		t.Errorf("sent %d requests for %v, want the rate limited request retried once", requests, embeddings)
+	}
+}
+
+func TestNewClientRetryPolicy(t *testing.T) {
+	configured := retry.Policy{Attempts: 5, BaseDelay: time.Second, MaxDelay: time.Minute}
+	if got := NewClient(&conf.LLMConfig{Provider: "deepseek", Retry: configured}).(*clientImpl).retry; got != configured {
+		t.Errorf("retry = %+v, want the configured %+v", got, configured)
+	}
+	if got := NewClient(&conf.LLMConfig{Provider: "deepseek"}).(*clientImpl).retry; got != (retry.Policy{}).WithDefaults() {
+		t.Errorf("retry = %+v, want the defaults", got)
+	}
+}
@@ -2,10 +2,15 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/shared"
 )
 
 type Client struct {
@@ -17,6 +22,8 @@ type CompletionRequest struct {
 	Messages    []Message `json:"messages"`
 	Temperature float64   `json:"temperature,omitempty"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
+	// ResponseFormat is "json_object" to request JSON output, empty for plain text
+	ResponseFormat string `json:"response_format,omitempty"`
 }
 
 type Message struct {
@@ -33,8 +40,8 @@ type CompletionResponse struct {
 }
 
 type EmbeddingRequest struct {
-	Model string `json:"model"`
-	Input string `json:"input"`
+	Model string   `json:"model"`
+	Input []string `json:"input"`
 }
 
 type EmbeddingResponse struct {
@@ -43,30 +50,102 @@ type EmbeddingResponse struct {
 	} `json:"data"`
 }
 
-func NewClient(apiKey string) *Client {
-	client := openai.NewClient(
+// NewClient creates a client, opts override the defaults, e.g. the base URL
+func NewClient(apiKey string, opts ...option.RequestOption) *Client {
+	client := openai.NewClient(append([]option.RequestOption{
 		option.WithAPIKey(apiKey), // defaults to os.LookupEnv("OPENAI_API_KEY")
-	)
+		// Retries follow the policy of the llm client instead
+		option.WithMaxRetries(0),
+	}, opts...)...)
 	return &Client{
 		client: client,
 	}
 }
 
 func (c *Client) CreateCompletion(ctx context.Context, req CompletionRequest) (string, error) {
+	content, _, err := c.CreateCompletionWithReasoning(ctx, req)
+	return content, err
+}
+
+// CreateCompletionWithReasoning returns the completion content together with the
+// reasoning_content field that reasoning models return alongside it, if any
+func (c *Client) CreateCompletionWithReasoning(ctx context.Context, req CompletionRequest) (string, string, error) {
 	// TODO: Add more open ai api's ability to create completions
-	chatCompletion, err := c.client.Chat.Completions.New(
-		context.Background(),
-		openai.ChatCompletionNewParams{
-			Messages: openai.F(c.toOpenAIMessage(req.Messages)),
-			Model:    openai.F(openai.ChatModelGPT4o),
-		},
-	)
+	params := openai.ChatCompletionNewParams{
+		Messages: openai.F(c.toOpenAIMessage(req.Messages)),
+		Model:    openai.F(openai.ChatModelGPT4o),
+	}
+	if req.ResponseFormat == string(openai.ResponseFormatJSONObjectTypeJSONObject) {
+		params.ResponseFormat = openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](openai.ResponseFormatJSONObjectParam{
+			Type: openai.F(openai.ResponseFormatJSONObjectTypeJSONObject),
+		})
+	}
+
+	chatCompletion, err := c.client.Chat.Completions.New(ctx, params, traceOptions(ctx)...)
 
 	if err != nil {
-		return "", fmt.Errorf("creating completion: %w", err)
+		return "", "", fmt.Errorf("creating completion: %w", err)
 	}
 
-	return chatCompletion.Choices[0].Message.Content, nil
+	message := chatCompletion.Choices[0].Message
+	return message.Content, reasoningContent(message), nil
+}
+
+// reasoningContent reads the non-standard reasoning_content field from the message
+func reasoningContent(message openai.ChatCompletionMessage) string {
+	field, ok := message.JSON.ExtraFields["reasoning_content"]
+	if !ok {
+		return ""
+	}
+
+	var reasoning string
+	if err := json.Unmarshal([]byte(field.Raw()), &reasoning); err != nil {
+		return ""
+	}
+	return reasoning
+}
+
+// Ping verifies the API key by fetching the model
+func (c *Client) Ping(ctx context.Context, model string) error {
+	if _, err := c.client.Models.Get(ctx, model); err != nil {
+		return fmt.Errorf("fetching model %s: %w", model, err)
+	}
+	return nil
+}
+
+// Moderate reports whether the moderation endpoint flags the input
+func (c *Client) Moderate(ctx context.Context, input string) (bool, error) {
+	resp, err := c.client.Moderations.New(ctx, openai.ModerationNewParams{
+		Input: openai.F[openai.ModerationNewParamsInputUnion](shared.UnionString(input)),
+		Model: openai.F(openai.ModerationModelOmniModerationLatest),
+	}, traceOptions(ctx)...)
+	if err != nil {
+		return false, fmt.Errorf("creating moderation: %w", err)
+	}
+
+	for _, result := range resp.Results {
+		if result.Flagged {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// StatusCode returns the HTTP status of an error the API answered with
+func StatusCode(err error) (int, bool) {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode, true
+	}
+	return 0, false
+}
+
+// traceOptions forwards the trace ID of ctx as a request header
+func traceOptions(ctx context.Context) []option.RequestOption {
+	if traceID := logger.TraceID(ctx); traceID != "" {
+		return []option.RequestOption{option.WithHeader(logger.TraceIDHeader, traceID)}
+	}
+	return nil
 }
 
 func (c *Client) toOpenAIMessage(messages []Message) []openai.ChatCompletionMessageParamUnion {
@@ -81,3 +160,31 @@ func (c *Client) toOpenAIMessage(messages []Message) []openai.ChatCompletionMess
 	}
 	return openAIMessages
 }
+
+func (c *Client) CreateEmbeddings(ctx context.Context, req EmbeddingRequest) ([][]float32, error) {
+	resp, err := c.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input:          openai.F[openai.EmbeddingNewParamsInputUnion](openai.EmbeddingNewParamsInputArrayOfStrings(req.Input)),
+		Model:          openai.F(req.Model),
+		EncodingFormat: openai.F(openai.EmbeddingNewParamsEncodingFormatFloat),
+	}, traceOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("creating embeddings: %w", err)
+	}
+
+	embeddings := make([][]float32, len(req.Input))
+	for _, data := range resp.Data {
+		if data.Index < 0 || int(data.Index) >= len(embeddings) {
+			continue
+		}
+		embeddings[data.Index] = toFloat32(data.Embedding)
+	}
+	return embeddings, nil
+}
+
+func toFloat32(values []float64) []float32 {
+	result := make([]float32, len(values))
+	for i, v := range values {
+		result[i] = float32(v)
+	}
+	return result
+}
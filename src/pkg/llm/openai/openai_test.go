@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// roundTripFunc lets a function act as the HTTP transport
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestCreateEmbeddings(t *testing.T) {
+	var got EmbeddingRequest
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(req.URL.Path, "/embeddings") {
+			t.Errorf("request path = %s, want the embeddings endpoint", req.URL.Path)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+			t.Fatalf("request body is not JSON: %v", err)
+		}
+		// Answer out of order to check the index is respected
+		body := `{"object":"list","model":"text-embedding-3-small","data":[
+			{"object":"embedding","index":1,"embedding":[0,1]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		]}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+
+	client := &Client{client: openai.NewClient(
+		option.WithAPIKey("test-key"),
+		option.WithHTTPClient(&http.Client{Transport: transport}),
+	)}
+
+	embeddings, err := client.CreateEmbeddings(context.Background(), EmbeddingRequest{
+		Model: "text-embedding-3-small",
+		Input: []string{"gm", "gn"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEmbeddings() error = %v", err)
+	}
+
+	if got.Model != "text-embedding-3-small" || len(got.Input) != 2 {
+		t.Errorf("request = %+v, want the model and both inputs", got)
+	}
+	want := [][]float32{{1, 0}, {0, 1}}
+	for i := range want {
+		if len(embeddings[i]) != 2 || embeddings[i][0] != want[i][0] || embeddings[i][1] != want[i][1] {
+			t.Errorf("embeddings[%d] = %v, want %v", i, embeddings[i], want[i])
+		}
+	}
+}
+
+func TestCreateCompletionWithReasoning(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		wantReasoning string
+	}{
+		{name: "reasoning field", message: `{"role":"assistant","content":"Go with A.","reasoning_content":"compare A and B"}`, wantReasoning: "compare A and B"},
+		{name: "no reasoning field", message: `{"role":"assistant","content":"Go with A."}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{"id":"1","object":"chat.completion","created":0,"model":"test-model","choices":[
+					{"index":0,"finish_reason":"stop","logprobs":null,"message":` + tt.message + `}
+				]}`
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Request:    req,
+				}, nil
+			})
+			client := &Client{client: openai.NewClient(
+				option.WithAPIKey("test-key"),
+				option.WithHTTPClient(&http.Client{Transport: transport}),
+			)}
+
+			content, reasoning, err := client.CreateCompletionWithReasoning(context.Background(), CompletionRequest{
+				Model:    "test-model",
+				Messages: []Message{{Role: "user", Content: "A or B?"}},
+			})
+			if err != nil {
+				t.Fatalf("CreateCompletionWithReasoning() error = %v", err)
+			}
+			if content != "Go with A." || reasoning != tt.wantReasoning {
+				t.Errorf("CreateCompletionWithReasoning() = %q, %q, want %q, %q", content, reasoning, "Go with A.", tt.wantReasoning)
+			}
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"error":{"message":"slow down"}}`)),
+			Request:    req,
+		}, nil
+	})
+
+	client := &Client{client: openai.NewClient(
+		option.WithAPIKey("test-key"),
+		option.WithHTTPClient(&http.Client{Transport: transport}),
+		option.WithMaxRetries(0),
+	)}
+
+	_, _, err := client.CreateCompletionWithReasoning(context.Background(), CompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []Message{{Role: "user", Content: "gm"}},
+	})
+	if status, ok := StatusCode(err); !ok || status != http.StatusTooManyRequests {
+		t.Errorf("StatusCode(%v) = %d, %v, want %d", err, status, ok, http.StatusTooManyRequests)
+	}
+	if _, ok := StatusCode(errors.New("connection reset")); ok {
+		t.Error("StatusCode() found a status in an error without one")
+	}
+}
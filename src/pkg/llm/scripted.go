@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+// ScriptedClient answers prompts with canned responses instead of calling an
+// LLM, for local demos and deterministic runs of the full agent
+type ScriptedClient struct {
+	// patterns are the lowercased prompt substrings, longest first
+	patterns        []string
+	responses       map[string]string
+	defaultResponse string
+}
+
+// NewScriptedClient creates a client answering from the script
+func NewScriptedClient(script conf.ScriptConfig) *ScriptedClient {
+	c := &ScriptedClient{
+		responses:       make(map[string]string, len(script.Responses)),
+		defaultResponse: script.DefaultResponse,
+	}
+	for pattern, response := range script.Responses {
+		pattern = strings.ToLower(pattern)
+		c.patterns = append(c.patterns, pattern)
+		c.responses[pattern] = response
+	}
+	sort.Slice(c.patterns, func(i, j int) bool {
+		if len(c.patterns[i]) != len(c.patterns[j]) {
+			return len(c.patterns[i]) > len(c.patterns[j])
+		}
+		return c.patterns[i] < c.patterns[j]
+	})
+	return c
+}
+
+// Respond returns the response of the longest pattern the prompt contains, or
+// the default response
+func (c *ScriptedClient) Respond(prompt string) string {
+	prompt = strings.ToLower(prompt)
+	for _, pattern := range c.patterns {
+		if strings.Contains(prompt, pattern) {
+			return c.responses[pattern]
+		}
+	}
+	return c.defaultResponse
+}
+
+func (c *ScriptedClient) CreateCompletion(ctx context.Context, request CompletionRequest) (string, error) {
+	completion, err := c.CreateCompletionWithReasoning(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return completion.Content, nil
+}
+
+// CreateCompletionWithReasoning matches the script against all the messages
+// of the request
+func (c *ScriptedClient) CreateCompletionWithReasoning(ctx context.Context, request CompletionRequest) (*Completion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	contents := make([]string, 0, len(request.Messages))
+	for _, message := range request.Messages {
+		contents = append(contents, message.Content)
+	}
+	return &Completion{Content: c.Respond(strings.Join(contents, "\n"))}, nil
+}
+
+func (c *ScriptedClient) CreateEmbeddings(ctx context.Context, inputs []string) ([][]float32, error) {
+	return nil, ErrEmbeddingsNotConfigured
+}
+
+func (c *ScriptedClient) Moderate(ctx context.Context, input string) (bool, error) {
+	return false, ErrModerationUnsupported
+}
+
+// Ping always succeeds, the script needs no connection
+func (c *ScriptedClient) Ping(ctx context.Context) error {
+	return nil
+}
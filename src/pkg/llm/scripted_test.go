@@ -0,0 +1,48 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+func TestScriptedClient(t *testing.T) {
+	client := NewClient(&conf.LLMConfig{
+		Provider: conf.LLMProviderScripted,
+		Script: conf.ScriptConfig{
+			Responses: map[string]string{
+				"balance":                 `{"should_reply": true, "response_msg": "Checking."}`,
+				"balance of the treasury": `{"should_reply": true, "response_msg": "The treasury holds 10 ETH."}`,
+			},
+			DefaultResponse: `{"should_reply": false}`,
+		},
+	})
+
+	tests := []struct {
+		name     string
+		messages []Message
+		want     string
+	}{
+		{name: "matching prompt", messages: []Message{{Role: "user", Content: "What is my BALANCE?"}}, want: `{"should_reply": true, "response_msg": "Checking."}`},
+		{name: "longest match wins", messages: []Message{{Role: "user", Content: "What is the balance of the treasury?"}}, want: `{"should_reply": true, "response_msg": "The treasury holds 10 ETH."}`},
+		{name: "match in system prompt", messages: []Message{{Role: "system", Content: "Report the balance."}, {Role: "user", Content: "gm"}}, want: `{"should_reply": true, "response_msg": "Checking."}`},
+		{name: "unmatched prompt", messages: []Message{{Role: "user", Content: "gm"}}, want: `{"should_reply": false}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.CreateCompletion(context.Background(), CompletionRequest{Model: "scripted", Messages: tt.messages})
+			if err != nil {
+				t.Fatalf("CreateCompletion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CreateCompletion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v, want the script to need no connection", err)
+	}
+}
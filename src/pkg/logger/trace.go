@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TraceIDHeader carries the trace ID on outgoing LLM and data API requests
+const TraceIDHeader = "X-Trace-Id"
+
+// TraceIDField is the log field holding the trace ID
+const TraceIDField = "trace_id"
+
+type traceIDKey struct{}
+
+// NewTraceID generates a trace ID for an inbound request
+func NewTraceID() string {
+	return uuid.NewString()
+}
+
+// WithTraceID returns a copy of ctx carrying the trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns the trace ID carried by ctx, empty when there is none
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// FromContext returns the logger with the trace ID of ctx attached, if any
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if traceID := TraceID(ctx); traceID != "" {
+		return GetLogger().With(TraceIDField, traceID)
+	}
+	return GetLogger()
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTraceID(t *testing.T) {
+	ctx := WithTraceID(context.Background(), "trace-1")
+	if got := TraceID(ctx); got != "trace-1" {
+		t.Errorf("TraceID() = %q, want trace-1", got)
+	}
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() without a trace = %q, want empty", got)
+	}
+	if got := TraceID(nil); got != "" {
+		t.Errorf("TraceID(nil) = %q, want empty", got)
+	}
+}
+
+func TestNewTraceIDIsUnique(t *testing.T) {
+	if first, second := NewTraceID(), NewTraceID(); first == "" || first == second {
+		t.Errorf("NewTraceID() = %q, %q, want two distinct IDs", first, second)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) == nil {
+		t.Error("FromContext() without a trace = nil, want the default logger")
+	}
+	if FromContext(WithTraceID(context.Background(), "trace-1")) == nil {
+		t.Error("FromContext() = nil, want a logger")
+	}
+}
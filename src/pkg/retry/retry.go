@@ -0,0 +1,102 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Default policy values, used for any field left unset
+const (
+	DefaultAttempts  = 3
+	DefaultBaseDelay = 1 * time.Second
+	DefaultMaxDelay  = 10 * time.Second
+)
+
+// Policy bounds how often a failed call is attempted and how long to wait
+// between attempts. The delay doubles after every attempt, starting at
+// BaseDelay and capped at MaxDelay.
+type Policy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// WithDefaults fills any unset field with its default
+func (p Policy) WithDefaults() Policy {
+	if p.Attempts <= 0 {
+		p.Attempts = DefaultAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultBaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultMaxDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// Delay returns the wait before the given retry, 1 being the first retry
+func (p Policy) Delay(retry int) time.Duration {
+	p = p.WithDefaults()
+
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// permanentError marks an error retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it without retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls fn until it succeeds or the attempts run out, returning the last
+// error. It stops early with the context error once ctx is done, and with the
+// unwrapped error when fn returns a Permanent one.
+func (p Policy) Do(ctx context.Context, fn func(attempt int) error) error {
+	p = p.WithDefaults()
+
+	var err error
+	for attempt := 1; attempt <= p.Attempts; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if attempt > 1 {
+			timer := time.NewTimer(p.Delay(attempt - 1))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient failure")
+
+func TestPolicyWithDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		want   Policy
+	}{
+		{name: "unset", want: Policy{Attempts: DefaultAttempts, BaseDelay: DefaultBaseDelay, MaxDelay: DefaultMaxDelay}},
+		{
+			name:   "configured",
+			policy: Policy{Attempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Second},
+			want:   Policy{Attempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Second},
+		},
+		{
+			name:   "max delay below base delay",
+			policy: Policy{Attempts: 2, BaseDelay: time.Minute, MaxDelay: time.Second},
+			want:   Policy{Attempts: 2, BaseDelay: time.Minute, MaxDelay: time.Minute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.WithDefaults(); got != tt.want {
+				t.Errorf("WithDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{retry: 1, want: 100 * time.Millisecond},
+		{retry: 2, want: 200 * time.Millisecond},
+		{retry: 3, want: 400 * time.Millisecond},
+		{retry: 4, want: 500 * time.Millisecond},
+		{retry: 50, want: 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Delay(tt.retry); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.retry, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyDo(t *testing.T) {
+	policy := Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := []struct {
+		name         string
+		failures     int
+		wantAttempts int
+		wantErr      error
+	}{
+		{name: "first attempt succeeds", wantAttempts: 1},
+		{name: "succeeds after retries", failures: 2, wantAttempts: 3},
+		{name: "attempts run out", failures: 5, wantAttempts: 3, wantErr: errTransient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts []int
+			err := policy.Do(context.Background(), func(attempt int) error {
+				attempts = append(attempts, attempt)
+				if len(attempts) <= tt.failures {
+					return errTransient
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(attempts) != tt.wantAttempts {
+				t.Fatalf("fn called %d times, want %d", len(attempts), tt.wantAttempts)
+			}
+			for i, attempt := range attempts {
+				if attempt != i+1 {
+					t.Errorf("attempts = %v, want them numbered from 1", attempts)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyDoStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{Attempts: 5, BaseDelay: time.Hour}
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- policy.Do(ctx, func(int) error {
+			calls++
+			return errTransient
+		})
+	}()
+
+	// Do is waiting an hour before the first retry
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Do() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Do() kept waiting after the context was cancelled")
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestPolicyDoWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := Policy{}.Do(ctx, func(int) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || called {
+		t.Errorf("Do() error = %v, called %v, want context.Canceled without a call", err, called)
+	}
+}
+
+func TestPolicyDoStopsOnPermanentError(t *testing.T) {
+	policy := Policy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	calls := 0
+	err := policy.Do(context.Background(), func(int) error {
+		calls++
+		return Permanent(errTransient)
+	})
+	if err != errTransient {
+		t.Errorf("Do() error = %v, want the unwrapped %v", err, errTransient)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
+
+func TestPermanentNil(t *testing.T) {
+	if err := Permanent(nil); err != nil {
+		t.Errorf("Permanent(nil) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,56 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkSendAppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results", "data.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	contents := []string{"first result", "second result"}
+	for _, content := range contents {
+		if err := sink.Send(context.Background(), content, map[string]interface{}{"action": "get_transaction"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q is not a record: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != len(contents) {
+		t.Fatalf("file has %d records, want %d", len(records), len(contents))
+	}
+	for i, record := range records {
+		if record.Content != contents[i] || record.Metadata["action"] != "get_transaction" {
+			t.Errorf("record %d = %+v, want %q from get_transaction", i, record, contents[i])
+		}
+	}
+}
+
+func TestNewFileSinkRequiresPath(t *testing.T) {
+	if _, err := NewFileSink(""); err == nil {
+		t.Error("NewFileSink() error = nil, want an error for an empty path")
+	}
+}
@@ -0,0 +1,18 @@
+package sinks
+
+import "time"
+
+// Record is what a sink delivers for each result
+type Record struct {
+	Content  string                 `json:"content"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	SentAt   time.Time              `json:"sent_at"`
+}
+
+func newRecord(content string, metadata map[string]interface{}) Record {
+	return Record{
+		Content:  content,
+		Metadata: metadata,
+		SentAt:   time.Now().UTC(),
+	}
+}
@@ -0,0 +1,66 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// WebhookSink POSTs each result as a JSON record to a URL
+type WebhookSink struct {
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewWebhookSink creates a webhook sink; headers are added to every request,
+// e.g. for authorization, and timeout defaults to 10 seconds when zero
+func NewWebhookSink(url string, headers map[string]string, timeout time.Duration) (*WebhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookSink{
+		url:     url,
+		headers: headers,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}, nil
+}
+
+func (s *WebhookSink) Send(ctx context.Context, content string, metadata map[string]interface{}) error {
+	body, err := json.Marshal(newRecord(content, metadata))
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
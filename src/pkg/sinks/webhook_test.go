@@ -0,0 +1,62 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookSinkSend(t *testing.T) {
+	var (
+		got    Record
+		header http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("request body is not a record: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL, map[string]string{"Authorization": "Bearer token"}, 0)
+	if err != nil {
+		t.Fatalf("NewWebhookSink() error = %v", err)
+	}
+	if err := sink.Send(context.Background(), "42 transactions", map[string]interface{}{"action": "fetch_transaction"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got.Content != "42 transactions" || got.Metadata["action"] != "fetch_transaction" || got.SentAt.IsZero() {
+		t.Errorf("webhook received %+v, want the content, metadata and time", got)
+	}
+	if header.Get("Authorization") != "Bearer token" || header.Get("Content-Type") != "application/json" {
+		t.Errorf("webhook received headers %v, want the configured and JSON headers", header)
+	}
+}
+
+func TestWebhookSinkSendStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "pipeline is down", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL, nil, 0)
+	if err != nil {
+		t.Fatalf("NewWebhookSink() error = %v", err)
+	}
+	err = sink.Send(context.Background(), "42 transactions", nil)
+	if err == nil || !strings.Contains(err.Error(), "502") || !strings.Contains(err.Error(), "pipeline is down") {
+		t.Errorf("Send() error = %v, want the status and body", err)
+	}
+}
+
+func TestNewWebhookSinkRequiresURL(t *testing.T) {
+	if _, err := NewWebhookSink("", nil, 0); err == nil {
+		t.Error("NewWebhookSink() error = nil, want an error for an empty url")
+	}
+}
@@ -0,0 +1,300 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// Ensure CompareAddressesAction implements actions.IAction
+var _ actions.IAction = (*CompareAddressesAction)(nil)
+
+// SharedCounterparty is an address both compared addresses send to
+type SharedCounterparty struct {
+	Address  string
+	TxCountA int
+	TxCountB int
+}
+
+// TransferFlow is the value sent directly from one compared address to the other
+type TransferFlow struct {
+	TxCount int
+	Value   float64
+}
+
+// AddressComparison relates the activity of two addresses
+type AddressComparison struct {
+	A *AddressProfile
+	B *AddressProfile
+	// SharedCounterparties are sorted by the transactions of both addresses
+	SharedCounterparties []SharedCounterparty
+	// SharedActiveDays are the days both addresses sent transactions
+	SharedActiveDays int
+	FlowAToB         TransferFlow
+	FlowBToA         TransferFlow
+	Summary          string
+}
+
+// CompareAddressesAction compares the activity of two addresses, e.g. wallets
+// suspected to belong to the same owner
+type CompareAddressesAction struct {
+	name        string
+	description string
+	dbProvider  types.DatabaseProvider
+	llmClient   llm.Client
+	model       string
+	// concurrency limits the sub-queries running at once
+	concurrency int
+	resultOutput
+}
+
+// NewCompareAddressesAction creates a new compare addresses action; concurrency
+// limits the sub-queries running at once, DefaultQueryConcurrency when 0
+func NewCompareAddressesAction(dbProvider types.DatabaseProvider, llmClient llm.Client, model string, concurrency int) *CompareAddressesAction {
+	return &CompareAddressesAction{
+		name:        "compare_addresses",
+		description: "Compare the activity of two Ethereum addresses: shared counterparties, timing and value sent between them",
+		dbProvider:  dbProvider,
+		llmClient:   llmClient,
+		model:       model,
+		concurrency: concurrency,
+	}
+}
+
+func (a *CompareAddressesAction) Name() string {
+	return a.name
+}
+
+func (a *CompareAddressesAction) Description() string {
+	return a.description
+}
+
+func (a *CompareAddressesAction) Type() string {
+	return "compare_addresses"
+}
+
+func (a *CompareAddressesAction) ParametersPrompt() string {
+	return `
+	# Parameters:
+	- address_a: string
+	- address_b: string
+	- days: int (history to consider, default 365)
+	`
+}
+
+func (a *CompareAddressesAction) Validate(params map[string]interface{}) error {
+	addressA, okA := params["address_a"].(string)
+	addressB, okB := params["address_b"].(string)
+	if !okA || !okB {
+		return fmt.Errorf("address_a and address_b parameters are required")
+	}
+	for _, address := range []string{addressA, addressB} {
+		if err := validateAddress(address); err != nil {
+			return err
+		}
+	}
+	if strings.EqualFold(addressA, addressB) {
+		return fmt.Errorf("address_a and address_b must differ")
+	}
+	if _, err := profileDays(params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute compares the addresses and logs the comparison
+func (a *CompareAddressesAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	if err := a.Validate(params); err != nil {
+		return err
+	}
+
+	days, _ := profileDays(params)
+	comparison, err := a.Compare(ctx, params["address_a"].(string), params["address_b"].(string), days)
+	if err != nil {
+		return err
+	}
+
+	formatted := FormatAddressComparison(comparison)
+	logger.GetLogger().Infow("Addresses compared",
+		"address_a", comparison.A.Address,
+		"address_b", comparison.B.Address,
+		"comparison", formatted,
+	)
+	a.emit(ctx, a.name, formatted, params)
+	return nil
+}
+
+// Compare profiles both addresses and relates their activity, running all
+// sub-queries concurrently
+func (a *CompareAddressesAction) Compare(ctx context.Context, addressA, addressB string, days int) (*AddressComparison, error) {
+	for _, address := range []string{addressA, addressB} {
+		if err := validateAddress(address); err != nil {
+			return nil, err
+		}
+	}
+	addressA, addressB = strings.ToLower(addressA), strings.ToLower(addressB)
+
+	profileA := profileQueries(addressA, days).list()
+	profileB := profileQueries(addressB, days).list()
+	shared := comparisonQueries(addressA, addressB, days)
+
+	queries := append(append(append([]SubQuery{}, profileA...), profileB...), shared...)
+	results, err := RunSubQueries(ctx, a.dbProvider, queries, a.concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	statsA := statsFromResults(results[:len(profileA)])
+	statsB := statsFromResults(results[len(profileA) : len(profileA)+len(profileB)])
+	comparison := &AddressComparison{
+		A: &AddressProfile{Address: addressA, Stats: *statsA, Traits: deriveTraits(statsA)},
+		B: &AddressProfile{Address: addressB, Stats: *statsB, Traits: deriveTraits(statsB)},
+	}
+	readComparisonResults(comparison, results[len(profileA)+len(profileB):])
+
+	summary, err := a.summarize(ctx, comparison)
+	if err != nil {
+		return nil, err
+	}
+	comparison.Summary = summary
+
+	return comparison, nil
+}
+
+// comparisonQueries builds the sub-queries relating both addresses, in the
+// order readComparisonResults reads them; the addresses have been validated as hex
+func comparisonQueries(addressA, addressB string, days int) []SubQuery {
+	since := sinceCondition(days)
+	return []SubQuery{
+		{Name: "shared counterparties", SQL: fmt.Sprintf(`SELECT to_address as counterparty,
+       sum(CASE WHEN from_address = '%s' THEN 1 ELSE 0 END) as tx_count_a,
+       sum(CASE WHEN from_address = '%s' THEN 1 ELSE 0 END) as tx_count_b
+FROM eth.transactions
+WHERE %s AND from_address IN ('%s', '%s') AND to_address NOT IN ('%s', '%s')
+GROUP BY to_address
+HAVING count(DISTINCT from_address) = 2
+ORDER BY count(*) DESC
+LIMIT 10;`, addressA, addressB, since, addressA, addressB, addressA, addressB)},
+		{Name: "shared active days", SQL: fmt.Sprintf(`SELECT count(*) as shared_days
+FROM (
+  SELECT date
+  FROM eth.transactions
+  WHERE %s AND from_address IN ('%s', '%s')
+  GROUP BY date
+  HAVING count(DISTINCT from_address) = 2
+) shared;`, since, addressA, addressB)},
+		{Name: "direct transfers", SQL: fmt.Sprintf(`SELECT from_address, count(*) as tx_count, sum(value) as value
+FROM eth.transactions
+WHERE %s AND ((from_address = '%s' AND to_address = '%s') OR (from_address = '%s' AND to_address = '%s'))
+GROUP BY from_address;`, since, addressA, addressB, addressB, addressA)},
+	}
+}
+
+// readComparisonResults fills the comparison from the results of comparisonQueries
+func readComparisonResults(comparison *AddressComparison, results []*types.TransactionQueryResult) {
+	for _, row := range resultRows(results[0]) {
+		comparison.SharedCounterparties = append(comparison.SharedCounterparties, SharedCounterparty{
+			Address:  fmt.Sprint(row["counterparty"]),
+			TxCountA: int(toFloat(row["tx_count_a"])),
+			TxCountB: int(toFloat(row["tx_count_b"])),
+		})
+	}
+
+	if days := resultRows(results[1]); len(days) > 0 {
+		comparison.SharedActiveDays = int(toFloat(days[0]["shared_days"]))
+	}
+
+	for _, row := range resultRows(results[2]) {
+		flow := TransferFlow{
+			TxCount: int(toFloat(row["tx_count"])),
+			Value:   toFloat(row["value"]),
+		}
+		switch strings.ToLower(fmt.Sprint(row["from_address"])) {
+		case comparison.A.Address:
+			comparison.FlowAToB = flow
+		case comparison.B.Address:
+			comparison.FlowBToA = flow
+		}
+	}
+}
+
+// summarize asks the LLM to assess how the addresses relate
+func (a *CompareAddressesAction) summarize(ctx context.Context, comparison *AddressComparison) (string, error) {
+	if a.llmClient == nil {
+		return "", fmt.Errorf("LLM client not initialized")
+	}
+
+	statsA, _ := json.MarshalIndent(comparison.A.Stats, "", "  ")
+	statsB, _ := json.MarshalIndent(comparison.B.Stats, "", "  ")
+	shared, _ := json.MarshalIndent(comparison.SharedCounterparties, "", "  ")
+	prompt := fmt.Sprintf(`Compare the Ethereum addresses A (%s) and B (%s).
+
+Statistics of A (values in ETH, traits %s):
+%s
+
+Statistics of B (values in ETH, traits %s):
+%s
+
+Counterparties both addresses sent to:
+%s
+
+Days both addresses sent transactions: %d
+Sent from A to B: %d transactions, %.4f ETH
+Sent from B to A: %d transactions, %.4f ETH
+
+Assess whether the addresses are related in a short paragraph, covering their
+shared counterparties, timing correlations and the value flowing between them.`,
+		comparison.A.Address, comparison.B.Address,
+		strings.Join(comparison.A.Traits, ", "), statsA,
+		strings.Join(comparison.B.Traits, ", "), statsB,
+		shared, comparison.SharedActiveDays,
+		comparison.FlowAToB.TxCount, comparison.FlowAToB.Value,
+		comparison.FlowBToA.TxCount, comparison.FlowBToA.Value)
+
+	response, err := a.llmClient.CreateCompletion(ctx, llm.CompletionRequest{
+		Model: a.model,
+		Messages: []llm.Message{
+			{Role: "system", Content: "You are a blockchain analyst investigating whether wallets are related."},
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate comparison: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// FormatAddressComparison formats the comparison for a reply
+func FormatAddressComparison(comparison *AddressComparison) string {
+	var builder strings.Builder
+	for _, side := range []struct {
+		label   string
+		profile *AddressProfile
+	}{{"A", comparison.A}, {"B", comparison.B}} {
+		builder.WriteString(fmt.Sprintf("Address %s: %s (%s)\n", side.label, side.profile.Address, strings.Join(side.profile.Traits, ", ")))
+		builder.WriteString(fmt.Sprintf("  Transactions: %d, value in: %.4f ETH, value out: %.4f ETH\n",
+			side.profile.Stats.TxCount, side.profile.Stats.ValueIn, side.profile.Stats.ValueOut))
+	}
+	builder.WriteString(fmt.Sprintf("Days active together: %d\n", comparison.SharedActiveDays))
+	builder.WriteString(fmt.Sprintf("A to B: %d transactions, %.4f ETH\n", comparison.FlowAToB.TxCount, comparison.FlowAToB.Value))
+	builder.WriteString(fmt.Sprintf("B to A: %d transactions, %.4f ETH\n", comparison.FlowBToA.TxCount, comparison.FlowBToA.Value))
+
+	if len(comparison.SharedCounterparties) > 0 {
+		builder.WriteString("Shared counterparties:\n")
+		for _, c := range comparison.SharedCounterparties {
+			builder.WriteString(fmt.Sprintf("- %s (A: %d, B: %d transactions)\n", c.Address, c.TxCountA, c.TxCountB))
+		}
+	}
+
+	if comparison.Summary != "" {
+		builder.WriteString("\n")
+		builder.WriteString(comparison.Summary)
+	}
+	return builder.String()
+}
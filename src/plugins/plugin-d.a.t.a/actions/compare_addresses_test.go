@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+const (
+	comparedAddressB = "0x8ba1f109551bd432803012645ac136ddd64dba72"
+	sharedExchange   = "0x28c6c06298d514db089934071355e5743bf21d60"
+)
+
+// comparisonProvider answers the profile and comparison sub-queries of two
+// addresses sharing one counterparty and trading on the same days
+type comparisonProvider struct {
+	types.DatabaseProvider
+	mu      sync.Mutex
+	queries []string
+}
+
+func (p *comparisonProvider) ExecuteQuery(_ context.Context, sql string) (*types.TransactionQueryResult, error) {
+	p.mu.Lock()
+	p.queries = append(p.queries, sql)
+	p.mu.Unlock()
+
+	var row map[string]interface{}
+	var rows []interface{}
+	switch {
+	case strings.Contains(sql, " as tx_count_a"):
+		row = map[string]interface{}{"counterparty": sharedExchange, "tx_count_a": 12, "tx_count_b": 9.0}
+	case strings.Contains(sql, " as shared_days"):
+		row = map[string]interface{}{"shared_days": "7"}
+	case strings.Contains(sql, "GROUP BY from_address"):
+		rows = []interface{}{
+			map[string]interface{}{"from_address": strings.ToUpper(profiledAddress[:2]) + profiledAddress[2:], "tx_count": 3, "value": "1.5"},
+			map[string]interface{}{"from_address": comparedAddressB, "tx_count": 1, "value": 0.25},
+		}
+	case strings.Contains(sql, " as tx_count,"):
+		row = map[string]interface{}{"tx_count": 40, "first_seen": "2024-01-01", "last_seen": "2024-12-01"}
+	}
+	if row != nil {
+		rows = []interface{}{row}
+	}
+	return &types.TransactionQueryResult{Success: true, Data: rows}, nil
+}
+
+// comparisonLLM answers with the shared counterparties the prompt lists
+type comparisonLLM struct {
+	llm.Client
+	prompts []string
+}
+
+func (f *comparisonLLM) CreateCompletion(_ context.Context, request llm.CompletionRequest) (string, error) {
+	prompt := request.Messages[len(request.Messages)-1].Content
+	f.prompts = append(f.prompts, prompt)
+	if strings.Contains(prompt, sharedExchange) {
+		return "Both wallets fund " + sharedExchange + " on the same days, so they are likely related.", nil
+	}
+	return "No link between the wallets.", nil
+}
+
+func TestCompareAddresses(t *testing.T) {
+	provider := &comparisonProvider{}
+	client := &comparisonLLM{}
+	action := NewCompareAddressesAction(provider, client, "test-model", 0)
+
+	comparison, err := action.Compare(context.Background(), profiledAddress, comparedAddressB, defaultProfileDays)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	// Four profile sub-queries per address and three relating them
+	if len(provider.queries) != 11 {
+		t.Errorf("ran %d sub-queries, want 11", len(provider.queries))
+	}
+	if len(comparison.SharedCounterparties) != 1 {
+		t.Fatalf("shared counterparties = %+v, want %s", comparison.SharedCounterparties, sharedExchange)
+	}
+	if got := comparison.SharedCounterparties[0]; got.Address != sharedExchange || got.TxCountA != 12 || got.TxCountB != 9 {
+		t.Errorf("shared counterparty = %+v, want %s with 12 and 9 transactions", got, sharedExchange)
+	}
+	if comparison.SharedActiveDays != 7 {
+		t.Errorf("shared active days = %d, want 7", comparison.SharedActiveDays)
+	}
+	if comparison.FlowAToB != (TransferFlow{TxCount: 3, Value: 1.5}) || comparison.FlowBToA != (TransferFlow{TxCount: 1, Value: 0.25}) {
+		t.Errorf("flows = %+v / %+v, want 3 transfers of 1.5 ETH from A and 1 of 0.25 ETH from B", comparison.FlowAToB, comparison.FlowBToA)
+	}
+
+	if !strings.Contains(comparison.Summary, sharedExchange) {
+		t.Errorf("comparison %q does not mention the shared counterparty", comparison.Summary)
+	}
+	if len(client.prompts) != 1 || !strings.Contains(client.prompts[0], "Days both addresses sent transactions: 7") {
+		t.Errorf("prompts = %q, want one stating the shared active days", client.prompts)
+	}
+	formatted := FormatAddressComparison(comparison)
+	for _, want := range []string{"- " + sharedExchange + " (A: 12, B: 9 transactions)", "A to B: 3 transactions, 1.5000 ETH", "likely related"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("formatted comparison does not contain %q:\n%s", want, formatted)
+		}
+	}
+}
+
+func TestCompareAddressesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr bool
+	}{
+		{name: "two addresses", params: map[string]interface{}{"address_a": profiledAddress, "address_b": comparedAddressB}},
+		{name: "with days", params: map[string]interface{}{"address_a": profiledAddress, "address_b": comparedAddressB, "days": "30"}},
+		{name: "missing address", params: map[string]interface{}{"address_a": profiledAddress}, wantErr: true},
+		{name: "invalid address", params: map[string]interface{}{"address_a": profiledAddress, "address_b": "0x123"}, wantErr: true},
+		{name: "same address in another case", params: map[string]interface{}{"address_a": profiledAddress, "address_b": "0x742D35CC6634C0532925A3B844BC454E4438F44E"}, wantErr: true},
+	}
+
+	action := NewCompareAddressesAction(&comparisonProvider{}, &comparisonLLM{}, "test-model", 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := action.Validate(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
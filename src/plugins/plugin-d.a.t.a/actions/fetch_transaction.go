@@ -3,15 +3,20 @@ package actions
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
 	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Ensure FetchTransactionAction implements core.FetchTransactionAction
 var _ actions.IAction = (*FetchTransactionAction)(nil)
+var _ actions.SchemaAction = (*FetchTransactionAction)(nil)
 
 // FetchTransactionAction represents the action for fetching transactions
 type FetchTransactionAction struct {
@@ -20,6 +25,7 @@ type FetchTransactionAction struct {
 	dbProvider  types.DatabaseProvider
 	examples    []string
 	similes     []string
+	resultOutput
 }
 
 // NewFetchTransactionAction creates a new fetch transaction action
@@ -54,12 +60,40 @@ func (a *FetchTransactionAction) ParametersPrompt() string {
 	- startDate: string
 	- endDate: string
 	- address: string
+	- addresses: []string (several addresses, e.g. a portfolio)
 	- orderBy: string
 	- orderDirection: string
 	- limit: int
 	`
 }
 
+// addressPattern matches a hex encoded address
+const addressPattern = "^0x[0-9a-fA-F]{40}$"
+
+// ParametersSchema declares the parameters as a JSON schema
+func (a *FetchTransactionAction) ParametersSchema() *actions.ParameterSchema {
+	address := actions.Parameter{Type: actions.ParameterString, Pattern: addressPattern, Description: "Ethereum address"}
+	return &actions.ParameterSchema{
+		Properties: map[string]actions.Parameter{
+			"message":   {Type: actions.ParameterString, Description: "The question to answer from the transactions"},
+			"startDate": {Type: actions.ParameterString, Format: "date-time", Description: "Start of the time range, RFC3339"},
+			"endDate":   {Type: actions.ParameterString, Format: "date-time", Description: "End of the time range, RFC3339"},
+			"address":   address,
+			"addresses": {Type: actions.ParameterArray, Items: &address, Description: "Several addresses, e.g. a portfolio"},
+			"orderBy": {
+				Type: actions.ParameterString,
+				Enum: []string{"block_timestamp", "value", "gas_price"},
+			},
+			"orderDirection": {Type: actions.ParameterString, Enum: []string{"ASC", "DESC"}},
+			"limit": {
+				Type:    actions.ParameterInteger,
+				Minimum: actions.Bound(1),
+				Maximum: actions.Bound(float64(a.dbProvider.MaxResultLimit())),
+			},
+		},
+	}
+}
+
 func (a *FetchTransactionAction) Validate(params map[string]interface{}) error {
 	// message is required for generating the query
 	if _, ok := params["message"].(string); !ok {
@@ -72,9 +106,11 @@ func (a *FetchTransactionAction) Validate(params map[string]interface{}) error {
 	}
 
 	// validate limit if provided
-	if v, ok := params["limit"].(int); ok {
-		if v <= 0 || v > 1000 {
-			return fmt.Errorf("limit must be between 1 and 1000")
+	if v, ok := params["limit"]; ok && v != nil {
+		maxLimit := a.dbProvider.MaxResultLimit()
+		limit, ok := types.ToInt(v)
+		if !ok || limit <= 0 || limit > int64(maxLimit) {
+			return fmt.Errorf("%w: limit must be between 1 and %d, got %v", types.ErrInvalidLimit, maxLimit, v)
 		}
 	}
 
@@ -107,6 +143,11 @@ func (a *FetchTransactionAction) Execute(ctx context.Context, params map[string]
 		return fmt.Errorf("message parameter is required")
 	}
 
+	// Require the address filter so the generated query covers exactly the given addresses
+	if filter := addressFilter(queryAddresses(params)); filter != "" {
+		message = fmt.Sprintf("%s\n\nThe query MUST include this condition in its WHERE clause: %s", message, filter)
+	}
+
 	// Generate query from message
 	query, err := a.GenerateQuery(ctx, message)
 	if err != nil {
@@ -114,28 +155,29 @@ func (a *FetchTransactionAction) Execute(ctx context.Context, params map[string]
 	}
 
 	// Execute query with parameters
-	err = a.ExecuteWithParams(ctx, query, params)
+	result, err := a.ExecuteWithParams(ctx, query, params)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
 
+	a.emit(ctx, a.name, FormatQueryResult(result), params)
 	return nil
 }
 
-// ExecuteWithParams executes the action with specific parameters
-// TODO: fix this function
-func (a *FetchTransactionAction) ExecuteWithParams(ctx context.Context, query string, params map[string]interface{}) error {
+// ExecuteWithParams executes the action with specific parameters. When only
+// the analysis fails the result still holds the data, with AnalysisError set.
+func (a *FetchTransactionAction) ExecuteWithParams(ctx context.Context, query string, params map[string]interface{}) (*types.TransactionQueryResult, error) {
 	// 1. execute the query
 	result, err := a.dbProvider.ExecuteQuery(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 
-	// 2. analyze the result
+	// 2. analyze the result, keeping the data when the analysis fails
 	analysis, err := a.dbProvider.AnalyzeQuery(ctx, result)
 	if err != nil {
-		// if the analysis failed, still return the original result
-		return nil
+		logger.GetLogger().Warnw("Transaction analysis failed, returning data only", "error", err)
+		result.AnalysisError = fmt.Sprintf("analysis unavailable: %v", err)
 	}
 
 	// 3. add the analysis result
@@ -149,7 +191,7 @@ func (a *FetchTransactionAction) ExecuteWithParams(ctx context.Context, query st
 		Query: query,
 	}
 
-	return nil
+	return result, nil
 }
 
 func (a *FetchTransactionAction) Name() string {
@@ -189,8 +231,25 @@ func (a *FetchTransactionAction) ValidateParams(params map[string]interface{}) e
 	}
 
 	// 2. validate the address format
-	if address, ok := params["address"].(string); ok && len(address) != 42 {
-		return fmt.Errorf("invalid ethereum address format")
+	if address, ok := params["address"].(string); ok {
+		if err := validateAddress(address); err != nil {
+			return err
+		}
+		// the tables store lowercase hex, the checksum has been verified above
+		params["address"] = strings.ToLower(address)
+	}
+	if raw, ok := params["addresses"]; ok {
+		addresses, err := stringList(raw)
+		if err != nil {
+			return fmt.Errorf("invalid addresses parameter: %w", err)
+		}
+		for i, address := range addresses {
+			if err := validateAddress(address); err != nil {
+				return err
+			}
+			addresses[i] = strings.ToLower(address)
+		}
+		params["addresses"] = addresses
 	}
 
 	// 3. validate the orderBy parameter
@@ -205,110 +264,93 @@ func (a *FetchTransactionAction) ValidateParams(params map[string]interface{}) e
 		}
 	}
 
-	// 4. validate the orderDirection parameter
+	// 4. validate the orderDirection parameter, accepting any casing
 	if orderDirection, ok := params["orderDirection"].(string); ok {
 		validDirection := map[string]bool{
 			"ASC":  true,
 			"DESC": true,
 		}
+		orderDirection = strings.ToUpper(strings.TrimSpace(orderDirection))
 		if !validDirection[orderDirection] {
 			return fmt.Errorf("invalid orderDirection parameter")
 		}
+		params["orderDirection"] = orderDirection
 	}
 
 	return nil
 }
 
-// getDatabaseSchema returns the database schema for prompting
-func getDatabaseSchema() string {
-	return `
-	CREATE EXTERNAL TABLE transactions(
-		hash string,
-		nonce bigint,
-		block_hash string,
-		block_number bigint,
-		block_timestamp timestamp,
-		date string,
-		transaction_index bigint,
-		from_address string,
-		to_address string,
-		value double,
-		gas bigint,
-		gas_price bigint,
-		input string,
-		max_fee_per_gas bigint,
-		max_priority_fee_per_gas bigint,
-		transaction_type bigint
-	) PARTITIONED BY (date string);
-	`
+// validateAddress checks the address format, and its EIP-55 checksum when
+// the address is mixed case. All-lowercase and all-uppercase addresses carry
+// no checksum and are accepted as is.
+func validateAddress(address string) error {
+	if !strings.HasPrefix(address, "0x") || !common.IsHexAddress(address) {
+		return fmt.Errorf("%w: %s", types.ErrInvalidAddress, address)
+	}
+
+	digits := address[2:]
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return nil
+	}
+	if checksummed := common.HexToAddress(address).Hex(); checksummed != address {
+		return fmt.Errorf("%w: %s, expected %s", types.ErrAddressChecksum, address, checksummed)
+	}
+	return nil
 }
 
-// lasted transaction example: SELECT * FROM eth.transactions WHERE date >= date_format(date_add('day', -90, current_date), '%Y-%m-%d') ORDER BY block_timestamp DESC LIMIT 3;
+// stringList converts a list parameter decoded from JSON or YAML to strings
+func stringList(raw interface{}) ([]string, error) {
+	switch v := raw.(type) {
+	case []string:
+		return append([]string(nil), v...), nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected strings, got %T", item)
+			}
+			list = append(list, str)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("expected a list, got %T", raw)
+	}
+}
 
-// getQueryExamples returns example queries for prompting
-func getQueryExamples() string {
-	return `
-	Common Query Examples:
-
-	1. Find Most Active Addresses in Last 7 Days:
-	SELECT from_address, COUNT(*) as tx_count 
-	FROM eth.transactions 
-	WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
-	GROUP BY from_address 
-	ORDER BY tx_count DESC 
-	LIMIT 10;
-
-	2. Analyze Gas Price Trends:
-	SELECT date_trunc('hour', block_timestamp) as hour,
-		   avg(gas_price) as avg_gas_price
-	FROM eth.transactions
-	WHERE date >= date_sub(current_date(), 1)
-	GROUP BY 1
-	ORDER BY 1;
-
-	3. Find latest transactions:
-	SELECT * FROM eth.transactions 
-	WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
-	ORDER BY block_timestamp DESC 
-	LIMIT 3;
-	`
+// queryAddresses returns the validated address and addresses params without duplicates
+func queryAddresses(params map[string]interface{}) []string {
+	var addresses []string
+	if address, ok := params["address"].(string); ok && address != "" {
+		addresses = append(addresses, address)
+	}
+	if list, ok := params["addresses"].([]string); ok {
+		addresses = append(addresses, list...)
+	}
+
+	seen := make(map[string]bool, len(addresses))
+	unique := addresses[:0]
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+	return unique
 }
 
-// getQueryTemplate returns the template for generating SQL queries
-func getQueryTemplate() string {
-	return `
-	# Database Schema
-	{{databaseSchema}}
-
-	# Query Examples
-	{{queryExamples}}
-
-	# User's Query
-	{{userQuery}}
-
-	# Query Guidelines:
-	1. Time Range Requirements:
-	   - ALWAYS include time range limitations in queries
-	   - Default to last 3 months if no specific time range is mentioned
-	   - Use date_parse(date, '%Y-%m-%d') >= date_format(date_add('month', -3, current_date), '%Y-%m-%d') for default time range
-	   - Adjust time range based on user's specific requirements
-
-	2. Query Optimization:
-	   - Include appropriate LIMIT clauses
-	   - Use proper indexing columns (date, address, block_number)
-	   - Consider partitioning by date
-	   - Add WHERE clauses for efficient filtering
-
-	3. Response Format Requirements:
-	   You MUST respond with ONLY the SQL query, no other text or explanation.
-	   The query should be a valid SQL statement that can be executed directly.
-
-	4. Safety Requirements:
-	   - Only SELECT statements are allowed
-	   - No modifications to the database
-	   - No creation of new tables or views
-	   - No execution of stored procedures
-	`
+// addressFilter builds the WHERE condition matching transactions sent or received by any of the addresses
+func addressFilter(addresses []string) string {
+	if len(addresses) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, len(addresses))
+	for i, address := range addresses {
+		quoted[i] = "'" + address + "'"
+	}
+	list := strings.Join(quoted, ", ")
+	return fmt.Sprintf("(from_address IN (%s) OR to_address IN (%s))", list, list)
 }
 
 // GenerateQuery generates a SQL query based on the message
@@ -323,24 +365,82 @@ func FormatQueryResult(result *types.TransactionQueryResult) string {
 	}
 
 	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("Found %d transactions\n", result.Metadata.Total))
-
-	if len(result.Data) > 0 {
-		builder.WriteString("\nTransactions:\n")
-		for _, tx := range result.Data {
-			if txMap, ok := tx.(map[string]interface{}); ok {
-				builder.WriteString(fmt.Sprintf("From: %v\n", txMap["from_address"]))
-				builder.WriteString(fmt.Sprintf("To: %v\n", txMap["to_address"]))
-				builder.WriteString(fmt.Sprintf("Value: %v ETH\n", txMap["value"]))
-				builder.WriteString(fmt.Sprintf("Hash: %v\n\n", txMap["hash"]))
-			}
-		}
+	if result.Metadata.QueryType == types.QueryTypeAggregate {
+		builder.WriteString(fmt.Sprintf("Found %d rows\n", result.Metadata.Total))
+		writeResultTable(&builder, result.Data)
+	} else {
+		builder.WriteString(fmt.Sprintf("Found %d transactions\n", result.Metadata.Total))
+		writeTransactions(&builder, result.Data)
+	}
+	if result.Metadata.Truncated {
+		builder.WriteString("More results were available than shown.\n")
 	}
 
 	if result.Analysis != "" {
 		builder.WriteString("\nAnalysis:\n")
 		builder.WriteString(result.Analysis)
+	} else if result.AnalysisError != "" {
+		builder.WriteString("\nAnalysis is unavailable right now, showing the data only.\n")
 	}
 
 	return builder.String()
 }
+
+// writeTransactions lists the sender, receiver, value and hash of each
+// transaction, skipping rows that aren't transactions
+func writeTransactions(builder *strings.Builder, data []interface{}) {
+	if len(data) > 0 {
+		builder.WriteString("\nTransactions:\n")
+		for i, item := range data {
+			tx, ok := types.RowOf(item)
+			if !ok || !tx.HasAll("from_address", "hash") {
+				logger.GetLogger().Warnw("Skipping malformed transaction row", "index", i, "row", item)
+				continue
+			}
+			builder.WriteString(fmt.Sprintf("From: %s\n", tx.GetString("from_address", "")))
+			// Contract creations have no receiver
+			builder.WriteString(fmt.Sprintf("To: %s\n", tx.GetString("to_address", "contract creation")))
+			builder.WriteString(fmt.Sprintf("Value: %s ETH\n", tx.GetString("value", "0")))
+			builder.WriteString(fmt.Sprintf("Hash: %s\n\n", tx.GetString("hash", "")))
+		}
+	}
+}
+
+// writeResultTable renders rows of any columns, such as the results of COUNT
+// or GROUP BY queries, as a table with the columns in alphabetical order
+func writeResultTable(builder *strings.Builder, data []interface{}) {
+	columnSet := make(map[string]bool)
+	var rows []types.Row
+	for i, item := range data {
+		row, ok := types.RowOf(item)
+		if !ok {
+			logger.GetLogger().Warnw("Skipping malformed result row", "index", i, "row", item)
+			continue
+		}
+		rows = append(rows, row)
+		for column := range row {
+			columnSet[column] = true
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := make([]string, 0, len(columnSet))
+	for column := range columnSet {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	builder.WriteString("\nResults:\n")
+	builder.WriteString(strings.Join(columns, " | "))
+	builder.WriteString("\n")
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			values[i] = row.GetString(column, "")
+		}
+		builder.WriteString(strings.Join(values, " | "))
+		builder.WriteString("\n")
+	}
+}
@@ -0,0 +1,349 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+const generatedQuery = "SELECT * FROM eth.transactions LIMIT 3;"
+
+func (f *fakeProvider) GenerateQuery(_ context.Context, message string) (string, error) {
+	f.prompts = append(f.prompts, message)
+	if f.generateErr != nil {
+		return "", f.generateErr
+	}
+	return generatedQuery, nil
+}
+
+func TestFetchTransactionDelegatesToProvider(t *testing.T) {
+	provider := &fakeProvider{rows: []interface{}{map[string]interface{}{"hash": "0x1"}}, analysis: "one transaction"}
+	action := NewFetchTransactionAction(provider)
+
+	if err := action.Execute(context.Background(), map[string]interface{}{"message": "latest transactions"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(provider.prompts) != 1 || provider.prompts[0] != "latest transactions" {
+		t.Errorf("provider generated queries for %q, want the message", provider.prompts)
+	}
+	if len(provider.queries) != 1 || provider.queries[0] != generatedQuery {
+		t.Errorf("provider executed %q, want the generated query", provider.queries)
+	}
+}
+
+func TestValidateParamsAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    error
+	}{
+		{name: "valid checksummed", address: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"},
+		{name: "all lowercase", address: "0x742d35cc6634c0532925a3b844bc454e4438f44e"},
+		{name: "all uppercase", address: "0x742D35CC6634C0532925A3B844BC454E4438F44E"},
+		{name: "corrupted checksum", address: "0x742d35cC6634C0532925a3b844Bc454e4438f44e", want: types.ErrAddressChecksum},
+		{name: "too short", address: "0x742d35Cc6634C0532925a3b844Bc454e4438f4", want: types.ErrInvalidAddress},
+		{name: "not hex", address: "0x742d35Cc6634C0532925a3b844Bc454e4438f44z", want: types.ErrInvalidAddress},
+		{name: "missing prefix", address: "00742d35Cc6634C0532925a3b844Bc454e4438f44e", want: types.ErrInvalidAddress},
+	}
+
+	action := NewFetchTransactionAction(&fakeProvider{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]interface{}{"address": tt.address}
+			err := action.ValidateParams(params)
+			if tt.want == nil && err != nil {
+				t.Errorf("ValidateParams() error = %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("ValidateParams() error = %v, want %v", err, tt.want)
+			}
+			// Valid addresses are lowercased for the query filter
+			if tt.want == nil && params["address"] != strings.ToLower(tt.address) {
+				t.Errorf("address = %v, want %s", params["address"], strings.ToLower(tt.address))
+			}
+		})
+	}
+}
+
+func TestValidateParamsOrderDirection(t *testing.T) {
+	tests := []struct {
+		direction string
+		want      string
+		wantErr   bool
+	}{
+		{direction: "asc", want: "ASC"},
+		{direction: "Desc", want: "DESC"},
+		{direction: " DESC ", want: "DESC"},
+		{direction: "sideways", wantErr: true},
+	}
+
+	action := NewFetchTransactionAction(&fakeProvider{})
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			params := map[string]interface{}{"orderDirection": tt.direction}
+			err := action.ValidateParams(params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && params["orderDirection"] != tt.want {
+				t.Errorf("orderDirection = %v, want %s", params["orderDirection"], tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   interface{}
+		wantErr bool
+	}{
+		{name: "within the cap", limit: 50},
+		{name: "at the cap", limit: 200},
+		{name: "from JSON", limit: 200.0},
+		{name: "above the cap", limit: 201, wantErr: true},
+		{name: "above the cap from JSON", limit: 1000.0, wantErr: true},
+		{name: "zero", limit: 0, wantErr: true},
+		{name: "fraction", limit: 10.5, wantErr: true},
+		{name: "text", limit: "lots", wantErr: true},
+	}
+
+	action := NewFetchTransactionAction(&fakeProvider{maxLimit: 200})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := action.Validate(map[string]interface{}{"message": "latest transfers", "limit": tt.limit})
+			if tt.wantErr && !errors.Is(err, types.ErrInvalidLimit) {
+				t.Errorf("Validate() error = %v, want ErrInvalidLimit", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestFetchTransactionParametersSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		wantErr string
+	}{
+		{name: "valid", params: `{"message": "largest transfers", "address": "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", "orderBy": "value", "orderDirection": "DESC", "limit": 200}`},
+		{name: "several addresses", params: `{"message": "portfolio", "addresses": ["0x742d35cc6634c0532925a3b844bc454e4438f44e", "0x8ba1f109551bd432803012645ac136ddd64dba72"]}`},
+		{name: "limit above the provider cap", params: `{"message": "largest transfers", "limit": 201}`, wantErr: "limit must be at most 200"},
+		{name: "short address", params: `{"message": "transfers", "address": "0x742d35"}`, wantErr: "address must match"},
+		{name: "malformed portfolio address", params: `{"message": "portfolio", "addresses": ["0x742d35cc6634c0532925a3b844bc454e4438f44e", "vitalik"]}`, wantErr: "addresses[1] must match"},
+		{name: "unknown order column", params: `{"message": "transfers", "orderBy": "nonce"}`, wantErr: "orderBy must be one of"},
+	}
+
+	schema := NewFetchTransactionAction(&fakeProvider{maxLimit: 200}).ParametersSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.params), &params); err != nil {
+				t.Fatalf("invalid test parameters: %v", err)
+			}
+
+			err := schema.Validate(params)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, actions.ErrInvalidParameters) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchTransactionAddresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     map[string]interface{}
+		wantFilter string
+		wantErr    error
+	}{
+		{
+			name: "two addresses",
+			params: map[string]interface{}{"addresses": []interface{}{
+				"0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				"0x000000000000000000000000000000000000dead",
+			}},
+			wantFilter: "(from_address IN ('0x742d35cc6634c0532925a3b844bc454e4438f44e', '0x000000000000000000000000000000000000dead')" +
+				" OR to_address IN ('0x742d35cc6634c0532925a3b844bc454e4438f44e', '0x000000000000000000000000000000000000dead'))",
+		},
+		{
+			name: "single address and a duplicate in the list",
+			params: map[string]interface{}{
+				"address":   "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				"addresses": []string{"0x742d35cc6634c0532925a3b844bc454e4438f44e"},
+			},
+			wantFilter: "(from_address IN ('0x742d35cc6634c0532925a3b844bc454e4438f44e') OR to_address IN ('0x742d35cc6634c0532925a3b844bc454e4438f44e'))",
+		},
+		{
+			name: "one invalid address in the list",
+			params: map[string]interface{}{"addresses": []interface{}{
+				"0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+				"0x1234",
+			}},
+			wantErr: types.ErrInvalidAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{rows: []interface{}{map[string]interface{}{"hash": "0x1"}}}
+			action := NewFetchTransactionAction(provider)
+			tt.params["message"] = "portfolio activity"
+
+			err := action.Validate(tt.params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := action.Execute(context.Background(), tt.params); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if len(provider.prompts) != 1 || !strings.Contains(provider.prompts[0], tt.wantFilter) {
+				t.Errorf("query prompt = %q, want the filter %s", provider.prompts, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestExecuteWithParamsKeepsDataWhenAnalysisFails(t *testing.T) {
+	rows := []interface{}{map[string]interface{}{"hash": "0x1"}, map[string]interface{}{"hash": "0x2"}}
+	provider := &fakeProvider{rows: rows, analysisErr: errors.New("llm unavailable")}
+
+	result, err := NewFetchTransactionAction(provider).ExecuteWithParams(context.Background(), generatedQuery, nil)
+	if err != nil {
+		t.Fatalf("ExecuteWithParams() error = %v, want the data without the analysis", err)
+	}
+
+	if len(result.Data) != len(rows) {
+		t.Errorf("result has %d rows, want %d", len(result.Data), len(rows))
+	}
+	if !strings.Contains(result.AnalysisError, "llm unavailable") {
+		t.Errorf("AnalysisError = %q, want the reason", result.AnalysisError)
+	}
+	if formatted := FormatQueryResult(result); !strings.Contains(formatted, "Analysis is unavailable") {
+		t.Errorf("formatted result does not say the analysis is unavailable:\n%s", formatted)
+	}
+}
+
+func TestFetchTransactionAmbiguousRequest(t *testing.T) {
+	provider := &fakeProvider{generateErr: &actions.ClarificationError{Question: "Which address do you mean?"}}
+
+	err := NewFetchTransactionAction(provider).Execute(context.Background(), map[string]interface{}{"message": "show me the thing"})
+	if question, ok := actions.NeedsClarification(err); !ok || question != "Which address do you mean?" {
+		t.Fatalf("Execute() error = %v, want the clarification question", err)
+	}
+	if len(provider.queries) != 0 {
+		t.Errorf("executed %q, want no fallback query", provider.queries)
+	}
+}
+
+func TestFormatQueryResultAggregate(t *testing.T) {
+	result := &types.TransactionQueryResult{
+		Success: true,
+		Data: []interface{}{
+			map[string]interface{}{"hour": "2024-01-01 00:00", "avg_gas_price": 21.5},
+			map[string]interface{}{"hour": "2024-01-01 01:00", "avg_gas_price": 18},
+		},
+	}
+	result.Metadata.Total = 2
+	result.Metadata.QueryType = types.QueryTypeAggregate
+
+	want := "Found 2 rows\n\nResults:\navg_gas_price | hour\n21.5 | 2024-01-01 00:00\n18 | 2024-01-01 01:00\n"
+	if got := FormatQueryResult(result); got != want {
+		t.Errorf("FormatQueryResult() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatQueryResultTransactions(t *testing.T) {
+	result := &types.TransactionQueryResult{
+		Success: true,
+		Data:    []interface{}{map[string]interface{}{"from_address": "0xa", "to_address": "0xb", "value": 1, "hash": "0x1"}},
+	}
+	result.Metadata.Total = 1
+	result.Metadata.QueryType = types.QueryTypeTransaction
+
+	formatted := FormatQueryResult(result)
+	for _, want := range []string{"Found 1 transactions", "From: 0xa", "To: 0xb"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("FormatQueryResult() = %q, want it to contain %q", formatted, want)
+		}
+	}
+}
+
+func TestFormatQueryResultSkipsMalformedRows(t *testing.T) {
+	result := &types.TransactionQueryResult{
+		Success: true,
+		Data: []interface{}{
+			nil,
+			"0xdeadbeef",
+			map[string]interface{}{"value": 5},
+			map[string]interface{}{"from_address": "0xa", "to_address": nil, "value": nil, "hash": "0x1"},
+		},
+	}
+	result.Metadata.Total = 4
+	result.Metadata.QueryType = types.QueryTypeTransaction
+
+	formatted := FormatQueryResult(result)
+	if strings.Contains(formatted, "<nil>") || strings.Count(formatted, "Hash:") != 1 {
+		t.Errorf("FormatQueryResult() = %q, want only the well formed transaction", formatted)
+	}
+	for _, want := range []string{"From: 0xa", "To: contract creation", "Value: 0 ETH", "Hash: 0x1"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("FormatQueryResult() = %q, want it to contain %q", formatted, want)
+		}
+	}
+}
+
+func TestFormatQueryResultTableSkipsMalformedRows(t *testing.T) {
+	result := &types.TransactionQueryResult{
+		Success: true,
+		Data: []interface{}{
+			42,
+			map[string]interface{}{"hour": "2024-01-01 00:00", "avg_gas_price": nil},
+		},
+	}
+	result.Metadata.Total = 2
+	result.Metadata.QueryType = types.QueryTypeAggregate
+
+	want := "Found 2 rows\n\nResults:\navg_gas_price | hour\n | 2024-01-01 00:00\n"
+	if got := FormatQueryResult(result); got != want {
+		t.Errorf("FormatQueryResult() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatQueryResultTruncated(t *testing.T) {
+	result := &types.TransactionQueryResult{
+		Success: true,
+		Data:    []interface{}{map[string]interface{}{"from_address": "0xa", "to_address": "0xb", "value": 1, "hash": "0x1"}},
+	}
+	result.Metadata.Total = 1
+	result.Metadata.QueryType = types.QueryTypeTransaction
+
+	if formatted := FormatQueryResult(result); strings.Contains(formatted, "More results were available") {
+		t.Errorf("FormatQueryResult() = %q, want no truncation note for a complete result", formatted)
+	}
+	result.Metadata.Truncated = true
+	if formatted := FormatQueryResult(result); !strings.Contains(formatted, "More results were available than shown.") {
+		t.Errorf("FormatQueryResult() = %q, want the truncation noted", formatted)
+	}
+}
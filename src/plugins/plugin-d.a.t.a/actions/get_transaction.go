@@ -0,0 +1,151 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// Ensure GetTransactionAction implements actions.IAction
+var _ actions.IAction = (*GetTransactionAction)(nil)
+
+// txHashPattern matches a transaction hash: 0x followed by 64 hex digits
+var txHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// GetTransactionAction looks up a single transaction by its hash
+type GetTransactionAction struct {
+	name        string
+	description string
+	dbProvider  types.DatabaseProvider
+	resultOutput
+}
+
+// NewGetTransactionAction creates a new get transaction action
+func NewGetTransactionAction(dbProvider types.DatabaseProvider) *GetTransactionAction {
+	return &GetTransactionAction{
+		name:        "get_transaction",
+		description: "Look up and analyze a single Ethereum transaction by its hash",
+		dbProvider:  dbProvider,
+	}
+}
+
+func (a *GetTransactionAction) Name() string {
+	return a.name
+}
+
+func (a *GetTransactionAction) Description() string {
+	return a.description
+}
+
+func (a *GetTransactionAction) Type() string {
+	return "get_transaction"
+}
+
+func (a *GetTransactionAction) ParametersPrompt() string {
+	return `
+	# Parameters:
+	- hash: string (transaction hash, 0x followed by 64 hex characters)
+	`
+}
+
+func (a *GetTransactionAction) Validate(params map[string]interface{}) error {
+	hash, ok := params["hash"].(string)
+	if !ok {
+		return fmt.Errorf("hash parameter is required")
+	}
+	return validateTxHash(hash)
+}
+
+// Execute looks up the transaction and logs its summary
+func (a *GetTransactionAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	if err := a.Validate(params); err != nil {
+		return err
+	}
+
+	result, err := a.Lookup(ctx, params["hash"].(string))
+	if err != nil {
+		return err
+	}
+
+	summary := FormatTransactionSummary(result)
+	logger.GetLogger().Infow("Transaction found",
+		"hash", params["hash"],
+		"summary", summary,
+	)
+	a.emit(ctx, a.name, summary, params)
+	return nil
+}
+
+// Lookup queries the transaction with the given hash and analyzes it
+func (a *GetTransactionAction) Lookup(ctx context.Context, hash string) (*types.TransactionQueryResult, error) {
+	if err := validateTxHash(hash); err != nil {
+		return nil, err
+	}
+
+	result, err := a.dbProvider.ExecuteQuery(ctx, transactionByHashQuery(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transaction: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("%w: %s", types.ErrTransactionNotFound, hash)
+	}
+
+	analysis, err := a.dbProvider.AnalyzeQuery(ctx, result)
+	if err != nil {
+		// the transaction itself is still useful without the analysis
+		logger.GetLogger().Warnw("Failed to analyze transaction", "hash", hash, "error", err)
+		result.AnalysisError = fmt.Sprintf("analysis unavailable: %v", err)
+	}
+	result.Analysis = analysis
+
+	return result, nil
+}
+
+// validateTxHash checks the hash is 0x followed by 64 hex digits
+func validateTxHash(hash string) error {
+	if !txHashPattern.MatchString(hash) {
+		return fmt.Errorf("%w: %s", types.ErrInvalidTxHash, hash)
+	}
+	return nil
+}
+
+// transactionByHashQuery builds the lookup query; the hash has been validated
+// as hex, so it is safe to inline
+func transactionByHashQuery(hash string) string {
+	return fmt.Sprintf("SELECT * FROM eth.transactions WHERE hash = '%s' LIMIT 1;", strings.ToLower(hash))
+}
+
+// FormatTransactionSummary formats a single transaction and its analysis
+func FormatTransactionSummary(result *types.TransactionQueryResult) string {
+	if len(result.Data) == 0 {
+		return "Transaction not found"
+	}
+
+	tx, ok := types.RowOf(result.Data[0])
+	if !ok || !tx.HasAll("hash") {
+		logger.GetLogger().Warnw("Malformed transaction row", "row", result.Data[0])
+		return "Transaction not found"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Hash: %s\n", tx.GetString("hash", "")))
+	builder.WriteString(fmt.Sprintf("Block: %s (%s)\n", tx.GetString("block_number", "unknown"), tx.GetString("block_timestamp", "unknown time")))
+	builder.WriteString(fmt.Sprintf("From: %s\n", tx.GetString("from_address", "unknown")))
+	builder.WriteString(fmt.Sprintf("To: %s\n", tx.GetString("to_address", "contract creation")))
+	builder.WriteString(fmt.Sprintf("Value: %s\n", tx.GetString("value", "0")))
+	builder.WriteString(fmt.Sprintf("Gas: %s at gas price %s\n", tx.GetString("gas", "unknown"), tx.GetString("gas_price", "unknown")))
+
+	if result.Analysis != "" {
+		builder.WriteString("\nAnalysis:\n")
+		builder.WriteString(result.Analysis)
+	} else if result.AnalysisError != "" {
+		builder.WriteString("\nAnalysis is unavailable right now, showing the data only.\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+const testTxHash = "0x5C504ED432CB51138BCF09AA5E8A410DD4A1E204EF84BFED1BE16DFBA1B22060"
+
+func TestGetTransactionLookup(t *testing.T) {
+	provider := &fakeProvider{
+		rows:     []interface{}{map[string]interface{}{"hash": strings.ToLower(testTxHash), "value": "1000"}},
+		analysis: "A plain ETH transfer.",
+	}
+
+	result, err := NewGetTransactionAction(provider).Lookup(context.Background(), testTxHash)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+
+	wantQuery := "WHERE hash = '" + strings.ToLower(testTxHash) + "'"
+	if len(provider.queries) != 1 || !strings.Contains(provider.queries[0], wantQuery) {
+		t.Errorf("queries = %q, want one containing %q", provider.queries, wantQuery)
+	}
+	summary := FormatTransactionSummary(result)
+	for _, want := range []string{"Hash: " + strings.ToLower(testTxHash), "Value: 1000", "A plain ETH transfer."} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary is missing %q:\n%s", want, summary)
+		}
+	}
+}
+
+func TestGetTransactionRejectsMalformedHash(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "missing prefix", hash: testTxHash[2:]},
+		{name: "too short", hash: testTxHash[:65]},
+		{name: "too long", hash: testTxHash + "0"},
+		{name: "non-hex", hash: "0x" + strings.Repeat("g", 64)},
+		{name: "injection", hash: "0x' OR '1'='1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{}
+			action := NewGetTransactionAction(provider)
+
+			if err := action.Validate(map[string]interface{}{"hash": tt.hash}); !errors.Is(err, types.ErrInvalidTxHash) {
+				t.Errorf("Validate() error = %v, want ErrInvalidTxHash", err)
+			}
+			if _, err := action.Lookup(context.Background(), tt.hash); !errors.Is(err, types.ErrInvalidTxHash) {
+				t.Errorf("Lookup() error = %v, want ErrInvalidTxHash", err)
+			}
+			if len(provider.queries) != 0 {
+				t.Errorf("queries = %q, want none for a malformed hash", provider.queries)
+			}
+		})
+	}
+}
+
+func TestGetTransactionNotFound(t *testing.T) {
+	_, err := NewGetTransactionAction(&fakeProvider{}).Lookup(context.Background(), testTxHash)
+	if !errors.Is(err, types.ErrTransactionNotFound) {
+		t.Errorf("Lookup() error = %v, want ErrTransactionNotFound", err)
+	}
+}
+
+func TestFormatTransactionSummaryMalformedRow(t *testing.T) {
+	tests := []struct {
+		name string
+		row  interface{}
+	}{
+		{name: "not an object", row: "0x1"},
+		{name: "null", row: nil},
+		{name: "without hash", row: map[string]interface{}{"value": "1000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &types.TransactionQueryResult{Success: true, Data: []interface{}{tt.row}}
+			if got := FormatTransactionSummary(result); got != "Transaction not found" {
+				t.Errorf("FormatTransactionSummary() = %q, want Transaction not found", got)
+			}
+		})
+	}
+}
+
+func TestGetTransactionKeepsDataWhenAnalysisFails(t *testing.T) {
+	provider := &fakeProvider{
+		rows:        []interface{}{map[string]interface{}{"hash": strings.ToLower(testTxHash)}},
+		analysisErr: errors.New("llm unavailable"),
+	}
+
+	result, err := NewGetTransactionAction(provider).Lookup(context.Background(), testTxHash)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v, want the transaction without the analysis", err)
+	}
+
+	if len(result.Data) != 1 {
+		t.Errorf("result has %d rows, want the transaction", len(result.Data))
+	}
+	if !strings.Contains(result.AnalysisError, "llm unavailable") {
+		t.Errorf("AnalysisError = %q, want the reason", result.AnalysisError)
+	}
+	if summary := FormatTransactionSummary(result); !strings.Contains(summary, "Analysis is unavailable") {
+		t.Errorf("summary does not say the analysis is unavailable:\n%s", summary)
+	}
+}
@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+)
+
+// OutputConfigurable is implemented by actions that can send their results to output sinks
+type OutputConfigurable interface {
+	SetOutputSinks(sinks []plugins.OutputSink)
+}
+
+// resultOutput sends the results of an action to its output sinks
+type resultOutput struct {
+	sinks []plugins.OutputSink
+}
+
+// SetOutputSinks sets the sinks that receive the results of the action
+func (o *resultOutput) SetOutputSinks(sinks []plugins.OutputSink) {
+	o.sinks = sinks
+}
+
+// emit sends a result to every sink. Failures are only logged so that a broken
+// sink does not fail the action.
+func (o *resultOutput) emit(ctx context.Context, action, content string, params map[string]interface{}) {
+	if len(o.sinks) == 0 {
+		return
+	}
+
+	metadata := map[string]interface{}{
+		"action": action,
+		"params": params,
+	}
+	for _, sink := range o.sinks {
+		if err := sink.Send(ctx, content, metadata); err != nil {
+			logger.GetLogger().Warnw("Failed to send result to output sink",
+				"action", action,
+				"error", err,
+			)
+		}
+	}
+}
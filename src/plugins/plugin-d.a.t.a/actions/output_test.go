@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+)
+
+// fakeSink records the results it receives, or fails with err
+type fakeSink struct {
+	err      error
+	contents []string
+	metadata []map[string]interface{}
+}
+
+func (s *fakeSink) Send(_ context.Context, content string, metadata map[string]interface{}) error {
+	s.contents = append(s.contents, content)
+	s.metadata = append(s.metadata, metadata)
+	return s.err
+}
+
+func TestExecuteSendsResultToOutputSinks(t *testing.T) {
+	provider := &fakeProvider{
+		rows:     []interface{}{map[string]interface{}{"hash": strings.ToLower(testTxHash)}},
+		analysis: "A plain ETH transfer.",
+	}
+	broken := &fakeSink{err: errors.New("pipeline is down")}
+	sink := &fakeSink{}
+	action := NewGetTransactionAction(provider)
+	action.SetOutputSinks([]plugins.OutputSink{broken, sink})
+
+	// A broken sink does not fail the action or stop the other sinks
+	if err := action.Execute(context.Background(), map[string]interface{}{"hash": testTxHash}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(sink.contents) != 1 || !strings.Contains(sink.contents[0], "A plain ETH transfer.") {
+		t.Fatalf("sink received %q, want the transaction summary", sink.contents)
+	}
+	if sink.metadata[0]["action"] != "get_transaction" {
+		t.Errorf("sink metadata = %v, want the action name", sink.metadata[0])
+	}
+	if len(broken.contents) != 1 {
+		t.Errorf("broken sink received %d results, want 1", len(broken.contents))
+	}
+}
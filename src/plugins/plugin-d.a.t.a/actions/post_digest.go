@@ -0,0 +1,199 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// Ensure PostDigestAction implements actions.IAction
+var _ actions.IAction = (*PostDigestAction)(nil)
+
+// digestQueries are the built-in digest queries, each taking the lookback in days
+var digestQueries = map[string]string{
+	"gas_trends": `SELECT date_trunc('hour', block_timestamp) as hour,
+       avg(gas_price) as avg_gas_price,
+       count(*) as tx_count
+FROM eth.transactions
+WHERE date >= date_format(date_add('day', -%d, current_date), '%%Y-%%m-%%d')
+GROUP BY 1
+ORDER BY 1;`,
+	"top_senders": `SELECT from_address, count(*) as tx_count, sum(value) as total_value
+FROM eth.transactions
+WHERE date >= date_format(date_add('day', -%d, current_date), '%%Y-%%m-%%d')
+GROUP BY from_address
+ORDER BY tx_count DESC
+LIMIT 10;`,
+}
+
+// digestLimits caps the digest length per platform
+var digestLimits = map[string]int{
+	"twitter":  280,
+	"discord":  2000,
+	"telegram": 4096,
+}
+
+const defaultDigestDays = 1
+
+// PostDigestAction runs a transaction query, analyzes the result and posts
+// the analysis as a digest. It is meant to be run from a configured schedule.
+type PostDigestAction struct {
+	name        string
+	description string
+	dbProvider  types.DatabaseProvider
+	publisher   plugins.Publisher
+}
+
+// NewPostDigestAction creates a new post digest action
+func NewPostDigestAction(dbProvider types.DatabaseProvider, publisher plugins.Publisher) *PostDigestAction {
+	return &PostDigestAction{
+		name:        "post_digest",
+		description: "Post a periodic digest of Ethereum transaction trends to a social platform",
+		dbProvider:  dbProvider,
+		publisher:   publisher,
+	}
+}
+
+func (a *PostDigestAction) Name() string {
+	return a.name
+}
+
+func (a *PostDigestAction) Description() string {
+	return a.description
+}
+
+func (a *PostDigestAction) Type() string {
+	return "post_digest"
+}
+
+func (a *PostDigestAction) ParametersPrompt() string {
+	return `
+	# Parameters:
+	- query: string (gas_trends, top_senders or a SQL query)
+	- platform: string (twitter, discord or telegram)
+	- days: int (lookback of the built-in queries, default 1)
+	- title: string (optional heading of the digest)
+	- channel_id: string (required for discord)
+	`
+}
+
+func (a *PostDigestAction) Validate(params map[string]interface{}) error {
+	query, ok := params["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return fmt.Errorf("query parameter is required")
+	}
+
+	platform, ok := params["platform"].(string)
+	if !ok {
+		return fmt.Errorf("platform parameter is required")
+	}
+	if _, ok := digestLimits[platform]; !ok {
+		return fmt.Errorf("unsupported platform: %s", platform)
+	}
+	if platform == "discord" {
+		if channelID, ok := params["channel_id"].(string); !ok || channelID == "" {
+			return fmt.Errorf("channel_id parameter is required for discord")
+		}
+	}
+
+	if _, err := digestDays(params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute runs the digest query and posts the analysis
+func (a *PostDigestAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	if a.publisher == nil {
+		return fmt.Errorf("no publisher configured for %s", a.name)
+	}
+	if err := a.Validate(params); err != nil {
+		return err
+	}
+
+	days, _ := digestDays(params)
+	sql := resolveDigestQuery(params["query"].(string), days)
+
+	result, err := a.dbProvider.ExecuteQuery(ctx, sql)
+	if err != nil {
+		return fmt.Errorf("failed to execute digest query: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return fmt.Errorf("digest query returned no data")
+	}
+
+	analysis, err := a.dbProvider.AnalyzeQuery(ctx, result)
+	if err != nil {
+		return fmt.Errorf("failed to analyze digest: %w", err)
+	}
+
+	platform := params["platform"].(string)
+	title, _ := params["title"].(string)
+	content := formatDigest(platform, title, analysis)
+
+	metadata := map[string]interface{}{}
+	if channelID, ok := params["channel_id"].(string); ok {
+		metadata["channel_id"] = channelID
+	}
+
+	if err := a.publisher.Publish(ctx, platform, content, metadata); err != nil {
+		return fmt.Errorf("failed to post digest to %s: %w", platform, err)
+	}
+	return nil
+}
+
+// digestDays returns the lookback of the built-in queries
+func digestDays(params map[string]interface{}) (int, error) {
+	val, ok := params["days"]
+	if !ok {
+		return defaultDigestDays, nil
+	}
+
+	var days int
+	switch v := val.(type) {
+	case int:
+		days = v
+	case float64:
+		days = int(v)
+	default:
+		return 0, fmt.Errorf("days must be a number, got %T", val)
+	}
+	if days <= 0 {
+		return 0, fmt.Errorf("days must be positive")
+	}
+	return days, nil
+}
+
+// resolveDigestQuery expands a built-in query name, other queries are used as is
+func resolveDigestQuery(query string, days int) string {
+	if tmpl, ok := digestQueries[query]; ok {
+		return fmt.Sprintf(tmpl, days)
+	}
+	return query
+}
+
+// formatDigest prefixes the title and trims the digest to the platform limit
+func formatDigest(platform, title, analysis string) string {
+	content := strings.TrimSpace(analysis)
+	if title != "" {
+		separator := "\n\n"
+		if platform == "twitter" {
+			separator = "\n"
+		}
+		if platform == "discord" {
+			title = "**" + title + "**"
+		}
+		content = title + separator + content
+	}
+
+	limit := digestLimits[platform]
+	runes := []rune(content)
+	if limit > 0 && len(runes) > limit {
+		content = string(runes[:limit-1]) + "…"
+	}
+	return content
+}
@@ -0,0 +1,157 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// fakeProvider answers every query with rows and analyzes them with analysis,
+// or fails the analysis with analysisErr and the generation with generateErr
+type fakeProvider struct {
+	types.DatabaseProvider
+	rows        []interface{}
+	analysis    string
+	analysisErr error
+	generateErr error
+	queries     []string
+	prompts     []string
+	// maxLimit is the result limit, 1000 when zero
+	maxLimit int
+}
+
+func (f *fakeProvider) MaxResultLimit() int {
+	if f.maxLimit == 0 {
+		return 1000
+	}
+	return f.maxLimit
+}
+
+func (f *fakeProvider) ExecuteQuery(_ context.Context, sql string) (*types.TransactionQueryResult, error) {
+	f.queries = append(f.queries, sql)
+	return &types.TransactionQueryResult{Success: true, Data: f.rows}, nil
+}
+
+func (f *fakeProvider) AnalyzeQuery(context.Context, *types.TransactionQueryResult) (string, error) {
+	if f.analysisErr != nil {
+		return "", f.analysisErr
+	}
+	return f.analysis, nil
+}
+
+// post is a digest handed to the fake publisher
+type post struct {
+	platform string
+	content  string
+	metadata map[string]interface{}
+}
+
+type fakePublisher struct {
+	posts []post
+}
+
+func (f *fakePublisher) Publish(_ context.Context, platform, content string, metadata map[string]interface{}) error {
+	f.posts = append(f.posts, post{platform: platform, content: content, metadata: metadata})
+	return nil
+}
+
+func TestPostDigestExecute(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]interface{}
+		wantQuery   string
+		wantContent string
+		wantChannel string
+	}{
+		{
+			name:        "built-in query on twitter",
+			params:      map[string]interface{}{"query": "gas_trends", "platform": "twitter", "days": 7, "title": "Gas digest"},
+			wantQuery:   "date_add('day', -7, current_date), '%Y-%m-%d'",
+			wantContent: "Gas digest\nGas is cheap today.",
+		},
+		{
+			name: "custom query on discord",
+			params: map[string]interface{}{
+				"query":      "SELECT count(*) FROM eth.transactions",
+				"platform":   "discord",
+				"title":      "Daily count",
+				"channel_id": "channel-1",
+			},
+			wantQuery:   "SELECT count(*) FROM eth.transactions",
+			wantContent: "**Daily count**\n\nGas is cheap today.",
+			wantChannel: "channel-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{rows: []interface{}{map[string]interface{}{"tx_count": 42}}, analysis: "Gas is cheap today.\n"}
+			publisher := &fakePublisher{}
+
+			if err := NewPostDigestAction(provider, publisher).Execute(context.Background(), tt.params); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if len(provider.queries) != 1 || !strings.Contains(provider.queries[0], tt.wantQuery) {
+				t.Errorf("queries = %q, want one containing %q", provider.queries, tt.wantQuery)
+			}
+			if len(publisher.posts) != 1 {
+				t.Fatalf("published %d digests, want 1", len(publisher.posts))
+			}
+			got := publisher.posts[0]
+			if got.platform != tt.params["platform"] || got.content != tt.wantContent {
+				t.Errorf("published %q to %s, want %q", got.content, got.platform, tt.wantContent)
+			}
+			if channel, _ := got.metadata["channel_id"].(string); channel != tt.wantChannel {
+				t.Errorf("channel_id = %q, want %q", channel, tt.wantChannel)
+			}
+		})
+	}
+}
+
+func TestPostDigestExecuteWithoutData(t *testing.T) {
+	publisher := &fakePublisher{}
+	action := NewPostDigestAction(&fakeProvider{analysis: "nothing"}, publisher)
+
+	err := action.Execute(context.Background(), map[string]interface{}{"query": "top_senders", "platform": "telegram"})
+	if err == nil {
+		t.Error("Execute() error = nil, want an error for an empty result")
+	}
+	if len(publisher.posts) != 0 {
+		t.Errorf("published %d digests, want none", len(publisher.posts))
+	}
+}
+
+func TestPostDigestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr bool
+	}{
+		{name: "valid", params: map[string]interface{}{"query": "gas_trends", "platform": "twitter"}},
+		{name: "days from JSON", params: map[string]interface{}{"query": "gas_trends", "platform": "twitter", "days": 3.0}},
+		{name: "missing query", params: map[string]interface{}{"platform": "twitter"}, wantErr: true},
+		{name: "unsupported platform", params: map[string]interface{}{"query": "gas_trends", "platform": "myspace"}, wantErr: true},
+		{name: "discord without channel", params: map[string]interface{}{"query": "gas_trends", "platform": "discord"}, wantErr: true},
+		{name: "negative days", params: map[string]interface{}{"query": "gas_trends", "platform": "twitter", "days": -1}, wantErr: true},
+		{name: "days as text", params: map[string]interface{}{"query": "gas_trends", "platform": "twitter", "days": "7"}, wantErr: true},
+	}
+
+	action := NewPostDigestAction(&fakeProvider{}, &fakePublisher{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := action.Validate(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatDigestTruncatesToPlatformLimit(t *testing.T) {
+	content := formatDigest("twitter", "", strings.Repeat("a", 300))
+	if runes := []rune(content); len(runes) != 280 || runes[279] != '…' {
+		t.Errorf("digest has %d characters, want 280 ending in an ellipsis", len(runes))
+	}
+}
@@ -0,0 +1,416 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// Ensure ProfileAddressAction implements actions.IAction
+var _ actions.IAction = (*ProfileAddressAction)(nil)
+
+const (
+	defaultProfileDays = 365
+
+	// Trait thresholds, values are in ETH
+	whaleValueThreshold    = 1000.0
+	botTxPerDayThreshold   = 100.0
+	exchangeTxThreshold    = 10000
+	contractHeavyThreshold = 0.5
+)
+
+// Address traits derived from the profile statistics
+const (
+	TraitWhale         = "whale"
+	TraitBot           = "bot"
+	TraitExchange      = "exchange"
+	TraitContractHeavy = "contract_heavy"
+	TraitNormal        = "normal"
+)
+
+// Counterparty is an address the profiled address sends to often
+type Counterparty struct {
+	Address string
+	TxCount int
+}
+
+// AddressStats are the statistics gathered by the profile sub-queries
+type AddressStats struct {
+	TxCount        int
+	FirstSeen      string
+	LastSeen       string
+	ValueIn        float64
+	ValueOut       float64
+	SentCount      int
+	ContractCalls  int
+	Counterparties []Counterparty
+}
+
+// ContractRatio is the share of sent transactions that call a contract
+func (s AddressStats) ContractRatio() float64 {
+	if s.SentCount == 0 {
+		return 0
+	}
+	return float64(s.ContractCalls) / float64(s.SentCount)
+}
+
+// AddressProfile is the behavior profile of an address
+type AddressProfile struct {
+	Address string
+	Stats   AddressStats
+	Traits  []string
+	Summary string
+}
+
+// ProfileAddressAction profiles the behavior of an address from its transactions
+type ProfileAddressAction struct {
+	name        string
+	description string
+	dbProvider  types.DatabaseProvider
+	llmClient   llm.Client
+	model       string
+	// concurrency limits the sub-queries running at once
+	concurrency int
+	resultOutput
+}
+
+// NewProfileAddressAction creates a new profile address action; concurrency
+// limits the sub-queries running at once, DefaultQueryConcurrency when 0
+func NewProfileAddressAction(dbProvider types.DatabaseProvider, llmClient llm.Client, model string, concurrency int) *ProfileAddressAction {
+	return &ProfileAddressAction{
+		name:        "profile_address",
+		description: "Profile the behavior of an Ethereum address, e.g. whale, bot, exchange or normal wallet",
+		dbProvider:  dbProvider,
+		llmClient:   llmClient,
+		model:       model,
+		concurrency: concurrency,
+	}
+}
+
+func (a *ProfileAddressAction) Name() string {
+	return a.name
+}
+
+func (a *ProfileAddressAction) Description() string {
+	return a.description
+}
+
+func (a *ProfileAddressAction) Type() string {
+	return "profile_address"
+}
+
+func (a *ProfileAddressAction) ParametersPrompt() string {
+	return `
+	# Parameters:
+	- address: string
+	- days: int (history to consider, default 365)
+	`
+}
+
+func (a *ProfileAddressAction) Validate(params map[string]interface{}) error {
+	address, ok := params["address"].(string)
+	if !ok {
+		return fmt.Errorf("address parameter is required")
+	}
+	if err := validateAddress(address); err != nil {
+		return err
+	}
+	if _, err := profileDays(params); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute profiles the address and logs the profile
+func (a *ProfileAddressAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	if err := a.Validate(params); err != nil {
+		return err
+	}
+
+	days, _ := profileDays(params)
+	profile, err := a.Profile(ctx, params["address"].(string), days)
+	if err != nil {
+		return err
+	}
+
+	formatted := FormatAddressProfile(profile)
+	logger.GetLogger().Infow("Address profiled",
+		"address", profile.Address,
+		"profile", formatted,
+	)
+	a.emit(ctx, a.name, formatted, params)
+	return nil
+}
+
+// Profile gathers the statistics of the address and synthesizes its profile
+func (a *ProfileAddressAction) Profile(ctx context.Context, address string, days int) (*AddressProfile, error) {
+	if err := validateAddress(address); err != nil {
+		return nil, err
+	}
+	address = strings.ToLower(address)
+
+	stats, err := a.collectStats(ctx, address, days)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &AddressProfile{
+		Address: address,
+		Stats:   *stats,
+		Traits:  deriveTraits(stats),
+	}
+
+	summary, err := a.summarize(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	profile.Summary = summary
+
+	return profile, nil
+}
+
+// collectStats runs the profile sub-queries concurrently
+func (a *ProfileAddressAction) collectStats(ctx context.Context, address string, days int) (*AddressStats, error) {
+	results, err := RunSubQueries(ctx, a.dbProvider, profileQueries(address, days).list(), a.concurrency)
+	if err != nil {
+		return nil, err
+	}
+	return statsFromResults(results), nil
+}
+
+// statsFromResults reads the statistics from the results of the profile
+// sub-queries, in the order of profileSubQueries.list
+func statsFromResults(results []*types.TransactionQueryResult) *AddressStats {
+	stats := &AddressStats{}
+	if activity := resultRows(results[0]); len(activity) > 0 {
+		stats.TxCount = int(toFloat(activity[0]["tx_count"]))
+		stats.FirstSeen = fmt.Sprint(activity[0]["first_seen"])
+		stats.LastSeen = fmt.Sprint(activity[0]["last_seen"])
+	}
+
+	if flow := resultRows(results[1]); len(flow) > 0 {
+		stats.ValueIn = toFloat(flow[0]["value_in"])
+		stats.ValueOut = toFloat(flow[0]["value_out"])
+	}
+
+	for _, row := range resultRows(results[2]) {
+		stats.Counterparties = append(stats.Counterparties, Counterparty{
+			Address: fmt.Sprint(row["counterparty"]),
+			TxCount: int(toFloat(row["tx_count"])),
+		})
+	}
+
+	if contracts := resultRows(results[3]); len(contracts) > 0 {
+		stats.SentCount = int(toFloat(contracts[0]["sent_count"]))
+		stats.ContractCalls = int(toFloat(contracts[0]["contract_calls"]))
+	}
+
+	return stats
+}
+
+// summarize asks the LLM to classify the address from its statistics and traits
+func (a *ProfileAddressAction) summarize(ctx context.Context, profile *AddressProfile) (string, error) {
+	if a.llmClient == nil {
+		return "", fmt.Errorf("LLM client not initialized")
+	}
+
+	stats, _ := json.MarshalIndent(profile.Stats, "", "  ")
+	prompt := fmt.Sprintf(`Profile the behavior of the Ethereum address %s.
+
+Statistics (values in ETH, contract ratio %.2f):
+%s
+
+Traits derived from the statistics: %s
+
+Classify the address as whale, bot, exchange or normal wallet and explain the
+classification in a short paragraph, referring to the traits and statistics.`,
+		profile.Address, profile.Stats.ContractRatio(), stats, strings.Join(profile.Traits, ", "))
+
+	response, err := a.llmClient.CreateCompletion(ctx, llm.CompletionRequest{
+		Model: a.model,
+		Messages: []llm.Message{
+			{Role: "system", Content: "You are a blockchain analyst profiling wallet behavior."},
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate profile: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// profileSubQueries are the SQL queries of a profile
+type profileSubQueries struct {
+	activity       string
+	valueFlow      string
+	counterparties string
+	contracts      string
+}
+
+// list returns the sub-queries in the order statsFromResults reads them
+func (q profileSubQueries) list() []SubQuery {
+	return []SubQuery{
+		{Name: "activity", SQL: q.activity},
+		{Name: "value flow", SQL: q.valueFlow},
+		{Name: "counterparties", SQL: q.counterparties},
+		{Name: "contract interactions", SQL: q.contracts},
+	}
+}
+
+// profileQueries builds the sub-queries; the address has been validated as hex
+func profileQueries(address string, days int) profileSubQueries {
+	since := sinceCondition(days)
+	return profileSubQueries{
+		activity: fmt.Sprintf(`SELECT count(*) as tx_count, min(block_timestamp) as first_seen, max(block_timestamp) as last_seen
+FROM eth.transactions
+WHERE %s AND (from_address = '%s' OR to_address = '%s');`, since, address, address),
+		valueFlow: fmt.Sprintf(`SELECT sum(CASE WHEN to_address = '%s' THEN value ELSE 0 END) as value_in,
+       sum(CASE WHEN from_address = '%s' THEN value ELSE 0 END) as value_out
+FROM eth.transactions
+WHERE %s AND (from_address = '%s' OR to_address = '%s');`, address, address, since, address, address),
+		counterparties: fmt.Sprintf(`SELECT to_address as counterparty, count(*) as tx_count
+FROM eth.transactions
+WHERE %s AND from_address = '%s'
+GROUP BY to_address
+ORDER BY tx_count DESC
+LIMIT 5;`, since, address),
+		contracts: fmt.Sprintf(`SELECT count(*) as sent_count, sum(CASE WHEN input <> '0x' THEN 1 ELSE 0 END) as contract_calls
+FROM eth.transactions
+WHERE %s AND from_address = '%s';`, since, address),
+	}
+}
+
+// sinceCondition restricts a query to the last days of transactions
+func sinceCondition(days int) string {
+	return fmt.Sprintf("date >= date_format(date_add('day', -%d, current_date), '%%Y-%%m-%%d')", days)
+}
+
+// deriveTraits classifies the statistics with simple thresholds
+func deriveTraits(stats *AddressStats) []string {
+	var traits []string
+
+	if stats.ValueIn+stats.ValueOut >= whaleValueThreshold {
+		traits = append(traits, TraitWhale)
+	}
+	if activeDays := activeDays(stats.FirstSeen, stats.LastSeen); activeDays > 0 &&
+		float64(stats.TxCount)/activeDays >= botTxPerDayThreshold {
+		traits = append(traits, TraitBot)
+	}
+	if stats.TxCount >= exchangeTxThreshold && stats.ContractRatio() < 0.1 {
+		traits = append(traits, TraitExchange)
+	}
+	if stats.SentCount > 0 && stats.ContractRatio() >= contractHeavyThreshold {
+		traits = append(traits, TraitContractHeavy)
+	}
+
+	if len(traits) == 0 {
+		traits = append(traits, TraitNormal)
+	}
+	return traits
+}
+
+// activeDays returns the days between the first and last transaction, at least 1
+func activeDays(firstSeen, lastSeen string) float64 {
+	first, errFirst := parseTimestamp(firstSeen)
+	last, errLast := parseTimestamp(lastSeen)
+	if errFirst != nil || errLast != nil {
+		return 0
+	}
+
+	days := last.Sub(first).Hours() / 24
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
+// parseTimestamp parses the timestamp formats returned by the data API
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05.000", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown timestamp format: %s", value)
+}
+
+// toFloat converts a numeric column value to float64
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	default:
+		return 0
+	}
+}
+
+// profileDays returns the history of the profile in days
+func profileDays(params map[string]interface{}) (int, error) {
+	val, ok := params["days"]
+	if !ok {
+		return defaultProfileDays, nil
+	}
+
+	var days int
+	switch v := val.(type) {
+	case int:
+		days = v
+	case float64:
+		days = int(v)
+	case string:
+		// command arguments arrive as text
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("days must be a number, got %q", v)
+		}
+		days = n
+	default:
+		return 0, fmt.Errorf("days must be a number, got %T", val)
+	}
+	if days <= 0 {
+		return 0, fmt.Errorf("days must be positive")
+	}
+	return days, nil
+}
+
+// FormatAddressProfile formats the profile for a reply
+func FormatAddressProfile(profile *AddressProfile) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Address: %s\n", profile.Address))
+	builder.WriteString(fmt.Sprintf("Traits: %s\n", strings.Join(profile.Traits, ", ")))
+	builder.WriteString(fmt.Sprintf("Transactions: %d (first seen %s, last seen %s)\n",
+		profile.Stats.TxCount, profile.Stats.FirstSeen, profile.Stats.LastSeen))
+	builder.WriteString(fmt.Sprintf("Value in: %.4f ETH, value out: %.4f ETH\n", profile.Stats.ValueIn, profile.Stats.ValueOut))
+	builder.WriteString(fmt.Sprintf("Contract interactions: %.0f%%\n", profile.Stats.ContractRatio()*100))
+
+	if len(profile.Stats.Counterparties) > 0 {
+		builder.WriteString("Top counterparties:\n")
+		for _, c := range profile.Stats.Counterparties {
+			builder.WriteString(fmt.Sprintf("- %s (%d transactions)\n", c.Address, c.TxCount))
+		}
+	}
+
+	if profile.Summary != "" {
+		builder.WriteString("\n")
+		builder.WriteString(profile.Summary)
+	}
+	return builder.String()
+}
@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+const profiledAddress = "0x742d35cc6634c0532925a3b844bc454e4438f44e"
+
+// cannedProvider answers each sub-query with the rows of the first column
+// alias it selects
+type cannedProvider struct {
+	types.DatabaseProvider
+	rows    map[string]map[string]interface{}
+	mu      sync.Mutex
+	queries []string
+}
+
+func (p *cannedProvider) ExecuteQuery(_ context.Context, sql string) (*types.TransactionQueryResult, error) {
+	p.mu.Lock()
+	p.queries = append(p.queries, sql)
+	p.mu.Unlock()
+	for alias, row := range p.rows {
+		if strings.Contains(sql, " as "+alias) {
+			return &types.TransactionQueryResult{Success: true, Data: []interface{}{row}}, nil
+		}
+	}
+	return &types.TransactionQueryResult{Success: true}, nil
+}
+
+// profileLLM writes the profile from the traits line of the prompt
+type profileLLM struct {
+	llm.Client
+	prompts []string
+}
+
+func (f *profileLLM) CreateCompletion(_ context.Context, request llm.CompletionRequest) (string, error) {
+	prompt := request.Messages[len(request.Messages)-1].Content
+	f.prompts = append(f.prompts, prompt)
+	for _, line := range strings.Split(prompt, "\n") {
+		if traits, ok := strings.CutPrefix(line, "Traits derived from the statistics: "); ok {
+			return "This wallet looks like a " + traits + ".", nil
+		}
+	}
+	return "", nil
+}
+
+func TestProfileAddress(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       map[string]map[string]interface{}
+		wantTraits []string
+	}{
+		{
+			name: "whale",
+			rows: map[string]map[string]interface{}{
+				"tx_count":     {"tx_count": 120, "first_seen": "2024-01-01", "last_seen": "2024-12-01"},
+				"value_in":     {"value_in": "2500.5", "value_out": 1200.0},
+				"counterparty": {"counterparty": "0xabc", "tx_count": 40},
+				"sent_count":   {"sent_count": 60, "contract_calls": 6},
+			},
+			wantTraits: []string{TraitWhale},
+		},
+		{
+			name: "contract heavy bot",
+			rows: map[string]map[string]interface{}{
+				"tx_count":   {"tx_count": 5000, "first_seen": "2024-06-01 00:00:00", "last_seen": "2024-06-11 00:00:00"},
+				"value_in":   {"value_in": 3.0, "value_out": 2.5},
+				"sent_count": {"sent_count": 4000, "contract_calls": 3900},
+			},
+			wantTraits: []string{TraitBot, TraitContractHeavy},
+		},
+		{
+			name: "exchange",
+			rows: map[string]map[string]interface{}{
+				"tx_count":   {"tx_count": 20000, "first_seen": "2020-01-01", "last_seen": "2024-12-01"},
+				"value_in":   {"value_in": 10.0, "value_out": 9.0},
+				"sent_count": {"sent_count": 10000, "contract_calls": 100},
+			},
+			wantTraits: []string{TraitExchange},
+		},
+		{
+			name: "normal",
+			rows: map[string]map[string]interface{}{
+				"tx_count":   {"tx_count": 30, "first_seen": "2024-01-01", "last_seen": "2024-12-01"},
+				"value_in":   {"value_in": 1.5, "value_out": 1.2},
+				"sent_count": {"sent_count": 15, "contract_calls": 3},
+			},
+			wantTraits: []string{TraitNormal},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &cannedProvider{rows: tt.rows}
+			client := &profileLLM{}
+			action := NewProfileAddressAction(provider, client, "test-model", 0)
+
+			profile, err := action.Profile(context.Background(), profiledAddress, defaultProfileDays)
+			if err != nil {
+				t.Fatalf("Profile() error = %v", err)
+			}
+
+			if len(provider.queries) != 4 {
+				t.Errorf("ran %d sub-queries, want 4", len(provider.queries))
+			}
+			for _, query := range provider.queries {
+				if !strings.Contains(query, "'"+profiledAddress+"'") {
+					t.Errorf("sub-query does not filter on the lowercased address:\n%s", query)
+				}
+			}
+			if strings.Join(profile.Traits, ",") != strings.Join(tt.wantTraits, ",") {
+				t.Errorf("traits = %v, want %v", profile.Traits, tt.wantTraits)
+			}
+
+			formatted := FormatAddressProfile(profile)
+			for _, trait := range tt.wantTraits {
+				if !strings.Contains(profile.Summary, trait) {
+					t.Errorf("profile %q does not mention %s", profile.Summary, trait)
+				}
+				if !strings.Contains(formatted, trait) {
+					t.Errorf("formatted profile does not mention %s:\n%s", trait, formatted)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileAddressValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr bool
+	}{
+		{name: "address", params: map[string]interface{}{"address": profiledAddress}},
+		{name: "address and days", params: map[string]interface{}{"address": profiledAddress, "days": float64(30)}},
+		{name: "missing address", params: map[string]interface{}{}, wantErr: true},
+		{name: "invalid address", params: map[string]interface{}{"address": "0x123"}, wantErr: true},
+		{name: "negative days", params: map[string]interface{}{"address": profiledAddress, "days": -1}, wantErr: true},
+		{name: "days from a command argument", params: map[string]interface{}{"address": profiledAddress, "days": " 30 "}},
+		{name: "days as words", params: map[string]interface{}{"address": profiledAddress, "days": "thirty"}, wantErr: true},
+		{name: "days as a list", params: map[string]interface{}{"address": profiledAddress, "days": []int{30}}, wantErr: true},
+	}
+
+	action := NewProfileAddressAction(&cannedProvider{}, &profileLLM{}, "test-model", 0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := action.Validate(tt.params); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// Ensure QueryTemplateAction implements actions.IAction
+var _ actions.IAction = (*QueryTemplateAction)(nil)
+
+// Query template parameter types
+const (
+	TemplateParamInt     = "int"
+	TemplateParamString  = "string"
+	TemplateParamAddress = "address"
+)
+
+// templatePlaceholder matches a named parameter such as {{days}}
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// QueryTemplate is a reusable SQL query with named parameters
+type QueryTemplate struct {
+	Description string
+	SQL         string
+	// Params maps each parameter name to its type: int, string or address
+	Params map[string]string
+}
+
+// Validate checks every placeholder of the SQL is a declared parameter of a known type
+func (t QueryTemplate) Validate() error {
+	if strings.TrimSpace(t.SQL) == "" {
+		return fmt.Errorf("sql is required")
+	}
+	for name, paramType := range t.Params {
+		switch paramType {
+		case TemplateParamInt, TemplateParamString, TemplateParamAddress:
+		default:
+			return fmt.Errorf("parameter %s has unknown type %q", name, paramType)
+		}
+	}
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(t.SQL, -1) {
+		if _, ok := t.Params[match[1]]; !ok {
+			return fmt.Errorf("placeholder %s is not a declared parameter", match[1])
+		}
+	}
+	return nil
+}
+
+// Render substitutes the parameters into the SQL, checking each against its type
+func (t QueryTemplate) Render(params map[string]interface{}) (string, error) {
+	values := make(map[string]string, len(t.Params))
+	for name, paramType := range t.Params {
+		val, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s is required", types.ErrInvalidTemplateParam, name)
+		}
+		literal, err := templateLiteral(paramType, val)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s %v", types.ErrInvalidTemplateParam, name, err)
+		}
+		values[name] = literal
+	}
+
+	return templatePlaceholder.ReplaceAllStringFunc(t.SQL, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		return values[name]
+	}), nil
+}
+
+// templateLiteral converts a parameter value to a SQL literal of the given type
+func templateLiteral(paramType string, val interface{}) (string, error) {
+	switch paramType {
+	case TemplateParamInt:
+		switch v := val.(type) {
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			if v != math.Trunc(v) {
+				return "", fmt.Errorf("must be an integer, got %v", v)
+			}
+			return strconv.FormatInt(int64(v), 10), nil
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("must be an integer, got %q", v)
+			}
+			return strconv.FormatInt(n, 10), nil
+		default:
+			return "", fmt.Errorf("must be an integer, got %T", val)
+		}
+	case TemplateParamAddress:
+		address, ok := val.(string)
+		if !ok {
+			return "", fmt.Errorf("must be an address, got %T", val)
+		}
+		if err := validateAddress(address); err != nil {
+			return "", err
+		}
+		return "'" + strings.ToLower(address) + "'", nil
+	case TemplateParamString:
+		str, ok := val.(string)
+		if !ok {
+			return "", fmt.Errorf("must be a string, got %T", val)
+		}
+		return "'" + strings.ReplaceAll(str, "'", "''") + "'", nil
+	default:
+		return "", fmt.Errorf("has unknown type %q", paramType)
+	}
+}
+
+// QueryTemplateAction runs a configured query template without generating SQL
+// with the LLM, for cheap and deterministic repeated analytics
+type QueryTemplateAction struct {
+	name        string
+	description string
+	dbProvider  types.DatabaseProvider
+	templates   map[string]QueryTemplate
+	resultOutput
+}
+
+// NewQueryTemplateAction creates a new query template action
+func NewQueryTemplateAction(dbProvider types.DatabaseProvider, templates map[string]QueryTemplate) *QueryTemplateAction {
+	return &QueryTemplateAction{
+		name:        "run_query_template",
+		description: "Run a predefined transaction query by name with the given parameters",
+		dbProvider:  dbProvider,
+		templates:   templates,
+	}
+}
+
+func (a *QueryTemplateAction) Name() string {
+	return a.name
+}
+
+func (a *QueryTemplateAction) Description() string {
+	return a.description
+}
+
+func (a *QueryTemplateAction) Type() string {
+	return "run_query_template"
+}
+
+func (a *QueryTemplateAction) ParametersPrompt() string {
+	names := make([]string, 0, len(a.templates))
+	for name := range a.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	builder.WriteString("\n\t# Parameters:\n")
+	builder.WriteString("\t- template: string (one of the templates below)\n")
+	builder.WriteString("\t- params: object (the parameters of the template)\n")
+	builder.WriteString("\t# Templates:\n")
+	for _, name := range names {
+		tmpl := a.templates[name]
+		paramNames := make([]string, 0, len(tmpl.Params))
+		for param, paramType := range tmpl.Params {
+			paramNames = append(paramNames, fmt.Sprintf("%s: %s", param, paramType))
+		}
+		sort.Strings(paramNames)
+		builder.WriteString(fmt.Sprintf("\t- %s (%s): %s\n", name, strings.Join(paramNames, ", "), tmpl.Description))
+	}
+	return builder.String()
+}
+
+func (a *QueryTemplateAction) Validate(params map[string]interface{}) error {
+	_, err := a.render(params)
+	return err
+}
+
+// Execute runs the template and logs the result
+func (a *QueryTemplateAction) Execute(ctx context.Context, params map[string]interface{}) error {
+	result, err := a.Run(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	formatted := FormatQueryResult(result)
+	logger.GetLogger().Infow("Query template executed",
+		"template", params["template"],
+		"result", formatted,
+	)
+	a.emit(ctx, a.name, formatted, params)
+	return nil
+}
+
+// Run renders the requested template and executes it
+func (a *QueryTemplateAction) Run(ctx context.Context, params map[string]interface{}) (*types.TransactionQueryResult, error) {
+	query, err := a.render(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.dbProvider.ExecuteQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query template: %w", err)
+	}
+	return result, nil
+}
+
+// render looks up the template named by the template parameter and renders it
+func (a *QueryTemplateAction) render(params map[string]interface{}) (string, error) {
+	name, ok := params["template"].(string)
+	if !ok || name == "" {
+		return "", fmt.Errorf("template parameter is required")
+	}
+	tmpl, ok := a.templates[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", types.ErrUnknownQueryTemplate, name)
+	}
+
+	templateParams, _ := params["params"].(map[string]interface{})
+	return tmpl.Render(templateParams)
+}
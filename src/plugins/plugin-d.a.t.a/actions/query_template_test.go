@@ -0,0 +1,127 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+var testTemplates = map[string]QueryTemplate{
+	"top_senders": {
+		Description: "Addresses sending the most transactions",
+		SQL:         "SELECT from_address FROM eth.transactions WHERE date >= {{days}} AND to_address = {{ to }} AND note = {{note}} LIMIT {{days}};",
+		Params:      map[string]string{"days": TemplateParamInt, "to": TemplateParamAddress, "note": TemplateParamString},
+	},
+}
+
+func TestQueryTemplateRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "typed params",
+			params: map[string]interface{}{"days": 7, "to": "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", "note": "gm"},
+			want:   "SELECT from_address FROM eth.transactions WHERE date >= 7 AND to_address = '0x742d35cc6634c0532925a3b844bc454e4438f44e' AND note = 'gm' LIMIT 7;",
+		},
+		{
+			name:   "JSON numbers and quoted strings",
+			params: map[string]interface{}{"days": float64(30), "to": "0x742d35cc6634c0532925a3b844bc454e4438f44e", "note": "it's"},
+			want:   "SELECT from_address FROM eth.transactions WHERE date >= 30 AND to_address = '0x742d35cc6634c0532925a3b844bc454e4438f44e' AND note = 'it''s' LIMIT 30;",
+		},
+		{
+			name:    "missing param",
+			params:  map[string]interface{}{"days": 7, "note": "gm"},
+			wantErr: types.ErrInvalidTemplateParam,
+		},
+		{
+			name:    "fractional int",
+			params:  map[string]interface{}{"days": 1.5, "to": "0x742d35cc6634c0532925a3b844bc454e4438f44e", "note": "gm"},
+			wantErr: types.ErrInvalidTemplateParam,
+		},
+		{
+			name:    "text int",
+			params:  map[string]interface{}{"days": "7 OR 1=1", "to": "0x742d35cc6634c0532925a3b844bc454e4438f44e", "note": "gm"},
+			wantErr: types.ErrInvalidTemplateParam,
+		},
+		{
+			name:    "invalid address",
+			params:  map[string]interface{}{"days": 7, "to": "0x123", "note": "gm"},
+			wantErr: types.ErrInvalidTemplateParam,
+		},
+		{
+			name:    "number as string",
+			params:  map[string]interface{}{"days": 7, "to": "0x742d35cc6634c0532925a3b844bc454e4438f44e", "note": 42},
+			wantErr: types.ErrInvalidTemplateParam,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testTemplates["top_senders"].Render(tt.params)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Render() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryTemplateActionRun(t *testing.T) {
+	provider := &fakeProvider{rows: []interface{}{map[string]interface{}{"from_address": "0x1"}}}
+	action := NewQueryTemplateAction(provider, testTemplates)
+
+	result, err := action.Run(context.Background(), map[string]interface{}{
+		"template": "top_senders",
+		"params":   map[string]interface{}{"days": 7, "to": "0x742d35cc6634c0532925a3b844bc454e4438f44e", "note": "gm"},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(provider.queries) != 1 || provider.queries[0] != "SELECT from_address FROM eth.transactions WHERE date >= 7 AND to_address = '0x742d35cc6634c0532925a3b844bc454e4438f44e' AND note = 'gm' LIMIT 7;" {
+		t.Errorf("queries = %q, want the rendered template", provider.queries)
+	}
+	if len(provider.prompts) != 0 {
+		t.Errorf("generated queries with the LLM for %q, want none", provider.prompts)
+	}
+	if len(result.Data) != 1 {
+		t.Errorf("result has %d rows, want 1", len(result.Data))
+	}
+}
+
+func TestQueryTemplateActionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		wantErr error
+	}{
+		{name: "unknown template", params: map[string]interface{}{"template": "bottom_senders"}, wantErr: types.ErrUnknownQueryTemplate},
+		{name: "missing params", params: map[string]interface{}{"template": "top_senders"}, wantErr: types.ErrInvalidTemplateParam},
+	}
+
+	action := NewQueryTemplateAction(&fakeProvider{}, testTemplates)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := action.Validate(tt.params); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := action.Validate(map[string]interface{}{}); err == nil {
+		t.Error("Validate() without a template error = nil, want an error")
+	}
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultQueryConcurrency is how many sub-queries run at once when no limit is configured
+const DefaultQueryConcurrency = 4
+
+// SubQuery is a named query that does not depend on the results of other queries
+type SubQuery struct {
+	Name string
+	SQL  string
+}
+
+// RunSubQueries executes independent sub-queries concurrently, at most limit
+// at a time. Results are returned in the order of the queries; the first
+// failure cancels the remaining queries and is returned.
+func RunSubQueries(ctx context.Context, provider types.DatabaseProvider, queries []SubQuery, limit int) ([]*types.TransactionQueryResult, error) {
+	if limit <= 0 {
+		limit = DefaultQueryConcurrency
+	}
+
+	results := make([]*types.TransactionQueryResult, len(queries))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+
+	for i, query := range queries {
+		g.Go(func() error {
+			result, err := provider.ExecuteQuery(gctx, query.SQL)
+			if err != nil {
+				return fmt.Errorf("failed to query %s: %w", query.Name, err)
+			}
+			results[i] = result
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// resultRows returns the rows of a query result that are JSON objects
+func resultRows(result *types.TransactionQueryResult) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(result.Data))
+	for _, item := range result.Data {
+		if row, ok := types.RowOf(item); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// slowProvider takes delay to answer each query with the query itself, and
+// fails the queries in failing
+type slowProvider struct {
+	types.DatabaseProvider
+	delay   time.Duration
+	failing map[string]error
+
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (p *slowProvider) ExecuteQuery(ctx context.Context, sql string) (*types.TransactionQueryResult, error) {
+	p.mu.Lock()
+	p.running++
+	p.maxRunning = max(p.maxRunning, p.running)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+
+	if err := p.failing[sql]; err != nil {
+		return nil, err
+	}
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &types.TransactionQueryResult{Success: true, Data: []interface{}{sql}}, nil
+}
+
+func subQueries(n int) []SubQuery {
+	queries := make([]SubQuery, n)
+	for i := range queries {
+		queries[i] = SubQuery{Name: fmt.Sprintf("query %d", i), SQL: fmt.Sprintf("SELECT %d;", i)}
+	}
+	return queries
+}
+
+func TestRunSubQueriesConcurrently(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		wantRunning int
+	}{
+		{name: "all at once", limit: 4, wantRunning: 4},
+		{name: "bounded", limit: 2, wantRunning: 2},
+		{name: "default limit", limit: 0, wantRunning: DefaultQueryConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const delay = 100 * time.Millisecond
+			provider := &slowProvider{delay: delay}
+			queries := subQueries(4)
+
+			start := time.Now()
+			results, err := RunSubQueries(context.Background(), provider, queries, tt.limit)
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("RunSubQueries() error = %v", err)
+			}
+
+			if provider.maxRunning != tt.wantRunning {
+				t.Errorf("%d queries ran at once, want %d", provider.maxRunning, tt.wantRunning)
+			}
+			// Serial execution would take a delay per query
+			if batches := len(queries) / tt.wantRunning; elapsed >= time.Duration(batches+1)*delay {
+				t.Errorf("queries took %v, want about %v", elapsed, time.Duration(batches)*delay)
+			}
+			for i, result := range results {
+				if result.Data[0] != queries[i].SQL {
+					t.Errorf("result %d = %v, want the result of %q", i, result.Data[0], queries[i].SQL)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSubQueriesReturnsFirstError(t *testing.T) {
+	errQuery := errors.New("query failed")
+	queries := subQueries(4)
+	provider := &slowProvider{delay: time.Minute, failing: map[string]error{queries[2].SQL: errQuery}}
+
+	start := time.Now()
+	results, err := RunSubQueries(context.Background(), provider, queries, 4)
+	if !errors.Is(err, errQuery) {
+		t.Fatalf("RunSubQueries() error = %v, want %v", err, errQuery)
+	}
+	if results != nil {
+		t.Errorf("results = %v, want none", results)
+	}
+	// The failure cancels the slow queries instead of waiting for them
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("RunSubQueries() returned after %v, want the other queries cancelled", elapsed)
+	}
+}
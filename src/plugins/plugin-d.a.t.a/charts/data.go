@@ -0,0 +1,158 @@
+package charts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+const defaultTopSenders = 10
+
+var (
+	// ErrChartsUnavailable is returned when the binary was built without the charts tag
+	ErrChartsUnavailable = errors.New("chart rendering not available, build with -tags charts")
+	// ErrNoChartData is returned when the result has no rows usable for the chart
+	ErrNoChartData = errors.New("no chart data in query result")
+)
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// gasPriceSeries extracts gas price points ordered by time, supporting both raw
+// transaction rows (block_timestamp, gas_price) and hourly aggregates (hour, avg_gas_price)
+func gasPriceSeries(result *types.TransactionQueryResult) ([]time.Time, []float64, error) {
+	if result == nil {
+		return nil, nil, ErrNoChartData
+	}
+
+	type point struct {
+		t time.Time
+		v float64
+	}
+
+	var points []point
+	for _, row := range result.Data {
+		rowMap, ok := types.RowOf(row)
+		if !ok {
+			continue
+		}
+
+		t, ok := parseTime(firstOf(rowMap, "block_timestamp", "hour"))
+		if !ok {
+			continue
+		}
+		v, ok := parseFloat(firstOf(rowMap, "gas_price", "avg_gas_price"))
+		if !ok {
+			continue
+		}
+		points = append(points, point{t: t, v: v})
+	}
+
+	if len(points) == 0 {
+		return nil, nil, ErrNoChartData
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].t.Before(points[j].t) })
+
+	xs := make([]time.Time, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.t
+		ys[i] = p.v
+	}
+	return xs, ys, nil
+}
+
+// topSenders ranks sending addresses by transaction count, using a tx_count
+// column when present and counting rows otherwise
+func topSenders(result *types.TransactionQueryResult, n int) ([]string, []float64, error) {
+	if result == nil {
+		return nil, nil, ErrNoChartData
+	}
+	if n <= 0 {
+		n = defaultTopSenders
+	}
+
+	counts := make(map[string]float64)
+	for _, row := range result.Data {
+		rowMap, ok := types.RowOf(row)
+		if !ok {
+			continue
+		}
+
+		sender, ok := rowMap["from_address"].(string)
+		if !ok || sender == "" {
+			continue
+		}
+		if count, ok := parseFloat(rowMap["tx_count"]); ok {
+			counts[sender] += count
+		} else {
+			counts[sender]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return nil, nil, ErrNoChartData
+	}
+
+	senders := make([]string, 0, len(counts))
+	for sender := range counts {
+		senders = append(senders, sender)
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		if counts[senders[i]] == counts[senders[j]] {
+			return senders[i] < senders[j]
+		}
+		return counts[senders[i]] > counts[senders[j]]
+	})
+	if len(senders) > n {
+		senders = senders[:n]
+	}
+
+	values := make([]float64, len(senders))
+	for i, sender := range senders {
+		values[i] = counts[sender]
+	}
+	return senders, values, nil
+}
+
+func firstOf(row types.Row, keys ...string) interface{} {
+	for _, key := range keys {
+		if v, ok := row[key]; ok && v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+func parseTime(v interface{}) (time.Time, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseFloat(v interface{}) (float64, bool) {
+	return types.ToFloat(v)
+}
+
+// shortAddress abbreviates an address for axis labels
+func shortAddress(address string) string {
+	if len(address) <= 10 {
+		return address
+	}
+	return fmt.Sprintf("%s…%s", address[:6], address[len(address)-4:])
+}
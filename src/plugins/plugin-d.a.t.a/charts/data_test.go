@@ -0,0 +1,92 @@
+package charts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// transactionResult wraps rows the way the query action returns them
+func transactionResult(rows ...map[string]interface{}) *types.TransactionQueryResult {
+	result := &types.TransactionQueryResult{Success: true}
+	for _, row := range rows {
+		result.Data = append(result.Data, row)
+	}
+	return result
+}
+
+func TestGasPriceSeries(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *types.TransactionQueryResult
+		want    []float64
+		wantErr error
+	}{
+		{
+			name: "raw transactions sorted by time",
+			result: transactionResult(
+				map[string]interface{}{"block_timestamp": "2024-01-02 10:00:00", "gas_price": "30"},
+				map[string]interface{}{"block_timestamp": "2024-01-01 10:00:00", "gas_price": 20.0},
+			),
+			want: []float64{20, 30},
+		},
+		{
+			name: "hourly aggregates",
+			result: transactionResult(
+				map[string]interface{}{"hour": "2024-01-01T10:00:00Z", "avg_gas_price": 12.5},
+			),
+			want: []float64{12.5},
+		},
+		{
+			name:    "rows without gas prices",
+			result:  transactionResult(map[string]interface{}{"block_timestamp": "2024-01-01"}),
+			wantErr: ErrNoChartData,
+		},
+		{name: "nil result", wantErr: ErrNoChartData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := gasPriceSeries(tt.result)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("gasPriceSeries() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("gasPriceSeries() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("gasPriceSeries() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestTopSenders(t *testing.T) {
+	result := transactionResult(
+		map[string]interface{}{"from_address": "0xbbb"},
+		map[string]interface{}{"from_address": "0xaaa"},
+		map[string]interface{}{"from_address": "0xbbb"},
+		map[string]interface{}{"from_address": "0xccc", "tx_count": "5"},
+	)
+
+	senders, counts, err := topSenders(result, 2)
+	if err != nil {
+		t.Fatalf("topSenders() error = %v", err)
+	}
+
+	wantSenders := []string{"0xccc", "0xbbb"}
+	wantCounts := []float64{5, 2}
+	if len(senders) != len(wantSenders) {
+		t.Fatalf("topSenders() = %v, want %v", senders, wantSenders)
+	}
+	for i := range senders {
+		if senders[i] != wantSenders[i] || counts[i] != wantCounts[i] {
+			t.Errorf("topSenders() = %v %v, want %v %v", senders, counts, wantSenders, wantCounts)
+			break
+		}
+	}
+}
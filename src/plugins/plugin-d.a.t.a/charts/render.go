@@ -0,0 +1,93 @@
+//go:build charts
+
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+
+	chart "github.com/wcharczuk/go-chart/v2"
+)
+
+const (
+	chartWidth  = 1024
+	chartHeight = 512
+)
+
+// RenderGasPriceChart renders gas price over time as a PNG line chart
+func RenderGasPriceChart(result *types.TransactionQueryResult) ([]byte, error) {
+	xs, ys, err := gasPriceSeries(result)
+	if err != nil {
+		return nil, err
+	}
+	// A line needs at least two points
+	if len(xs) == 1 {
+		xs = append(xs, xs[0])
+		ys = append(ys, ys[0])
+	}
+
+	graph := chart.Chart{
+		Title:  "Gas Price",
+		Width:  chartWidth,
+		Height: chartHeight,
+		XAxis: chart.XAxis{
+			Name:           "Time",
+			ValueFormatter: chart.TimeHourValueFormatter,
+		},
+		YAxis: chart.YAxis{
+			Name: "Gas Price (wei)",
+		},
+		Series: []chart.Series{
+			chart.TimeSeries{
+				Name:    "gas_price",
+				XValues: xs,
+				YValues: ys,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("failed to render gas price chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderTopSendersChart renders the n most active senders as a PNG bar chart
+func RenderTopSendersChart(result *types.TransactionQueryResult, n int) ([]byte, error) {
+	senders, counts, err := topSenders(result, n)
+	if err != nil {
+		return nil, err
+	}
+
+	bars := make([]chart.Value, len(senders))
+	var maxCount float64
+	for i, sender := range senders {
+		bars[i] = chart.Value{
+			Label: shortAddress(sender),
+			Value: counts[i],
+		}
+		maxCount = math.Max(maxCount, counts[i])
+	}
+
+	graph := chart.BarChart{
+		Title:    "Top Senders",
+		Width:    chartWidth,
+		Height:   chartHeight,
+		BarWidth: 60,
+		Bars:     bars,
+		// Anchor the axis at zero, equal counts would otherwise give an empty range
+		YAxis: chart.YAxis{
+			Range: &chart.ContinuousRange{Min: 0, Max: maxCount},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("failed to render top senders chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
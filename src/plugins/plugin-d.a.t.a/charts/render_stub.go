@@ -0,0 +1,17 @@
+//go:build !charts
+
+package charts
+
+import (
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
+)
+
+// RenderGasPriceChart is unavailable without the charts build tag
+func RenderGasPriceChart(result *types.TransactionQueryResult) ([]byte, error) {
+	return nil, ErrChartsUnavailable
+}
+
+// RenderTopSendersChart is unavailable without the charts build tag
+func RenderTopSendersChart(result *types.TransactionQueryResult, n int) ([]byte, error) {
+	return nil, ErrChartsUnavailable
+}
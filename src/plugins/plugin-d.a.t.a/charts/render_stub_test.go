@@ -0,0 +1,19 @@
+//go:build !charts
+
+package charts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenderChartsUnavailable(t *testing.T) {
+	result := transactionResult(map[string]interface{}{"from_address": "0xaaa"})
+
+	if _, err := RenderGasPriceChart(result); !errors.Is(err, ErrChartsUnavailable) {
+		t.Errorf("RenderGasPriceChart() error = %v, want %v", err, ErrChartsUnavailable)
+	}
+	if _, err := RenderTopSendersChart(result, 5); !errors.Is(err, ErrChartsUnavailable) {
+		t.Errorf("RenderTopSendersChart() error = %v, want %v", err, ErrChartsUnavailable)
+	}
+}
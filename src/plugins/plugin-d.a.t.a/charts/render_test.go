@@ -0,0 +1,37 @@
+//go:build charts
+
+package charts
+
+import (
+	"bytes"
+	"testing"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func TestRenderCharts(t *testing.T) {
+	result := transactionResult(
+		map[string]interface{}{"block_timestamp": "2024-01-01 10:00:00", "gas_price": "20", "from_address": "0xaaa"},
+		map[string]interface{}{"block_timestamp": "2024-01-01 11:00:00", "gas_price": "30", "from_address": "0xbbb"},
+	)
+
+	tests := []struct {
+		name   string
+		render func() ([]byte, error)
+	}{
+		{name: "gas price", render: func() ([]byte, error) { return RenderGasPriceChart(result) }},
+		{name: "top senders", render: func() ([]byte, error) { return RenderTopSendersChart(result, 5) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			png, err := tt.render()
+			if err != nil {
+				t.Fatalf("render error = %v", err)
+			}
+			if !bytes.HasPrefix(png, pngSignature) {
+				t.Errorf("rendered %d bytes, want a PNG", len(png))
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package data
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/sinks"
 	walletactions "github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/actions"
 	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/providers"
 
@@ -22,6 +26,18 @@ const (
 	ConfigKeyLLM       = "llm"        // LLM configuration section
 )
 
+// Optional configuration keys
+const (
+	ConfigKeyMaxQueryLength      = "max_query_length"      // maps to DatabaseConfig.MaxQueryLength
+	ConfigKeyDefaultLookbackDays = "default_lookback_days" // maps to DatabaseConfig.DefaultLookbackDays
+	ConfigKeyMaxResultLimit      = "max_result_limit"      // maps to DatabaseConfig.MaxResultLimit
+	ConfigKeyMaxResultPages      = "max_result_pages"      // maps to DatabaseConfig.MaxResultPages
+	ConfigKeyQueryConcurrency    = "query_concurrency"     // limit of sub-queries an action runs at once
+	ConfigKeyQueryTemplates      = "query_templates"       // named SQL queries for the run_query_template action
+	ConfigKeyOutputSinks         = "output_sinks"          // webhook and file sinks receiving action results, by action name
+	ConfigKeyAuth                = "auth"                  // maps to DatabaseConfig.Auth
+)
+
 // dataPlugin implements the core.Plugin interface for data functionality
 type dataPlugin struct {
 	llmClient  llm.Client
@@ -37,42 +53,136 @@ type dataPlugin struct {
 func NewPlugin(llmClient llm.Client, config *plugins.Config) (plugins.Plugin, error) {
 	logger := logger.GetLogger().With(zap.String("plugin", "d.a.t.a"))
 
+	if config == nil {
+		return nil, fmt.Errorf("invalid plugin configuration: config cannot be nil")
+	}
 	if err := validateConfig(config.Options); err != nil {
 		return nil, fmt.Errorf("invalid plugin configuration: %w", err)
 	}
 
+	apiURL, err := stringOption(config.Options, ConfigKeyAPIURL)
+	if err != nil {
+		return nil, err
+	}
+	authToken, err := stringOption(config.Options, ConfigKeyAuthToken)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := stringOption(config.Options, ConfigKeyChain)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize provider
-	llmConfig, ok := config.Options[ConfigKeyLLM].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid LLM configuration type: expected map[string]interface{}")
+	llmConfig, err := mapOption(config.Options, ConfigKeyLLM)
+	if err != nil {
+		return nil, err
+	}
+	model, err := stringOption(llmConfig, "model")
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM configuration: %w", err)
+	}
+	queryModel, err := taskModelOption(llmConfig, "query_model", config.Models["query"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM configuration: %w", err)
+	}
+	analysisModel, err := taskModelOption(llmConfig, "analysis_model", config.Models["analysis"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM configuration: %w", err)
+	}
+	var querySystemPrompt string
+	if _, ok := llmConfig["query_system_prompt"]; ok {
+		if querySystemPrompt, err = stringOption(llmConfig, "query_system_prompt"); err != nil {
+			return nil, fmt.Errorf("invalid LLM configuration: %w", err)
+		}
+	}
+
+	maxQueryLength, err := intOption(config.Options, ConfigKeyMaxQueryLength)
+	if err != nil {
+		return nil, err
 	}
 
-	model, ok := llmConfig["model"].(string)
-	if !ok || model == "" {
-		return nil, fmt.Errorf("invalid or missing model in LLM configuration")
+	lookbackDays, err := intOption(config.Options, ConfigKeyDefaultLookbackDays)
+	if err != nil {
+		return nil, err
+	}
+
+	maxResultLimit, err := intOption(config.Options, ConfigKeyMaxResultLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	maxResultPages, err := intOption(config.Options, ConfigKeyMaxResultPages)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := authOption(config.Options, ConfigKeyAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	queryConcurrency, err := intOption(config.Options, ConfigKeyQueryConcurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	queryTemplates, err := queryTemplatesOption(config.Options, ConfigKeyQueryTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	outputSinks, err := outputSinksOption(config.Options, ConfigKeyOutputSinks)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create provider using factory
 	provider := providers.NewDatabaseProvider(
 		"ethereum_database_provider",
-		config.Options[ConfigKeyAPIURL].(string),
-		config.Options[ConfigKeyAuthToken].(string),
-		config.Options[ConfigKeyChain].(string),
-		getDefaultDatabaseSchema(),
-		getDefaultQueryExamples(),
+		apiURL,
+		authToken,
+		chain,
+		providers.DefaultDatabaseSchema(),
+		providers.DefaultQueryExamples(),
 		llmClient,
 		model,
+		&providers.DatabaseConfig{
+			MaxQueryLength:      maxQueryLength,
+			DefaultLookbackDays: lookbackDays,
+			MaxResultLimit:      maxResultLimit,
+			MaxResultPages:      maxResultPages,
+			QueryModel:          queryModel,
+			AnalysisModel:       analysisModel,
+			QuerySystemPrompt:   querySystemPrompt,
+			Auth:                auth,
+			Retry:               config.Retry,
+		},
 		logger,
 	)
 
-	// Create action using factory
-	action := walletactions.NewFetchTransactionAction(provider)
+	// Create actions using factory
+	pluginActions := []actions.IAction{
+		walletactions.NewFetchTransactionAction(provider),
+		walletactions.NewGetTransactionAction(provider),
+		walletactions.NewProfileAddressAction(provider, llmClient, modelOr(analysisModel, model), queryConcurrency),
+		walletactions.NewCompareAddressesAction(provider, llmClient, modelOr(analysisModel, model), queryConcurrency),
+	}
+	if len(queryTemplates) > 0 {
+		pluginActions = append(pluginActions, walletactions.NewQueryTemplateAction(provider, queryTemplates))
+	}
+	if config.Publisher != nil {
+		pluginActions = append(pluginActions, walletactions.NewPostDigestAction(provider, config.Publisher))
+	}
+	if err := applyOutputSinks(pluginActions, outputSinks); err != nil {
+		return nil, err
+	}
 
 	return &dataPlugin{
 		llmClient: llmClient,
 		logger:    logger,
 		providers: []plugins.Provider{provider},
-		actions:   []actions.IAction{action},
+		actions:   pluginActions,
 		metadata: plugins.PluginMetadata{
 			Name:        "d.a.t.a",
 			Description: "Data interaction plugin",
@@ -127,34 +237,269 @@ func (p *dataPlugin) Init(ctx context.Context) error {
 
 // validateConfig validates the plugin configuration
 func validateConfig(opts map[string]interface{}) error {
-	required := []string{ConfigKeyAPIURL, ConfigKeyAuthToken, ConfigKeyChain, ConfigKeyLLM}
-	for _, key := range required {
-		val, ok := opts[key]
+	for _, key := range []string{ConfigKeyAPIURL, ConfigKeyAuthToken, ConfigKeyChain} {
+		if _, err := stringOption(opts, key); err != nil {
+			return err
+		}
+	}
+
+	llmConfig, err := mapOption(opts, ConfigKeyLLM)
+	if err != nil {
+		return err
+	}
+	if _, err := stringOption(llmConfig, "model"); err != nil {
+		return fmt.Errorf("invalid LLM configuration: %w", err)
+	}
+	return nil
+}
+
+// stringOption returns a required non-empty string option
+func stringOption(opts map[string]interface{}, key string) (string, error) {
+	val, ok := opts[key]
+	if !ok {
+		return "", fmt.Errorf("missing required configuration: %s", key)
+	}
+	strVal, ok := val.(string)
+	if !ok || strVal == "" {
+		return "", fmt.Errorf("invalid configuration value for %s: must be a non-empty string, got %T", key, val)
+	}
+	return strVal, nil
+}
+
+// authOption returns the optional auth scheme of the data API requests
+func authOption(opts map[string]interface{}, key string) (providers.AuthConfig, error) {
+	var auth providers.AuthConfig
+	if _, ok := opts[key]; !ok {
+		return auth, nil
+	}
+	section, err := mapOption(opts, key)
+	if err != nil {
+		return auth, err
+	}
+	for name, field := range map[string]*string{"scheme": &auth.Scheme, "header": &auth.Header, "format": &auth.Format} {
+		if _, ok := section[name]; !ok {
+			continue
+		}
+		if *field, err = stringOption(section, name); err != nil {
+			return auth, fmt.Errorf("invalid auth configuration: %w", err)
+		}
+	}
+	if err := auth.Validate(); err != nil {
+		return auth, fmt.Errorf("invalid auth configuration: %w", err)
+	}
+	return auth, nil
+}
+
+// taskModelOption returns the optional model override of a task, falling back
+// to the model configured for the task in llm_config; empty means the plugin model
+func taskModelOption(llmConfig map[string]interface{}, key, fallback string) (string, error) {
+	if _, ok := llmConfig[key]; !ok {
+		return fallback, nil
+	}
+	return stringOption(llmConfig, key)
+}
+
+// modelOr returns model, or fallback when model is empty
+func modelOr(model, fallback string) string {
+	if model == "" {
+		return fallback
+	}
+	return model
+}
+
+// intOption returns an optional positive integer option, 0 when unset
+func intOption(opts map[string]interface{}, key string) (int, error) {
+	val, ok := opts[key]
+	if !ok {
+		return 0, nil
+	}
+
+	var intVal int
+	switch v := val.(type) {
+	case int:
+		intVal = v
+	case int64:
+		intVal = int(v)
+	case float64:
+		intVal = int(v)
+	default:
+		return 0, fmt.Errorf("invalid configuration value for %s: must be an integer, got %T", key, val)
+	}
+	if intVal <= 0 {
+		return 0, fmt.Errorf("invalid configuration value for %s: must be positive", key)
+	}
+	return intVal, nil
+}
+
+// mapOption returns a required map option, accepting both map types produced by YAML decoders
+func mapOption(opts map[string]interface{}, key string) (map[string]interface{}, error) {
+	val, ok := opts[key]
+	if !ok {
+		return nil, fmt.Errorf("missing required configuration: %s", key)
+	}
+
+	m, ok := stringMap(val)
+	if !ok {
+		return nil, fmt.Errorf("invalid configuration value for %s: must be a map, got %T", key, val)
+	}
+	return m, nil
+}
+
+// stringMap converts both map types produced by YAML decoders to map[string]interface{}
+func stringMap(val interface{}) (map[string]interface{}, bool) {
+	switch m := val.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			if kStr, ok := k.(string); ok {
+				converted[kStr] = v
+			}
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+// queryTemplatesOption returns the optional query templates, keyed by name
+func queryTemplatesOption(opts map[string]interface{}, key string) (map[string]walletactions.QueryTemplate, error) {
+	if _, ok := opts[key]; !ok {
+		return nil, nil
+	}
+	section, err := mapOption(opts, key)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]walletactions.QueryTemplate, len(section))
+	for name := range section {
+		entry, err := mapOption(section, name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query template %s: %w", name, err)
+		}
+		sql, err := stringOption(entry, "sql")
+		if err != nil {
+			return nil, fmt.Errorf("invalid query template %s: %w", name, err)
+		}
+
+		tmpl := walletactions.QueryTemplate{
+			SQL:    sql,
+			Params: make(map[string]string),
+		}
+		tmpl.Description, _ = entry["description"].(string)
+		if _, ok := entry["params"]; ok {
+			params, err := mapOption(entry, "params")
+			if err != nil {
+				return nil, fmt.Errorf("invalid query template %s: %w", name, err)
+			}
+			for param := range params {
+				paramType, err := stringOption(params, param)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query template %s: %w", name, err)
+				}
+				tmpl.Params[param] = paramType
+			}
+		}
+
+		if err := tmpl.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid query template %s: %w", name, err)
+		}
+		templates[name] = tmpl
+	}
+	return templates, nil
+}
+
+// outputSinksOption returns the optional output sinks, keyed by action name
+func outputSinksOption(opts map[string]interface{}, key string) (map[string][]plugins.OutputSink, error) {
+	if _, ok := opts[key]; !ok {
+		return nil, nil
+	}
+	section, err := mapOption(opts, key)
+	if err != nil {
+		return nil, err
+	}
+
+	outputSinks := make(map[string][]plugins.OutputSink, len(section))
+	for actionName, val := range section {
+		entries, ok := val.([]interface{})
 		if !ok {
-			return fmt.Errorf("missing required configuration: %s", key)
+			return nil, fmt.Errorf("invalid output sinks of %s: must be a list, got %T", actionName, val)
 		}
-		if key == ConfigKeyLLM {
-			// Try both map[interface{}]interface{} and map[string]interface{}
-			llmConfig, ok := val.(map[string]interface{})
+		for i, entry := range entries {
+			cfg, ok := stringMap(entry)
 			if !ok {
-				// Try the old type as fallback
-				if llmConfig2, ok := val.(map[interface{}]interface{}); ok {
-					// Convert to map[string]interface{}
-					llmConfig = make(map[string]interface{})
-					for k, v := range llmConfig2 {
-						if kStr, ok := k.(string); ok {
-							llmConfig[kStr] = v
-						}
-					}
-				} else {
-					return fmt.Errorf("invalid configuration value for %s: must be a map", key)
+				return nil, fmt.Errorf("invalid output sink %d of %s: must be a map, got %T", i, actionName, entry)
+			}
+			sink, err := newOutputSink(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid output sink %d of %s: %w", i, actionName, err)
+			}
+			outputSinks[actionName] = append(outputSinks[actionName], sink)
+		}
+	}
+	return outputSinks, nil
+}
+
+// newOutputSink creates a webhook or file sink from its configuration
+func newOutputSink(cfg map[string]interface{}) (plugins.OutputSink, error) {
+	sinkType, err := stringOption(cfg, "type")
+	if err != nil {
+		return nil, err
+	}
+
+	switch sinkType {
+	case "webhook":
+		url, err := stringOption(cfg, "url")
+		if err != nil {
+			return nil, err
+		}
+		timeout, err := intOption(cfg, "timeout")
+		if err != nil {
+			return nil, err
+		}
+		headers := make(map[string]string)
+		if _, ok := cfg["headers"]; ok {
+			headerOpts, err := mapOption(cfg, "headers")
+			if err != nil {
+				return nil, err
+			}
+			for name := range headerOpts {
+				if headers[name], err = stringOption(headerOpts, name); err != nil {
+					return nil, err
 				}
 			}
-			if model, ok := llmConfig["model"].(string); !ok || model == "" {
-				return fmt.Errorf("invalid or missing model in LLM configuration")
+		}
+		return sinks.NewWebhookSink(url, headers, time.Duration(timeout)*time.Second)
+	case "file":
+		path, err := stringOption(cfg, "path")
+		if err != nil {
+			return nil, err
+		}
+		return sinks.NewFileSink(path)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q, must be webhook or file", sinkType)
+	}
+}
+
+// applyOutputSinks hands the configured sinks to their actions
+func applyOutputSinks(pluginActions []actions.IAction, outputSinks map[string][]plugins.OutputSink) error {
+	for actionName, actionSinks := range outputSinks {
+		found := false
+		for _, action := range pluginActions {
+			if action.Name() != actionName {
+				continue
+			}
+			configurable, ok := action.(walletactions.OutputConfigurable)
+			if !ok {
+				return fmt.Errorf("action %s does not support output sinks", actionName)
 			}
-		} else if strVal, ok := val.(string); !ok || strVal == "" {
-			return fmt.Errorf("invalid configuration value for %s: must be a non-empty string", key)
+			configurable.SetOutputSinks(actionSinks)
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("output sinks configured for unknown action %s", actionName)
 		}
 	}
 	return nil
@@ -194,63 +539,31 @@ func (p *dataPlugin) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Close providers and clients holding connections
+	for _, provider := range p.providers {
+		closer, ok := provider.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			p.logger.Errorw("Failed to close provider",
+				"provider", provider.Name(),
+				"error", err,
+			)
+			errs = append(errs, fmt.Errorf("failed to close provider %s: %w", provider.Name(), err))
+		}
+	}
+	if closer, ok := p.llmClient.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			p.logger.Errorw("Failed to close LLM client", "error", err)
+			errs = append(errs, fmt.Errorf("failed to close LLM client: %w", err))
+		}
+	}
+
 	if len(errs) > 0 {
-		return fmt.Errorf("failed to stop plugin cleanly: %v", errs)
+		return fmt.Errorf("failed to stop plugin cleanly: %w", errors.Join(errs...))
 	}
 
 	p.logger.Info("Data plugin stopped successfully")
 	return nil
 }
-
-// getDefaultDatabaseSchema returns the default database schema
-func getDefaultDatabaseSchema() string {
-	return `
-CREATE EXTERNAL TABLE transactions(
-    hash string,
-    nonce bigint,
-    block_hash string,
-    block_number bigint,
-    block_timestamp timestamp,
-    date string,
-    transaction_index bigint,
-    from_address string,
-    to_address string,
-    value double,
-    gas bigint,
-    gas_price bigint,
-    input string,
-    max_fee_per_gas bigint,
-    max_priority_fee_per_gas bigint,
-    transaction_type bigint
-) PARTITIONED BY (date string);
-`
-}
-
-// getDefaultQueryExamples returns default query examples
-func getDefaultQueryExamples() string {
-	return `
-Common Query Examples:
-
-1. Find Most Active Addresses in Last 7 Days:
-SELECT from_address, COUNT(*) as tx_count 
-FROM eth.transactions 
-WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
-GROUP BY from_address 
-ORDER BY tx_count DESC 
-LIMIT 10;
-
-2. Analyze Gas Price Trends:
-SELECT date_trunc('hour', block_timestamp) as hour,
-       avg(gas_price) as avg_gas_price
-FROM eth.transactions
-WHERE date >= date_sub(current_date(), 1)
-GROUP BY 1
-ORDER BY 1;
-
-3. Find latest transactions:
-SELECT * FROM eth.transactions 
-WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
-ORDER BY block_timestamp DESC 
-LIMIT 3;
-`
-}
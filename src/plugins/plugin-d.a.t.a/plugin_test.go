@@ -0,0 +1,237 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
+	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/providers"
+
+	"go.uber.org/zap"
+)
+
+func validOptions() map[string]interface{} {
+	return map[string]interface{}{
+		ConfigKeyAPIURL:    "https://api.example",
+		ConfigKeyAuthToken: "token",
+		ConfigKeyChain:     "ethereum-mainnet",
+		ConfigKeyLLM:       map[interface{}]interface{}{"model": "gpt-4o"},
+	}
+}
+
+func TestNewPluginOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(opts map[string]interface{})
+		wantErr string
+	}{
+		{name: "valid", modify: func(map[string]interface{}) {}},
+		{name: "int api url", modify: func(opts map[string]interface{}) { opts[ConfigKeyAPIURL] = 8080 }, wantErr: "api_url"},
+		{name: "int auth token", modify: func(opts map[string]interface{}) { opts[ConfigKeyAuthToken] = 42 }, wantErr: "auth_token"},
+		{name: "empty chain", modify: func(opts map[string]interface{}) { opts[ConfigKeyChain] = "" }, wantErr: "chain"},
+		{name: "missing chain", modify: func(opts map[string]interface{}) { delete(opts, ConfigKeyChain) }, wantErr: "missing required configuration: chain"},
+		{name: "llm not a map", modify: func(opts map[string]interface{}) { opts[ConfigKeyLLM] = "gpt-4o" }, wantErr: "must be a map"},
+		{name: "max query length", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxQueryLength] = 8000 }},
+		{name: "max query length from JSON", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxQueryLength] = 8000.0 }},
+		{name: "text max query length", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxQueryLength] = "8000" }, wantErr: "max_query_length"},
+		{name: "negative max query length", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxQueryLength] = -1 }, wantErr: "must be positive"},
+		{name: "max result limit", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxResultLimit] = 500 }},
+		{name: "negative max result limit", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxResultLimit] = -5 }, wantErr: "must be positive"},
+		{name: "max result pages", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxResultPages] = 3 }},
+		{name: "text max result pages", modify: func(opts map[string]interface{}) { opts[ConfigKeyMaxResultPages] = "3" }, wantErr: "max_result_pages"},
+		{name: "bearer auth", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyAuth] = map[interface{}]interface{}{"scheme": "header", "format": "Bearer {{token}}"}
+		}},
+		{name: "hmac auth", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyAuth] = map[string]interface{}{"scheme": "hmac", "header": "X-Signature"}
+		}},
+		{name: "unknown auth scheme", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyAuth] = map[string]interface{}{"scheme": "basic"}
+		}, wantErr: "unknown auth scheme"},
+		{name: "auth format without token", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyAuth] = map[string]interface{}{"format": "Bearer"}
+		}, wantErr: "invalid auth configuration"},
+		{name: "int auth header", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyAuth] = map[string]interface{}{"header": 7}
+		}, wantErr: "invalid auth configuration"},
+		{name: "auth not a map", modify: func(opts map[string]interface{}) { opts[ConfigKeyAuth] = "bearer" }, wantErr: "must be a map"},
+		{name: "query concurrency", modify: func(opts map[string]interface{}) { opts[ConfigKeyQueryConcurrency] = 2 }},
+		{name: "negative query concurrency", modify: func(opts map[string]interface{}) { opts[ConfigKeyQueryConcurrency] = -2 }, wantErr: "must be positive"},
+		{name: "query templates", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyQueryTemplates] = map[string]interface{}{
+				"top_senders": map[string]interface{}{"sql": "SELECT 1 LIMIT {{limit}};", "params": map[string]interface{}{"limit": "int"}},
+			}
+		}},
+		{name: "query template without sql", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyQueryTemplates] = map[string]interface{}{"top_senders": map[string]interface{}{}}
+		}, wantErr: "invalid query template top_senders"},
+		{name: "query template with unknown param type", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyQueryTemplates] = map[string]interface{}{
+				"top_senders": map[string]interface{}{"sql": "SELECT {{n}};", "params": map[string]interface{}{"n": "float"}},
+			}
+		}, wantErr: "unknown type"},
+		{name: "query template with undeclared placeholder", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyQueryTemplates] = map[string]interface{}{"top_senders": map[string]interface{}{"sql": "SELECT {{n}};"}}
+		}, wantErr: "not a declared parameter"},
+		{name: "output sinks", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyOutputSinks] = map[string]interface{}{
+				"get_transaction": []interface{}{
+					map[interface{}]interface{}{"type": "webhook", "url": "https://example.com/hook", "headers": map[interface{}]interface{}{"Authorization": "Bearer token"}},
+				},
+			}
+		}},
+		{name: "output sinks of unknown action", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyOutputSinks] = map[string]interface{}{
+				"fetch_everything": []interface{}{map[string]interface{}{"type": "webhook", "url": "https://example.com/hook"}},
+			}
+		}, wantErr: "unknown action fetch_everything"},
+		{name: "unknown output sink type", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyOutputSinks] = map[string]interface{}{
+				"get_transaction": []interface{}{map[string]interface{}{"type": "kafka"}},
+			}
+		}, wantErr: "unknown sink type"},
+		{name: "output sinks not a list", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyOutputSinks] = map[string]interface{}{"get_transaction": "webhook"}
+		}, wantErr: "must be a list"},
+		{name: "int model", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": 4}
+		}, wantErr: "invalid LLM configuration"},
+		{name: "task models", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": "deepseek-chat", "query_model": "deepseek-chat", "analysis_model": "deepseek-reasoner"}
+		}},
+		{name: "int query model", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": "deepseek-chat", "query_model": 4}
+		}, wantErr: "invalid LLM configuration"},
+		{name: "int analysis model", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": "deepseek-chat", "analysis_model": 4}
+		}, wantErr: "invalid LLM configuration"},
+		{name: "query system prompt", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": "deepseek-chat", "query_system_prompt": "Write {{chain}} SQL"}
+		}},
+		{name: "int query system prompt", modify: func(opts map[string]interface{}) {
+			opts[ConfigKeyLLM] = map[string]interface{}{"model": "deepseek-chat", "query_system_prompt": 4}
+		}, wantErr: "invalid LLM configuration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := validOptions()
+			tt.modify(opts)
+
+			plugin, err := NewPlugin(nil, &plugins.Config{Options: opts})
+			if tt.wantErr == "" {
+				if err != nil || plugin == nil {
+					t.Fatalf("NewPlugin() = %v, %v, want a plugin", plugin, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewPlugin() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewPluginNilConfig(t *testing.T) {
+	if _, err := NewPlugin(nil, nil); err == nil {
+		t.Error("NewPlugin(nil) error = nil, want an error")
+	}
+}
+
+var errCloseFailed = errors.New("close failed")
+
+// idleTransport records CloseIdleConnections calls
+type idleTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *idleTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+// closingProvider records Close calls and fails them with err
+type closingProvider struct {
+	plugins.Provider
+	name   string
+	err    error
+	closed int
+}
+
+func (p *closingProvider) Name() string { return p.name }
+
+func (p *closingProvider) Close() error {
+	p.closed++
+	return p.err
+}
+
+// closingLLM records Close calls
+type closingLLM struct {
+	llm.Client
+	closed int
+}
+
+func (c *closingLLM) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestStopClosesProviders(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	transport := &idleTransport{}
+	dbProvider := providers.NewDatabaseProvider("test_provider", "https://api.example", "token", "ethereum", "", "", nil, "gpt-4o",
+		&providers.DatabaseConfig{Transport: transport}, logger)
+	other := &closingProvider{name: "other"}
+	client := &closingLLM{}
+	plugin := &dataPlugin{
+		llmClient: client,
+		logger:    logger,
+		providers: []plugins.Provider{dbProvider, &stateOnlyProvider{}, other},
+	}
+
+	if err := plugin.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if transport.closed != 1 {
+		t.Errorf("closed idle connections %d times, want 1", transport.closed)
+	}
+	if other.closed != 1 {
+		t.Errorf("closed provider %d times, want 1", other.closed)
+	}
+	if client.closed != 1 {
+		t.Errorf("closed LLM client %d times, want 1", client.closed)
+	}
+}
+
+func TestStopAggregatesCloseErrors(t *testing.T) {
+	first := &closingProvider{name: "first", err: errCloseFailed}
+	second := &closingProvider{name: "second", err: errCloseFailed}
+	plugin := &dataPlugin{
+		logger:    zap.NewNop().Sugar(),
+		providers: []plugins.Provider{first, second},
+	}
+
+	err := plugin.Stop(context.Background())
+	if !errors.Is(err, errCloseFailed) {
+		t.Fatalf("Stop() error = %v, want errCloseFailed", err)
+	}
+	for _, name := range []string{"first", "second"} {
+		if !strings.Contains(err.Error(), "failed to close provider "+name) {
+			t.Errorf("Stop() error = %v, want it to mention provider %s", err, name)
+		}
+	}
+	if first.closed != 1 || second.closed != 1 {
+		t.Errorf("closed providers %d and %d times, want both once", first.closed, second.closed)
+	}
+}
+
+// stateOnlyProvider doesn't hold any resource to close
+type stateOnlyProvider struct {
+	plugins.Provider
+}
+
+func (p *stateOnlyProvider) Name() string { return "state_only" }
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auth schemes of the data API requests
+const (
+	// AuthSchemeHeader sends the auth token in a header
+	AuthSchemeHeader = "header"
+	// AuthSchemeHMAC signs every request with the auth token as the key
+	AuthSchemeHMAC = "hmac"
+)
+
+const (
+	defaultAuthHeader      = "Authorization"
+	defaultSignatureHeader = "X-Signature"
+	// timestampHeader carries the unix time an HMAC signature covers
+	timestampHeader = "X-Timestamp"
+)
+
+// AuthConfig configures how data API requests are authenticated. The header
+// scheme sends Format with {{token}} replaced by the auth token, e.g.
+// "Bearer {{token}}". The hmac scheme sends Format with {{signature}}
+// replaced by the hex HMAC-SHA256 of the timestamp, a newline and the body,
+// next to the timestamp in X-Timestamp.
+type AuthConfig struct {
+	// Scheme is AuthSchemeHeader or AuthSchemeHMAC, AuthSchemeHeader when empty
+	Scheme string
+	// Header defaults to Authorization, or X-Signature for the hmac scheme
+	Header string
+	// Format defaults to the bare token or signature
+	Format string
+}
+
+// Validate checks the scheme and that the format has its placeholder
+func (c AuthConfig) Validate() error {
+	switch c.scheme() {
+	case AuthSchemeHeader, AuthSchemeHMAC:
+	default:
+		return fmt.Errorf("unknown auth scheme %q, expected %s or %s", c.Scheme, AuthSchemeHeader, AuthSchemeHMAC)
+	}
+	if c.Format != "" && !strings.Contains(c.Format, c.placeholder()) {
+		return fmt.Errorf("auth format %q must contain %s", c.Format, c.placeholder())
+	}
+	return nil
+}
+
+func (c AuthConfig) scheme() string {
+	if c.Scheme == "" {
+		return AuthSchemeHeader
+	}
+	return strings.ToLower(c.Scheme)
+}
+
+func (c AuthConfig) placeholder() string {
+	if c.scheme() == AuthSchemeHMAC {
+		return "{{signature}}"
+	}
+	return "{{token}}"
+}
+
+func (c AuthConfig) header() string {
+	switch {
+	case c.Header != "":
+		return c.Header
+	case c.scheme() == AuthSchemeHMAC:
+		return defaultSignatureHeader
+	default:
+		return defaultAuthHeader
+	}
+}
+
+// apply sets the auth headers of a request with the given body
+func (c AuthConfig) apply(req *http.Request, token string, body []byte, now time.Time) {
+	value := token
+	if c.scheme() == AuthSchemeHMAC {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(token))
+		mac.Write([]byte(timestamp + "\n"))
+		mac.Write(body)
+		value = hex.EncodeToString(mac.Sum(nil))
+		req.Header.Set(timestampHeader, timestamp)
+	}
+	if c.Format != "" {
+		value = strings.ReplaceAll(c.Format, c.placeholder(), value)
+	}
+	req.Header.Set(c.header(), value)
+}
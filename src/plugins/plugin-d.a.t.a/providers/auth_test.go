@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestAuthConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AuthConfig
+		wantErr bool
+	}{
+		{name: "defaults", config: AuthConfig{}},
+		{name: "header with format", config: AuthConfig{Scheme: "header", Format: "Bearer {{token}}"}},
+		{name: "scheme is case insensitive", config: AuthConfig{Scheme: "HMAC"}},
+		{name: "hmac with format", config: AuthConfig{Scheme: "hmac", Format: "sig={{signature}}"}},
+		{name: "unknown scheme", config: AuthConfig{Scheme: "basic"}, wantErr: true},
+		{name: "header format without token", config: AuthConfig{Format: "Bearer"}, wantErr: true},
+		{name: "hmac format with the token placeholder", config: AuthConfig{Scheme: "hmac", Format: "{{token}}"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthConfigApply(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"sql_content":"SELECT 1"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000\n"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name          string
+		config        AuthConfig
+		wantHeader    string
+		wantValue     string
+		wantTimestamp string
+	}{
+		{name: "raw token", config: AuthConfig{}, wantHeader: "Authorization", wantValue: "secret"},
+		{name: "bearer token", config: AuthConfig{Format: "Bearer {{token}}"}, wantHeader: "Authorization", wantValue: "Bearer secret"},
+		{name: "custom header", config: AuthConfig{Header: "X-API-Key"}, wantHeader: "X-API-Key", wantValue: "secret"},
+		{name: "hmac", config: AuthConfig{Scheme: "hmac"}, wantHeader: "X-Signature", wantValue: signature, wantTimestamp: "1700000000"},
+		{
+			name:          "hmac with format and header",
+			config:        AuthConfig{Scheme: "hmac", Header: "Authorization", Format: "HMAC {{signature}}"},
+			wantHeader:    "Authorization",
+			wantValue:     "HMAC " + signature,
+			wantTimestamp: "1700000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://test.api/sql_query", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			tt.config.apply(req, "secret", body, now)
+
+			if got := req.Header.Get(tt.wantHeader); got != tt.wantValue {
+				t.Errorf("%s = %q, want %q", tt.wantHeader, got, tt.wantValue)
+			}
+			if got := req.Header.Get("X-Timestamp"); got != tt.wantTimestamp {
+				t.Errorf("X-Timestamp = %q, want %q", got, tt.wantTimestamp)
+			}
+		})
+	}
+}
+
+func TestExecuteAPIRequestAppliesAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       AuthConfig
+		wantHeader string
+		wantValue  string
+	}{
+		{name: "default raw token", wantHeader: "Authorization", wantValue: "secret"},
+		{name: "bearer token", auth: AuthConfig{Scheme: AuthSchemeHeader, Format: "Bearer {{token}}"}, wantHeader: "Authorization", wantValue: "Bearer secret"},
+		{name: "api key header", auth: AuthConfig{Header: "X-API-Key"}, wantHeader: "X-API-Key", wantValue: "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Clone()
+				w.Write([]byte(`{"code":0,"msg":"ok","data":{"column_infos":["n"],"rows":[{"items":[1]}]}}`))
+			}))
+			defer server.Close()
+			provider := NewDatabaseProvider("test_provider", server.URL, "secret", "ethereum", "", "", nil, "test-model",
+				&DatabaseConfig{Auth: tt.auth}, zap.NewNop().Sugar())
+
+			if _, err := provider.executeAPIRequest(context.Background(), "SELECT 1", ""); err != nil {
+				t.Fatalf("executeAPIRequest() error = %v", err)
+			}
+			if value := got.Get(tt.wantHeader); value != tt.wantValue {
+				t.Errorf("%s = %q, want %q", tt.wantHeader, value, tt.wantValue)
+			}
+			if tt.wantHeader != "Authorization" && got.Get("Authorization") != "" {
+				t.Errorf("Authorization = %q, want the token only in %s", got.Get("Authorization"), tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestExecuteAPIRequestSignsBody(t *testing.T) {
+	var signature, timestamp string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature, timestamp = r.Header.Get("X-Signature"), r.Header.Get("X-Timestamp")
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte(`{"code":0,"msg":"ok","data":{"column_infos":["n"],"rows":[{"items":[1]}]}}`))
+	}))
+	defer server.Close()
+	provider := NewDatabaseProvider("test_provider", server.URL, "secret", "ethereum", "", "", nil, "test-model",
+		&DatabaseConfig{Auth: AuthConfig{Scheme: AuthSchemeHMAC}}, zap.NewNop().Sugar())
+
+	if _, err := provider.executeAPIRequest(context.Background(), "SELECT 1", ""); err != nil {
+		t.Fatalf("executeAPIRequest() error = %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "\n"))
+	mac.Write(body)
+	if want := hex.EncodeToString(mac.Sum(nil)); timestamp == "" || signature != want {
+		t.Errorf("X-Signature = %q at %q, want the signature %q of the sent body", signature, timestamp, want)
+	}
+}
@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/carv-protocol/d.a.t.a/src/internal/actions"
 	"github.com/carv-protocol/d.a.t.a/src/internal/plugins"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/llm"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
+	"github.com/carv-protocol/d.a.t.a/src/pkg/retry"
 	"github.com/carv-protocol/d.a.t.a/src/plugins/plugin-d.a.t.a/types"
 
 	"go.uber.org/zap"
@@ -20,14 +27,20 @@ import (
 
 const (
 	clientTimeout       = 30 * time.Second
-	defaultRetryCount   = 3
-	defaultRetryDelay   = 1 * time.Second
 	maxIdleConns        = 100
 	maxIdleConnsPerHost = 100
 	idleConnTimeout     = 90 * time.Second
-	maxRetries          = 3
 	requestTimeout      = 2 * time.Minute
-	maxQueryLength      = 5000
+	probeTimeout        = 5 * time.Second
+	probeCacheTTL       = 30 * time.Second
+	// defaultMaxQueryLength is used when DatabaseConfig.MaxQueryLength is not set
+	defaultMaxQueryLength = 5000
+	// defaultLookbackDays is used when DatabaseConfig.DefaultLookbackDays is not set
+	defaultLookbackDays = 90
+	// defaultMaxResultLimit is used when DatabaseConfig.MaxResultLimit is not set
+	defaultMaxResultLimit = 1000
+	// defaultMaxResultPages is used when DatabaseConfig.MaxResultPages is not set
+	defaultMaxResultPages = 10
 )
 
 var defaultTransport = &http.Transport{
@@ -36,11 +49,6 @@ var defaultTransport = &http.Transport{
 	IdleConnTimeout:     idleConnTimeout,
 }
 
-var defaultClient = &http.Client{
-	Timeout:   clientTimeout,
-	Transport: defaultTransport,
-}
-
 // QueryMetadata represents the metadata for a query
 type QueryMetadata struct {
 	ExecutionTime time.Duration `json:"executionTime"`
@@ -54,17 +62,37 @@ type QueryMetadata struct {
 // DatabaseProviderImpl implements the DatabaseProvider interface
 type DatabaseProviderImpl struct {
 	llmClient  llm.Client
+	httpClient *http.Client
 	logger     *zap.SugaredLogger
 	config     *DatabaseConfig
 	name       string
-	lastQuery  string
-	queryCount int
 	model      string
 	apiURL     string
 	authToken  string
 	chain      string
 	dbSchema   string
 	sqlExample string
+
+	queryCount    atomic.Int64
+	lastQueryMu   sync.RWMutex
+	lastQueryTime time.Time
+
+	probeMu sync.Mutex
+	probe   probeResult
+}
+
+// Provider connectivity states
+const (
+	StateConnected    = "connected"
+	StateDegraded     = "degraded"
+	StateDisconnected = "disconnected"
+)
+
+// probeResult caches the outcome of the last connectivity probe
+type probeResult struct {
+	state     string
+	err       error
+	checkedAt time.Time
 }
 
 // DatabaseConfig contains configuration for database connection
@@ -75,6 +103,28 @@ type DatabaseConfig struct {
 	Port         int
 	Username     string
 	Password     string
+	// MaxQueryLength is the longest SQL query accepted, defaultMaxQueryLength when 0
+	MaxQueryLength int
+	// DefaultLookbackDays is the time range of queries that don't ask for one, defaultLookbackDays when 0
+	DefaultLookbackDays int
+	// MaxResultLimit caps the rows of every query, defaultMaxResultLimit when 0
+	MaxResultLimit int
+	// MaxResultPages caps the pages fetched of a paginated result, defaultMaxResultPages when 0
+	MaxResultPages int
+	// QueryModel generates SQL queries and AnalysisModel analyzes their
+	// results, both fall back to the provider model when empty
+	QueryModel    string
+	AnalysisModel string
+	// QuerySystemPrompt is the system prompt generating SQL queries, with
+	// {{chain}} and {{databaseSchema}} placeholders; defaultQuerySystemPrompt when empty
+	QuerySystemPrompt string
+	// Auth is how data API requests send the auth token, the raw token in
+	// Authorization when unset
+	Auth AuthConfig
+	// Retry is the policy of LLM and data API requests, retry defaults when unset
+	Retry retry.Policy
+	// Transport sends the data API requests, a transport shared by all providers when nil
+	Transport http.RoundTripper
 }
 
 // NewDatabaseProvider creates a new database provider instance
@@ -87,9 +137,22 @@ func NewDatabaseProvider(
 	sqlExample string,
 	llmClient llm.Client,
 	model string,
+	config *DatabaseConfig,
 	logger *zap.SugaredLogger,
 ) *DatabaseProviderImpl {
+	if config == nil {
+		config = &DatabaseConfig{}
+	}
+	transport := config.Transport
+	if transport == nil {
+		transport = defaultTransport
+	}
 	return &DatabaseProviderImpl{
+		config: config,
+		httpClient: &http.Client{
+			Timeout:   clientTimeout,
+			Transport: transport,
+		},
 		name:       name,
 		apiURL:     apiURL,
 		authToken:  authToken,
@@ -147,50 +210,75 @@ func (p *DatabaseProviderImpl) AnalyzeQuery(ctx context.Context, result *types.T
 func (p *DatabaseProviderImpl) GenerateQuery(ctx context.Context, prompt string) (string, error) {
 	// Create completion request
 	request := llm.CompletionRequest{
-		Model: p.model,
+		Model: p.queryModel(),
 		Messages: []llm.Message{
 			{
 				Role:    "system",
-				Content: "You are a SQL query generator. Generate only the SQL query without any explanation.",
+				Content: p.buildQuerySystemPrompt(),
 			},
 			{
 				Role:    "user",
-				Content: prompt,
+				Content: p.buildQueryPrompt(prompt),
 			},
 		},
 	}
 
 	var response string
-	var lastErr error
-
-	for i := 0; i < maxRetries; i++ {
+	policy := p.retryPolicy()
+	err := policy.Do(ctx, func(int) error {
 		timeoutCtx, cancel := context.WithTimeout(ctx, requestTimeout)
 		defer cancel()
 
-		response, lastErr = p.llmClient.CreateCompletion(timeoutCtx, request)
-		if lastErr == nil {
-			break
-		}
-
-		if i < maxRetries-1 {
-			time.Sleep(time.Duration(i+1) * time.Second)
-		}
+		var err error
+		response, err = p.llmClient.CreateCompletion(timeoutCtx, request)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate query after %d attempts: %w", policy.Attempts, types.ClassifyRequestError(err))
 	}
 
-	if lastErr != nil {
-		return "", fmt.Errorf("failed to generate query after %d retries: %w", maxRetries, lastErr)
+	// An ambiguous request gets a question for the user instead of a guessed query
+	if question, ok := clarificationQuestion(response); ok {
+		return "", &actions.ClarificationError{Question: question}
 	}
 
 	// Extract SQL query from response
 	query := p.extractSQLQuery(response)
 	if query == "" {
-		return "", fmt.Errorf("no valid SQL query found in response")
+		return "", types.ErrNoSQLQuery
 	}
 
-	return query, nil
+	return normalizeAddressLiterals(query), nil
 }
 
-// extractSQLQuery extracts a valid SQL query from the response
+// addressLiteralPattern matches hex addresses inside SQL string literals
+var addressLiteralPattern = regexp.MustCompile(`'0[xX][0-9a-fA-F]{40}'`)
+
+// normalizeAddressLiterals lowercases address literals, since the tables
+// store addresses as lowercase hex and a checksummed address matches no rows
+func normalizeAddressLiterals(query string) string {
+	return addressLiteralPattern.ReplaceAllStringFunc(query, strings.ToLower)
+}
+
+// clarificationPrefix starts the reply to a request too ambiguous to query
+const clarificationPrefix = "CLARIFY:"
+
+// clarificationQuestion returns the question of a CLARIFY line in the response
+func clarificationQuestion(response string) (string, bool) {
+	for _, line := range strings.Split(strings.TrimSpace(response), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(clarificationPrefix) || !strings.EqualFold(line[:len(clarificationPrefix)], clarificationPrefix) {
+			continue
+		}
+		if question := strings.TrimSpace(line[len(clarificationPrefix):]); question != "" {
+			return question, true
+		}
+	}
+	return "", false
+}
+
+// extractSQLQuery extracts a valid SQL query from the response, or returns an
+// empty string when the response has none
 func (p *DatabaseProviderImpl) extractSQLQuery(response string) string {
 	// Clean the response
 	response = strings.TrimSpace(response)
@@ -217,8 +305,111 @@ func (p *DatabaseProviderImpl) extractSQLQuery(response string) string {
 		}
 	}
 
-	// If no valid query found, return default query
-	return "SELECT * FROM eth.transactions WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d') ORDER BY block_timestamp DESC LIMIT 3;"
+	// No valid query found, let the caller decide how to handle it
+	return ""
+}
+
+// defaultQuerySystemPrompt is the system prompt for generating SQL queries
+// when the plugin doesn't configure one
+const defaultQuerySystemPrompt = "You are an expert SQL query generator for {{chain}} blockchain data. " +
+	"Generate only the SQL query without any explanation, or a CLARIFY line when the request is too ambiguous."
+
+// queryPromptTemplate is the prompt for generating SQL queries
+const queryPromptTemplate = `
+# Database Schema
+{{databaseSchema}}
+
+# Query Examples
+{{queryExamples}}
+
+# User's Query
+{{userQuery}}
+
+# Query Guidelines:
+1. Time Range Requirements:
+   - ALWAYS include time range limitations in queries
+   - Default to the last {{defaultLookbackDays}} days if no specific time range is mentioned
+   - Use date >= date_format(date_add('day', -{{defaultLookbackDays}}, current_date), '%Y-%m-%d') for the default time range
+   - Adjust time range based on user's specific requirements
+
+2. Query Optimization:
+   - Include a LIMIT clause of at most {{maxResultLimit}} rows
+   - Use proper indexing columns (date, address, block_number)
+   - Consider partitioning by date
+   - Add WHERE clauses for efficient filtering
+   - Addresses are stored as lowercase hex, compare them in lowercase
+
+3. Response Format Requirements:
+   You MUST respond with ONLY the SQL query, no other text or explanation,
+   unless the request is ambiguous (see 5).
+   The query should be a valid SQL statement that can be executed directly.
+
+4. Safety Requirements:
+   - Only SELECT statements are allowed
+   - No modifications to the database
+   - No creation of new tables or views
+   - No execution of stored procedures
+
+5. Ambiguous Requests:
+   If the request doesn't say what data is wanted, so that any query would be a
+   guess, respond with ONLY one line asking the user what they mean:
+   CLARIFY: <a short question for the user>
+`
+
+// buildQueryPrompt fills the query prompt template for a user request
+func (p *DatabaseProviderImpl) buildQueryPrompt(userQuery string) string {
+	return strings.NewReplacer(
+		"{{databaseSchema}}", p.dbSchema,
+		"{{queryExamples}}", p.sqlExample,
+		"{{userQuery}}", userQuery,
+		"{{defaultLookbackDays}}", strconv.Itoa(p.lookbackDays()),
+		"{{maxResultLimit}}", strconv.Itoa(p.MaxResultLimit()),
+	).Replace(queryPromptTemplate)
+}
+
+// buildQuerySystemPrompt fills the configured or default query system prompt
+// with the chain and schema of the provider
+func (p *DatabaseProviderImpl) buildQuerySystemPrompt() string {
+	template := defaultQuerySystemPrompt
+	if p.config != nil && p.config.QuerySystemPrompt != "" {
+		template = p.config.QuerySystemPrompt
+	}
+	return strings.NewReplacer(
+		"{{chain}}", p.chain,
+		"{{databaseSchema}}", p.dbSchema,
+	).Replace(template)
+}
+
+// retryPolicy returns the configured retry policy with defaults filled in
+func (p *DatabaseProviderImpl) retryPolicy() retry.Policy {
+	if p.config == nil {
+		return retry.Policy{}.WithDefaults()
+	}
+	return p.config.Retry.WithDefaults()
+}
+
+// queryModel returns the model generating SQL queries
+func (p *DatabaseProviderImpl) queryModel() string {
+	if p.config != nil && p.config.QueryModel != "" {
+		return p.config.QueryModel
+	}
+	return p.model
+}
+
+// analysisModel returns the model analyzing query results
+func (p *DatabaseProviderImpl) analysisModel() string {
+	if p.config != nil && p.config.AnalysisModel != "" {
+		return p.config.AnalysisModel
+	}
+	return p.model
+}
+
+// lookbackDays returns the configured default time range in days
+func (p *DatabaseProviderImpl) lookbackDays() int {
+	if p.config != nil && p.config.DefaultLookbackDays > 0 {
+		return p.config.DefaultLookbackDays
+	}
+	return defaultLookbackDays
 }
 
 // ExecuteQuery executes a SQL query and returns the result
@@ -233,50 +424,29 @@ func (p *DatabaseProviderImpl) ExecuteQuery(ctx context.Context, query string) (
 	}
 
 	// Validate query
-	if query == "" || len(query) > 5000 {
-		return nil, fmt.Errorf("invalid SQL query length")
+	if maxLength := p.maxQueryLength(); query == "" || len(query) > maxLength {
+		return nil, fmt.Errorf("%w: %d characters, limit is %d", types.ErrInvalidQueryLength, len(query), maxLength)
+	}
+	if err := validateReadOnlySQL(query); err != nil {
+		return nil, err
+	}
+	if limited := limitQuery(query, p.MaxResultLimit()); limited != query {
+		logger.FromContext(ctx).Infow("Capped query result limit", "max_result_limit", p.MaxResultLimit())
+		query = limited
 	}
 
-	queryType := "transaction"
+	p.recordQuery()
+
+	queryType := types.QueryTypeTransaction
 	if strings.Contains(strings.ToLower(query), "token_transfers") {
-		queryType = "token"
+		queryType = types.QueryTypeToken
 	} else if strings.Contains(strings.ToLower(query), "count") {
-		queryType = "aggregate"
-	}
-
-	// Execute query with retries
-	var apiResponse *types.APIResponse
-	var lastErr error
-	var err error
-	for retries := 0; retries < defaultRetryCount; retries++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		if retries > 0 {
-			time.Sleep(defaultRetryDelay * time.Duration(retries))
-		}
-
-		apiResponse, err = p.executeAPIRequest(ctx, query)
-		if err == nil {
-			break
-		}
-
-		lastErr = err
-		logger.GetLogger().Warn("Request failed, retrying",
-			zap.Int("attempt", retries+1),
-			zap.Error(err))
+		queryType = types.QueryTypeAggregate
 	}
 
-	if apiResponse == nil {
-		return nil, fmt.Errorf("failed after %d attempts, last error: %w", defaultRetryCount, lastErr)
-	}
-
-	// Check API response status
-	if apiResponse.Code != 0 {
-		return nil, fmt.Errorf("API error: %s", apiResponse.Msg)
+	apiResponse, truncated, err := p.fetchPages(ctx, query)
+	if err != nil {
+		return nil, err
 	}
 
 	// Transform data
@@ -298,6 +468,7 @@ func (p *DatabaseProviderImpl) ExecuteQuery(ctx context.Context, query string) (
 				ParamValidation []string `json:"paramValidation,omitempty"`
 			} `json:"queryDetails,omitempty"`
 			BlockStats *types.BlockStats `json:"blockStats,omitempty"`
+			Truncated  bool              `json:"truncated,omitempty"`
 		}{
 			Total:         len(transformedData),
 			QueryTime:     time.Now().Format(time.RFC3339),
@@ -310,15 +481,165 @@ func (p *DatabaseProviderImpl) ExecuteQuery(ctx context.Context, query string) (
 			}{
 				Query: query,
 			},
+			Truncated: truncated,
 		},
 	}
 
 	return result, nil
 }
 
-// executeAPIRequest executes the API request with the given SQL query
-func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string) (*types.APIResponse, error) {
-	logger.GetLogger().With(
+// fetchPages executes the query, following the page tokens of a large result
+// until the last page, MaxResultLimit rows or MaxResultPages pages. The rows
+// of all pages are returned in one response; truncated reports that rows were
+// left out, by the API or by the caps.
+func (p *DatabaseProviderImpl) fetchPages(ctx context.Context, query string) (*types.APIResponse, bool, error) {
+	var (
+		combined  *types.APIResponse
+		pageToken string
+	)
+	maxPages := p.maxResultPages()
+	for page := 1; ; page++ {
+		// Execute each page with retries
+		var apiResponse *types.APIResponse
+		policy := p.retryPolicy()
+		err := policy.Do(ctx, func(attempt int) error {
+			resp, err := p.executeAPIRequest(ctx, query, pageToken)
+			if err != nil {
+				logger.FromContext(ctx).Warn("Request failed",
+					zap.Int("attempt", attempt),
+					zap.Int("page", page),
+					zap.Error(err))
+				return err
+			}
+			apiResponse = resp
+			return nil
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed after %d attempts, last error: %w", policy.Attempts, types.ClassifyRequestError(err))
+		}
+
+		// Check API response status
+		if apiResponse.Code != 0 {
+			return nil, false, &types.UpstreamError{Code: apiResponse.Code, Message: apiResponse.Msg}
+		}
+
+		if combined == nil {
+			combined = apiResponse
+		} else {
+			combined.Data.Rows = append(combined.Data.Rows, apiResponse.Data.Rows...)
+		}
+		truncated := apiResponse.Data.Truncated
+		pageToken = apiResponse.Data.NextPageToken
+		done := truncated || pageToken == ""
+		if !done && (len(combined.Data.Rows) >= p.MaxResultLimit() || page >= maxPages) {
+			logger.FromContext(ctx).Warnw("Query result truncated",
+				"rows", len(combined.Data.Rows),
+				"pages", page,
+			)
+			truncated, done = true, true
+		}
+		if !done {
+			continue
+		}
+
+		// Whatever ended the result, never return more than the cap
+		if len(combined.Data.Rows) > p.MaxResultLimit() {
+			combined.Data.Rows = combined.Data.Rows[:p.MaxResultLimit()]
+			truncated = true
+		}
+		return combined, truncated, nil
+	}
+}
+
+// maxResultPages returns the most pages fetched of a paginated result
+func (p *DatabaseProviderImpl) maxResultPages() int {
+	if p.config != nil && p.config.MaxResultPages > 0 {
+		return p.config.MaxResultPages
+	}
+	return defaultMaxResultPages
+}
+
+// recordQuery counts an executed query and notes when it ran
+func (p *DatabaseProviderImpl) recordQuery() {
+	p.queryCount.Add(1)
+
+	p.lastQueryMu.Lock()
+	p.lastQueryTime = time.Now()
+	p.lastQueryMu.Unlock()
+}
+
+// getLastQueryTime returns when the last query ran, zero before the first one
+func (p *DatabaseProviderImpl) getLastQueryTime() time.Time {
+	p.lastQueryMu.RLock()
+	defer p.lastQueryMu.RUnlock()
+	return p.lastQueryTime
+}
+
+// maxQueryLength returns the configured query length limit
+func (p *DatabaseProviderImpl) maxQueryLength() int {
+	if p.config != nil && p.config.MaxQueryLength > 0 {
+		return p.config.MaxQueryLength
+	}
+	return defaultMaxQueryLength
+}
+
+// MaxResultLimit returns the most rows a query may return
+func (p *DatabaseProviderImpl) MaxResultLimit() int {
+	if p.config != nil && p.config.MaxResultLimit > 0 {
+		return p.config.MaxResultLimit
+	}
+	return defaultMaxResultLimit
+}
+
+// trailingLimitPattern matches the LIMIT clause ending a query
+var trailingLimitPattern = regexp.MustCompile(`(?i)\bLIMIT\s+(\d+)(\s+OFFSET\s+\d+)?\s*$`)
+
+// limitQuery lowers the final LIMIT of a query to max, adding one when the
+// query has none
+func limitQuery(query string, max int) string {
+	statement := strings.TrimSuffix(strings.TrimSpace(query), ";")
+	match := trailingLimitPattern.FindStringSubmatchIndex(statement)
+	if match == nil {
+		return fmt.Sprintf("%s LIMIT %d;", strings.TrimSpace(statement), max)
+	}
+	if limit, err := strconv.Atoi(statement[match[2]:match[3]]); err == nil && limit <= max {
+		return query
+	}
+	return statement[:match[2]] + strconv.Itoa(max) + statement[match[3]:] + ";"
+}
+
+// forbiddenSQLKeywords are statements that modify data or schema
+var forbiddenSQLKeywords = []string{
+	"INSERT", "UPDATE", "DELETE", "MERGE", "DROP", "ALTER", "CREATE", "TRUNCATE", "GRANT", "REVOKE",
+}
+
+// validateReadOnlySQL only lets a single SELECT statement through
+func validateReadOnlySQL(query string) error {
+	statement := strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if strings.Contains(statement, ";") {
+		return fmt.Errorf("%w: multiple statements", types.ErrForbiddenSQL)
+	}
+
+	words := strings.FieldsFunc(strings.ToUpper(statement), func(r rune) bool {
+		return !(r == '_' || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'))
+	})
+	if len(words) == 0 || (words[0] != "SELECT" && words[0] != "WITH") {
+		return fmt.Errorf("%w: only SELECT queries are allowed", types.ErrForbiddenSQL)
+	}
+	for _, word := range words {
+		for _, keyword := range forbiddenSQLKeywords {
+			if word == keyword {
+				return fmt.Errorf("%w: %s is not allowed", types.ErrForbiddenSQL, keyword)
+			}
+		}
+	}
+	return nil
+}
+
+// executeAPIRequest executes the API request with the given SQL query,
+// requesting the page of pageToken unless it is empty
+func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql, pageToken string) (*types.APIResponse, error) {
+	logger.FromContext(ctx).With(
 		zap.String("sql", sql),
 		zap.String("url", p.apiURL),
 	).Info("Executing API request")
@@ -328,9 +649,12 @@ func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string
 	body := map[string]string{
 		"sql_content": sql,
 	}
+	if pageToken != "" {
+		body["page_token"] = pageToken
+	}
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Error(err),
 		).Error("Failed to marshal request body")
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
@@ -339,7 +663,7 @@ func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyBytes))
 	if err != nil {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Error(err),
 		).Error("Failed to create request")
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -348,13 +672,16 @@ func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	if p.authToken != "" {
-		req.Header.Set("Authorization", p.authToken)
+		p.config.Auth.apply(req, p.authToken, bodyBytes, time.Now())
+	}
+	if traceID := logger.TraceID(ctx); traceID != "" {
+		req.Header.Set(logger.TraceIDHeader, traceID)
 	}
 
 	// Execute request
-	resp, err := defaultClient.Do(req)
+	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Error(err),
 		).Error("Failed to execute request")
 		return nil, fmt.Errorf("failed to execute request: %w", err)
@@ -364,7 +691,7 @@ func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Error(err),
 		).Error("Failed to read response body")
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -372,27 +699,28 @@ func (p *DatabaseProviderImpl) executeAPIRequest(ctx context.Context, sql string
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Int("status_code", resp.StatusCode),
 			zap.String("response", string(respBody)),
 		).Error("API request failed")
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, &types.UpstreamError{StatusCode: resp.StatusCode, Message: string(respBody)}
 	}
 
 	// Parse response
 	var apiResp types.APIResponse
 	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		logger.GetLogger().With(
+		logger.FromContext(ctx).With(
 			zap.Error(err),
 			zap.String("response", string(respBody)),
 		).Error("Failed to unmarshal response")
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	logger.GetLogger().With(
+	logger.FromContext(ctx).With(
 		zap.Int("code", apiResp.Code),
 		zap.String("message", apiResp.Msg),
 		zap.Int("rows", len(apiResp.Data.Rows)),
+		zap.Bool("next_page", apiResp.Data.NextPageToken != ""),
 	).Info("API request completed")
 
 	return &apiResp, nil
@@ -441,7 +769,7 @@ func (p *DatabaseProviderImpl) generateAnalysis(ctx context.Context, template st
 	}
 
 	request := llm.CompletionRequest{
-		Model: p.model,
+		Model: p.analysisModel(),
 		Messages: []llm.Message{
 			{
 				Role:    "system",
@@ -472,7 +800,8 @@ func prettyJSON(v interface{}) string {
 	return string(b)
 }
 
-func getDefaultDatabaseSchema() string {
+// DefaultDatabaseSchema returns the schema of the transactions table used in prompts
+func DefaultDatabaseSchema() string {
 	return `
 CREATE EXTERNAL TABLE transactions(
     hash string,
@@ -495,14 +824,15 @@ CREATE EXTERNAL TABLE transactions(
 `
 }
 
-func getDefaultQueryExamples() string {
+// DefaultQueryExamples returns the example queries used in prompts
+func DefaultQueryExamples() string {
 	return `
 Common Query Examples:
 
 1. Find Most Active Addresses in Last 7 Days:
 SELECT from_address, COUNT(*) as tx_count 
 FROM eth.transactions 
-WHERE date >= date_sub(current_date(), 7)
+WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
 GROUP BY from_address 
 ORDER BY tx_count DESC 
 LIMIT 10;
@@ -514,6 +844,12 @@ FROM eth.transactions
 WHERE date >= date_sub(current_date(), 1)
 GROUP BY 1
 ORDER BY 1;
+
+3. Find latest transactions:
+SELECT * FROM eth.transactions 
+WHERE date >= date_format(date_add('day', -7, current_date), '%Y-%m-%d')
+ORDER BY block_timestamp DESC 
+LIMIT 3;
 `
 }
 
@@ -522,28 +858,113 @@ func (p *DatabaseProviderImpl) Type() string {
 	return "database"
 }
 
-// GetProviderState returns the current state of the provider
+// GetProviderState returns the current state of the provider. The state is
+// logged and shown to the LLM, so it carries operational fields only and never
+// the API endpoint, auth token or query text.
 func (p *DatabaseProviderImpl) GetProviderState(ctx context.Context) (*plugins.ProviderState, error) {
+	probe := p.checkConnectivity(ctx)
+
+	lastQueryTime := ""
+	if t := p.getLastQueryTime(); !t.IsZero() {
+		lastQueryTime = t.Format(time.RFC3339)
+	}
+
 	state := &plugins.ProviderState{
 		Name:  p.Name(),
 		Type:  p.Type(),
-		State: "connected", // Default state since we don't maintain persistent connections
+		State: probe.state,
 		Metadata: map[string]interface{}{
-			"api_url":     p.apiURL,
-			"chain":       p.chain,
-			"last_query":  p.lastQuery,
-			"query_count": p.queryCount,
+			"chain":           p.chain,
+			"query_count":     p.queryCount.Load(),
+			"last_query_time": lastQueryTime,
+			"healthy":         probe.state == StateConnected,
+			"checked_at":      probe.checkedAt.Format(time.RFC3339),
 		},
 	}
+	if probe.err != nil {
+		state.Error = p.redactSecrets(probe.err.Error())
+	}
 
 	return state, nil
 }
 
+// urlPattern matches the URLs quoted by transport errors
+var urlPattern = regexp.MustCompile(`https?://[^\s"]+`)
+
+// redactSecrets strips URLs, which may embed credentials, and the auth token
+// from a message
+func (p *DatabaseProviderImpl) redactSecrets(message string) string {
+	message = urlPattern.ReplaceAllString(message, "[data api]")
+	if p.authToken != "" {
+		message = strings.ReplaceAll(message, p.authToken, "[redacted]")
+	}
+	return message
+}
+
+// checkConnectivity probes the data API with a trivial query, reusing the
+// result for probeCacheTTL so building the agent state stays cheap
+func (p *DatabaseProviderImpl) checkConnectivity(ctx context.Context) probeResult {
+	p.probeMu.Lock()
+	defer p.probeMu.Unlock()
+
+	if !p.probe.checkedAt.IsZero() && time.Since(p.probe.checkedAt) < probeCacheTTL {
+		return p.probe
+	}
+
+	err := p.probeAPI(ctx)
+	p.probe = probeResult{
+		state:     connectivityState(err),
+		err:       err,
+		checkedAt: time.Now(),
+	}
+	return p.probe
+}
+
+// probeAPI runs a single cheap query without retries
+func (p *DatabaseProviderImpl) probeAPI(ctx context.Context) error {
+	if p.apiURL == "" {
+		return fmt.Errorf("API URL is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	resp, err := p.executeAPIRequest(ctx, "SELECT 1", "")
+	if err != nil {
+		return types.ClassifyRequestError(err)
+	}
+	if resp.Code != 0 {
+		return &types.UpstreamError{Code: resp.Code, Message: resp.Msg}
+	}
+	return nil
+}
+
+// connectivityState maps a probe error to a provider state: the API is
+// degraded when it answers with an error or too slowly, and disconnected
+// when it cannot be reached at all
+func connectivityState(err error) string {
+	var upstreamErr *types.UpstreamError
+	switch {
+	case err == nil:
+		return StateConnected
+	case errors.As(err, &upstreamErr), errors.Is(err, types.ErrTimeout):
+		return StateDegraded
+	default:
+		return StateDisconnected
+	}
+}
+
 // Name returns the name of the provider
 func (p *DatabaseProviderImpl) Name() string {
 	return p.name
 }
 
+// Close releases the idle connections of the provider's transport
+func (p *DatabaseProviderImpl) Close() error {
+	p.httpClient.CloseIdleConnections()
+	return nil
+}
+
 func (p *DatabaseProviderImpl) AnalyzeResults(ctx context.Context, results interface{}) (string, error) {
 	// Convert results to JSON for analysis
 	resultsJSON, err := json.Marshal(results)
@@ -562,7 +983,7 @@ Please provide:
 4. Recommendations based on the data`, string(resultsJSON))
 
 	request := llm.CompletionRequest{
-		Model: p.model,
+		Model: p.analysisModel(),
 		Messages: []llm.Message{
 			{
 				Role:    "system",
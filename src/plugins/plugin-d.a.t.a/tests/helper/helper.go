@@ -28,6 +28,7 @@ func SetupTestProviderWithLLM(t *testing.T, llmClient llm.Client) types.Database
 		"test-examples",
 		llmClient,
 		"test-model",
+		nil,
 		zap.NewNop().Sugar(),
 	)
 }
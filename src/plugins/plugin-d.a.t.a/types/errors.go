@@ -0,0 +1,69 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+var (
+	// ErrInvalidQueryLength is returned for empty queries or queries over the length limit
+	ErrInvalidQueryLength = errors.New("invalid SQL query length")
+	// ErrForbiddenSQL is returned for queries that are not a single read-only statement
+	ErrForbiddenSQL = errors.New("forbidden SQL statement")
+	// ErrNoSQLQuery is returned when the LLM response contains no valid SQL query
+	ErrNoSQLQuery = errors.New("no valid SQL query found in response")
+	// ErrUpstream is returned when the data API or the LLM fails
+	ErrUpstream = errors.New("upstream request failed")
+	// ErrTimeout is returned when a request runs out of time
+	ErrTimeout = errors.New("request timed out")
+	// ErrInvalidAddress is returned for malformed ethereum addresses
+	ErrInvalidAddress = errors.New("invalid ethereum address format")
+	// ErrAddressChecksum is returned when a mixed-case address fails its EIP-55 checksum
+	ErrAddressChecksum = errors.New("invalid ethereum address checksum")
+	// ErrInvalidTxHash is returned for malformed transaction hashes
+	ErrInvalidTxHash = errors.New("invalid transaction hash")
+	// ErrTransactionNotFound is returned when no transaction has the requested hash
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrUnknownQueryTemplate is returned when no query template has the requested name
+	ErrUnknownQueryTemplate = errors.New("unknown query template")
+	// ErrInvalidTemplateParam is returned for missing or mistyped query template parameters
+	ErrInvalidTemplateParam = errors.New("invalid query template parameter")
+	// ErrInvalidLimit is returned for a row limit below 1 or above the configured maximum
+	ErrInvalidLimit = errors.New("invalid result limit")
+)
+
+// UpstreamError describes a failed response from the data API
+type UpstreamError struct {
+	// StatusCode is the HTTP status, or 0 when the API reported an error code
+	StatusCode int
+	// Code is the error code in the API response body
+	Code    int
+	Message string
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error %d: %s", e.Code, e.Message)
+}
+
+// Is reports UpstreamError as ErrUpstream
+func (e *UpstreamError) Is(target error) bool {
+	return target == ErrUpstream
+}
+
+// ClassifyRequestError wraps err with ErrTimeout or ErrUpstream
+func ClassifyRequestError(err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) || errors.Is(err, ErrUpstream) {
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", ErrUpstream, err)
+}
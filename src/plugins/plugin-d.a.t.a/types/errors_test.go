@@ -0,0 +1,56 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyRequestError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: ErrTimeout},
+		{name: "network timeout", err: fmt.Errorf("dial: %w", timeoutError{}), want: ErrTimeout},
+		{name: "other failure", err: errors.New("connection refused"), want: ErrUpstream},
+		{name: "already classified", err: &UpstreamError{StatusCode: 502}, want: ErrUpstream},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyRequestError(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("ClassifyRequestError() = %v, want it to match %v", got, tt.want)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("ClassifyRequestError() = %v, want it to wrap %v", got, tt.err)
+			}
+		})
+	}
+
+	if ClassifyRequestError(nil) != nil {
+		t.Error("ClassifyRequestError(nil) != nil")
+	}
+}
+
+func TestUpstreamErrorIsErrUpstream(t *testing.T) {
+	err := fmt.Errorf("query failed: %w", &UpstreamError{Code: 1001, Message: "MALFORMED_QUERY"})
+
+	if !errors.Is(err, ErrUpstream) || errors.Is(err, ErrTimeout) {
+		t.Errorf("errors.Is(%v) does not match ErrUpstream only", err)
+	}
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || upstream.Code != 1001 {
+		t.Errorf("errors.As(%v) = %+v, want the API error code", err, upstream)
+	}
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Row is a row of a query result, a JSON object of column values
+type Row map[string]interface{}
+
+// RowOf returns item as a row, false when it isn't a JSON object
+func RowOf(item interface{}) (Row, bool) {
+	switch row := item.(type) {
+	case Row:
+		return row, row != nil
+	case map[string]interface{}:
+		return Row(row), row != nil
+	}
+	return nil, false
+}
+
+// HasAll reports whether every column is present and not null
+func (r Row) HasAll(columns ...string) bool {
+	for _, column := range columns {
+		if r[column] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// GetString returns the column as text, or def when it is missing or null.
+// Numbers are formatted so large integers don't turn into exponents.
+func (r Row) GetString(column, def string) string {
+	switch v := r[column].(type) {
+	case nil:
+		return def
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// GetFloat returns the column as a number, or def when it is missing or not numeric
+func (r Row) GetFloat(column string, def float64) float64 {
+	if f, ok := ToFloat(r[column]); ok {
+		return f
+	}
+	return def
+}
+
+// GetInt returns the column as an integer, or def when it is missing or not
+// an integer
+func (r Row) GetInt(column string, def int64) int64 {
+	if n, ok := ToInt(r[column]); ok {
+		return n
+	}
+	return def
+}
+
+// ToFloat converts a JSON number, Go number or numeric string to a float
+func ToFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// ToInt converts a whole JSON number, Go integer or integer string to an int64
+func ToInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		if n != float64(int64(n)) {
+			return 0, false
+		}
+		return int64(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return i, err == nil
+	case string:
+		i, err := strconv.ParseInt(n, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
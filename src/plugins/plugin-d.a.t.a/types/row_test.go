@@ -0,0 +1,78 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRowOf(t *testing.T) {
+	tests := []struct {
+		name string
+		item interface{}
+		want bool
+	}{
+		{name: "object", item: map[string]interface{}{"hash": "0x1"}, want: true},
+		{name: "row", item: Row{"hash": "0x1"}, want: true},
+		{name: "nil", item: nil},
+		{name: "nil object", item: map[string]interface{}(nil)},
+		{name: "string", item: "0x1"},
+		{name: "array", item: []interface{}{"0x1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := RowOf(tt.item); ok != tt.want {
+				t.Errorf("RowOf(%v) ok = %v, want %v", tt.item, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowAccessors(t *testing.T) {
+	row := Row{
+		"hash":      "0x1",
+		"value":     float64(1e21),
+		"gas":       json.Number("21000"),
+		"gas_price": "1.5",
+		"to":        nil,
+		"nested":    map[string]interface{}{"a": 1},
+		"fraction":  2.5,
+	}
+
+	if got := row.GetString("hash", "?"); got != "0x1" {
+		t.Errorf("GetString(hash) = %q, want 0x1", got)
+	}
+	if got := row.GetString("value", "?"); got != "1000000000000000000000" {
+		t.Errorf("GetString(value) = %q, want the integer without an exponent", got)
+	}
+	if got := row.GetString("to", "contract creation"); got != "contract creation" {
+		t.Errorf("GetString(to) = %q, want the default for null", got)
+	}
+	if got := row.GetString("missing", "?"); got != "?" {
+		t.Errorf("GetString(missing) = %q, want the default", got)
+	}
+
+	if got := row.GetFloat("gas_price", -1); got != 1.5 {
+		t.Errorf("GetFloat(gas_price) = %v, want 1.5", got)
+	}
+	if got := row.GetFloat("hash", -1); got != -1 {
+		t.Errorf("GetFloat(hash) = %v, want the default for text", got)
+	}
+	if got := row.GetFloat("nested", -1); got != -1 {
+		t.Errorf("GetFloat(nested) = %v, want the default for an object", got)
+	}
+
+	if got := row.GetInt("gas", -1); got != 21000 {
+		t.Errorf("GetInt(gas) = %v, want 21000", got)
+	}
+	if got := row.GetInt("fraction", -1); got != -1 {
+		t.Errorf("GetInt(fraction) = %v, want the default for a fraction", got)
+	}
+	if got := row.GetInt("to", -1); got != -1 {
+		t.Errorf("GetInt(to) = %v, want the default for null", got)
+	}
+
+	if !row.HasAll("hash", "gas") || row.HasAll("hash", "to") {
+		t.Error("HasAll() should require every column present and not null")
+	}
+}
@@ -4,6 +4,13 @@ import (
 	"context"
 )
 
+// Query types of a TransactionQueryResult
+const (
+	QueryTypeTransaction = "transaction"
+	QueryTypeToken       = "token"
+	QueryTypeAggregate   = "aggregate"
+)
+
 // BlockStats represents the statistics of the blocks
 type BlockStats struct {
 	BlockRange struct {
@@ -25,7 +32,9 @@ type TransactionQueryResult struct {
 	Success  bool          `json:"success"`
 	Data     []interface{} `json:"data"`
 	Analysis string        `json:"analysis,omitempty"`
-	Metadata struct {
+	// AnalysisError explains why the analysis is missing when only the query succeeded
+	AnalysisError string `json:"analysisError,omitempty"`
+	Metadata      struct {
 		Total         int    `json:"total"`
 		QueryTime     string `json:"queryTime"`
 		QueryType     string `json:"queryType"`
@@ -36,6 +45,8 @@ type TransactionQueryResult struct {
 			ParamValidation []string `json:"paramValidation,omitempty"`
 		} `json:"queryDetails,omitempty"`
 		BlockStats *BlockStats `json:"blockStats,omitempty"`
+		// Truncated is set when more rows were available than were fetched
+		Truncated bool `json:"truncated,omitempty"`
 	} `json:"metadata"`
 	Error *struct {
 		Code    string      `json:"code"`
@@ -50,6 +61,8 @@ type DatabaseProvider interface {
 	ProcessQuery(ctx context.Context, params map[string]interface{}) (*TransactionQueryResult, error)
 	AnalyzeQuery(ctx context.Context, result *TransactionQueryResult) (string, error)
 	GenerateQuery(ctx context.Context, message string) (string, error)
+	// MaxResultLimit is the most rows a query may return
+	MaxResultLimit() int
 }
 
 // APIResponse represents the response from the API
@@ -61,5 +74,9 @@ type APIResponse struct {
 		Rows        []struct {
 			Items []interface{} `json:"items"`
 		} `json:"rows"`
+		// NextPageToken requests the next page of a large result, empty on the last page
+		NextPageToken string `json:"next_page_token,omitempty"`
+		// Truncated is set when the API cut the result short without another page
+		Truncated bool `json:"truncated,omitempty"`
 	} `json:"data"`
 }
@@ -76,9 +76,25 @@ const erc20ABI = `[
    }
 ]`
 
+// ethBackend is the subset of ethclient.Client used by BaseClient, so a mock
+// can stand in for a live node
+type ethBackend interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	Close()
+}
+
+var _ ethBackend = (*ethclient.Client)(nil)
+
 // BaseClient represents a client for interacting with Base chain
 type BaseClient struct {
-	client     *ethclient.Client
+	client     ethBackend
 	chainID    *big.Int
 	PrivateKey *ecdsa.PrivateKey
 	address    string
@@ -107,6 +123,11 @@ func NewBaseClient(cfg Config) (*BaseClient, error) {
 		return nil, fmt.Errorf("failed to connect to Base chain: %w", err)
 	}
 
+	return newBaseClient(client, cfg)
+}
+
+// newBaseClient creates a client on top of the given backend
+func newBaseClient(client ethBackend, cfg Config) (*BaseClient, error) {
 	// Verify chain ID
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
@@ -284,7 +305,7 @@ func (c *BaseClient) GetERC20TokenBalance(ctx context.Context, tokenAddress, add
 	}
 
 	// Create contract binding
-	contract := bind.NewBoundContract(common.HexToAddress(tokenAddress), parsed, c.client, c.client, c.client)
+	contract := bind.NewBoundContract(common.HexToAddress(tokenAddress), parsed, c.client, nil, nil)
 
 	// Call balanceOf
 	var result []interface{}
@@ -339,7 +360,7 @@ func (c *BaseClient) GetTokenInfo(ctx context.Context, tokenAddress string) (*To
 	}
 
 	address := common.HexToAddress(tokenAddress)
-	contract := bind.NewBoundContract(address, parsed, c.client, c.client, c.client)
+	contract := bind.NewBoundContract(address, parsed, c.client, nil, nil)
 
 	var (
 		nameRes   []interface{}
@@ -0,0 +1,136 @@
+package clients
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var errNodeUnavailable = errors.New("node unavailable")
+
+// mockBackend stands in for a node, recording the transactions sent to it
+type mockBackend struct {
+	ethBackend
+	chainID *big.Int
+	balance *big.Int
+	sendErr error
+	sent    []*types.Transaction
+}
+
+func (m *mockBackend) ChainID(context.Context) (*big.Int, error) {
+	return m.chainID, nil
+}
+
+func (m *mockBackend) BalanceAt(context.Context, common.Address, *big.Int) (*big.Int, error) {
+	return m.balance, nil
+}
+
+func (m *mockBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 7, nil
+}
+
+func (m *mockBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (m *mockBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if m.sendErr != nil {
+		return m.sendErr
+	}
+	m.sent = append(m.sent, tx)
+	return nil
+}
+
+func (m *mockBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return &types.Receipt{TxHash: hash, Status: types.ReceiptStatusSuccessful, GasUsed: 21000}, nil
+}
+
+func newTestClient(t *testing.T, backend *mockBackend) *BaseClient {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	client, err := newBaseClient(backend, Config{ChainID: 8453, PrivateKey: "0x" + hex.EncodeToString(crypto.FromECDSA(key))})
+	if err != nil {
+		t.Fatalf("newBaseClient() error = %v", err)
+	}
+	return client
+}
+
+func TestTransfer(t *testing.T) {
+	to := "0x000000000000000000000000000000000000dEaD"
+	tests := []struct {
+		name    string
+		sendErr error
+		wantErr bool
+	}{
+		{name: "success"},
+		{name: "send failure", sendErr: errNodeUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &mockBackend{chainID: big.NewInt(8453), sendErr: tt.sendErr}
+			client := newTestClient(t, backend)
+
+			result, err := client.Transfer(context.Background(), TransferInput{
+				To:       to,
+				Amount:   big.NewFloat(0.5),
+				GasLimit: 21000,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Transfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, errNodeUnavailable) {
+					t.Errorf("Transfer() error = %v, want it to wrap the send error", err)
+				}
+				return
+			}
+
+			if len(backend.sent) != 1 {
+				t.Fatalf("sent %d transactions, want 1", len(backend.sent))
+			}
+			tx := backend.sent[0]
+			wantValue, _ := new(big.Int).SetString("500000000000000000", 10)
+			if tx.To().Hex() != to || tx.Value().Cmp(wantValue) != 0 || tx.Nonce() != 7 {
+				t.Errorf("sent tx to %s of %s wei with nonce %d, want %s of %s wei with nonce 7",
+					tx.To().Hex(), tx.Value(), tx.Nonce(), to, wantValue)
+			}
+			if !result.Status || result.TxHash != tx.Hash().Hex() || result.From != client.GetAddress(context.Background()) {
+				t.Errorf("result = %+v, want a successful transfer of %s", result, tx.Hash().Hex())
+			}
+		})
+	}
+}
+
+func TestNewBaseClientRejectsWrongChain(t *testing.T) {
+	_, err := newBaseClient(&mockBackend{chainID: big.NewInt(1)}, Config{ChainID: 8453, PrivateKey: "0x01"})
+	if err == nil {
+		t.Error("newBaseClient() error = nil, want a chain ID mismatch")
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	balance, _ := new(big.Int).SetString("1500000000000000000", 10)
+	client := newTestClient(t, &mockBackend{chainID: big.NewInt(8453), balance: balance})
+
+	got, err := client.GetBalance(context.Background(), "0x000000000000000000000000000000000000dEaD")
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if amount, _ := got.Amount.Float64(); amount != 1.5 || got.Symbol != "ETH" {
+		t.Errorf("balance = %v %s, want 1.5 ETH", amount, got.Symbol)
+	}
+
+	if _, err := client.GetBalance(context.Background(), "not-an-address"); err == nil {
+		t.Error("GetBalance() error = nil, want an invalid address error")
+	}
+}
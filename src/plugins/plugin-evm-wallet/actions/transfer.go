@@ -47,6 +47,11 @@ func (a *TransferAction) Type() string {
 	return a.actionType
 }
 
+// RequiresConfirmation asks the user to confirm before moving funds
+func (a *TransferAction) RequiresConfirmation() bool {
+	return true
+}
+
 /*
   Parameters:
     - toAddress: string
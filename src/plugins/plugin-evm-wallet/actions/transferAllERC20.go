@@ -49,6 +49,11 @@ func (a *TransferAllERC20Action) Type() string {
 	return a.actionType
 }
 
+// RequiresConfirmation asks the user to confirm before moving funds
+func (a *TransferAllERC20Action) RequiresConfirmation() bool {
+	return true
+}
+
 /*
   Parameters:
     - toAddress: string
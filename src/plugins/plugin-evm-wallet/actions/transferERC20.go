@@ -50,6 +50,11 @@ func (a *TransferERC20Action) Type() string {
 	return a.actionType
 }
 
+// RequiresConfirmation asks the user to confirm before moving funds
+func (a *TransferERC20Action) RequiresConfirmation() bool {
+	return true
+}
+
 /*
   Parameters:
     - toAddress: string
@@ -1,30 +1,44 @@
 package web
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/web/proto"
 
 	"github.com/gin-gonic/gin"
 )
 
 func Healthy(c *gin.Context) {
-	SetOrigin(c)
-
 	c.JSON(http.StatusOK, proto.HealthyRsp{})
 }
 
 func AreYouReady(c *gin.Context) {
-	SetOrigin(c)
-
 	c.JSON(http.StatusOK, proto.AreYouReadyRsp{
 		Status: "success",
 	})
 }
 
-func Talk(c *gin.Context) {
-	SetOrigin(c)
+// Reload re-reads the config file and applies its reloadable settings
+func Reload(c *gin.Context) {
+	if reloadConfig == nil {
+		c.JSON(http.StatusNotImplemented, proto.ReloadRsp{Error: *CommErr(http.StatusNotImplemented, "reload is not available")})
+		return
+	}
 
+	if err := reloadConfig(c.Request.Context()); err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, conf.ErrImmutableConfig) {
+			code = http.StatusConflict
+		}
+		c.JSON(code, proto.ReloadRsp{Error: *CommErr(int64(code), err.Error())})
+		return
+	}
+	c.JSON(http.StatusOK, proto.ReloadRsp{Error: *NilErr()})
+}
+
+func Talk(c *gin.Context) {
 	var req proto.TalkReq
 	if err := ParamsCheck(c, &req); err != nil {
 		c.JSON(http.StatusOK, *CommErr(http.StatusBadRequest, err.Error()))
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+)
+
+func TestReload(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   conf.WebAuthConfig
+		reload func(context.Context) error
+		want   int
+	}{
+		{name: "valid reload", auth: conf.WebAuthConfig{Enabled: true, APIKeys: []string{"secret"}}, reload: func(context.Context) error { return nil }, want: http.StatusOK},
+		{name: "immutable change", auth: conf.WebAuthConfig{Enabled: true, APIKeys: []string{"secret"}}, reload: func(context.Context) error {
+			return fmt.Errorf("%w: database.type", conf.ErrImmutableConfig)
+		}, want: http.StatusConflict},
+		{name: "invalid config", auth: conf.WebAuthConfig{Enabled: true, APIKeys: []string{"secret"}}, reload: func(context.Context) error {
+			return fmt.Errorf("invalid access config")
+		}, want: http.StatusBadRequest},
+		{name: "auth disabled", reload: func(context.Context) error { return nil }, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			OnReload(tt.reload)
+			t.Cleanup(func() { OnReload(nil) })
+			handler := newServer(conf.WebConfig{Auth: tt.auth}).Handler
+
+			req := httptest.NewRequest(http.MethodPost, "/reload", nil)
+			req.Header.Set("X-API-Key", "secret")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("POST /reload = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
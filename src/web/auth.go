@@ -0,0 +1,106 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAPIKeyHeader = "X-API-Key"
+
+// Cors sets CORS headers for allowed origins and answers preflight requests.
+// Without allowed origins no CORS headers are sent, and "*" allows any origin
+// without credentials.
+func Cors(cfg conf.WebAuthConfig) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, origin := range cfg.AllowedOrigins {
+		allowed[origin] = true
+	}
+	allowHeaders := corsAllowHeaders(cfg)
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		switch {
+		case origin == "":
+		case allowed[origin]:
+			SetOrigin(c, origin, allowHeaders, true)
+		case allowed["*"]:
+			SetOrigin(c, "*", allowHeaders, false)
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// corsAllowHeaders lists the request headers the API reads: the API key
+// header, the Authorization bearer token and the JSON content type
+func corsAllowHeaders(cfg conf.WebAuthConfig) string {
+	header := cfg.Header
+	if header == "" {
+		header = defaultAPIKeyHeader
+	}
+	headers := []string{"Authorization", "Content-Type"}
+	if !strings.EqualFold(header, "Authorization") {
+		headers = append(headers, header)
+	}
+	return strings.Join(headers, ", ")
+}
+
+// APIKeyAuth rejects requests that do not carry one of the configured API keys,
+// either in the configured header or as an Authorization bearer token
+func APIKeyAuth(cfg conf.WebAuthConfig) gin.HandlerFunc {
+	header := cfg.Header
+	if header == "" {
+		header = defaultAPIKeyHeader
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		key := c.Request.Header.Get(header)
+		if key == "" {
+			key = strings.TrimPrefix(c.Request.Header.Get("Authorization"), "Bearer ")
+		}
+
+		if !validAPIKey(key, cfg.APIKeys) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, *CommErr(http.StatusUnauthorized, "unauthorized"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAuth refuses requests when API key authentication is disabled, for
+// endpoints that must never be open
+func RequireAuth(cfg conf.WebAuthConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.AbortWithStatusJSON(http.StatusForbidden, *CommErr(http.StatusForbidden, "endpoint requires web auth to be enabled"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func validAPIKey(key string, keys []string) bool {
+	if key == "" {
+		return false
+	}
+	for _, k := range keys {
+		if k != "" && subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			return true
+		}
+	}
+	return false
+}
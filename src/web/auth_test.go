@@ -0,0 +1,156 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	cfg := conf.WebConfig{Auth: conf.WebAuthConfig{Enabled: true, APIKeys: []string{"secret"}}}
+	handler := newServer(cfg).Handler
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "protected without key", path: "/talk", want: http.StatusUnauthorized},
+		{name: "protected with wrong key", path: "/talk", headers: map[string]string{"X-API-Key": "nope"}, want: http.StatusUnauthorized},
+		{name: "protected with key header", path: "/talk", headers: map[string]string{"X-API-Key": "secret"}, want: http.StatusOK},
+		{name: "protected with bearer token", path: "/talk", headers: map[string]string{"Authorization": "Bearer secret"}, want: http.StatusOK},
+		{name: "health is open", path: "/healthy", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthDisabled(t *testing.T) {
+	handler := newServer(conf.WebConfig{}).Handler
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/talk", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /talk = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestCors(t *testing.T) {
+	cfg := conf.WebConfig{Auth: conf.WebAuthConfig{AllowedOrigins: []string{"https://app.example"}}}
+	handler := newServer(cfg).Handler
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		wantOrigin string
+		wantStatus int
+	}{
+		{name: "allowed origin", method: http.MethodGet, origin: "https://app.example", wantOrigin: "https://app.example", wantStatus: http.StatusOK},
+		{name: "other origin", method: http.MethodGet, origin: "https://evil.example", wantStatus: http.StatusOK},
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.example", wantOrigin: "https://app.example", wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/healthy", nil)
+			req.Header.Set("Origin", tt.origin)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestCorsHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name            string
+		cfg             conf.WebAuthConfig
+		origin          string
+		wantOrigin      string
+		wantCredentials string
+		wantHeaders     string
+	}{
+		{
+			name:   "no allowed origins",
+			origin: "https://evil.example",
+		},
+		{
+			name:            "listed origin",
+			cfg:             conf.WebAuthConfig{AllowedOrigins: []string{"https://app.example"}},
+			origin:          "https://app.example",
+			wantOrigin:      "https://app.example",
+			wantCredentials: "true",
+			wantHeaders:     "Authorization, Content-Type, X-API-Key",
+		},
+		{
+			name:   "unlisted origin",
+			cfg:    conf.WebAuthConfig{AllowedOrigins: []string{"https://app.example"}},
+			origin: "https://evil.example",
+		},
+		{
+			name:        "wildcard has no credentials",
+			cfg:         conf.WebAuthConfig{Header: "X-Token", AllowedOrigins: []string{"*"}},
+			origin:      "https://any.example",
+			wantOrigin:  "*",
+			wantHeaders: "Authorization, Content-Type, X-Token",
+		},
+		{
+			name:            "authorization header is not repeated",
+			cfg:             conf.WebAuthConfig{Header: "Authorization", AllowedOrigins: []string{"https://app.example"}},
+			origin:          "https://app.example",
+			wantOrigin:      "https://app.example",
+			wantCredentials: "true",
+			wantHeaders:     "Authorization, Content-Type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Cors(tt.cfg))
+			r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials"); got != tt.wantCredentials {
+				t.Errorf("Allow-Credentials = %q, want %q", got, tt.wantCredentials)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+		})
+	}
+}
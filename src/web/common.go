@@ -22,13 +22,17 @@ func NilErr() *proto.Error {
 	}
 }
 
-func SetOrigin(c *gin.Context) {
-	origin := c.Request.Header.Get("Origin")
+// SetOrigin allows the origin to call the API with the given request headers.
+// Credentials are only allowed for an explicitly listed origin.
+func SetOrigin(c *gin.Context, origin, allowHeaders string, credentials bool) {
 	c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 	c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE,UPDATE")
-	c.Header("Access-Control-Allow-Headers", "Authorization, Content-Length, X-CSRF-Token, Token,session")
+	c.Header("Access-Control-Allow-Headers", allowHeaders)
 	c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers")
-	c.Header("Access-Control-Allow-Credentials", "true")
+	if credentials {
+		c.Header("Access-Control-Allow-Credentials", "true")
+	}
+	c.Header("Vary", "Origin")
 }
 
 func ParamsCheck(c *gin.Context, req interface{}) error {
@@ -9,6 +9,10 @@ type TalkRsp struct {
 	Content string `json:"content"`
 }
 
+type ReloadRsp struct {
+	Error
+}
+
 type HealthyRsp struct{}
 
 type AreYouReadyRsp struct {
@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/httputil"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/carv-protocol/d.a.t.a/src/internal/conf"
 	"github.com/carv-protocol/d.a.t.a/src/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -19,10 +21,17 @@ import (
 
 var (
 	server *http.Server
+	// reloadConfig applies a re-read config file to the running agent
+	reloadConfig func(ctx context.Context) error
 )
 
-func Start(port int) {
-	server = newServer(port)
+// OnReload sets the function the /reload endpoint calls to reload the config
+func OnReload(fn func(ctx context.Context) error) {
+	reloadConfig = fn
+}
+
+func Start(cfg conf.WebConfig) {
+	server = newServer(cfg)
 	go func() {
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.GetLogger().Fatalf("listen err: %v", err)
@@ -30,28 +39,33 @@ func Start(port int) {
 	}()
 }
 
-func Stop() {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
+// Stop shuts the server down, waiting for in-flight requests until ctx is done
+func Stop(ctx context.Context) error {
+	if server == nil {
+		return nil
+	}
 	if err := server.Shutdown(ctx); err != nil {
-		logger.GetLogger().Errorf("[web] api svr shutdown err: %v", err)
+		return fmt.Errorf("failed to stop web server: %w", err)
 	}
+	return nil
 }
 
-func newServer(port int) *http.Server {
+func newServer(cfg conf.WebConfig) *http.Server {
 
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
-	r.Use(GinRecovery(true), ZapLogger(logger.GetLogger()))
+	r.Use(GinRecovery(true), ZapLogger(logger.GetLogger()), Cors(cfg.Auth))
 
-	r.Any("/talk", Talk)
 	r.GET("/healthy", Healthy)
 	r.GET("/are/you/ready", AreYouReady)
 
+	protected := r.Group("/", APIKeyAuth(cfg.Auth))
+	protected.Any("/talk", Talk)
+	protected.POST("/reload", RequireAuth(cfg.Auth), Reload)
+
 	return &http.Server{
-		Addr:    ":" + strconv.Itoa(port),
+		Addr:    ":" + strconv.Itoa(cfg.Port),
 		Handler: r,
 	}
 }